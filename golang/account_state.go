@@ -0,0 +1,114 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+const (
+	// DefaultAccountStateAttempts default number of reads before giving up on a consistent account state
+	DefaultAccountStateAttempts = 3
+	// indexerHeightPollInterval interval at which WaitForIndexerHeight polls the indexer
+	indexerHeightPollInterval = 500 * time.Millisecond
+)
+
+// ErrInconsistentAccountState the indexer advanced during every read of the account state
+var ErrInconsistentAccountState = errors.New("indexer advanced while reading account state")
+
+// IndexerPosition position in the chain event stream that indexer data reflects
+type IndexerPosition struct {
+	BlockHeight      uint64 // Last handled block height
+	BlockTime        uint64 // Last handled block time, unit: milliseconds
+	TransactionIndex string // Last handled transaction index
+	EventIndex       string // Last handled event index
+}
+
+// AccountState collateral, positions and active orders of a subaccount read at the same indexer position
+type AccountState struct {
+	SubaccountId   string                      // Subaccount ID
+	Position       IndexerPosition             // Indexer position all lists reflect
+	CollateralList []types.PerpetualCollateral // Collateral list
+	PositionList   []types.PerpetualPosition   // Position list
+	OrderList      []types.Order               // Active order list
+}
+
+// AccountAssetPosition returns the indexer position an account asset response reflects
+func AccountAssetPosition(data *types.GetPerpetualAccountAssetRespData) IndexerPosition {
+	return IndexerPosition{
+		BlockHeight:      data.LastHandledBlockHeight,
+		BlockTime:        data.LastHandledBlockTime,
+		TransactionIndex: data.LastHandledTransactionIndex,
+		EventIndex:       data.LastHandledEventIndex,
+	}
+}
+
+// GetAccountState reads the collateral, positions and active orders of a subaccount at one indexer position.
+// The gateway cannot serve reads as of a block height, so the account asset is read before and after the active orders
+// and the read is retried when the indexer advanced in between
+func (c *AntxClient) GetAccountState(subaccountId string) (*AccountState, error) {
+	for attempt := 0; attempt < DefaultAccountStateAttempts; attempt++ {
+		before, err := c.GetPerpetualAccountAsset(types.GetPerpetualAccountAssetReq{SubaccountId: subaccountId})
+		if err != nil {
+			return nil, err
+		}
+		orders, err := c.getAllActiveOrders(subaccountId)
+		if err != nil {
+			return nil, err
+		}
+		after, err := c.GetPerpetualAccountAsset(types.GetPerpetualAccountAssetReq{SubaccountId: subaccountId})
+		if err != nil {
+			return nil, err
+		}
+		position := AccountAssetPosition(&after.Data)
+		if AccountAssetPosition(&before.Data) != position {
+			continue
+		}
+		return &AccountState{
+			SubaccountId:   subaccountId,
+			Position:       position,
+			CollateralList: after.Data.CollateralList,
+			PositionList:   after.Data.PositionList,
+			OrderList:      orders,
+		}, nil
+	}
+	return nil, fmt.Errorf("subaccount %s: %w", subaccountId, ErrInconsistentAccountState)
+}
+
+// WaitForIndexerHeight waits until the indexer has handled the block at height, e.g. the block of a transaction just sent,
+// and returns the indexer position reached
+func (c *AntxClient) WaitForIndexerHeight(subaccountId string, height uint64, timeout time.Duration) (IndexerPosition, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := c.GetPerpetualAccountAsset(types.GetPerpetualAccountAssetReq{SubaccountId: subaccountId})
+		if err != nil {
+			return IndexerPosition{}, err
+		}
+		position := AccountAssetPosition(&resp.Data)
+		if position.BlockHeight >= height {
+			return position, nil
+		}
+		if time.Now().After(deadline) {
+			return position, fmt.Errorf("indexer at height %d did not reach height %d within %s", position.BlockHeight, height, timeout)
+		}
+		time.Sleep(indexerHeightPollInterval)
+	}
+}
+
+// getAllActiveOrders reads all pages of the active orders of a subaccount
+func (c *AntxClient) getAllActiveOrders(subaccountId string) ([]types.Order, error) {
+	req := types.GetActiveOrderReq{
+		SubaccountId: subaccountId,
+		Size:         100,
+	}
+	return types.FetchAllPages(req.Size, func(cursor types.Cursor) ([]types.Order, types.IndexerPageOffsetData, error) {
+		req.PageOffsetDataCreatedTime, req.PageOffsetDataItemId = cursor.CreatedTime, cursor.ItemId
+		resp, err := c.GetActiveOrder(req)
+		if err != nil {
+			return nil, types.IndexerPageOffsetData{}, err
+		}
+		return resp.Data.OrderList, resp.Data.PageOffsetData, nil
+	})
+}
@@ -0,0 +1,374 @@
+package sdk
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// DefaultAccountSyncInterval default interval between reconciliations with the gateway
+const DefaultAccountSyncInterval = 30 * time.Second
+
+// AccountView orders, positions and collateral of a subaccount at one point of the sync
+type AccountView struct {
+	SubaccountId   string                      // Subaccount ID
+	Position       IndexerPosition             // Indexer position of the last reconciliation
+	OrderList      []types.Order               // Active orders, ordered by order ID
+	PositionList   []types.PerpetualPosition   // Open positions, ordered by exchange ID
+	CollateralList []types.PerpetualCollateral // Collaterals, ordered by coin ID
+	ReconciledAt   time.Time                   // Time of the last reconciliation
+	UpdatedAt      time.Time                   // Time of the last change
+}
+
+// AccountChange entities changed by one WebSocket event or reconciliation
+type AccountChange struct {
+	SubaccountId   string                       // Subaccount ID
+	OrderList      []types.Order                // Changed orders, orders in a final status or missing on reconciliation left the view
+	PositionList   []types.PerpetualPosition    // Changed positions, positions with zero open size left the view
+	CollateralList []types.PerpetualCollateral  // Changed collaterals
+	FillList       []types.OrderFillTransaction // New fills
+	Reconciled     bool                         // Whether the change comes from a reconciliation with the gateway
+}
+
+// AccountSyncConfig account sync configuration
+type AccountSyncConfig struct {
+	SubaccountId string              // Subaccount to sync
+	Interval     time.Duration       // Interval between reconciliations, defaults to DefaultAccountSyncInterval
+	OnChange     func(AccountChange) // Called after each change of the view
+	ErrorHandler func(error)         // Called on event and reconciliation errors, errors are logged when nil
+}
+
+// AccountSync keeps one consistent read model of the orders, positions and collateral of a subaccount: it bootstraps
+// from the gateway, applies the private WebSocket events and periodically reconciles with the gateway again to repair
+// dropped events. An entity is only replaced by a version updated at the same time or later, so late events and
+// reconciliations racing with events do not roll the view back. Collaterals are versioned by their cumulative amounts.
+type AccountSync struct {
+	client *AntxClient
+	config AccountSyncConfig
+
+	mu           sync.RWMutex
+	orders       map[string]types.Order
+	positions    map[string]types.PerpetualPosition
+	collaterals  map[string]types.PerpetualCollateral
+	position     IndexerPosition
+	reconciledAt time.Time
+	updatedAt    time.Time
+
+	done chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// NewAccountSync creates an account sync of a subaccount, the WebSocket must be connected before Start
+func (c *AntxClient) NewAccountSync(config AccountSyncConfig) (*AccountSync, error) {
+	if config.SubaccountId == "" {
+		return nil, fmt.Errorf("subaccount ID is required")
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultAccountSyncInterval
+	}
+	return &AccountSync{
+		client:      c,
+		config:      config,
+		orders:      make(map[string]types.Order),
+		positions:   make(map[string]types.PerpetualPosition),
+		collaterals: make(map[string]types.PerpetualCollateral),
+		done:        make(chan struct{}),
+	}, nil
+}
+
+// Start subscribes to the private events, bootstraps the view from the gateway and keeps it in sync until Stop is called
+func (s *AccountSync) Start() error {
+	// Subscribe first so that no event between the bootstrap and the subscription is missed
+	tradeDataChan, err := s.client.SubscribeToTradeData()
+	if err != nil {
+		return err
+	}
+	if err := s.Reconcile(); err != nil {
+		return err
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.done:
+				return
+			case msg := <-tradeDataChan:
+				event, err := s.client.ParseTradeDataEvent(msg)
+				if err != nil {
+					s.report(err)
+					continue
+				}
+				s.Apply(event)
+			case <-ticker.C:
+				if err := s.Reconcile(); err != nil {
+					s.report(err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops applying events and reconciling
+func (s *AccountSync) Stop() {
+	s.once.Do(func() { close(s.done) })
+	s.wg.Wait()
+}
+
+// Reconcile reads a consistent account state from the gateway and merges it into the view
+func (s *AccountSync) Reconcile() error {
+	state, err := s.client.GetAccountState(s.config.SubaccountId)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile account: %w", err)
+	}
+	change := AccountChange{SubaccountId: s.config.SubaccountId, Reconciled: true}
+
+	s.mu.Lock()
+	active := make(map[string]bool, len(state.OrderList))
+	for _, order := range state.OrderList {
+		active[order.Id] = true
+		if s.applyOrder(order) {
+			change.OrderList = append(change.OrderList, order)
+		}
+	}
+	// Orders missing from the gateway were closed, unless an event updated them after the state was read
+	for id, order := range s.orders {
+		if !active[id] && order.UpdatedTime <= state.Position.BlockTime {
+			delete(s.orders, id)
+			change.OrderList = append(change.OrderList, order)
+		}
+	}
+	open := make(map[string]bool, len(state.PositionList))
+	for _, position := range state.PositionList {
+		open[positionKey(&position)] = true
+		if s.applyPosition(position) {
+			change.PositionList = append(change.PositionList, position)
+		}
+	}
+	for key, position := range s.positions {
+		if !open[key] && position.UpdatedTime <= state.Position.BlockTime {
+			delete(s.positions, key)
+			position.OpenSize = "0"
+			change.PositionList = append(change.PositionList, position)
+		}
+	}
+	for _, collateral := range state.CollateralList {
+		if s.applyCollateral(collateral) {
+			change.CollateralList = append(change.CollateralList, collateral)
+		}
+	}
+	s.position = state.Position
+	s.reconciledAt = time.Now()
+	s.mu.Unlock()
+
+	s.notify(change)
+	return nil
+}
+
+// Apply merges a private WebSocket event into the view, entities of other subaccounts are ignored
+func (s *AccountSync) Apply(event *types.TradeDataEvent) {
+	change := AccountChange{SubaccountId: s.config.SubaccountId}
+
+	s.mu.Lock()
+	for _, order := range event.OrderList {
+		if order.SubaccountId == s.config.SubaccountId && s.applyOrder(order) {
+			change.OrderList = append(change.OrderList, order)
+		}
+	}
+	for _, position := range event.PositionList {
+		if position.SubaccountId == s.config.SubaccountId && s.applyPosition(position) {
+			change.PositionList = append(change.PositionList, position)
+		}
+	}
+	for _, collateral := range event.CollateralList {
+		if collateral.SubaccountId == s.config.SubaccountId && s.applyCollateral(collateral) {
+			change.CollateralList = append(change.CollateralList, collateral)
+		}
+	}
+	for _, fill := range event.OrderFillTransactionList {
+		if fill.SubaccountId == s.config.SubaccountId {
+			change.FillList = append(change.FillList, fill)
+		}
+	}
+	s.mu.Unlock()
+
+	s.notify(change)
+}
+
+// View returns the orders, positions and collateral at one point of the sync
+func (s *AccountSync) View() AccountView {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	view := AccountView{
+		SubaccountId:   s.config.SubaccountId,
+		Position:       s.position,
+		OrderList:      make([]types.Order, 0, len(s.orders)),
+		PositionList:   make([]types.PerpetualPosition, 0, len(s.positions)),
+		CollateralList: make([]types.PerpetualCollateral, 0, len(s.collaterals)),
+		ReconciledAt:   s.reconciledAt,
+		UpdatedAt:      s.updatedAt,
+	}
+	for _, order := range s.orders {
+		view.OrderList = append(view.OrderList, order)
+	}
+	for _, position := range s.positions {
+		view.PositionList = append(view.PositionList, position)
+	}
+	for _, collateral := range s.collaterals {
+		view.CollateralList = append(view.CollateralList, collateral)
+	}
+	sort.Slice(view.OrderList, func(i, j int) bool { return view.OrderList[i].Id < view.OrderList[j].Id })
+	sort.Slice(view.PositionList, func(i, j int) bool {
+		return positionKey(&view.PositionList[i]) < positionKey(&view.PositionList[j])
+	})
+	sort.Slice(view.CollateralList, func(i, j int) bool { return view.CollateralList[i].CoinId < view.CollateralList[j].CoinId })
+	return view
+}
+
+// Order returns an active order by order ID
+func (s *AccountSync) Order(orderId string) (types.Order, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	order, ok := s.orders[orderId]
+	return order, ok
+}
+
+// Position returns the open position of an exchange in a margin mode
+func (s *AccountSync) Position(exchangeId string, marginMode uint32) (types.PerpetualPosition, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	position, ok := s.positions[fmt.Sprintf("%s/%d", exchangeId, marginMode)]
+	return position, ok
+}
+
+// applyOrder merges an order unless the view holds a later version, must be called with the lock held
+func (s *AccountSync) applyOrder(order types.Order) bool {
+	current, ok := s.orders[order.Id]
+	if ok && (current.UpdatedTime > order.UpdatedTime || current == order) {
+		return false
+	}
+	if isFinalOrderStatus(order.Status) {
+		if !ok {
+			return false
+		}
+		delete(s.orders, order.Id)
+	} else {
+		s.orders[order.Id] = order
+	}
+	s.updatedAt = time.Now()
+	return true
+}
+
+// applyPosition merges a position unless the view holds a later version, must be called with the lock held
+func (s *AccountSync) applyPosition(position types.PerpetualPosition) bool {
+	key := positionKey(&position)
+	current, ok := s.positions[key]
+	if ok && (current.UpdatedTime > position.UpdatedTime || current == position) {
+		return false
+	}
+	size, err := parseOptionalDecimal(position.OpenSize)
+	if err != nil {
+		s.report(fmt.Errorf("failed to parse open size of position %s: %w", key, err))
+		return false
+	}
+	if size.IsZero() {
+		if !ok {
+			return false
+		}
+		delete(s.positions, key)
+	} else {
+		s.positions[key] = position
+	}
+	s.updatedAt = time.Now()
+	return true
+}
+
+// applyCollateral merges a collateral unless the view holds a later version, must be called with the lock held
+func (s *AccountSync) applyCollateral(collateral types.PerpetualCollateral) bool {
+	if current, ok := s.collaterals[collateral.CoinId]; ok {
+		if current == collateral {
+			return false
+		}
+		older, err := collateralOlder(collateral, current)
+		if err != nil {
+			s.report(fmt.Errorf("failed to compare collateral of coin %s: %w", collateral.CoinId, err))
+			return false
+		}
+		if older {
+			return false
+		}
+	}
+	s.collaterals[collateral.CoinId] = collateral
+	s.updatedAt = time.Now()
+	return true
+}
+
+// collateralOlder reports whether collateral is an earlier version than current. Collaterals carry no update time, so
+// their version is given by the cumulative amounts, which only grow: collateral is earlier when it is behind current
+// on one of them and ahead on none.
+func collateralOlder(collateral, current types.PerpetualCollateral) (bool, error) {
+	pairs := [][2]string{
+		{collateral.CumDepositAmount, current.CumDepositAmount},
+		{collateral.CumWithdrawAmount, current.CumWithdrawAmount},
+		{collateral.CumTransferInAmount, current.CumTransferInAmount},
+		{collateral.CumTransferOutAmount, current.CumTransferOutAmount},
+		{collateral.CumCrossPositionOpenLongAmount, current.CumCrossPositionOpenLongAmount},
+		{collateral.CumCrossPositionOpenShortAmount, current.CumCrossPositionOpenShortAmount},
+		{collateral.CumCrossPositionCloseLongAmount, current.CumCrossPositionCloseLongAmount},
+		{collateral.CumCrossPositionCloseShortAmount, current.CumCrossPositionCloseShortAmount},
+		{collateral.CumIsolatedPositionOpenAmount, current.CumIsolatedPositionOpenAmount},
+		{collateral.CumIsolatedPositionCloseAmount, current.CumIsolatedPositionCloseAmount},
+	}
+	behind := false
+	for _, pair := range pairs {
+		a, err := parseOptionalDecimal(pair[0])
+		if err != nil {
+			return false, err
+		}
+		b, err := parseOptionalDecimal(pair[1])
+		if err != nil {
+			return false, err
+		}
+		// Compared by magnitude, so amounts recorded as deductions grow the same way
+		switch a.Abs().Cmp(b.Abs()) {
+		case 1:
+			return false, nil
+		case -1:
+			behind = true
+		}
+	}
+	return behind, nil
+}
+
+// notify calls the change callback when the change is not empty
+func (s *AccountSync) notify(change AccountChange) {
+	if s.config.OnChange == nil {
+		return
+	}
+	if len(change.OrderList) == 0 && len(change.PositionList) == 0 && len(change.CollateralList) == 0 && len(change.FillList) == 0 {
+		return
+	}
+	s.config.OnChange(change)
+}
+
+// report passes an error to the configured handler
+func (s *AccountSync) report(err error) {
+	if s.config.ErrorHandler != nil {
+		s.config.ErrorHandler(err)
+		return
+	}
+	s.client.Logger().Errorf("account sync: subaccount %s: %v", s.config.SubaccountId, err)
+}
+
+// positionKey identifies a position by exchange and margin mode
+func positionKey(position *types.PerpetualPosition) string {
+	return fmt.Sprintf("%s/%d", position.ExchangeId, position.MarginMode)
+}
@@ -0,0 +1,363 @@
+package sdk
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// AccountingFormat output format of ExportAccounting
+type AccountingFormat string
+
+const (
+	AccountingBeancount AccountingFormat = "beancount"  // Beancount ledger with the open directives of the accounts used
+	AccountingLedger    AccountingFormat = "ledger"     // ledger-cli journal
+	AccountingCSV       AccountingFormat = "csv"        // One CSV row per posting
+	AccountingKoinlyCSV AccountingFormat = "koinly_csv" // Koinly universal CSV, one row per entry, accepted by most crypto tax tools
+)
+
+// Accounting entry kinds
+const (
+	AccountingKindFill       = "fill"       // Realized PnL, fee and liquidation fee of a fill
+	AccountingKindFunding    = "funding"    // Funding settlement
+	AccountingKindDeposit    = "deposit"    // Collateral credited from outside the subaccount
+	AccountingKindWithdrawal = "withdrawal" // Collateral debited to outside the subaccount
+	AccountingKindTransfer   = "transfer"   // Collateral moved from or to another subaccount
+)
+
+// AccountingPosting amount booked to one account by an accounting entry
+type AccountingPosting struct {
+	Account  string          // Account name, e.g. "Assets:Antx:Sub123:USDT"
+	Amount   decimal.Decimal // Amount, the amounts of the postings of an entry sum to zero
+	Currency string          // Coin symbol
+}
+
+// AccountingEntry balanced double-entry transaction built from a fill or a collateral transaction
+type AccountingEntry struct {
+	Time         time.Time           // Creation time
+	Id           string              // ID of the source fill or collateral transaction
+	Kind         string              // Entry kind, one of the AccountingKind constants
+	SubaccountId string              // Subaccount ID
+	ExchangeId   string              // Exchange ID, empty for collateral movements
+	Symbol       string              // Exchange symbol, empty for collateral movements
+	Description  string              // Human readable description
+	Postings     []AccountingPosting // Postings, zero amounts are left out
+}
+
+// AccountingSymbols symbols of the exchanges and coins referenced by the transactions, by ID
+type AccountingSymbols struct {
+	Exchanges map[string]*types.Exchange // Exchanges by exchange ID
+	Coins     map[string]*types.Coin     // Coins by coin ID
+}
+
+// ExportAccounting reads the fills and collateral transactions of a subaccount created in [start, end), resolves their
+// exchange and coin symbols through the metadata cache and writes them to w in a double-entry or tax CSV format
+func (c *AntxClient) ExportAccounting(w io.Writer, format AccountingFormat, subaccountId string, start, end time.Time) error {
+	fills, collaterals, err := c.getFillsAndCollaterals(subaccountId, start, end)
+	if err != nil {
+		return err
+	}
+	symbols := AccountingSymbols{Exchanges: make(map[string]*types.Exchange), Coins: make(map[string]*types.Coin)}
+	exchangeIds := make([]string, 0, len(fills)+len(collaterals))
+	coinIds := make([]string, 0, len(fills)+len(collaterals))
+	for _, fill := range fills {
+		exchangeIds, coinIds = append(exchangeIds, fill.ExchangeId), append(coinIds, fill.CoinId)
+	}
+	for _, tx := range collaterals {
+		exchangeIds, coinIds = append(exchangeIds, tx.ExchangeId), append(coinIds, tx.CoinId)
+	}
+	for _, exchangeId := range exchangeIds {
+		if _, ok := symbols.Exchanges[exchangeId]; ok || exchangeId == "" || exchangeId == "0" {
+			continue
+		}
+		if symbols.Exchanges[exchangeId], err = c.GetExchange(exchangeId); err != nil {
+			return err
+		}
+		coinIds = append(coinIds, symbols.Exchanges[exchangeId].QuoteCoinId)
+	}
+	for _, coinId := range coinIds {
+		if _, ok := symbols.Coins[coinId]; ok || coinId == "" || coinId == "0" {
+			continue
+		}
+		if symbols.Coins[coinId], err = c.GetCoin(coinId); err != nil {
+			return err
+		}
+	}
+	entries, err := BuildAccountingEntries(fills, collaterals, symbols)
+	if err != nil {
+		return err
+	}
+	return WriteAccounting(w, format, entries)
+}
+
+// BuildAccountingEntries translates fills and collateral transactions into balanced entries, oldest first. Collateral
+// transactions of fills are left out as the fills book them.
+func BuildAccountingEntries(fills []types.OrderFillTransaction, collaterals []types.CollateralTransaction, symbols AccountingSymbols) ([]AccountingEntry, error) {
+	entries := make([]AccountingEntry, 0, len(fills)+len(collaterals))
+	for _, fill := range fills {
+		var values [3]decimal.Decimal
+		for i, value := range []string{fill.RealizePnl, fill.FillFee, fill.LiquidateFee} {
+			var err error
+			if values[i], err = parseOptionalDecimal(value); err != nil {
+				return nil, fmt.Errorf("failed to parse fill %s: %w", fill.Id, err)
+			}
+		}
+		pnl, fees := values[0], values[1].Add(values[2])
+		symbol, currency := symbols.exchange(fill.ExchangeId, fill.CoinId)
+		side := "sell"
+		if fill.IsBuy {
+			side = "buy"
+		}
+		entry := AccountingEntry{
+			Time:         time.UnixMilli(int64(fill.CreatedTime)).UTC(),
+			Id:           fill.Id,
+			Kind:         AccountingKindFill,
+			SubaccountId: fill.SubaccountId,
+			ExchangeId:   fill.ExchangeId,
+			Symbol:       symbol,
+			Description:  fmt.Sprintf("%s %s %s @ %s", symbol, side, fill.FillSize, fill.FillPrice),
+		}
+		entry.post(collateralAccount(fill.SubaccountId, currency), pnl.Add(fees), currency)
+		entry.post("Income:Antx:Trading:"+accountComponent(symbol), pnl.Neg(), currency)
+		entry.post("Expenses:Antx:Fees:"+accountComponent(symbol), fees.Neg(), currency)
+		if len(entry.Postings) > 0 {
+			entries = append(entries, entry)
+		}
+	}
+	for _, tx := range collaterals {
+		if tx.OrderFillTransactionId != "" && tx.OrderFillTransactionId != "0" {
+			continue
+		}
+		delta, err := parseOptionalDecimal(tx.DeltaAmount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse collateral transaction %s: %w", tx.Id, err)
+		}
+		currency := symbols.coin(tx.CoinId)
+		entry := AccountingEntry{
+			Time:         time.UnixMilli(int64(tx.CreatedTime)).UTC(),
+			Id:           tx.Id,
+			SubaccountId: tx.SubaccountId,
+		}
+		counter := "Equity:Antx:Transfers"
+		switch {
+		case tx.FundingTime != 0:
+			entry.Kind = AccountingKindFunding
+			entry.ExchangeId = tx.ExchangeId
+			entry.Symbol, _ = symbols.exchange(tx.ExchangeId, tx.CoinId)
+			entry.Description = fmt.Sprintf("%s funding at rate %s", entry.Symbol, tx.FundingRate)
+			counter = "Income:Antx:Funding:" + accountComponent(entry.Symbol)
+		case tx.TransferPeerSubaccountId != "" && tx.TransferPeerSubaccountId != "0":
+			entry.Kind = AccountingKindTransfer
+			entry.Description = fmt.Sprintf("transfer with subaccount %s", tx.TransferPeerSubaccountId)
+			counter = collateralAccount(tx.TransferPeerSubaccountId, currency)
+		case delta.IsNegative():
+			entry.Kind = AccountingKindWithdrawal
+			entry.Description = fmt.Sprintf("withdraw %s %s", delta.Neg(), currency)
+		default:
+			entry.Kind = AccountingKindDeposit
+			entry.Description = fmt.Sprintf("deposit %s %s", delta, currency)
+		}
+		if tx.TransferRemark != "" {
+			entry.Description += " (" + tx.TransferRemark + ")"
+		}
+		entry.post(collateralAccount(tx.SubaccountId, currency), delta, currency)
+		entry.post(counter, delta.Neg(), currency)
+		if len(entry.Postings) > 0 {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].Time.Before(entries[j].Time) })
+	return entries, nil
+}
+
+// WriteAccounting writes entries to w in a format
+func WriteAccounting(w io.Writer, format AccountingFormat, entries []AccountingEntry) error {
+	switch format {
+	case AccountingBeancount:
+		return writeBeancount(w, entries)
+	case AccountingLedger:
+		return writeLedger(w, entries)
+	case AccountingCSV:
+		return writeAccountingCSV(w, entries)
+	case AccountingKoinlyCSV:
+		return writeKoinlyCSV(w, entries)
+	default:
+		return fmt.Errorf("unsupported accounting format %q", format)
+	}
+}
+
+// writeBeancount writes the open directives of the accounts used, dated by the first entry, then the entries
+func writeBeancount(w io.Writer, entries []AccountingEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	var accounts []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		for _, posting := range entry.Postings {
+			if !seen[posting.Account] {
+				seen[posting.Account] = true
+				accounts = append(accounts, posting.Account)
+			}
+		}
+	}
+	sort.Strings(accounts)
+	var b strings.Builder
+	openDate := entries[0].Time.Format("2006-01-02")
+	for _, account := range accounts {
+		fmt.Fprintf(&b, "%s open %s\n", openDate, account)
+	}
+	for _, entry := range entries {
+		fmt.Fprintf(&b, "\n%s * %q %q\n", entry.Time.Format("2006-01-02"), "Antx", entry.Description)
+		fmt.Fprintf(&b, "  id: %q\n  kind: %q\n  time: %q\n", entry.Id, entry.Kind, entry.Time.Format(time.RFC3339))
+		for _, posting := range entry.Postings {
+			fmt.Fprintf(&b, "  %-48s %s %s\n", posting.Account, posting.Amount, posting.Currency)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeLedger writes the entries as a ledger-cli journal
+func writeLedger(w io.Writer, entries []AccountingEntry) error {
+	var b strings.Builder
+	for i, entry := range entries {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s * %s\n", entry.Time.Format("2006/01/02"), entry.Description)
+		fmt.Fprintf(&b, "    ; id: %s\n    ; kind: %s\n    ; time: %s\n", entry.Id, entry.Kind, entry.Time.Format(time.RFC3339))
+		for _, posting := range entry.Postings {
+			fmt.Fprintf(&b, "    %-48s %s %s\n", posting.Account, posting.Amount, posting.Currency)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeAccountingCSV writes one row per posting
+func writeAccountingCSV(w io.Writer, entries []AccountingEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"time", "id", "kind", "subaccount_id", "exchange_id", "symbol", "account", "amount", "currency", "description"}); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		for _, posting := range entry.Postings {
+			if err := cw.Write([]string{
+				entry.Time.Format(time.RFC3339), entry.Id, entry.Kind, entry.SubaccountId, entry.ExchangeId, entry.Symbol,
+				posting.Account, posting.Amount.String(), posting.Currency, entry.Description,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeKoinlyCSV writes one row per entry in the Koinly universal layout: gains and funding received as received
+// amounts, losses and funding paid as sent amounts, fees in the fee columns
+func writeKoinlyCSV(w io.Writer, entries []AccountingEntry) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{
+		"Date", "Sent Amount", "Sent Currency", "Received Amount", "Received Currency", "Fee Amount", "Fee Currency",
+		"Net Worth Amount", "Net Worth Currency", "Label", "Description", "TxHash",
+	}); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		var amount, fee decimal.Decimal
+		var currency, label string
+		for _, posting := range entry.Postings {
+			switch {
+			case strings.HasPrefix(posting.Account, "Income:"), strings.HasPrefix(posting.Account, "Equity:"):
+				amount, currency = amount.Sub(posting.Amount), posting.Currency
+			case strings.HasPrefix(posting.Account, "Expenses:"):
+				fee, currency = fee.Add(posting.Amount), posting.Currency
+			case entry.Kind == AccountingKindTransfer && !strings.HasPrefix(posting.Account, collateralAccount(entry.SubaccountId, "")):
+				amount, currency = amount.Sub(posting.Amount), posting.Currency
+			}
+		}
+		switch entry.Kind {
+		case AccountingKindFill:
+			label = "realized gain"
+		case AccountingKindFunding:
+			label = "realized gain"
+			if amount.IsNegative() {
+				label = "margin fee"
+			}
+		}
+		row := make([]string, 12)
+		row[0] = entry.Time.Format("2006-01-02 15:04:05 UTC")
+		if amount.IsNegative() {
+			row[1], row[2] = amount.Neg().String(), currency
+		} else if amount.IsPositive() {
+			row[3], row[4] = amount.String(), currency
+		}
+		if !fee.IsZero() {
+			row[5], row[6] = fee.String(), currency
+		}
+		row[9], row[10], row[11] = label, entry.Description, entry.Id
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// post appends a posting unless its amount is zero
+func (e *AccountingEntry) post(account string, amount decimal.Decimal, currency string) {
+	if amount.IsZero() {
+		return
+	}
+	e.Postings = append(e.Postings, AccountingPosting{Account: account, Amount: amount, Currency: currency})
+}
+
+// exchange returns the symbol and quote currency of an exchange, falling back to its ID and to the coin of the
+// transaction when it is unknown
+func (s AccountingSymbols) exchange(exchangeId, coinId string) (symbol, currency string) {
+	exchange, ok := s.Exchanges[exchangeId]
+	if !ok {
+		return exchangeId, s.coin(coinId)
+	}
+	return exchange.Symbol, s.coin(exchange.QuoteCoinId)
+}
+
+// coin returns the symbol of a coin as a commodity name, falling back to its ID when it is unknown
+func (s AccountingSymbols) coin(coinId string) string {
+	if coin, ok := s.Coins[coinId]; ok && coin.Symbol != "" {
+		return strings.ToUpper(accountComponent(coin.Symbol))
+	}
+	return "COIN" + coinId
+}
+
+// collateralAccount returns the asset account of the collateral of a subaccount in a currency
+func collateralAccount(subaccountId, currency string) string {
+	return "Assets:Antx:Sub" + subaccountId + ":" + currency
+}
+
+// accountComponent turns a symbol into a valid account name component: letters and digits, other characters replaced
+// by dashes, starting with a capital letter or a digit
+func accountComponent(symbol string) string {
+	var b strings.Builder
+	for _, r := range symbol {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('-')
+		}
+	}
+	component := strings.Trim(b.String(), "-")
+	if component == "" {
+		return "Unknown"
+	}
+	return strings.ToUpper(component[:1]) + component[1:]
+}
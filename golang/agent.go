@@ -1,6 +1,7 @@
 package sdk
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -8,9 +9,16 @@ import (
 	agenttypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/agent"
 	"github.com/antxprotocol/antx-sdk-golang/constants"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/crypto"
 )
 
+// BindAgentMessage returns the message the owner signs with personal_sign to bind an agent, times in milliseconds
+func BindAgentMessage(agentAddress string, createTime, expireTime uint64, chainId string) string {
+	return fmt.Sprintf("Action:BindAgent\nAgentAddress:%s\nCreateTime:%d\nExpireTime:%d\nChainId:%s",
+		agentAddress, createTime, expireTime, chainId)
+}
+
 func (c *AntxClient) BindAgent(ethPrivatekeyHex string, chainId string, expireTime uint64) (string, error) {
 	ethPrivatekeyHex = strings.TrimPrefix(ethPrivatekeyHex, "0x")
 	ethPrivateKey, err := crypto.HexToECDSA(ethPrivatekeyHex)
@@ -18,12 +26,10 @@ func (c *AntxClient) BindAgent(ethPrivatekeyHex string, chainId string, expireTi
 		return "", err
 	}
 	ethAddress := crypto.PubkeyToAddress(ethPrivateKey.PublicKey).Hex()
-	agentAddress := c.agentAddress.String()
-	createTime := uint64(time.Now().UnixMilli())
-	expireTime = uint64(time.Now().Add(time.Duration(expireTime) * time.Second).UnixMilli())
+	agentAddress := c.GetAgentAddress()
+	createTime, expireTime := c.BindAgentTimes(time.Duration(expireTime) * time.Second)
 
-	message := fmt.Sprintf("Action:BindAgent\nAgentAddress:%s\nCreateTime:%d\nExpireTime:%d\nChainId:%s",
-		agentAddress, createTime, expireTime, chainId)
+	message := BindAgentMessage(agentAddress, createTime, expireTime, chainId)
 
 	// Sign message using personal_sign method
 	signDoc := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)
@@ -32,19 +38,73 @@ func (c *AntxClient) BindAgent(ethPrivatekeyHex string, chainId string, expireTi
 	if err != nil {
 		return "", err
 	}
+
+	return c.sendBindAgent(ethAddress, createTime, expireTime, signature)
+}
+
+// BindAgentWithSigner binds the agent for an owner that signs outside the SDK, e.g. a Safe or other smart-contract wallet.
+// sign receives the binding message and returns the personal_sign signature of the owner, expireTime is in seconds from now.
+// When caller is not nil the signature is verified with EIP-1271 before broadcasting
+func (c *AntxClient) BindAgentWithSigner(ownerAddress, chainId string, expireTime uint64, sign func(message string) ([]byte, error), caller ethereum.ContractCaller) (string, error) {
+	createTime, expireTime := c.BindAgentTimes(time.Duration(expireTime) * time.Second)
+	signature, err := sign(BindAgentMessage(c.GetAgentAddress(), createTime, expireTime, chainId))
+	if err != nil {
+		return "", fmt.Errorf("failed to sign bind agent message: %w", err)
+	}
+	return c.BindAgentWithSignature(ownerAddress, chainId, createTime, expireTime, signature, caller)
+}
+
+// BindAgentWithSignature binds the agent with a signature the owner produced in advance over BindAgentMessage,
+// createTime and expireTime are the millisecond times of the signed message.
+// When caller is not nil the signature is verified with EIP-1271 before broadcasting
+func (c *AntxClient) BindAgentWithSignature(ownerAddress, chainId string, createTime, expireTime uint64, signature []byte, caller ethereum.ContractCaller) (string, error) {
+	ownerAddress, err := NormalizeEthAddress(ownerAddress)
+	if err != nil {
+		return "", err
+	}
+	if expireTime <= uint64(c.ServerNow().UnixMilli()) {
+		return "", fmt.Errorf("bind agent message expired at %d", expireTime)
+	}
+	message := BindAgentMessage(c.GetAgentAddress(), createTime, expireTime, chainId)
+	valid, err := VerifyEthPersonalSignatureWithContract(context.Background(), caller, ownerAddress, []byte(message), signature)
+	if err != nil {
+		return "", fmt.Errorf("failed to verify bind agent signature: %w", err)
+	}
+	if !valid {
+		return "", fmt.Errorf("bind agent signature is not valid for owner %s", ownerAddress)
+	}
+	return c.sendBindAgent(ownerAddress, createTime, expireTime, signature)
+}
+
+// UnbindAgent revokes the binding of the client agent to an owner address
+func (c *AntxClient) UnbindAgent(ownerAddress string) (string, error) {
+	ownerAddress, err := NormalizeEthAddress(ownerAddress)
+	if err != nil {
+		return "", err
+	}
+	msg := agenttypes.MsgUnbindAgent{
+		AgentAddress: c.GetAgentAddress(),
+		ChainType:    agenttypes.ChainType_CHAIN_TYPE_EVM,
+		ChainAddress: ownerAddress,
+	}
+	return c.signAndSendTx(context.Background(), constants.MsgUnbindAgentTypeURL, &msg, false, 0)
+}
+
+// sendBindAgent broadcasts a BindAgent message signed by the owner
+func (c *AntxClient) sendBindAgent(ownerAddress string, createTime, expireTime uint64, signature []byte) (string, error) {
 	// Convert to hex string with 0x prefix
 	ethSignature := fmt.Sprintf("0x%x", signature)
 
 	msg := agenttypes.MsgBindAgent{
-		AgentAddress:   agentAddress,
+		AgentAddress:   c.GetAgentAddress(),
 		ChainType:      agenttypes.ChainType_CHAIN_TYPE_EVM,
-		ChainAddress:   ethAddress,
+		ChainAddress:   ownerAddress,
 		CreateTime:     createTime,
 		ExpireTime:     expireTime,
 		ChainSignature: ethSignature,
 	}
 
-	txHash, err := c.signAndSendTx(constants.MsgBindAgentTypeURL, &msg, false)
+	txHash, err := c.signAndSendTx(context.Background(), constants.MsgBindAgentTypeURL, &msg, false, 0)
 	if err != nil {
 		return "", err
 	}
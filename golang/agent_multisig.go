@@ -0,0 +1,187 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethCrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// safeDomainTypeHash keccak256("EIP712Domain(uint256 chainId,address verifyingContract)")
+	safeDomainTypeHash = ethCrypto.Keccak256([]byte("EIP712Domain(uint256 chainId,address verifyingContract)"))
+	// safeMessageTypeHash keccak256("SafeMessage(bytes message)")
+	safeMessageTypeHash = ethCrypto.Keccak256([]byte("SafeMessage(bytes message)"))
+)
+
+// PendingBinding BindAgent request collecting the owner signatures of a Safe multi-signature wallet,
+// serialized with Marshal to pass it between signers out-of-band
+type PendingBinding struct {
+	AgentAddress string            `json:"agentAddress"` // Agent address to bind
+	OwnerAddress string            `json:"ownerAddress"` // Safe wallet address
+	ChainId      string            `json:"chainId"`      // Chain ID
+	EvmChainId   uint64            `json:"evmChainId"`   // ID of the EVM chain the Safe is deployed on, part of its EIP-712 domain
+	CreateTime   uint64            `json:"createTime"`   // Create time, unit: milliseconds
+	ExpireTime   uint64            `json:"expireTime"`   // Expiration time, unit: milliseconds
+	Message      string            `json:"message"`      // BindAgent message the Safe signs
+	Signatures   map[string]string `json:"signatures"`   // Hex signatures by checksummed signer address
+}
+
+// NewPendingBinding starts a BindAgent request for a Safe owner deployed on the EVM chain evmChainId, expireTime is in
+// seconds from now
+func (c *AntxClient) NewPendingBinding(ownerAddress, chainId string, evmChainId uint64, expireTime uint64) (*PendingBinding, error) {
+	ownerAddress, err := NormalizeEthAddress(ownerAddress)
+	if err != nil {
+		return nil, err
+	}
+	createTime, expireTime := c.BindAgentTimes(time.Duration(expireTime) * time.Second)
+	binding := &PendingBinding{
+		AgentAddress: c.GetAgentAddress(),
+		OwnerAddress: ownerAddress,
+		ChainId:      chainId,
+		EvmChainId:   evmChainId,
+		CreateTime:   createTime,
+		ExpireTime:   expireTime,
+		Signatures:   make(map[string]string),
+	}
+	binding.Message = BindAgentMessage(binding.AgentAddress, binding.CreateTime, binding.ExpireTime, chainId)
+	return binding, nil
+}
+
+// UnmarshalPendingBinding decodes a pending binding and checks that its message matches its fields
+func UnmarshalPendingBinding(data []byte) (*PendingBinding, error) {
+	var binding PendingBinding
+	if err := json.Unmarshal(data, &binding); err != nil {
+		return nil, fmt.Errorf("failed to decode pending binding: %w", err)
+	}
+	if binding.Message != BindAgentMessage(binding.AgentAddress, binding.CreateTime, binding.ExpireTime, binding.ChainId) {
+		return nil, fmt.Errorf("pending binding message does not match its fields")
+	}
+	if binding.Signatures == nil {
+		binding.Signatures = make(map[string]string)
+	}
+	return &binding, nil
+}
+
+// Marshal encodes the pending binding to JSON
+func (b *PendingBinding) Marshal() ([]byte, error) {
+	return json.Marshal(b)
+}
+
+// SafeMessageHash returns the hash each Safe owner signs with personal_sign: the SafeMessage hash the Safe checks the
+// owner signatures against when it validates the signature of Message through EIP-1271
+func (b *PendingBinding) SafeMessageHash() ([]byte, error) {
+	if err := ValidateEthAddress(b.OwnerAddress); err != nil {
+		return nil, err
+	}
+	if b.EvmChainId == 0 {
+		return nil, fmt.Errorf("pending binding has no EVM chain ID")
+	}
+	domainSeparator := ethCrypto.Keccak256(
+		safeDomainTypeHash,
+		common.LeftPadBytes(new(big.Int).SetUint64(b.EvmChainId).Bytes(), 32),
+		common.LeftPadBytes(common.HexToAddress(b.OwnerAddress).Bytes(), 32),
+	)
+	// The Safe fallback handler wraps the EIP-1271 hash as the bytes message abi.encode(hash)
+	structHash := ethCrypto.Keccak256(safeMessageTypeHash, ethCrypto.Keccak256(accounts.TextHash([]byte(b.Message))))
+	return ethCrypto.Keccak256([]byte{0x19, 0x01}, domainSeparator, structHash), nil
+}
+
+// AddSignature adds the personal_sign signature of one owner over SafeMessageHash, verifying it against the owner address
+func (b *PendingBinding) AddSignature(signer string, signature []byte) error {
+	signer, err := NormalizeEthAddress(signer)
+	if err != nil {
+		return err
+	}
+	if len(signature) != 65 {
+		return fmt.Errorf("signature of %s must be 65 bytes, got %d", signer, len(signature))
+	}
+	hash, err := b.SafeMessageHash()
+	if err != nil {
+		return err
+	}
+	// VerifyEthPersonalSignature normalizes v in place
+	if !VerifyEthPersonalSignature(signer, hash, append([]byte(nil), signature...)) {
+		return fmt.Errorf("signature does not match signer %s", signer)
+	}
+	b.Signatures[signer] = hexutil.Encode(signature)
+	return nil
+}
+
+// Signers returns the addresses that signed, in ascending numeric order as the Safe requires
+func (b *PendingBinding) Signers() []string {
+	signers := make([]string, 0, len(b.Signatures))
+	for signer := range b.Signatures {
+		signers = append(signers, signer)
+	}
+	sort.Slice(signers, func(i, j int) bool {
+		return bytes.Compare(common.HexToAddress(signers[i]).Bytes(), common.HexToAddress(signers[j]).Bytes()) < 0
+	})
+	return signers
+}
+
+// Signature assembles the Safe signature: the collected signatures in ascending owner address order, each with v
+// raised by 4 to mark it as an eth_sign signature of SafeMessageHash
+func (b *PendingBinding) Signature() ([]byte, error) {
+	if len(b.Signatures) == 0 {
+		return nil, fmt.Errorf("pending binding has no signatures")
+	}
+	assembled := make([]byte, 0, 65*len(b.Signatures))
+	for _, signer := range b.Signers() {
+		signature, err := hexutil.Decode(b.Signatures[signer])
+		if err != nil {
+			return nil, fmt.Errorf("invalid signature of %s: %w", signer, err)
+		}
+		if len(signature) != 65 {
+			return nil, fmt.Errorf("signature of %s must be 65 bytes, got %d", signer, len(signature))
+		}
+		if signature[64] < 27 {
+			signature[64] += 27
+		}
+		signature[64] += 4
+		assembled = append(assembled, signature...)
+	}
+	return assembled, nil
+}
+
+// BroadcastPendingBinding assembles the collected signatures and broadcasts the binding,
+// when caller is not nil the wallet must also accept the assembled signature through EIP-1271
+func (c *AntxClient) BroadcastPendingBinding(binding *PendingBinding, caller ethereum.ContractCaller) (string, error) {
+	if binding.AgentAddress != c.GetAgentAddress() {
+		return "", fmt.Errorf("pending binding is for agent %s, client agent is %s", binding.AgentAddress, c.GetAgentAddress())
+	}
+	signature, err := binding.Signature()
+	if err != nil {
+		return "", err
+	}
+	if caller != nil {
+		return c.BindAgentWithSignature(binding.OwnerAddress, binding.ChainId, binding.CreateTime, binding.ExpireTime, signature, caller)
+	}
+	// A deserialized binding may carry signatures that were never verified locally
+	hash, err := binding.SafeMessageHash()
+	if err != nil {
+		return "", err
+	}
+	for _, signer := range binding.Signers() {
+		signature, err := hexutil.Decode(binding.Signatures[signer])
+		if err != nil || len(signature) != 65 || !VerifyEthPersonalSignature(signer, hash, signature) {
+			return "", fmt.Errorf("signature does not match signer %s", signer)
+		}
+	}
+	ownerAddress, err := NormalizeEthAddress(binding.OwnerAddress)
+	if err != nil {
+		return "", err
+	}
+	if binding.ExpireTime <= uint64(c.ServerNow().UnixMilli()) {
+		return "", fmt.Errorf("bind agent message expired at %d", binding.ExpireTime)
+	}
+	return c.sendBindAgent(ownerAddress, binding.CreateTime, binding.ExpireTime, signature)
+}
@@ -0,0 +1,79 @@
+package sdk
+
+import (
+	ordertypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/order"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+)
+
+// authzExec wraps an order message in an authz MsgExec when the client executes under the grant of Config.AuthzGranter.
+// The agent address of the message is replaced by the granter, which the chain authenticates through the grant.
+func (c *AntxClient) authzExec(msg sdk.Msg) (sdk.Msg, bool) {
+	if c.authzGranter.Empty() {
+		return msg, false
+	}
+	granter := c.authzGranter.String()
+	switch m := msg.(type) {
+	case *ordertypes.MsgCreateOrder:
+		m.AgentAddress = granter
+	case *ordertypes.MsgCreateOrderBatch:
+		m.AgentAddress = granter
+	case *ordertypes.MsgCancelOrder:
+		m.AgentAddress = granter
+	case *ordertypes.MsgCancelOrderByClientId:
+		m.AgentAddress = granter
+	case *ordertypes.MsgCancelAllOrder:
+		m.AgentAddress = granter
+	case *ordertypes.MsgCloseAllPosition:
+		m.AgentAddress = granter
+	default:
+		// Agent binding and other messages are signed by the agent itself
+		return msg, false
+	}
+	exec := authz.NewMsgExec(c.agentAddress, []sdk.Msg{msg})
+	return &exec, true
+}
+
+// GetAuthzGrants gets the grants of a granter to a grantee, an empty msgTypeURL returns the grants of all message types
+func (c *AntxClient) GetAuthzGrants(granter, grantee, msgTypeURL string) ([]types.AuthzGrant, error) {
+	grants, err := c.getAuthzGrantList("/cosmos/authz/v1beta1/grants", map[string]string{
+		"granter":      granter,
+		"grantee":      grantee,
+		"msg_type_url": msgTypeURL,
+	})
+	// Grants queried by granter and grantee do not repeat them
+	for i := range grants {
+		grants[i].Granter, grants[i].Grantee = granter, grantee
+	}
+	return grants, err
+}
+
+// GetAuthzGrantsByGranter gets the grants issued by a granter
+func (c *AntxClient) GetAuthzGrantsByGranter(granter string) ([]types.AuthzGrant, error) {
+	return c.getAuthzGrantList("/cosmos/authz/v1beta1/grants/granter/"+granter, nil)
+}
+
+// GetAuthzGrantsByGrantee gets the grants received by a grantee, e.g. the agent address
+func (c *AntxClient) GetAuthzGrantsByGrantee(grantee string) ([]types.AuthzGrant, error) {
+	return c.getAuthzGrantList("/cosmos/authz/v1beta1/grants/grantee/"+grantee, nil)
+}
+
+// getAuthzGrantList reads all pages of a grant list
+func (c *AntxClient) getAuthzGrantList(path string, query map[string]string) ([]types.AuthzGrant, error) {
+	var grants []types.AuthzGrant
+	err := nodeGetPages(func(params map[string]string) (*types.NodePageResp, error) {
+		for k, v := range query {
+			if v != "" {
+				params[k] = v
+			}
+		}
+		var result types.GetAuthzGrantListResp
+		if err := c.nodeGet(path, params, &result, &result.NodeResp); err != nil {
+			return nil, err
+		}
+		grants = append(grants, result.Grants...)
+		return result.Pagination, nil
+	})
+	return grants, err
+}
@@ -0,0 +1,111 @@
+package sdk
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+const (
+	// maxBlockRange upper bound of the number of blocks fetched by one ListBlocks call
+	maxBlockRange = 100
+	// blockFetchConcurrency number of blocks ListBlocks fetches at once
+	blockFetchConcurrency = 8
+	// txSearchPageSize transactions per page of a transaction search
+	txSearchPageSize = 100
+)
+
+// GetNodeBlock gets the block at height through the node REST API in Config.NodeAPI
+func (c *AntxClient) GetNodeBlock(height uint64) (*types.NodeBlock, error) {
+	var result types.GetNodeBlockResp
+	path := "/cosmos/base/tendermint/v1beta1/blocks/" + strconv.FormatUint(height, 10)
+	if err := c.nodeGet(path, nil, &result, &result.NodeResp); err != nil {
+		return nil, err
+	}
+	return &result.NodeBlock, nil
+}
+
+// ListBlocks gets the blocks with heights in [fromHeight, toHeight] through the node REST API, at most 100 blocks per
+// call fetched 8 at a time
+func (c *AntxClient) ListBlocks(fromHeight, toHeight uint64) ([]types.NodeBlock, error) {
+	if fromHeight == 0 || toHeight < fromHeight {
+		return nil, fmt.Errorf("invalid block range [%d, %d]", fromHeight, toHeight)
+	}
+	if toHeight-fromHeight+1 > maxBlockRange {
+		return nil, fmt.Errorf("block range [%d, %d] exceeds %d blocks", fromHeight, toHeight, maxBlockRange)
+	}
+	blocks := make([]types.NodeBlock, toHeight-fromHeight+1)
+	errs := make([]error, len(blocks))
+	heights := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < blockFetchConcurrency && w < len(blocks); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range heights {
+				block, err := c.GetNodeBlock(fromHeight + uint64(i))
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				blocks[i] = *block
+			}
+		}()
+	}
+	for i := range blocks {
+		heights <- i
+	}
+	close(heights)
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return blocks, nil
+}
+
+// GetBlockTransactions gets the results of the transactions of the block at height through the node REST API
+func (c *AntxClient) GetBlockTransactions(height uint64) ([]types.NodeTxResponse, error) {
+	return c.searchTransactions(fmt.Sprintf("tx.height=%d", height))
+}
+
+// SearchTransactionsByAddress gets the results of the transactions sent by address in blocks with heights in
+// [fromHeight, toHeight] through the node REST API, with one indexed search instead of a scan of the blocks
+func (c *AntxClient) SearchTransactionsByAddress(address string, fromHeight, toHeight uint64) ([]types.NodeTxResponse, error) {
+	if address == "" {
+		return nil, fmt.Errorf("address is required")
+	}
+	if fromHeight == 0 || toHeight < fromHeight {
+		return nil, fmt.Errorf("invalid block range [%d, %d]", fromHeight, toHeight)
+	}
+	antxAddress, err := ConvertToAntxAddr(address)
+	if err != nil {
+		return nil, err
+	}
+	return c.searchTransactions(fmt.Sprintf("message.sender='%s' AND tx.height>=%d AND tx.height<=%d", antxAddress, fromHeight, toHeight))
+}
+
+// searchTransactions reads all pages of a transaction search by events
+func (c *AntxClient) searchTransactions(query string) ([]types.NodeTxResponse, error) {
+	var txs []types.NodeTxResponse
+	for page := 1; ; page++ {
+		var result types.GetNodeTxListResp
+		params := map[string]string{
+			"query":    query,
+			"page":     strconv.Itoa(page),
+			"limit":    strconv.Itoa(txSearchPageSize),
+			"order_by": "ORDER_BY_ASC",
+		}
+		if err := c.nodeGet("/cosmos/tx/v1beta1/txs", params, &result, &result.NodeResp); err != nil {
+			return nil, err
+		}
+		txs = append(txs, result.TxResponses...)
+		total, _ := strconv.Atoi(result.Total)
+		if len(result.TxResponses) < txSearchPageSize || (total > 0 && len(txs) >= total) {
+			return txs, nil
+		}
+	}
+}
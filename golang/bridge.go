@@ -0,0 +1,236 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	ethCrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// bridgeReceiptPollInterval interval at which SendBridgeDeposit polls the approve receipt
+	bridgeReceiptPollInterval = 2 * time.Second
+	// bridgeReceiptTimeout time limit of SendBridgeDeposit waiting for the approve to be mined when ctx has no deadline
+	bridgeReceiptTimeout = 5 * time.Minute
+	// depositPollInterval interval at which WaitForDeposit polls the collateral transactions
+	depositPollInterval = 5 * time.Second
+)
+
+// erc20ApproveSelector bytes4(keccak256("approve(address,uint256)"))
+var erc20ApproveSelector = []byte{0x09, 0x5e, 0xa7, 0xb3}
+
+// BridgeConfig bridge contract of an asset chain
+type BridgeConfig struct {
+	Address       string // Bridge contract address
+	DepositMethod string // Solidity signature of the deposit function of the bridge ABI taking (address token, uint256 amount, uint64 subaccountId), e.g. "deposit(address,uint256,uint64)", required
+}
+
+// BridgeDeposit ERC-20 approve and bridge deposit calls crediting a subaccount, to be sent from the owner address
+type BridgeDeposit struct {
+	ChainId      string         // Asset chain ID of the coin
+	CoinId       string         // Coin ID
+	SubaccountId uint64         // Subaccount credited
+	Token        common.Address // ERC-20 contract of the coin
+	Bridge       common.Address // Bridge contract
+	Amount       *big.Int       // Amount in token base units
+	ApproveData  []byte         // Call data of approve(bridge, amount) on the token
+	DepositData  []byte         // Call data of the deposit on the bridge
+}
+
+// BridgeDepositResult transactions sent by SendBridgeDeposit
+type BridgeDepositResult struct {
+	ApproveTxHash common.Hash // Approve transaction hash
+	DepositTxHash common.Hash // Deposit transaction hash
+	SentAt        time.Time   // Time the deposit was sent, for WaitForDeposit
+}
+
+// EthTransactor EVM chain client sending the deposit transactions, e.g. an *ethclient.Client
+type EthTransactor interface {
+	ethereum.TransactionSender
+	ethereum.GasPricer
+	ethereum.GasEstimator
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*ethTypes.Receipt, error)
+	ChainID(ctx context.Context) (*big.Int, error)
+}
+
+// BuildBridgeDeposit builds the approve and deposit calls of an amount of a coin, in token base units, to a subaccount
+func (c *AntxClient) BuildBridgeDeposit(bridge BridgeConfig, coinId string, subaccountId uint64, amount *big.Int) (*BridgeDeposit, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, fmt.Errorf("deposit amount must be positive")
+	}
+	if err := ValidateEthAddress(bridge.Address); err != nil {
+		return nil, fmt.Errorf("invalid bridge address: %w", err)
+	}
+	// The selector must come from the bridge ABI, a guessed one would send the deposit to the wrong function
+	if bridge.DepositMethod == "" {
+		return nil, fmt.Errorf("bridge deposit method is required")
+	}
+	coin, err := c.GetCoin(coinId)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateEthAddress(coin.AssetContractAddress); err != nil {
+		return nil, fmt.Errorf("coin %s has no ERC-20 contract: %w", coinId, err)
+	}
+
+	deposit := &BridgeDeposit{
+		ChainId:      coin.AssetChainId,
+		CoinId:       coinId,
+		SubaccountId: subaccountId,
+		Token:        common.HexToAddress(coin.AssetContractAddress),
+		Bridge:       common.HexToAddress(bridge.Address),
+		Amount:       new(big.Int).Set(amount),
+	}
+	deposit.ApproveData = encodeCall(erc20ApproveSelector, deposit.Bridge.Bytes(), amount.Bytes())
+	deposit.DepositData = encodeCall(ethCrypto.Keccak256([]byte(bridge.DepositMethod))[:4],
+		deposit.Token.Bytes(), amount.Bytes(), new(big.Int).SetUint64(subaccountId).Bytes())
+	return deposit, nil
+}
+
+// SendBridgeDeposit signs the approve and deposit transactions with the ETH private key of the client and sends them
+// through eth, waiting for the approve to be mined before sending the deposit, for at most 5 minutes when ctx has no
+// deadline
+func (c *AntxClient) SendBridgeDeposit(ctx context.Context, eth EthTransactor, deposit *BridgeDeposit) (*BridgeDepositResult, error) {
+	if c.ReadOnly() || c.ethPrivateKey == nil {
+		return nil, ErrReadOnly
+	}
+	chainID, err := eth.ChainID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if deposit.ChainId != "" && chainID.String() != deposit.ChainId {
+		return nil, fmt.Errorf("eth client is on chain %s, coin %s is on chain %s", chainID, deposit.CoinId, deposit.ChainId)
+	}
+	from := c.ethAddress
+	nonce, err := eth.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	result := &BridgeDepositResult{}
+	approve, err := c.sendEthCall(ctx, eth, chainID, nonce, deposit.Token, deposit.ApproveData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send approve: %w", err)
+	}
+	result.ApproveTxHash = approve.Hash()
+
+	// The deposit gas can only be estimated once the allowance is set
+	waitCtx := ctx
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, bridgeReceiptTimeout)
+		defer cancel()
+	}
+	for {
+		receipt, err := eth.TransactionReceipt(waitCtx, approve.Hash())
+		if err == nil {
+			if receipt.Status != ethTypes.ReceiptStatusSuccessful {
+				return result, fmt.Errorf("approve transaction %s reverted", approve.Hash())
+			}
+			break
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return result, fmt.Errorf("failed to get approve receipt %s: %w", approve.Hash(), err)
+		}
+		select {
+		case <-waitCtx.Done():
+			return result, fmt.Errorf("approve transaction %s not mined: %w", approve.Hash(), waitCtx.Err())
+		case <-time.After(bridgeReceiptPollInterval):
+		}
+	}
+
+	result.SentAt = time.Now()
+	depositTx, err := c.sendEthCall(ctx, eth, chainID, nonce+1, deposit.Bridge, deposit.DepositData)
+	if err != nil {
+		return result, fmt.Errorf("failed to send deposit: %w", err)
+	}
+	result.DepositTxHash = depositTx.Hash()
+	return result, nil
+}
+
+// WaitForDeposit polls the collateral transactions of a subaccount until a deposit of a coin created at or after since
+// is credited, amount is matched when it is not zero
+func (c *AntxClient) WaitForDeposit(subaccountId uint64, coinId string, amount decimal.Decimal, since time.Time, timeout time.Duration) (*types.CollateralTransaction, error) {
+	deadline := time.Now().Add(timeout)
+	req := types.GetCollateralTransactionReq{
+		SubaccountId: strconv.FormatUint(subaccountId, 10),
+		Size:         100,
+		FilterCoinId: coinId,
+		// Tolerate clock skew between the client and the chain
+		FilterStartCreatedTimeInclusive: uint64(since.Add(-time.Minute).UnixMilli()),
+	}
+	for {
+		resp, err := c.GetCollateralTransaction(req)
+		if err != nil {
+			return nil, err
+		}
+		for i := range resp.Data.CollateralTransactionList {
+			if tx := &resp.Data.CollateralTransactionList[i]; isDepositCredit(tx, amount) {
+				return tx, nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("deposit of coin %s to subaccount %d not credited within %s", coinId, subaccountId, timeout)
+		}
+		time.Sleep(depositPollInterval)
+	}
+}
+
+// sendEthCall signs and sends a contract call from the client ETH address
+func (c *AntxClient) sendEthCall(ctx context.Context, eth EthTransactor, chainID *big.Int, nonce uint64, to common.Address, data []byte) (*ethTypes.Transaction, error) {
+	gasPrice, err := eth.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	gas, err := eth.EstimateGas(ctx, ethereum.CallMsg{From: c.ethAddress, To: &to, Data: data})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	tx := ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: nonce, To: &to, Gas: gas, GasPrice: gasPrice, Data: data})
+	signed, err := ethTypes.SignTx(tx, ethTypes.LatestSignerForChainID(chainID), c.ethPrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	if err := eth.SendTransaction(ctx, signed); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// isDepositCredit reports whether a collateral transaction credits collateral from outside the exchange: positive and
+// not caused by an order, a funding settlement or a transfer between subaccounts
+func isDepositCredit(tx *types.CollateralTransaction, amount decimal.Decimal) bool {
+	delta, err := decimal.NewFromString(tx.DeltaAmount)
+	if err != nil || !delta.IsPositive() {
+		return false
+	}
+	if !isZeroId(tx.OrderId) || !isZeroId(tx.OrderFillTransactionId) || !isZeroId(tx.TransferPeerSubaccountId) || tx.FundingTime != 0 {
+		return false
+	}
+	return amount.IsZero() || delta.Equal(amount)
+}
+
+// isZeroId reports whether an optional gateway ID is unset
+func isZeroId(id string) bool {
+	return id == "" || id == "0"
+}
+
+// encodeCall ABI encodes a call of a function with static arguments, each left-padded to 32 bytes
+func encodeCall(selector []byte, args ...[]byte) []byte {
+	data := make([]byte, 0, 4+32*len(args))
+	data = append(data, selector...)
+	for _, arg := range args {
+		data = append(data, common.LeftPadBytes(arg, 32)...)
+	}
+	return data
+}
@@ -0,0 +1,149 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// telegramAPIURL Telegram Bot API base URL
+const telegramAPIURL = "https://api.telegram.org"
+
+// chatTokens chat services configured in Config
+type chatTokens struct {
+	TelegramBotToken  string
+	TelegramChatId    string
+	SlackWebhookURL   string
+	DiscordWebhookURL string
+}
+
+// ChatTemplates text/template of the chat message of each event type, executed on the WebhookEvent. Besides the
+// builtin functions, templates can use side (bool to BUY/SELL), nonzero (whether a decimal string is not zero) and
+// time (milliseconds to RFC 3339).
+type ChatTemplates map[WebhookEventType]string
+
+// DefaultChatTemplates chat messages used for the event types missing from the configured templates
+var DefaultChatTemplates = ChatTemplates{
+	WebhookEventFill: `Fill: {{side .Data.IsBuy}} {{.Data.FillSize}} on exchange {{.Data.ExchangeId}} at {{.Data.FillPrice}}, ` +
+		`fee {{.Data.FillFee}}{{if nonzero .Data.RealizePnl}}, realized PnL {{.Data.RealizePnl}}{{end}} (subaccount {{.SubaccountId}})`,
+	WebhookEventLiquidation: `Liquidation: {{side .Data.IsBuy}} {{.Data.FillSize}} on exchange {{.Data.ExchangeId}} at {{.Data.FillPrice}}, ` +
+		`liquidation fee {{.Data.LiquidateFee}} (subaccount {{.SubaccountId}})`,
+	WebhookEventOrderRejected: `Order rejected: {{side .Data.IsBuy}} {{.Data.Size}} on exchange {{.Data.ExchangeId}} at {{.Data.Price}}, ` +
+		`order {{.Data.Id}}{{if .Data.ClientOrderId}} ({{.Data.ClientOrderId}}){{end}} (subaccount {{.SubaccountId}})`,
+	WebhookEventMarginRatioBreach: `Margin ratio {{.Data.MarginRatio}} above {{.Data.Threshold}} (subaccount {{.SubaccountId}})`,
+	WebhookEventPnlAlert: `PnL alert: realized {{.Data.RealizePnl}} on exchange {{.Data.ExchangeId}} closing {{.Data.FillSize}} at {{.Data.FillPrice}} ` +
+		`(subaccount {{.SubaccountId}})`,
+}
+
+// chatTemplateFuncs functions available to chat templates
+var chatTemplateFuncs = template.FuncMap{
+	"side": func(isBuy bool) string {
+		if isBuy {
+			return "BUY"
+		}
+		return "SELL"
+	},
+	"nonzero": func(value string) bool {
+		d, err := decimal.NewFromString(value)
+		return err == nil && !d.IsZero()
+	},
+	"time": func(ms int64) string {
+		return time.UnixMilli(ms).UTC().Format(time.RFC3339)
+	},
+}
+
+// NewTelegramNotifier creates a notifier sending events as messages of a Telegram bot to a chat. URL and Encode of
+// config are set from the bot token and templates, the other fields apply as for NewWebhookNotifier.
+func NewTelegramNotifier(botToken, chatId string, templates ChatTemplates, config WebhookConfig) (*WebhookNotifier, error) {
+	if botToken == "" || chatId == "" {
+		return nil, fmt.Errorf("telegram bot token and chat ID are required")
+	}
+	config.URL = fmt.Sprintf("%s/bot%s/sendMessage", telegramAPIURL, botToken)
+	return newChatNotifier(templates, config, func(text string) interface{} {
+		return map[string]string{"chat_id": chatId, "text": text}
+	})
+}
+
+// NewSlackNotifier creates a notifier sending events as messages to a Slack incoming webhook
+func NewSlackNotifier(webhookURL string, templates ChatTemplates, config WebhookConfig) (*WebhookNotifier, error) {
+	if webhookURL == "" {
+		return nil, fmt.Errorf("slack webhook URL is required")
+	}
+	config.URL = webhookURL
+	return newChatNotifier(templates, config, func(text string) interface{} {
+		return map[string]string{"text": text}
+	})
+}
+
+// NewDiscordNotifier creates a notifier sending events as messages to a Discord webhook
+func NewDiscordNotifier(webhookURL string, templates ChatTemplates, config WebhookConfig) (*WebhookNotifier, error) {
+	if webhookURL == "" {
+		return nil, fmt.Errorf("discord webhook URL is required")
+	}
+	config.URL = webhookURL
+	return newChatNotifier(templates, config, func(text string) interface{} {
+		return map[string]string{"content": text}
+	})
+}
+
+// ChatNotifiers creates a notifier for each chat service configured in Config, none when no token is configured
+func (c *AntxClient) ChatNotifiers(templates ChatTemplates, config WebhookConfig) ([]*WebhookNotifier, error) {
+	var notifiers []*WebhookNotifier
+	add := func(notifier *WebhookNotifier, err error) error {
+		if err != nil {
+			return err
+		}
+		notifiers = append(notifiers, notifier)
+		return nil
+	}
+	c.txMu.RLock()
+	chat := c.chat
+	c.txMu.RUnlock()
+	if chat.TelegramBotToken != "" {
+		if err := add(NewTelegramNotifier(chat.TelegramBotToken, chat.TelegramChatId, templates, config)); err != nil {
+			return nil, err
+		}
+	}
+	if chat.SlackWebhookURL != "" {
+		if err := add(NewSlackNotifier(chat.SlackWebhookURL, templates, config)); err != nil {
+			return nil, err
+		}
+	}
+	if chat.DiscordWebhookURL != "" {
+		if err := add(NewDiscordNotifier(chat.DiscordWebhookURL, templates, config)); err != nil {
+			return nil, err
+		}
+	}
+	return notifiers, nil
+}
+
+// newChatNotifier creates a notifier whose request body wraps the rendered message of an event
+func newChatNotifier(templates ChatTemplates, config WebhookConfig, body func(text string) interface{}) (*WebhookNotifier, error) {
+	parsed := make(map[WebhookEventType]*template.Template, len(DefaultChatTemplates))
+	for _, set := range []ChatTemplates{DefaultChatTemplates, templates} {
+		for eventType, text := range set {
+			t, err := template.New(string(eventType)).Funcs(chatTemplateFuncs).Parse(text)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s template: %w", eventType, err)
+			}
+			parsed[eventType] = t
+		}
+	}
+	config.Encode = func(event WebhookEvent) ([]byte, error) {
+		t, ok := parsed[event.Type]
+		if !ok {
+			return nil, fmt.Errorf("no template for event type %s", event.Type)
+		}
+		var text bytes.Buffer
+		if err := t.Execute(&text, event); err != nil {
+			return nil, err
+		}
+		return json.Marshal(body(strings.TrimSpace(text.String())))
+	}
+	return NewWebhookNotifier(config)
+}
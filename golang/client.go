@@ -1,934 +1,604 @@
 package sdk
 
 import (
-	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"encoding/base64"
 	"encoding/hex"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/query"
+	"github.com/antxprotocol/antx-sdk-golang/sign"
+	"github.com/antxprotocol/antx-sdk-golang/store"
 	"github.com/antxprotocol/antx-sdk-golang/types"
-	"github.com/cosmos/cosmos-sdk/client"
-	"github.com/cosmos/cosmos-sdk/client/flags"
-	"github.com/cosmos/cosmos-sdk/client/tx"
-	"github.com/cosmos/cosmos-sdk/codec"
-	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
-	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
-	"github.com/cosmos/cosmos-sdk/crypto/keyring"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
-	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
-	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
-	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
-	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 	ethCommon "github.com/ethereum/go-ethereum/common"
 	ethCrypto "github.com/ethereum/go-ethereum/crypto"
-	"github.com/zeromicro/go-zero/core/logx"
 )
 
 var (
 	globalClient *AntxClient
 )
 
+var (
+	// ErrReadOnly returned by the methods signing or broadcasting transactions on a read-only client
+	ErrReadOnly = errors.New("client is read-only")
+	// ErrOrderNotFound the gateway does not know the order
+	ErrOrderNotFound = errors.New("order not found")
+)
+
 // Config client configuration
 type Config struct {
-	GatewayHost     string // Gateway URI, e.g., "http://127.0.0.1:8080"
-	ChainID         string // Chain ID, e.g., "antx-devnet"
-	EthPrivateKey   string // Private key in hexadecimal string
-	AgentPrivateKey string // Private key in hexadecimal string
-}
+	GatewayHost  string // Gateway URI, e.g., "http://127.0.0.1:8080", required unless MockTransport is set
+	WebSocketURL string // Gateway WebSocket URI, e.g. "ws://127.0.0.1:8080/ws", required by the WebSocket subscriptions
 
-// AntxClient encapsulates the client for interacting with Antx chain
-type AntxClient struct {
-	clientCtx       client.Context
-	ethPrivateKey   *ecdsa.PrivateKey
-	ethAddress      ethCommon.Address
-	agentPrivateKey cryptotypes.PrivKey
-	agentAddress    sdk.AccAddress
-	chainID         string
-	gatewayHost     string
-	accountNumber   uint64
-	// merged HTTP/WebSocket capabilities
-	baseURL    string
-	wsURL      string
-	httpClient *http.Client
-	wsClient   *WebSocketClient
-}
+	FallbackGateways []query.GatewayEndpoint // Gateways failed over to, in order, when GatewayHost fails repeatedly, see query.Client.SetFailover
+	ChainID          string                  // Chain ID, e.g., "antx-devnet"
+	EthPrivateKey    string                  // Private key in hexadecimal string
+	AgentPrivateKey  string                  // Private key in hexadecimal string
 
-// NewAntxClient creates a new Antx client
-func NewAntxClient(config Config) (*AntxClient, error) {
-	// Validate configuration parameters
-	if config.ChainID == "" {
-		return nil, fmt.Errorf("chain ID cannot be empty")
-	}
-	if config.EthPrivateKey == "" {
-		return nil, fmt.Errorf("eth private key cannot be empty")
-	}
-	if config.AgentPrivateKey == "" {
-		return nil, fmt.Errorf("agent private key cannot be empty")
-	}
+	APIPrefix     string            // Prefix of the gateway API paths replacing constants.BaseAPIPath, e.g. "/gateway/api/v2"
+	PathOverrides map[string]string // Gateway paths replacing those of constants, by constant value, e.g. {constants.GetKlinePath: "/v2/kline"}
 
-	// Parse private keys
-	ethPrivateKeyHex := strings.TrimPrefix(config.EthPrivateKey, "0x")
-	agentPrivateKeyHex := strings.TrimPrefix(config.AgentPrivateKey, "0x")
-	if len(ethPrivateKeyHex) != 64 {
-		return nil, fmt.Errorf("invalid eth private key length: expected 64 characters, got %d", len(ethPrivateKeyHex))
-	}
-	if len(agentPrivateKeyHex) != 64 {
-		return nil, fmt.Errorf("invalid agent private key length: expected 64 characters, got %d", len(agentPrivateKeyHex))
-	}
+	APIKey    string // API key of gateways fronted by API key authentication
+	APISecret string // API secret signing each gateway request with query.SignAPIRequest, empty when requests are not signed
 
-	agentPrivateKeyBytes, err := hex.DecodeString(agentPrivateKeyHex)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode agent private key: %w", err)
-	}
+	UserAgentTag string            // Application tag appended to the antx-sdk-golang/<version> User-Agent, e.g. "my-bot/0.3"
+	Headers      map[string]string // Headers sent with every gateway request and WebSocket handshake, overriding the defaults, an empty value removes a default, e.g. {query.HeaderAppToken: token} behind a WAF expecting an app token
 
-	// Create secp256k1 private key
-	ethPrivatekeyHex := strings.TrimPrefix(config.EthPrivateKey, "0x")
-	ethPrivateKey, err := ethCrypto.HexToECDSA(ethPrivatekeyHex)
-	if err != nil {
-		return nil, fmt.Errorf("failed to decode eth private key: %w", err)
-	}
-	agentPrivateKey := &secp256k1.PrivKey{Key: agentPrivateKeyBytes}
+	CompressRequestsAbove      int  // Size in bytes from which gateway request bodies are gzipped, 0 disables request compression
+	DisableResponseCompression bool // Whether gzip/deflate gateway responses and permessage-deflate WebSocket messages are not requested
 
-	// Get addresses
-	ethAddress := ethCrypto.PubkeyToAddress(ethPrivateKey.PublicKey)
-	agentAddress := sdk.AccAddress(agentPrivateKey.PubKey().Address())
+	Logger Logger // Receives the log messages of the client and its components, nil for go-zero logx, query.NopLogger() disables logging
 
-	// Create interface registry
-	interfaceRegistry := codectypes.NewInterfaceRegistry()
+	HTTPClient  *http.Client           // HTTP client sending the gateway requests, e.g. with a proxy or custom TLS, nil for the default
+	HTTPTimeout time.Duration          // Time limit of each gateway request, 0 keeps the limit of HTTPClient or query.DefaultHTTPTimeout
+	Transport   *query.TransportConfig // Connection pooling, HTTP/2, proxy and TLS settings replacing the transport of HTTPClient, the proxy and TLS settings also apply to WebSockets, nil keeps it
 
-	cryptocodec.RegisterInterfaces(interfaceRegistry)
-	authtypes.RegisterInterfaces(interfaceRegistry)
-	banktypes.RegisterInterfaces(interfaceRegistry)
+	RateLimiter *query.RateLimiter // Rate limiter of the gateway requests by endpoint group, may be shared by several clients, nil disables rate limiting
+	RetryPolicy *query.RetryPolicy // Retries of the gateway requests failing with a transport error, a 429 or a 5xx status, nil for query.DefaultRetryPolicy
 
-	// Create codec
-	cdc := codec.NewProtoCodec(interfaceRegistry)
+	MockTransport *query.MockTransport // In-memory fake gateway the requests are routed to instead of the network, for tests and dry runs, see query.NewMockTransport
 
-	// Create client context
-	clientCtx := client.Context{}.
-		WithCodec(cdc).
-		WithInterfaceRegistry(interfaceRegistry).
-		WithBroadcastMode(flags.BroadcastSync).
-		WithChainID(config.ChainID).
-		WithFromAddress(agentAddress).
-		WithAccountRetriever(authtypes.AccountRetriever{}).
-		WithTxConfig(authtx.NewTxConfig(cdc, authtx.DefaultSignModes))
+	EthKeyProvider   KeyProvider // Source of the ETH private key when EthPrivateKey is empty
+	AgentKeyProvider KeyProvider // Source of the agent private key when AgentPrivateKey is empty
 
-	client := &AntxClient{
-		clientCtx:       clientCtx,
-		ethPrivateKey:   ethPrivateKey,
-		ethAddress:      ethAddress,
-		agentPrivateKey: agentPrivateKey,
-		agentAddress:    agentAddress,
-		chainID:         config.ChainID,
-		gatewayHost:     config.GatewayHost,
-	}
+	Signer sign.TxSigner // Signs transactions as the agent instead of the agent private key, e.g. a remote signer
 
-	// initialize http client and baseURL
-	client.httpClient = &http.Client{Timeout: 30 * time.Second}
-	client.baseURL = config.GatewayHost
+	ReadOnly bool // Whether the client refuses to sign and broadcast transactions, trading methods return ErrReadOnly
 
-	if config.GatewayHost != "" {
-		accountNumber, _, err := client.GetAccountNumberAndSequence(agentAddress.String())
-		if err != nil {
-			return nil, fmt.Errorf("failed to get account number and sequence: %w", err)
-		}
-		client.accountNumber, err = strconv.ParseUint(accountNumber, 10, 64)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse account number: %w", err)
-		}
-	}
-	return client, nil
-}
+	Simulator          TxSimulator // Simulates transactions for SimulateTx and SimulateBeforeSend, e.g. NewGRPCSimulator
+	SimulateBeforeSend bool        // Whether transactions are simulated before broadcast: rejections are returned without consuming a sequence and the gas limit is set from the estimate
+	GasAdjustment      float64     // Multiplier of the simulated gas giving the gas limit, defaults to DefaultGasAdjustment
 
-// NewAntxQueryClient creates a lightweight client for HTTP queries and WebSocket only (no on-chain signing configuration required)
-func NewAntxQueryClient(baseURL, wsURL string) *AntxClient {
-	return &AntxClient{
-		baseURL:    baseURL,
-		wsURL:      wsURL,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
-	}
-}
+	GasLimits map[string]uint64 // Gas limit by message type, overriding DefaultGasLimits
 
-// GetAgentAddress gets the agent address
-func (c *AntxClient) GetAgentAddress() string {
-	return c.agentAddress.String()
-}
+	DedupeWindow   time.Duration // Window in which CreateOrder and CreateOrderBatch reject a client order ID already submitted for the subaccount, before signing; 0 disables. Keep it below SubmissionGuardConfig.SettleTime when both are used
+	DedupeCapacity int           // Client order IDs remembered for deduplication, defaults to DefaultDedupeCapacity
 
-// SetGateway sets the HTTP and WebSocket gateway addresses
-func (c *AntxClient) SetGateway(baseURL, wsURL string) {
-	c.baseURL = baseURL
-	c.wsURL = wsURL
-	if c.httpClient == nil {
-		c.httpClient = &http.Client{Timeout: 30 * time.Second}
-	}
-}
+	RiskLimits *RiskLimits // Pre-trade limits CreateOrder and CreateOrderBatch check before signing, nil disables the checks
 
-// =============================== HTTP Request Methods (merged) ===============================
+	Fee        string // Fee of each transaction as coins, e.g. "1000uantx", empty for no fee
+	FeeGranter string // Address paying the fees of the agent under a fee grant, empty when the agent pays
+	NodeAPI    string // cosmos-sdk REST API of a node for fee grant and authz queries, e.g. "http://127.0.0.1:1317"
 
-func (c *AntxClient) httpGet(path string, params map[string]string, result interface{}) error {
-	if c.baseURL == "" {
-		return fmt.Errorf("gateway baseURL is not set")
-	}
-	u, err := url.Parse(c.baseURL + path)
-	if err != nil {
-		return fmt.Errorf("failed to parse URL: %w", err)
-	}
-	q := u.Query()
-	for k, v := range params {
-		q.Set(k, v)
-	}
-	u.RawQuery = q.Encode()
+	AuthzGranter string // Address whose authz grant the agent executes under: order messages are sent as the granter wrapped in MsgExec instead of relying on BindAgent
 
-	if c.httpClient == nil {
-		c.httpClient = &http.Client{Timeout: 30 * time.Second}
-	}
-	req, err := http.NewRequest("GET", u.String(), nil)
-	if err != nil {
-		return fmt.Errorf("failed to create GET request: %w", err)
-	}
-	// Set request headers to avoid WAF blocking
-	req.Header.Set("X-App-Token", "ANTECH-APP-SECRET-KEY-001")
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Mobile; FlutterApp/1.0)")
-	req.Header.Set("Accept", "application/json")
+	TelegramBotToken  string // Telegram bot token of ChatNotifiers
+	TelegramChatId    string // Telegram chat receiving the messages of ChatNotifiers
+	SlackWebhookURL   string // Slack incoming webhook URL of ChatNotifiers
+	DiscordWebhookURL string // Discord webhook URL of ChatNotifiers
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send GET request: %w", err)
+// AntxClient encapsulates the client for interacting with Antx chain. It is safe for concurrent use: orders may be
+// placed and queries sent from many goroutines, and Reload may run while they are. The Set methods of its components,
+// e.g. SetMetricsCollector, SetStore and SetReduceOnlyPolicy, must be called before the client is shared.
+type AntxClient struct {
+	signer        sign.TxSigner
+	ethPrivateKey *ecdsa.PrivateKey
+	ethAddress    ethCommon.Address
+	agentAddress  sdk.AccAddress
+	chainID       string
+	gatewayHost   string
+	accountNumber uint64
+	readOnly      bool
+	// held for reading by transactions being signed and sent and by the readers of the fields Reload replaces, for
+	// writing by Reload
+	txMu sync.RWMutex
+	// middleware around the broadcast of signed transactions
+	broadcastMu         sync.RWMutex
+	broadcastMiddleware []BroadcastMiddleware
+	// HTTP/WebSocket queries
+	*query.Client
+	// methods grouped by surface, for callers depending on one of them
+	Market  MarketDataClient // Market data queries, the client itself
+	Account AccountClient    // Account queries, the client itself
+	Orders  OrderClient      // Order transactions
+	Chain   ChainClient      // Transaction signing and chain queries, the client itself
+	// transaction simulation
+	simulator          TxSimulator
+	simulateBeforeSend bool
+	gasAdjustment      float64
+	gasLimits          map[string]uint64
+	// transaction fee and its granter
+	feeAmount  sdk.Coins
+	feeGranter sdk.AccAddress
+	// granter of the authz grant order messages execute under
+	authzGranter sdk.AccAddress
+	// recently submitted client order IDs
+	dedupe *dedupeCache
+	// cosmos-sdk REST API of a node
+	node *query.Client
+	// cached market metadata
+	metaMu        sync.RWMutex
+	exchangeCache map[string]types.Exchange
+	coinCache     map[string]types.Coin
+	subaccounts   []types.Subaccount
+
+	// reduce-only pre-validation
+	reduceOnlyPolicy ReduceOnlyPolicy
+	positionSource   PositionSource
+
+	// pre-trade risk limits
+	riskMu     sync.Mutex
+	riskLimits *RiskLimits
+	riskStates map[string]*riskState // Account state by subaccount ID read by the risk checks
+	riskMarks  map[string]riskMark   // Mark price by exchange ID read by the risk checks
+
+	// live order trackers by subaccount
+	trackerMu     sync.Mutex
+	orderTrackers map[uint64]*OrderTracker
+	store         store.Store
+
+	// inventory trackers fed by TrackInventory and snapshots waiting for them
+	inventoryTrackers  map[string]*InventoryTracker
+	pendingInventories map[string]InventorySnapshot
+
+	// private stream connection shared by WaitForOrderStatus and its watchers
+	orderWatchMu  sync.Mutex
+	orderWatchWS  *query.WebSocketClient
+	orderWatchers map[chan types.Order]struct{}
+
+	// optional metrics collector
+	metrics MetricsCollector
+
+	// event bus created by Events
+	eventsMu sync.Mutex
+	events   *EventBus
+
+	// gateway clock offset estimated from transaction responses and SyncServerTime
+	serverClock ServerClock
+
+	// phase timing of the last transaction
+	latencyMu   sync.Mutex
+	lastLatency LatencyBreakdown
+
+	// chat notifier tokens
+	chat chatTokens
+}
+
+// NewAntxClient creates a new Antx client with the settings of opts, e.g.
+//
+//	client, err := sdk.NewAntxClient(
+//		sdk.WithGateway("http://127.0.0.1:8080"),
+//		sdk.WithChainID("antx-devnet"),
+//		sdk.WithEthPrivateKey(ethKey),
+//		sdk.WithAgentPrivateKey(agentKey),
+//		sdk.WithTimeout(5*time.Second),
+//	)
+//
+// Settings without an option are set with WithConfig.
+func NewAntxClient(opts ...Option) (*AntxClient, error) {
+	var config Config
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return NewAntxClientWithConfig(config)
+}
+
+// NewAntxClientWithConfig creates a new Antx client with the settings of config
+func NewAntxClientWithConfig(config Config) (*AntxClient, error) {
+	// Validate configuration parameters
+	if config.ChainID == "" {
+		return nil, fmt.Errorf("chain ID cannot be empty")
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+	var err error
+	if config.EthPrivateKey, err = resolveKey(config.EthPrivateKey, config.EthKeyProvider, "eth"); err != nil {
+		return nil, err
 	}
-
-	if err := json.Unmarshal(body, result); err != nil {
-		return fmt.Errorf("failed to unmarshal response: %w, body: %s", err, string(body))
+	if config.AgentPrivateKey, err = resolveKey(config.AgentPrivateKey, config.AgentKeyProvider, "agent"); err != nil {
+		return nil, err
 	}
-	return nil
-}
-
-func (c *AntxClient) httpPost(path string, data interface{}, result interface{}) error {
-	if c.baseURL == "" {
-		return fmt.Errorf("gateway baseURL is not set")
+	if config.GatewayHost == "" && config.MockTransport == nil {
+		return nil, fmt.Errorf("gateway host cannot be empty, set MockTransport to run without a gateway")
 	}
-	b, err := json.Marshal(data)
-	if err != nil {
-		return fmt.Errorf("failed to marshal request data: %w", err)
+	if config.EthPrivateKey == "" {
+		return nil, fmt.Errorf("eth private key cannot be empty")
 	}
-	u, err := url.Parse(c.baseURL + path)
-	if err != nil {
-		return fmt.Errorf("failed to parse URL: %w", err)
+	if config.AgentPrivateKey == "" && config.Signer == nil {
+		return nil, fmt.Errorf("agent private key cannot be empty")
 	}
-	if c.httpClient == nil {
-		c.httpClient = &http.Client{Timeout: 30 * time.Second}
+	if config.SimulateBeforeSend && config.Simulator == nil {
+		return nil, fmt.Errorf("simulate before send requires a simulator")
 	}
-	req, err := http.NewRequest("POST", u.String(), bytes.NewBuffer(b))
-	if err != nil {
-		return fmt.Errorf("failed to create POST request: %w", err)
+	if config.GasAdjustment <= 0 {
+		config.GasAdjustment = DefaultGasAdjustment
 	}
-	// Set request headers to avoid WAF blocking
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-App-Token", "ANTECH-APP-SECRET-KEY-001")
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Mobile; FlutterApp/1.0)")
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := c.httpClient.Do(req)
+	feeAmount, err := sdk.ParseCoinsNormalized(config.Fee)
 	if err != nil {
-		return fmt.Errorf("failed to send POST request: %w", err)
+		return nil, fmt.Errorf("invalid fee %q: %w", config.Fee, err)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	if err := json.Unmarshal(body, result); err != nil {
-		return fmt.Errorf("failed to unmarshal response: %w, body: %s", err, string(body))
+	var feeGranter sdk.AccAddress
+	if config.FeeGranter != "" {
+		if feeGranter, err = parseAddress(config.FeeGranter); err != nil {
+			return nil, fmt.Errorf("invalid fee granter: %w", err)
+		}
 	}
-	return nil
-}
-
-// GetAccountNumberAndSequence gets the account number and sequence
-func (c *AntxClient) GetAccountNumberAndSequence(address string) (string, string, error) {
-	if c.baseURL == "" {
-		return "0", "0", nil
+	var authzGranter sdk.AccAddress
+	if config.AuthzGranter != "" {
+		if authzGranter, err = parseAddress(config.AuthzGranter); err != nil {
+			return nil, fmt.Errorf("invalid authz granter: %w", err)
+		}
 	}
 
-	var result types.GetAccountNumberAndSequenceResponse
-	params := map[string]string{
-		"address": address,
-	}
-	if err := c.httpGet(constants.GetAddressInfoPath, params, &result); err != nil {
-		return "", "", err
+	// Parse private keys
+	ethPrivateKeyHex := strings.TrimPrefix(config.EthPrivateKey, "0x")
+	if len(ethPrivateKeyHex) != 64 {
+		return nil, fmt.Errorf("invalid eth private key length: expected 64 characters, got %d", len(ethPrivateKeyHex))
 	}
-
-	if result.BaseResp.Code != "0" {
-		return "", "", fmt.Errorf("get account info failed: %s", result.BaseResp.Msg)
+	ethPrivateKey, err := ethCrypto.HexToECDSA(ethPrivateKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode eth private key: %w", err)
 	}
-
-	return result.Data.AccountNumber, result.Data.Sequence, nil
-}
-
-// SendRawTx sends a raw transaction
-func (c *AntxClient) SendRawTx(req types.SendRawTxRequest) (*types.SendRawTxResponse, error) {
-	if c.baseURL == "" {
-		return &types.SendRawTxResponse{
-			BaseResp: types.BaseResp{Code: "0", Msg: "success"},
-			Data: types.SendRawTxResponseData{
-				TxHash:     "mock_tx_hash",
-				RawTx:      req.RawTx,
-				ResultData: "mock_result",
-			},
-		}, nil
-	}
-
-	var result types.SendRawTxResponse
-	if err := c.httpPost(constants.SendTransactionPath, req, &result); err != nil {
+	signer, err := agentSigner(config)
+	if err != nil {
 		return nil, err
 	}
 
-	// Add debug information
-	if result.Data.TxHash != "" {
-		logx.Infof("SendRawTx response: txHash=%s", result.Data.TxHash)
+	client := &AntxClient{
+		signer:             signer,
+		ethPrivateKey:      ethPrivateKey,
+		ethAddress:         ethCrypto.PubkeyToAddress(ethPrivateKey.PublicKey),
+		agentAddress:       signer.Address(),
+		chainID:            config.ChainID,
+		gatewayHost:        config.GatewayHost,
+		readOnly:           config.ReadOnly,
+		Client:             query.NewClient(config.GatewayHost, config.WebSocketURL),
+		simulator:          config.Simulator,
+		simulateBeforeSend: config.SimulateBeforeSend,
+		gasAdjustment:      config.GasAdjustment,
+		gasLimits:          config.GasLimits,
+		feeAmount:          feeAmount,
+		feeGranter:         feeGranter,
+		authzGranter:       authzGranter,
+		dedupe:             newDedupeCache(config.DedupeWindow, config.DedupeCapacity),
+		riskLimits:         config.RiskLimits,
+		chat: chatTokens{
+			TelegramBotToken:  config.TelegramBotToken,
+			TelegramChatId:    config.TelegramChatId,
+			SlackWebhookURL:   config.SlackWebhookURL,
+			DiscordWebhookURL: config.DiscordWebhookURL,
+		},
+	}
+	client.initSubClients()
+
+	client.SetAPIPrefix(config.APIPrefix)
+	client.SetAPICredentials(query.APICredentials{Key: config.APIKey, Secret: config.APISecret})
+	client.SetUserAgentTag(config.UserAgentTag)
+	client.SetHeaders(config.Headers)
+	client.SetRequestCompression(config.CompressRequestsAbove)
+	client.SetResponseCompression(!config.DisableResponseCompression)
+	client.SetLogger(config.Logger)
+	client.Use(client.httpMetrics)
+	client.SetWebSocketDropHandler(client.countDroppedMessage)
+	if config.HTTPClient != nil {
+		client.SetHTTPClient(config.HTTPClient)
+	}
+	if config.HTTPTimeout > 0 {
+		client.SetHTTPTimeout(config.HTTPTimeout)
+	}
+	if config.Transport != nil {
+		if err := client.SetTransport(*config.Transport); err != nil {
+			return nil, err
+		}
 	}
-
-	return &result, nil
-}
-
-// GetEthAddress gets the Ethereum address
-func (c *AntxClient) GetEthAddress() string {
-	addr, _ := ConvertToEthAddr(c.ethAddress.String())
-	return addr
-}
-
-func (c *AntxClient) SignAndSendTx(typeURL string, msg sdk.Msg, unordered bool) (string, error) {
-	return c.signAndSendTx(typeURL, msg, unordered)
-}
-
-func (c *AntxClient) signAndSendTx(typeURL string, msg sdk.Msg, unordered bool) (string, error) {
-	// Create transaction builder
-	txBuilder := c.clientCtx.TxConfig.NewTxBuilder()
-	if err := txBuilder.SetMsgs(msg); err != nil {
-		logx.Errorf("failed to set messages: %w", err)
-		return "", fmt.Errorf("failed to set messages: %w", err)
+	if config.RetryPolicy != nil {
+		client.SetRetry(*config.RetryPolicy)
 	}
-	timeoutInt := time.Now().Add(10 * time.Second).UnixNano()
-	timeout := time.Unix(timeoutInt/1e9, timeoutInt%1e9)
-	if unordered {
-		txBuilder.SetUnordered(unordered)
-		txBuilder.SetTimeoutTimestamp(timeout)
+	client.SetRateLimiter(config.RateLimiter)
+	for path, override := range config.PathOverrides {
+		client.SetPathOverride(path, override)
 	}
-
-	// Set gas and fee
-	txBuilder.SetGasLimit(200000)
-	txBuilder.SetFeeAmount(sdk.NewCoins()) // No fee
-
-	// Create in-memory keyring for signing
-	kr := keyring.NewInMemory(c.clientCtx.Codec)
-
-	// Import private key directly to keyring
-	keyName := "temp-key"
-	privKeyHex := hex.EncodeToString(c.agentPrivateKey.Bytes())
-	err := kr.ImportPrivKeyHex(keyName, privKeyHex, "secp256k1")
-	if err != nil {
-		logx.Errorf("failed to import private key to keyring: %w", err)
-		return "", fmt.Errorf("failed to import private key to keyring: %w", err)
+	if err := client.SetFailover(failoverConfig(config)); err != nil {
+		return nil, err
 	}
 
-	// Create transaction factory
-	txFactory := tx.Factory{}.
-		WithChainID(c.chainID).
-		WithTxConfig(c.clientCtx.TxConfig).
-		WithAccountNumber(c.accountNumber).
-		WithSignMode(authtx.DefaultSignModes[0]).
-		WithKeybase(kr)
-
-	if !unordered {
-		_, sequence, err := c.GetAccountNumberAndSequence(c.agentAddress.String())
-		if err != nil {
-			logx.Errorf("failed to get account number and sequence: %w", err)
-			return "", fmt.Errorf("failed to get account number and sequence: %w", err)
+	if config.NodeAPI != "" {
+		client.node = query.NewClient(config.NodeAPI, "")
+		if config.Transport != nil {
+			if err := client.node.SetTransport(*config.Transport); err != nil {
+				return nil, err
+			}
 		}
-		sequenceUint, err := strconv.ParseUint(sequence, 10, 64)
-		if err != nil {
-			logx.Errorf("failed to parse sequence: %w", err)
-			return "", fmt.Errorf("failed to parse sequence: %w", err)
-		}
-		txFactory = txFactory.WithSequence(sequenceUint)
 	}
 
-	// Sign transaction using tx.Sign
-	if err := tx.Sign(context.Background(), txFactory, keyName, txBuilder, true); err != nil {
-		logx.Errorf("failed to sign transaction: %w, ttl: %v", err, timeout.Format(time.RFC3339))
-		return "", fmt.Errorf("failed to sign transaction: %w", err)
+	if config.MockTransport != nil {
+		client.SetMockTransport(config.MockTransport)
 	}
 
-	txBytes, err := c.clientCtx.TxConfig.TxEncoder()(txBuilder.GetTx())
+	accountNumber, _, err := client.GetAccountNumberAndSequence(client.agentAddress.String())
 	if err != nil {
-		logx.Errorf("failed to encode transaction: %w, ttl: %v", err, timeout.Format(time.RFC3339))
-		return "", fmt.Errorf("failed to encode transaction: %w, ttl: %v", err, timeout.Format(time.RFC3339))
+		return nil, fmt.Errorf("failed to get account number and sequence: %w", err)
 	}
-	logx.Infof("rawTx: %s", base64.StdEncoding.EncodeToString(txBytes))
-
-	// Send transaction
-	req := types.SendRawTxRequest{
-		TypeURL:       typeURL,
-		RawTx:         base64.StdEncoding.EncodeToString(txBytes),
-		AccountNumber: c.accountNumber,
-	}
-	resp, err := c.SendRawTx(req)
+	client.accountNumber, err = strconv.ParseUint(accountNumber, 10, 64)
 	if err != nil {
-		logx.Errorf("failed to send transaction: %w, ttl: %v", err, timeout.Format(time.RFC3339))
-		return "", fmt.Errorf("failed to send transaction: %w, ttl: %v", err, timeout.Format(time.RFC3339))
-	}
-	// Try to get transaction hash, support multiple field names
-	txHash := resp.Data.TxHash
-	if txHash == "" {
-		txHash = resp.Data.Hash
-	}
-	if txHash == "" {
-		txHash = resp.Data.TxID
+		return nil, fmt.Errorf("failed to parse account number: %w", err)
 	}
-
-	return txHash, nil
+	return client, nil
 }
 
-// =============================== Market Data and Trading Queries (merged from SDKClient) ===============================
-
-// GetCoinList gets the coin list
-func (c *AntxClient) GetCoinList() ([]types.Coin, error) {
-	var result types.GetCoinListResponse
-	if err := c.httpGet(constants.GetCoinListPath, map[string]string{}, &result); err != nil {
-		return nil, err
-	}
-	if result.BaseResp.Code != "0" {
-		return nil, fmt.Errorf("get coin list failed: %s", result.BaseResp.Msg)
+// failoverConfig returns the gateway failover of the configuration, without endpoints when it has no fallback gateway
+func failoverConfig(config Config) query.FailoverConfig {
+	if len(config.FallbackGateways) == 0 {
+		return query.FailoverConfig{}
 	}
-	return result.Data.CoinList, nil
+	endpoints := append([]query.GatewayEndpoint{{BaseURL: config.GatewayHost, WsURL: config.WebSocketURL}}, config.FallbackGateways...)
+	return query.FailoverConfig{Endpoints: endpoints}
 }
 
-// GetSubaccountList gets the subaccount list
-func (c *AntxClient) GetSubaccountList(chainType int32, chainAddress, agentAddress string) ([]types.Subaccount, error) {
-	var result types.GetSubaccountListResponse
-	params := map[string]string{
-		"chainType":    strconv.FormatInt(int64(chainType), 10),
-		"chainAddress": chainAddress,
-		"agentAddress": agentAddress,
+// agentSigner returns the signer of the configuration, or a signer of its agent private key
+func agentSigner(config Config) (sign.TxSigner, error) {
+	if config.Signer != nil {
+		return config.Signer, nil
 	}
-	if err := c.httpGet(constants.GetSubaccountPath, params, &result); err != nil {
-		return nil, err
+	agentPrivateKeyHex := strings.TrimPrefix(config.AgentPrivateKey, "0x")
+	if len(agentPrivateKeyHex) != 64 {
+		return nil, fmt.Errorf("invalid agent private key length: expected 64 characters, got %d", len(agentPrivateKeyHex))
 	}
-	if result.BaseResp.Code != "0" {
-		return nil, fmt.Errorf("get subaccount list failed: %s", result.BaseResp.Msg)
+	agentPrivateKeyBytes, err := hex.DecodeString(agentPrivateKeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode agent private key: %w", err)
 	}
-	return result.Data.SubaccountList, nil
+	// Create secp256k1 private key
+	return sign.NewKeyringSigner(&secp256k1.PrivKey{Key: agentPrivateKeyBytes})
 }
 
-// GetExchangeList gets the exchange list
-func (c *AntxClient) GetExchangeList() ([]types.Exchange, error) {
-	var result types.GetExchangeListResponse
-	if err := c.httpGet(constants.GetExchangeListPath, map[string]string{}, &result); err != nil {
-		return nil, err
-	}
-	if result.BaseResp.Code != "0" {
-		return nil, fmt.Errorf("get exchange list failed: %s", result.BaseResp.Msg)
-	}
-	return result.Data.ExchangeList, nil
+// NewAntxQueryClient creates a lightweight read-only client for HTTP queries and WebSocket only (no on-chain signing
+// configuration required), trading methods return ErrReadOnly. It logs to go-zero logx like the trading client.
+func NewAntxQueryClient(baseURL, wsURL string) *AntxClient {
+	client := &AntxClient{Client: query.NewClient(baseURL, wsURL), readOnly: true}
+	client.SetLogger(nil)
+	client.initSubClients()
+	return client
 }
 
-// GetKline gets K-line data
-func (c *AntxClient) GetKline(req types.GetKLineReq) (*types.GetKLineResp, error) {
-	var result types.GetKLineResp
-	params := map[string]string{
-		"exchangeId": req.ExchangeId,
-		"klineType":  req.KlineType,
-		"priceType":  req.PriceType,
-	}
-	if req.Size > 0 {
-		params["size"] = strconv.FormatUint(uint64(req.Size), 10)
-	}
-	if req.OffsetData != "" {
-		params["offsetData"] = req.OffsetData
-	}
-	if req.FilterBeginKlineTimeInclusive > 0 {
-		params["filterBeginKlineTimeInclusive"] = strconv.FormatInt(req.FilterBeginKlineTimeInclusive, 10)
-	}
-	if req.FilterEndKlineTimeExclusive > 0 {
-		params["filterEndKlineTimeExclusive"] = strconv.FormatInt(req.FilterEndKlineTimeExclusive, 10)
-	}
-	if err := c.httpGet(constants.GetKlinePath, params, &result); err != nil {
-		return nil, err
-	}
-	if result.BaseResp.Code != "0" {
-		return nil, fmt.Errorf("get kline failed: %s", result.BaseResp.Msg)
-	}
-	return &result, nil
+// ReadOnly reports whether the client refuses to sign and broadcast transactions
+func (c *AntxClient) ReadOnly() bool {
+	c.txMu.RLock()
+	defer c.txMu.RUnlock()
+	return c.isReadOnly()
 }
 
-// GetFundingHistory gets funding rate history
-func (c *AntxClient) GetFundingHistory(req types.GetFundingHistoryReq) (*types.GetFundingHistoryResp, error) {
-	var result types.GetFundingHistoryResp
-	params := map[string]string{
-		"exchangeId": req.ExchangeId,
-		"size":       strconv.FormatUint(uint64(req.Size), 10),
-	}
-	if req.OffsetData != "" {
-		params["offsetData"] = req.OffsetData
-	}
-	if req.FilterSettlementFundingRate {
-		params["filterSettlementFundingRate"] = "true"
-	}
-	if req.FilterBeginTimeInclusive > 0 {
-		params["filterBeginTimeInclusive"] = strconv.FormatUint(req.FilterBeginTimeInclusive, 10)
-	}
-	if req.FilterEndTimeExclusive > 0 {
-		params["filterEndTimeExclusive"] = strconv.FormatUint(req.FilterEndTimeExclusive, 10)
-	}
-	if err := c.httpGet(constants.GetFundingHistoryPath, params, &result); err != nil {
-		return nil, err
-	}
-	if result.BaseResp.Code != "0" {
-		return nil, fmt.Errorf("get funding history failed: %s", result.BaseResp.Msg)
-	}
-	return &result, nil
+// SendRawTx sends a raw transaction, ErrReadOnly on a read-only client
+func (c *AntxClient) SendRawTx(req types.SendRawTxRequest) (*types.SendRawTxResponse, error) {
+	return c.SendRawTxContext(context.Background(), req)
 }
 
-// GetActiveOrder gets active orders
-func (c *AntxClient) GetActiveOrder(req types.GetActiveOrderReq) (*types.GetActiveOrderResp, error) {
-	var result types.GetActiveOrderResp
-	params := map[string]string{
-		"subaccountId": req.SubaccountId,
-		"size":         strconv.FormatUint(uint64(req.Size), 10),
-	}
-	if req.OffsetData != "" {
-		params["offsetData"] = req.OffsetData
-	}
-	if req.PageOffsetDataCreatedTime != "" {
-		params["pageOffsetDataCreatedTime"] = req.PageOffsetDataCreatedTime
+// SendRawTxContext is SendRawTx with a context canceling the request. It shadows query.Client.SendRawTxContext so a
+// read-only client cannot broadcast a transaction signed elsewhere.
+func (c *AntxClient) SendRawTxContext(ctx context.Context, req types.SendRawTxRequest) (*types.SendRawTxResponse, error) {
+	if c.ReadOnly() {
+		return nil, ErrReadOnly
 	}
-	if req.PageOffsetDataItemId != "" {
-		params["pageOffsetDataItemId"] = req.PageOffsetDataItemId
-	}
-	if req.FilterExchangeIdList != "" {
-		params["filterExchangeIdList"] = req.FilterExchangeIdList
-	}
-	if req.FilterOrderStatusList != "" {
-		params["filterOrderStatusList"] = req.FilterOrderStatusList
-	}
-	if req.FilterIsLiquidateList != "" {
-		params["filterIsLiquidateList"] = req.FilterIsLiquidateList
-	}
-	if req.FilterIsDeleverageList != "" {
-		params["filterIsDeleverageList"] = req.FilterIsDeleverageList
-	}
-	if req.FilterIsPositionTpslList != "" {
-		params["filterIsPositionTpslList"] = req.FilterIsPositionTpslList
-	}
-	if req.FilterOrderIdList != "" {
-		params["filterOrderIdList"] = req.FilterOrderIdList
-	}
-	if req.FilterStartCreatedTimeInclusive > 0 {
-		params["filterStartCreatedTimeInclusive"] = strconv.FormatUint(req.FilterStartCreatedTimeInclusive, 10)
-	}
-	if req.FilterEndCreatedTimeExclusive > 0 {
-		params["filterEndCreatedTimeExclusive"] = strconv.FormatUint(req.FilterEndCreatedTimeExclusive, 10)
-	}
-	// Add debug information
-	logx.Infof("GetActiveOrder request params: %+v", params)
-
-	if err := c.httpGet(constants.GetActiveOrderPath, params, &result); err != nil {
-		return nil, err
-	}
-	if result.BaseResp.Code != "0" {
-		return nil, fmt.Errorf("get active order failed: %s", result.BaseResp.Msg)
-	}
-	return &result, nil
+	return c.Client.SendRawTxContext(ctx, req)
 }
 
-// GetHistoryOrder gets history orders
-func (c *AntxClient) GetHistoryOrder(req types.GetHistoryOrderReq) (*types.GetHistoryOrderResp, error) {
-	var result types.GetHistoryOrderResp
-	params := map[string]string{
-		"subaccountId": req.SubaccountId,
-		"size":         strconv.FormatUint(uint64(req.Size), 10),
-	}
-	if req.OffsetData != "" {
-		params["offsetData"] = req.OffsetData
-	}
-	if req.PageOffsetDataCreatedTime != "" {
-		params["pageOffsetDataCreatedTime"] = req.PageOffsetDataCreatedTime
-	}
-	if req.PageOffsetDataItemId != "" {
-		params["pageOffsetDataItemId"] = req.PageOffsetDataItemId
-	}
-	if req.FilterExchangeIdList != "" {
-		params["filterExchangeIdList"] = req.FilterExchangeIdList
-	}
-	if req.FilterOrderStatusList != "" {
-		params["filterOrderStatusList"] = req.FilterOrderStatusList
-	}
-	if req.FilterIsLiquidateList != "" {
-		params["filterIsLiquidateList"] = req.FilterIsLiquidateList
-	}
-	if req.FilterIsDeleverageList != "" {
-		params["filterIsDeleverageList"] = req.FilterIsDeleverageList
-	}
-	if req.FilterIsPositionTpslList != "" {
-		params["filterIsPositionTpslList"] = req.FilterIsPositionTpslList
-	}
-	if req.FilterOrderIdList != "" {
-		params["filterOrderIdList"] = req.FilterOrderIdList
-	}
-	if req.FilterStartCreatedTimeInclusive > 0 {
-		params["filterStartCreatedTimeInclusive"] = strconv.FormatUint(req.FilterStartCreatedTimeInclusive, 10)
-	}
-	if req.FilterEndCreatedTimeExclusive > 0 {
-		params["filterEndCreatedTimeExclusive"] = strconv.FormatUint(req.FilterEndCreatedTimeExclusive, 10)
-	}
-	if err := c.httpGet(constants.GetHistoryOrderPath, params, &result); err != nil {
-		return nil, err
-	}
-	if result.BaseResp.Code != "0" {
-		return nil, fmt.Errorf("get history order failed: %s", result.BaseResp.Msg)
-	}
-	return &result, nil
+// isReadOnly is ReadOnly, must be called with txMu held
+func (c *AntxClient) isReadOnly() bool {
+	return c.readOnly || c.signer == nil
 }
 
-// GetPerpetualAccountAsset gets perpetual contract account assets
-func (c *AntxClient) GetPerpetualAccountAsset(req types.GetPerpetualAccountAssetReq) (*types.GetPerpetualAccountAssetResp, error) {
-	var result types.GetPerpetualAccountAssetResp
-	params := map[string]string{"subaccountId": req.SubaccountId}
-	if err := c.httpGet(constants.GetPerpetualAccountAssetPath, params, &result); err != nil {
-		return nil, err
-	}
-	if result.BaseResp.Code != "0" {
-		return nil, fmt.Errorf("get perpetual account asset failed: %s", result.BaseResp.Msg)
-	}
-	return &result, nil
+// GetAgentAddress gets the agent address
+func (c *AntxClient) GetAgentAddress() string {
+	c.txMu.RLock()
+	defer c.txMu.RUnlock()
+	return c.agentAddress.String()
 }
 
-// GetPositionTransaction gets position transactions
-func (c *AntxClient) GetPositionTransaction(req types.GetPositionTransactionReq) (*types.GetPositionTransactionResp, error) {
-	var result types.GetPositionTransactionResp
-	params := map[string]string{
-		"subaccountId": req.SubaccountId,
-		"size":         strconv.FormatUint(uint64(req.Size), 10),
-	}
-	if req.PageOffsetDataCreatedTime != "" {
-		params["pageOffsetDataCreatedTime"] = req.PageOffsetDataCreatedTime
-	}
-	if req.PageOffsetDataItemId != "" {
-		params["pageOffsetDataItemId"] = req.PageOffsetDataItemId
-	}
-	if req.FilterExchangeIdList != "" {
-		params["filterExchangeIdList"] = req.FilterExchangeIdList
-	}
-	if req.FilterTypeList != "" {
-		params["filterTypeList"] = req.FilterTypeList
-	}
-	if req.FilterMarginModeList != "" {
-		params["filterMarginModeList"] = req.FilterMarginModeList
-	}
-	if req.FilterStartCreatedTimeInclusive > 0 {
-		params["filterStartCreatedTimeInclusive"] = strconv.FormatUint(req.FilterStartCreatedTimeInclusive, 10)
-	}
-	if req.FilterEndCreatedTimeExclusive > 0 {
-		params["filterEndCreatedTimeExclusive"] = strconv.FormatUint(req.FilterEndCreatedTimeExclusive, 10)
-	}
-	if err := c.httpGet(constants.GetPositionTransactionPath, params, &result); err != nil {
+// GetAddressInfo gets the chain account and trading subaccounts of an ETH or antx address
+func (c *AntxClient) GetAddressInfo(address string) (*types.AddressInfo, error) {
+	antxAddress, err := ConvertToAntxAddr(address)
+	if err != nil {
 		return nil, err
 	}
-	if result.BaseResp.Code != "0" {
-		return nil, fmt.Errorf("get position transaction failed: %s", result.BaseResp.Msg)
-	}
-	return &result, nil
-}
-
-// GetCollateralTransaction gets collateral transactions
-func (c *AntxClient) GetCollateralTransaction(req types.GetCollateralTransactionReq) (*types.GetCollateralTransactionResp, error) {
-	var result types.GetCollateralTransactionResp
-	params := map[string]string{
-		"subaccountId": req.SubaccountId,
-		"size":         strconv.FormatUint(uint64(req.Size), 10),
-	}
-	if req.PageOffsetDataCreatedTime != "" {
-		params["pageOffsetDataCreatedTime"] = req.PageOffsetDataCreatedTime
-	}
-	if req.PageOffsetDataItemId != "" {
-		params["pageOffsetDataItemId"] = req.PageOffsetDataItemId
-	}
-	if req.FilterCoinId != "" {
-		params["filterCoinId"] = req.FilterCoinId
-	}
-	if req.FilterTypeList != "" {
-		params["filterTypeList"] = req.FilterTypeList
-	}
-	if req.FilterStartCreatedTimeInclusive > 0 {
-		params["filterStartCreatedTimeInclusive"] = strconv.FormatUint(req.FilterStartCreatedTimeInclusive, 10)
-	}
-	if req.FilterEndCreatedTimeExclusive > 0 {
-		params["filterEndCreatedTimeExclusive"] = strconv.FormatUint(req.FilterEndCreatedTimeExclusive, 10)
-	}
-	if err := c.httpGet(constants.GetCollateralTransactionPath, params, &result); err != nil {
+	ethAddress, err := ConvertToEthAddr(address)
+	if err != nil {
 		return nil, err
 	}
-	if result.BaseResp.Code != "0" {
-		return nil, fmt.Errorf("get collateral transaction failed: %s", result.BaseResp.Msg)
-	}
-	return &result, nil
-}
 
-// GetAssetSnapshot gets asset snapshots
-func (c *AntxClient) GetAssetSnapshot(req types.GetAssetSnapshotReq) (*types.GetAssetSnapshotResp, error) {
-	var result types.GetAssetSnapshotResp
-	params := map[string]string{
-		"subaccountId": req.SubaccountId,
-		"size":         strconv.FormatUint(uint64(req.Size), 10),
-	}
-	if req.PageOffsetDataCreatedTime != "" {
-		params["pageOffsetDataCreatedTime"] = req.PageOffsetDataCreatedTime
-	}
-	if req.PageOffsetDataItemId != "" {
-		params["pageOffsetDataItemId"] = req.PageOffsetDataItemId
-	}
-	if req.FilterCoinId != "" {
-		params["filterCoinId"] = req.FilterCoinId
-	}
-	if req.FilterTimeTag != "" {
-		params["filterTimeTag"] = req.FilterTimeTag
-	}
-	if req.FilterStartCreatedTimeInclusive > 0 {
-		params["filterStartCreatedTimeInclusive"] = strconv.FormatUint(req.FilterStartCreatedTimeInclusive, 10)
-	}
-	if req.FilterEndCreatedTimeExclusive > 0 {
-		params["filterEndCreatedTimeExclusive"] = strconv.FormatUint(req.FilterEndCreatedTimeExclusive, 10)
-	}
-	if err := c.httpGet(constants.GetAssetSnapshotPath, params, &result); err != nil {
+	var result types.GetAccountNumberAndSequenceResponse
+	if err := c.Do(context.Background(), http.MethodGet, constants.GetAddressInfoPath, map[string]string{"address": antxAddress}, nil, &result); err != nil {
 		return nil, err
 	}
-	if result.BaseResp.Code != "0" {
-		return nil, fmt.Errorf("get asset snapshot failed: %s", result.BaseResp.Msg)
-	}
-	return &result, nil
-}
 
-// GetHistoryOrderFillTransaction gets history order fill transactions
-func (c *AntxClient) GetHistoryOrderFillTransaction(req types.GetHistoryOrderFillTransactionReq) (*types.GetHistoryOrderFillTransactionResp, error) {
-	var result types.GetHistoryOrderFillTransactionResp
-	params := map[string]string{
-		"subaccountId": req.SubaccountId,
-		"size":         strconv.FormatUint(uint64(req.Size), 10),
-	}
-	if req.PageOffsetDataCreatedTime != "" {
-		params["pageOffsetDataCreatedTime"] = req.PageOffsetDataCreatedTime
-	}
-	if req.PageOffsetDataItemId != "" {
-		params["pageOffsetDataItemId"] = req.PageOffsetDataItemId
+	info := &types.AddressInfo{
+		Address:    antxAddress,
+		EthAddress: ethAddress,
+		Exist:      result.Data.Exist,
 	}
-	if req.FilterExchangeIdList != "" {
-		params["filterExchangeIdList"] = req.FilterExchangeIdList
-	}
-	if req.FilterCoinIdList != "" {
-		params["filterCoinIdList"] = req.FilterCoinIdList
-	}
-	if req.FilterOrderIdList != "" {
-		params["filterOrderIdList"] = req.FilterOrderIdList
-	}
-	if req.FilterStartCreatedTimeInclusive > 0 {
-		params["filterStartCreatedTimeInclusive"] = strconv.FormatUint(req.FilterStartCreatedTimeInclusive, 10)
+	if result.Data.AccountNumber != "" {
+		if info.AccountNumber, err = strconv.ParseUint(result.Data.AccountNumber, 10, 64); err != nil {
+			return nil, fmt.Errorf("failed to parse account number: %w", err)
+		}
 	}
-	if req.FilterEndCreatedTimeExclusive > 0 {
-		params["filterEndCreatedTimeExclusive"] = strconv.FormatUint(req.FilterEndCreatedTimeExclusive, 10)
+	if result.Data.Sequence != "" {
+		if info.Sequence, err = strconv.ParseUint(result.Data.Sequence, 10, 64); err != nil {
+			return nil, fmt.Errorf("failed to parse sequence: %w", err)
+		}
 	}
-	if err := c.httpGet(constants.GetHistoryOrderFillTransactionPath, params, &result); err != nil {
+
+	info.SubaccountList, err = c.GetSubaccountList(1, ethAddress, "") // chain type 1: EVM
+	if err != nil {
 		return nil, err
 	}
-	if result.BaseResp.Code != "0" {
-		return nil, fmt.Errorf("get history order fill transaction failed: %s", result.BaseResp.Msg)
-	}
-	return &result, nil
+	return info, nil
 }
 
-// GetHistoryPositionTerm gets history position terms
-func (c *AntxClient) GetHistoryPositionTerm(req types.GetHistoryPositionTermReq) (*types.GetHistoryPositionTermResp, error) {
-	var result types.GetHistoryPositionTermResp
-	params := map[string]string{
-		"subaccountId": req.SubaccountId,
-		"size":         strconv.FormatUint(uint64(req.Size), 10),
-	}
-	if req.PageOffsetDataCreatedTime != "" {
-		params["pageOffsetDataCreatedTime"] = req.PageOffsetDataCreatedTime
-	}
-	if req.PageOffsetDataItemId != "" {
-		params["pageOffsetDataItemId"] = req.PageOffsetDataItemId
-	}
-	if req.FilterExchangeIdList != "" {
-		params["filterExchangeIdList"] = req.FilterExchangeIdList
-	}
-	if req.FilterStartCreatedTimeInclusive > 0 {
-		params["filterStartCreatedTimeInclusive"] = strconv.FormatUint(req.FilterStartCreatedTimeInclusive, 10)
-	}
-	if req.FilterEndCreatedTimeExclusive > 0 {
-		params["filterEndCreatedTimeExclusive"] = strconv.FormatUint(req.FilterEndCreatedTimeExclusive, 10)
-	}
-	if err := c.httpGet(constants.GetHistoryPositionTermPath, params, &result); err != nil {
-		return nil, err
-	}
-	if result.BaseResp.Code != "0" {
-		return nil, fmt.Errorf("get history position term failed: %s", result.BaseResp.Msg)
-	}
-	return &result, nil
+// GetEthAddress gets the Ethereum address
+func (c *AntxClient) GetEthAddress() string {
+	addr, _ := ConvertToEthAddr(c.ethAddress.String())
+	return addr
 }
 
-// =============================== WebSocket Integration and Parsing ===============================
+// SubscribeToTradeData subscribes to private account events of the client address
+func (c *AntxClient) SubscribeToTradeData() (<-chan []byte, error) {
+	return c.Client.SubscribeToTradeData(c.GetEthAddress())
+}
 
-// ConnectWebSocket establishes connection
-func (c *AntxClient) ConnectWebSocket(messageHandler func([]byte), errorHandler func(error)) error {
-	if c.wsClient != nil {
-		_ = c.wsClient.Disconnect()
-	}
-	if c.wsURL == "" {
-		return fmt.Errorf("wsURL is not set")
-	}
-	c.wsClient = NewWebSocketClient(c.wsURL, messageHandler, errorHandler)
-	return c.wsClient.Connect()
+func (c *AntxClient) SignAndSendTx(typeURL string, msg sdk.Msg, unordered bool) (string, error) {
+	return c.signAndSendTx(context.Background(), typeURL, msg, unordered, 0)
 }
 
-// SubscribeToTicker subscribes to Ticker
-func (c *AntxClient) SubscribeToTicker(exchangeId string) (<-chan []byte, error) {
-	if c.wsClient == nil {
-		return nil, fmt.Errorf("websocket not connected")
-	}
-	return c.wsClient.SubscribeToTicker(exchangeId)
+// SignAndSendTxContext is SignAndSendTx with a context canceling the account, simulation and broadcast requests
+func (c *AntxClient) SignAndSendTxContext(ctx context.Context, typeURL string, msg sdk.Msg, unordered bool) (string, error) {
+	return c.signAndSendTx(ctx, typeURL, msg, unordered, 0)
 }
 
-// SubscribeToKline subscribes to K-line
-func (c *AntxClient) SubscribeToKline(priceType, exchangeId, klineType string) (<-chan []byte, error) {
-	if c.wsClient == nil {
-		return nil, fmt.Errorf("websocket not connected")
-	}
-	return c.wsClient.SubscribeToKline(priceType, exchangeId, klineType)
+// SignAndSendTxWithGasLimit signs and sends a transaction with a gas limit overriding the gas table and the simulation estimate
+func (c *AntxClient) SignAndSendTxWithGasLimit(typeURL string, msg sdk.Msg, unordered bool, gasLimit uint64) (string, error) {
+	return c.signAndSendTx(context.Background(), typeURL, msg, unordered, gasLimit)
 }
 
-// DisconnectWebSocket disconnects
-func (c *AntxClient) DisconnectWebSocket() error {
-	if c.wsClient != nil {
-		return c.wsClient.Disconnect()
+// signAndSendTx signs and sends a transaction, a zero gas limit uses the simulation estimate or the gas table
+func (c *AntxClient) signAndSendTx(ctx context.Context, typeURL string, msg sdk.Msg, unordered bool, gasLimit uint64) (string, error) {
+	c.txMu.RLock()
+	defer c.txMu.RUnlock()
+	if c.isReadOnly() {
+		return "", ErrReadOnly
 	}
-	return nil
-}
+	latency := LatencyBreakdown{TypeURL: typeURL, SentAt: time.Now()}
+	phaseStart := latency.SentAt
 
-// ParseTickerData parses Ticker
-func (c *AntxClient) ParseTickerData(data []byte) (*types.TickerData, error) {
-	// WebSocket push format: {"channel":"...","event":"payload","data":[{...}]}
-	var wsResp struct {
-		Channel string             `json:"channel"`
-		Event   string             `json:"event"`
-		Data    []types.TickerData `json:"data"`
+	opts, err := c.txOptions(ctx, unordered)
+	if err != nil {
+		return "", err
 	}
-	if err := json.Unmarshal(data, &wsResp); err != nil {
-		return nil, fmt.Errorf("failed to parse websocket response: %w", err)
+	timeout := opts.Timeout
+	opts.GasLimit = c.GasLimit(typeURL)
+	if !unordered {
+		latency.Sequence, phaseStart = time.Since(phaseStart), time.Now()
 	}
-	if len(wsResp.Data) == 0 {
-		return nil, fmt.Errorf("no ticker data in response")
+	if exec, ok := c.authzExec(msg); ok {
+		typeURL, msg = constants.MsgExecTypeURL, exec
 	}
-	return &wsResp.Data[0], nil
-}
 
-// ParseKlineData parses K-line
-func (c *AntxClient) ParseKlineData(data []byte) (*types.KLine, error) {
-	// WebSocket push format: {"channel":"...","event":"payload","data":[{...}]}
-	var wsResp struct {
-		Channel string        `json:"channel"`
-		Event   string        `json:"event"`
-		Data    []types.KLine `json:"data"`
-	}
-	if err := json.Unmarshal(data, &wsResp); err != nil {
-		return nil, fmt.Errorf("failed to parse websocket response: %w", err)
+	// Simulate first so that failing transactions are rejected without consuming a sequence, and size the gas limit
+	if c.simulateBeforeSend {
+		simulation, err := c.simulate(ctx, msg, opts)
+		if err != nil {
+			return "", err
+		}
+		opts.GasLimit = simulation.GasLimit
+		latency.Simulate, phaseStart = time.Since(phaseStart), time.Now()
 	}
-	if len(wsResp.Data) == 0 {
-		return nil, fmt.Errorf("no kline data in response")
+	if gasLimit > 0 {
+		opts.GasLimit = gasLimit
 	}
-	return &wsResp.Data[0], nil
-}
 
-// ParseDepthData parses depth
-func (c *AntxClient) ParseDepthData(data []byte) (*types.DepthData, error) {
-	// WebSocket push format: {"channel":"...","event":"payload","data":[{...}]}
-	var wsResp struct {
-		Channel string            `json:"channel"`
-		Event   string            `json:"event"`
-		Data    []types.DepthData `json:"data"`
-	}
-	if err := json.Unmarshal(data, &wsResp); err != nil {
-		return nil, fmt.Errorf("failed to parse websocket response: %w", err)
+	// Build, sign and encode the transaction
+	var txBytes []byte
+	if phased, ok := c.signer.(sign.PhasedTxSigner); ok {
+		var phases sign.SignPhases
+		txBytes, phases, err = phased.SignTxPhases(ctx, msg, opts)
+		latency.Build, latency.Sign, latency.Encode = phases.Build, phases.Sign, phases.Encode
+	} else {
+		txBytes, err = c.signer.SignTx(ctx, msg, opts)
+		latency.Sign = time.Since(phaseStart)
 	}
-	if len(wsResp.Data) == 0 {
-		return nil, fmt.Errorf("no depth data in response")
+	if err != nil {
+		c.Logger().Errorf("%v, ttl: %v", err, timeout.Format(time.RFC3339))
+		return "", err
 	}
-	return &wsResp.Data[0], nil
-}
+	rawTx := base64.StdEncoding.EncodeToString(txBytes)
+	phaseStart = time.Now()
+	c.Logger().Debugf("rawTx: %s", rawTx)
 
-// ParseTradeData parses trade
-func (c *AntxClient) ParseTradeData(data []byte) (*types.Ticket, error) {
-	// WebSocket push format: {"channel":"...","event":"payload","data":[{...}]}
-	var wsResp struct {
-		Channel string         `json:"channel"`
-		Event   string         `json:"event"`
-		Data    []types.Ticket `json:"data"`
+	// Send transaction
+	req := types.SendRawTxRequest{
+		TypeURL:       typeURL,
+		RawTx:         rawTx,
+		AccountNumber: c.accountNumber,
 	}
-	if err := json.Unmarshal(data, &wsResp); err != nil {
-		return nil, fmt.Errorf("failed to parse websocket response: %w", err)
+	resp, err := c.broadcast(ctx, &BroadcastTx{Msg: msg, Options: opts, Request: req})
+	if err != nil {
+		c.Logger().Errorf("failed to send transaction: %v, ttl: %v", err, timeout.Format(time.RFC3339))
+		c.addCounter(MetricTxBroadcastFailures, 1, map[string]string{"type_url": typeURL})
+		return "", &broadcastError{fmt.Errorf("failed to send transaction: %w, ttl: %v", err, timeout.Format(time.RFC3339))}
 	}
-	if len(wsResp.Data) == 0 {
-		return nil, fmt.Errorf("no trade data in response")
+	latency.HTTP = time.Since(phaseStart)
+	if serverTime, ok := parseServerTime(resp.ResponseTime, nil); ok {
+		c.serverClock.Observe(phaseStart, phaseStart.Add(latency.HTTP), serverTime)
 	}
-	return &wsResp.Data[0], nil
-}
+	latency.Gateway = gatewayProcessingTime(resp.RequestTime, resp.ResponseTime)
+	latency.Total = time.Since(latency.SentAt)
+	c.recordLatency(latency)
 
-// ParseFundingRateData parses funding rate
-func (c *AntxClient) ParseFundingRateData(data []byte) (*types.FundingRate, error) {
-	// WebSocket push format: {"channel":"...","event":"payload","data":[{...}]}
-	var wsResp struct {
-		Channel string              `json:"channel"`
-		Event   string              `json:"event"`
-		Data    []types.FundingRate `json:"data"`
-	}
-	if err := json.Unmarshal(data, &wsResp); err != nil {
-		return nil, fmt.Errorf("failed to parse websocket response: %w", err)
+	// Try to get transaction hash, support multiple field names
+	txHash := resp.Data.TxHash
+	if txHash == "" {
+		txHash = resp.Data.Hash
 	}
-	if len(wsResp.Data) == 0 {
-		return nil, fmt.Errorf("no funding rate data in response")
+	if txHash == "" {
+		txHash = resp.Data.TxID
 	}
-	return &wsResp.Data[0], nil
+
+	return txHash, nil
 }
 
-// ParsePriceData parses price
-func (c *AntxClient) ParsePriceData(data []byte) (*types.Price, error) {
-	// WebSocket push format: {"channel":"...","event":"payload","data":[{...}]}
-	var wsResp struct {
-		Channel string        `json:"channel"`
-		Event   string        `json:"event"`
-		Data    []types.Price `json:"data"`
-	}
-	if err := json.Unmarshal(data, &wsResp); err != nil {
-		return nil, fmt.Errorf("failed to parse websocket response: %w", err)
+// broadcastError failure of the broadcast of a signed transaction, which may have reached the mempool
+type broadcastError struct {
+	err error
+}
+
+// Error returns the failure
+func (e *broadcastError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the failure
+func (e *broadcastError) Unwrap() error {
+	return e.err
+}
+
+// txOptions returns the transaction parameters of the agent, fetching the account sequence of ordered transactions
+func (c *AntxClient) txOptions(ctx context.Context, unordered bool) (sign.TxOptions, error) {
+	opts := sign.TxOptions{
+		ChainID:       c.chainID,
+		AccountNumber: c.accountNumber,
+		Unordered:     unordered,
+		Timeout:       c.ServerNow().Add(10 * time.Second),
+		FeeAmount:     c.feeAmount,
+		FeeGranter:    c.feeGranter,
 	}
-	if len(wsResp.Data) == 0 {
-		return nil, fmt.Errorf("no price data in response")
+	if !unordered {
+		_, sequence, err := c.GetAccountNumberAndSequenceContext(ctx, c.agentAddress.String())
+		if err != nil {
+			c.Logger().Errorf("failed to get account number and sequence: %v", err)
+			return opts, fmt.Errorf("failed to get account number and sequence: %w", err)
+		}
+		opts.Sequence, err = strconv.ParseUint(sequence, 10, 64)
+		if err != nil {
+			c.Logger().Errorf("failed to parse sequence: %v", err)
+			return opts, fmt.Errorf("failed to parse sequence: %w", err)
+		}
 	}
-	return &wsResp.Data[0], nil
+	return opts, nil
 }
@@ -0,0 +1,47 @@
+package sdk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// clientOrderIdHashLength number of hex characters of the hash in derived client order IDs
+const clientOrderIdHashLength = 40
+
+// DeriveClientOrderId returns a client order ID derived from a strategy decision: the same strategy, exchange, slot and
+// parameters always give the same ID, so re-running a decision, e.g. after a restart, is de-duplicated by the client
+// order ID idempotency of the chain. slot identifies the decision within the strategy, e.g. a time bucket or grid level,
+// and params are formatted with %v, so they must format deterministically. The ID is the strategy ID, reduced to
+// letters, digits, '-' and '_' and cut to fit, followed by a hash of all inputs, at most MaxClientOrderIdLength characters.
+func DeriveClientOrderId(strategyId string, exchangeId uint64, slot int64, params ...interface{}) string {
+	h := sha256.New()
+	writeClientOrderIdField(h, strategyId)
+	writeClientOrderIdField(h, fmt.Sprint(exchangeId))
+	writeClientOrderIdField(h, fmt.Sprint(slot))
+	for _, param := range params {
+		writeClientOrderIdField(h, fmt.Sprintf("%T:%v", param, param))
+	}
+	sum := hex.EncodeToString(h.Sum(nil))[:clientOrderIdHashLength]
+
+	prefix := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' {
+			return r
+		}
+		return -1
+	}, strategyId)
+	if maxPrefix := MaxClientOrderIdLength - clientOrderIdHashLength - 1; len(prefix) > maxPrefix {
+		prefix = prefix[:maxPrefix]
+	}
+	if prefix == "" {
+		return sum
+	}
+	return prefix + "-" + sum
+}
+
+// writeClientOrderIdField writes a length-prefixed field, so that fields cannot run into each other
+func writeClientOrderIdField(h hash.Hash, field string) {
+	fmt.Fprintf(h, "%d:%s;", len(field), field)
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// newFlagSet creates the flag set of a command
+func newFlagSet(name string) *flag.FlagSet {
+	return flag.NewFlagSet("antx "+name, flag.ExitOnError)
+}
+
+// subaccountFlag registers -subaccount, defaulting to the profile subaccount
+func subaccountFlag(fs *flag.FlagSet, e *env) *uint64 {
+	return fs.Uint64("subaccount", e.profile.SubaccountId, "Subaccount ID")
+}
+
+// requireSubaccount checks that a subaccount was given
+func requireSubaccount(subaccountId uint64) error {
+	if subaccountId == 0 {
+		return fmt.Errorf("-subaccount is required, or set subaccountId in the profile")
+	}
+	return nil
+}
+
+// ordersList lists the active or history orders of a subaccount
+func ordersList(e *env, args []string) error {
+	fs := newFlagSet("orders list")
+	subaccountId := subaccountFlag(fs, e)
+	exchangeIds := fs.String("exchange", "", "Comma-separated exchange IDs to filter on")
+	history := fs.Bool("history", false, "List history orders instead of active orders")
+	size := fs.Uint("size", 100, "Number of orders, at most 100")
+	fs.Parse(args)
+	if err := requireSubaccount(*subaccountId); err != nil {
+		return err
+	}
+
+	client := e.queryClient()
+	subaccount := strconv.FormatUint(*subaccountId, 10)
+	if *history {
+		resp, err := client.GetHistoryOrder(types.GetHistoryOrderReq{SubaccountId: subaccount, Size: uint32(*size), FilterExchangeIdList: *exchangeIds})
+		if err != nil {
+			return err
+		}
+		return printJSON(resp.Data)
+	}
+	resp, err := client.GetActiveOrder(types.GetActiveOrderReq{SubaccountId: subaccount, Size: uint32(*size), FilterExchangeIdList: *exchangeIds})
+	if err != nil {
+		return err
+	}
+	return printJSON(resp.Data)
+}
+
+// ordersCreate creates a limit order, or a market order with -market
+func ordersCreate(e *env, args []string) error {
+	fs := newFlagSet("orders create")
+	subaccountId := subaccountFlag(fs, e)
+	exchangeId := fs.String("exchange", "", "Exchange ID")
+	side := fs.String("side", "", "buy or sell")
+	size := fs.String("size", "", "Order size")
+	price := fs.String("price", "", "Limit price")
+	market := fs.Bool("market", false, "Send a market order instead of a limit order")
+	clientOrderId := fs.String("client-id", "", "Client order ID")
+	reduceOnly := fs.Bool("reduce-only", false, "Only reduce the position")
+	postOnly := fs.Bool("post-only", false, "Cancel the order instead of taking liquidity")
+	fs.Parse(args)
+	if err := requireSubaccount(*subaccountId); err != nil {
+		return err
+	}
+	if *exchangeId == "" {
+		return fmt.Errorf("-exchange is required")
+	}
+	if *side != "buy" && *side != "sell" {
+		return fmt.Errorf("-side must be buy or sell")
+	}
+	if *market == (*price != "") {
+		return fmt.Errorf("either -price or -market is required")
+	}
+	orderSize, err := decimal.NewFromString(*size)
+	if err != nil {
+		return fmt.Errorf("invalid -size %q: %w", *size, err)
+	}
+
+	client, err := e.tradingClient()
+	if err != nil {
+		return err
+	}
+	builder, err := client.NewOrderBuilder(*exchangeId, *subaccountId)
+	if err != nil {
+		return err
+	}
+	builder.Side(*side == "buy").Size(orderSize)
+	if *market {
+		builder.Market()
+	} else {
+		limitPrice, err := decimal.NewFromString(*price)
+		if err != nil {
+			return fmt.Errorf("invalid -price %q: %w", *price, err)
+		}
+		builder.Limit(limitPrice)
+	}
+	if *clientOrderId != "" {
+		builder.ClientOrderId(*clientOrderId)
+	}
+	if *reduceOnly {
+		builder.ReduceOnly()
+	}
+	if *postOnly {
+		builder.PostOnly()
+	}
+	order, err := builder.Build()
+	if err != nil {
+		return err
+	}
+	txHash, err := client.CreateOrder(order)
+	if err != nil {
+		return err
+	}
+	return printJSON(map[string]string{"txHash": txHash})
+}
+
+// ordersCancel cancels orders by ID, by client ID, or all orders of the subaccount
+func ordersCancel(e *env, args []string) error {
+	fs := newFlagSet("orders cancel")
+	subaccountId := subaccountFlag(fs, e)
+	orderIds := fs.String("ids", "", "Comma-separated order IDs")
+	clientOrderIds := fs.String("client-ids", "", "Comma-separated client order IDs")
+	all := fs.Bool("all", false, "Cancel all orders, of the -exchange IDs when given")
+	exchangeIds := fs.String("exchange", "", "Comma-separated exchange IDs for -all")
+	fs.Parse(args)
+	if err := requireSubaccount(*subaccountId); err != nil {
+		return err
+	}
+	selected := 0
+	for _, set := range []bool{*orderIds != "", *clientOrderIds != "", *all} {
+		if set {
+			selected++
+		}
+	}
+	if selected != 1 {
+		return fmt.Errorf("exactly one of -ids, -client-ids or -all is required")
+	}
+
+	client, err := e.tradingClient()
+	if err != nil {
+		return err
+	}
+	var txHash string
+	switch {
+	case *orderIds != "":
+		ids, err := parseUintList(*orderIds)
+		if err != nil {
+			return fmt.Errorf("invalid -ids: %w", err)
+		}
+		txHash, err = client.CancelOrder(&types.CancelOrderParam{SubaccountId: *subaccountId, OrderIdList: ids})
+		if err != nil {
+			return err
+		}
+	case *clientOrderIds != "":
+		txHash, err = client.CancelOrderByClientId(&types.CancelOrderByClientIdParam{SubaccountId: *subaccountId, ClientOrderIdList: strings.Split(*clientOrderIds, ",")})
+		if err != nil {
+			return err
+		}
+	default:
+		ids, err := parseUintList(*exchangeIds)
+		if err != nil {
+			return fmt.Errorf("invalid -exchange: %w", err)
+		}
+		txHash, err = client.CancelAllOrder(&types.CancelAllOrderParam{SubaccountId: *subaccountId, FilterExchangeIdList: ids})
+		if err != nil {
+			return err
+		}
+	}
+	return printJSON(map[string]string{"txHash": txHash})
+}
+
+// accountAssets prints the collateral and positions of a subaccount
+func accountAssets(e *env, args []string) error {
+	fs := newFlagSet("account assets")
+	subaccountId := subaccountFlag(fs, e)
+	fs.Parse(args)
+	if err := requireSubaccount(*subaccountId); err != nil {
+		return err
+	}
+	resp, err := e.queryClient().GetPerpetualAccountAsset(types.GetPerpetualAccountAssetReq{SubaccountId: strconv.FormatUint(*subaccountId, 10)})
+	if err != nil {
+		return err
+	}
+	return printJSON(resp.Data)
+}
+
+// kline prints the K-lines of an exchange
+func kline(e *env, args []string) error {
+	fs := newFlagSet("kline")
+	exchangeId := fs.String("exchange", "", "Exchange ID")
+	klineType := fs.String("type", constants.KlineTypeMinute1, "K-line type, e.g. MINUTE_1 or HOUR_1")
+	priceType := fs.String("price-type", constants.PriceTypeLast, "Price type, e.g. PRICE_TYPE_LAST or PRICE_TYPE_MARK")
+	size := fs.Uint("size", 100, "Number of K-lines")
+	fs.Parse(args)
+	if *exchangeId == "" {
+		return fmt.Errorf("-exchange is required")
+	}
+	resp, err := e.queryClient().GetKline(types.GetKLineReq{ExchangeId: *exchangeId, KlineType: *klineType, PriceType: *priceType, Size: uint32(*size)})
+	if err != nil {
+		return err
+	}
+	return printJSON(resp.Data)
+}
+
+// wsSub prints the messages of WebSocket channels, one per line, until interrupted
+func wsSub(e *env, args []string) error {
+	fs := newFlagSet("ws sub")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: antx ws sub <channel>...\n\nChannels, e.g. ticker.200001, depth.200001.200 or kline.PRICE_TYPE_LAST.200001.MINUTE_1\n")
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("at least one channel is required")
+	}
+
+	client := e.queryClient()
+	errs := make(chan error, 1)
+	err := client.ConnectWebSocket(func(message []byte) {
+		os.Stdout.Write(append(message, '\n'))
+	}, func(err error) {
+		select {
+		case errs <- err:
+		default:
+		}
+	})
+	if err != nil {
+		return err
+	}
+	defer client.DisconnectWebSocket()
+	for _, channel := range fs.Args() {
+		if err := client.WebSocket().Subscribe(channel); err != nil {
+			return err
+		}
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	select {
+	case err := <-errs:
+		return err
+	case <-interrupt:
+		return nil
+	}
+}
+
+// agentBind binds the agent key to the ETH owner of the profile
+func agentBind(e *env, args []string) error {
+	fs := newFlagSet("agent bind")
+	expire := fs.Uint64("expire", 30*24*3600, "Seconds until the binding expires")
+	fs.Parse(args)
+
+	client, err := e.tradingClient()
+	if err != nil {
+		return err
+	}
+	ethKey, _ := e.profile.keyProviders()
+	ethPrivateKey, err := ethKey.PrivateKey()
+	if err != nil {
+		return err
+	}
+	txHash, err := client.BindAgent(ethPrivateKey, e.profile.ChainID, *expire)
+	if err != nil {
+		return err
+	}
+	return printJSON(map[string]string{"txHash": txHash, "agentAddress": client.GetAgentAddress(), "ethAddress": client.GetEthAddress()})
+}
+
+// parseUintList parses comma-separated unsigned integers, an empty string is an empty list
+func parseUintList(s string) ([]uint64, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var values []uint64
+	for _, part := range strings.Split(s, ",") {
+		v, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
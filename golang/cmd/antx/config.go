@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	sdk "github.com/antxprotocol/antx-sdk-golang"
+)
+
+const (
+	// defaultEthKeyEnv environment variable holding the ETH private key unless the profile names another
+	defaultEthKeyEnv = "ETH_PRIVATE_KEY"
+	// defaultAgentKeyEnv environment variable holding the agent private key unless the profile names another
+	defaultAgentKeyEnv = "AGENT_PRIVATE_KEY"
+)
+
+// Profile gateway and credentials of one environment
+type Profile struct {
+	Gateway         string            `json:"gateway"`                   // Gateway URI, e.g. "https://testnet.antex.ai"
+	WsURL           string            `json:"ws"`                        // WebSocket URL, e.g. "wss://testnet.antex.ai/api/v1/ws"
+	ChainID         string            `json:"chainId"`                   // Chain ID
+	SubaccountId    uint64            `json:"subaccountId,omitempty"`    // Default subaccount of the commands
+	EthKeyEnv       string            `json:"ethKeyEnv,omitempty"`       // Environment variable of the ETH private key, defaults to ETH_PRIVATE_KEY
+	AgentKeyEnv     string            `json:"agentKeyEnv,omitempty"`     // Environment variable of the agent private key, defaults to AGENT_PRIVATE_KEY
+	KeychainService string            `json:"keychainService,omitempty"` // macOS Keychain service holding the "eth" and "agent" keys, instead of the environment
+	Headers         map[string]string `json:"headers,omitempty"`         // Headers sent with every gateway request, e.g. {"X-App-Token": "..."} behind a WAF
+}
+
+// configFile profiles file, ~/.antx/config.json by default
+type configFile struct {
+	Default  string             `json:"default"`  // Profile used without -profile or ANTX_PROFILE
+	Profiles map[string]Profile `json:"profiles"` // Profiles by name
+}
+
+// builtinProfiles profiles available without a config file
+var builtinProfiles = map[string]Profile{
+	"testnet": {
+		Gateway: "https://testnet.antex.ai",
+		WsURL:   "wss://testnet.antex.ai/api/v1/ws",
+		ChainID: "antex-testnet",
+	},
+}
+
+// defaultConfigPath returns ~/.antx/config.json
+func defaultConfigPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".antx", "config.json")
+}
+
+// loadProfile loads a profile from the config file at path, falling back to the built-in profiles. An empty name selects
+// ANTX_PROFILE, then the default profile of the file, then testnet.
+func loadProfile(path, name string) (Profile, error) {
+	config := configFile{Profiles: map[string]Profile{}}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return Profile{}, fmt.Errorf("failed to read config %s: %w", path, err)
+		}
+		if err == nil {
+			if err := json.Unmarshal(data, &config); err != nil {
+				return Profile{}, fmt.Errorf("failed to parse config %s: %w", path, err)
+			}
+		}
+	}
+	if name == "" {
+		name = os.Getenv("ANTX_PROFILE")
+	}
+	if name == "" {
+		name = config.Default
+	}
+	if name == "" {
+		name = "testnet"
+	}
+	if profile, ok := config.Profiles[name]; ok {
+		return profile, nil
+	}
+	if profile, ok := builtinProfiles[name]; ok {
+		return profile, nil
+	}
+	return Profile{}, fmt.Errorf("unknown profile %q", name)
+}
+
+// keyProviders returns the sources of the ETH and agent private keys of the profile
+func (p Profile) keyProviders() (eth, agent sdk.KeyProvider) {
+	if p.KeychainService != "" {
+		return sdk.KeychainKeyProvider{Service: p.KeychainService, Account: "eth"},
+			sdk.KeychainKeyProvider{Service: p.KeychainService, Account: "agent"}
+	}
+	ethEnv, agentEnv := p.EthKeyEnv, p.AgentKeyEnv
+	if ethEnv == "" {
+		ethEnv = defaultEthKeyEnv
+	}
+	if agentEnv == "" {
+		agentEnv = defaultAgentKeyEnv
+	}
+	return envKeyProvider(ethEnv), envKeyProvider(agentEnv)
+}
+
+// envKeyProvider reads a private key from an environment variable
+type envKeyProvider string
+
+// PrivateKey returns the value of the environment variable
+func (e envKeyProvider) PrivateKey() (string, error) {
+	key := os.Getenv(string(e))
+	if key == "" {
+		return "", fmt.Errorf("environment variable %s is not set", string(e))
+	}
+	return key, nil
+}
@@ -0,0 +1,114 @@
+// Command antx queries and trades on Antx from the command line, printing results as JSON.
+//
+//	antx [-config path] [-profile name] <command> [flags]
+//
+// Profiles select the gateway, chain and credentials of an environment, see Profile. Run antx without arguments for
+// the list of commands.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	sdk "github.com/antxprotocol/antx-sdk-golang"
+)
+
+// command CLI subcommand
+type command struct {
+	name  string                              // Words that select the command, e.g. "orders list"
+	usage string                              // One-line description
+	run   func(env *env, args []string) error // Runs the command with the remaining arguments
+}
+
+var commands = []command{
+	{"orders list", "List active orders, or history orders with -history", ordersList},
+	{"orders create", "Create a limit or market order", ordersCreate},
+	{"orders cancel", "Cancel orders by ID, by client ID, or all", ordersCancel},
+	{"account assets", "Show the collateral and positions of a subaccount", accountAssets},
+	{"kline", "Get K-lines of an exchange", kline},
+	{"ws sub", "Subscribe to WebSocket channels and print the messages", wsSub},
+	{"agent bind", "Bind the agent key to the ETH owner", agentBind},
+}
+
+// env profile and clients shared by the commands
+type env struct {
+	profile Profile
+}
+
+// queryClient returns a client for queries, which needs no credentials
+func (e *env) queryClient() *sdk.AntxClient {
+	client := sdk.NewAntxQueryClient(e.profile.Gateway, e.profile.WsURL)
+	client.SetHeaders(e.profile.Headers)
+	return client
+}
+
+// tradingClient returns a client signing with the profile credentials
+func (e *env) tradingClient() (*sdk.AntxClient, error) {
+	ethKey, agentKey := e.profile.keyProviders()
+	return sdk.NewAntxClientWithConfig(sdk.Config{
+		GatewayHost:      e.profile.Gateway,
+		WebSocketURL:     e.profile.WsURL,
+		ChainID:          e.profile.ChainID,
+		EthKeyProvider:   ethKey,
+		AgentKeyProvider: agentKey,
+		Headers:          e.profile.Headers,
+	})
+}
+
+func main() {
+	configPath := flag.String("config", defaultConfigPath(), "Profiles file")
+	profileName := flag.String("profile", "", "Profile name, defaults to ANTX_PROFILE or the default profile of the config")
+	flag.Usage = usage
+	flag.Parse()
+
+	cmd, args := findCommand(flag.Args())
+	if cmd == nil {
+		usage()
+		os.Exit(2)
+	}
+	profile, err := loadProfile(*configPath, *profileName)
+	if err != nil {
+		fatal(err)
+	}
+	if err := cmd.run(&env{profile: profile}, args); err != nil {
+		fatal(err)
+	}
+}
+
+// findCommand returns the command selected by the leading arguments and the arguments after it
+func findCommand(args []string) (*command, []string) {
+	for i := range commands {
+		words := strings.Fields(commands[i].name)
+		if len(args) >= len(words) && strings.Join(args[:len(words)], " ") == commands[i].name {
+			return &commands[i], args[len(words):]
+		}
+	}
+	return nil, nil
+}
+
+// usage prints the global flags and the commands
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: antx [-config path] [-profile name] <command> [flags]\n\nFlags:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(os.Stderr, "\nCommands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun antx <command> -h for the flags of a command.\n")
+}
+
+// printJSON prints v as indented JSON
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// fatal prints err and exits
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "antx: %v\n", err)
+	os.Exit(1)
+}
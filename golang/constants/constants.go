@@ -97,5 +97,9 @@ const (
 	MsgCloseAllPositionTypeURL      = "/antx.chain.order.MsgCloseAllPosition"
 
 	// Agent related message types
-	MsgBindAgentTypeURL = "/antx.chain.agent.MsgBindAgent"
+	MsgBindAgentTypeURL   = "/antx.chain.agent.MsgBindAgent"
+	MsgUnbindAgentTypeURL = "/antx.chain.agent.MsgUnbindAgent"
+
+	// Authz related message types
+	MsgExecTypeURL = "/cosmos.authz.v1beta1.MsgExec"
 )
@@ -0,0 +1,354 @@
+package sdk
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// PriceSource returns the reference price of an exchange
+type PriceSource func(exchangeId string) (decimal.Decimal, error)
+
+// DCAPlan recurring order of a fixed notional
+type DCAPlan struct {
+	Id           string          // Plan ID, unique within the scheduler, prefixes the client order IDs
+	ExchangeId   string          // Exchange ID
+	SubaccountId uint64          // Subaccount ID
+	IsBuy        bool            // Whether to buy
+	Notional     decimal.Decimal // Quote value of each tranche, the size is rounded down to the step size
+	Schedule     Schedule        // Tranche times, e.g. Every(24 * time.Hour) or ParseCron("0 9 * * 1", nil)
+	LimitOffset  decimal.Decimal // Relative distance of a limit price from the reference price, e.g. 0.001 buys 0.1% below, zero sends market orders
+}
+
+// DCATranche one scheduled tranche of a plan
+type DCATranche struct {
+	PlanId        string          // Plan ID
+	ScheduledAt   time.Time       // Scheduled time
+	ExecutedAt    time.Time       // Time the order was sent, zero when skipped
+	Price         decimal.Decimal // Reference price
+	Size          decimal.Decimal // Order size
+	ClientOrderId string          // Client order ID
+	TxHash        string          // Transaction hash
+	Skipped       bool            // Whether the tranche was skipped or the plan paused
+	Err           error           // Failure, nil when sent
+}
+
+// DCASummary executed tranches of a plan
+type DCASummary struct {
+	PlanId       string          // Plan ID
+	Paused       bool            // Whether the plan is paused
+	NextAt       time.Time       // Next scheduled tranche
+	Executed     int             // Tranches sent
+	Skipped      int             // Tranches skipped
+	Failed       int             // Tranches that failed
+	Size         decimal.Decimal // Total size sent
+	Notional     decimal.Decimal // Total quote value sent at the reference prices
+	AveragePrice decimal.Decimal // Notional-weighted reference price, zero before the first tranche
+	Tranches     []DCATranche    // All tranches in order
+}
+
+// DCAConfig DCA scheduler configuration
+type DCAConfig struct {
+	PriceSource  PriceSource      // Reference price, defaults to the close of the latest 1-minute last price K-line
+	OnTranche    func(DCATranche) // Called after each tranche, including skipped and failed ones
+	ErrorHandler func(error)      // Called on tranche errors, errors are logged when nil
+}
+
+// dcaPlanState plan with its controls and tranches
+type dcaPlanState struct {
+	plan     DCAPlan
+	paused   bool
+	skip     int
+	nextAt   time.Time
+	tranches []DCATranche
+	stop     chan struct{}
+}
+
+// DCAScheduler places fixed-notional orders for each plan on its schedule until stopped. Plans can be paused,
+// resumed, and have upcoming tranches skipped while the scheduler runs.
+type DCAScheduler struct {
+	client *AntxClient
+	config DCAConfig
+
+	mu    sync.Mutex
+	plans map[string]*dcaPlanState
+
+	started bool
+	done    chan struct{}
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// NewDCAScheduler creates a DCA scheduler
+func (c *AntxClient) NewDCAScheduler(config DCAConfig) *DCAScheduler {
+	s := &DCAScheduler{
+		client: c,
+		config: config,
+		plans:  make(map[string]*dcaPlanState),
+		done:   make(chan struct{}),
+	}
+	if s.config.PriceSource == nil {
+		s.config.PriceSource = s.lastPrice
+	}
+	return s
+}
+
+// AddPlan adds a plan, which starts running at once when the scheduler is started
+func (s *DCAScheduler) AddPlan(plan DCAPlan) error {
+	if plan.Id == "" {
+		return fmt.Errorf("plan ID is required")
+	}
+	if plan.ExchangeId == "" {
+		return fmt.Errorf("plan %s: exchange ID is required", plan.Id)
+	}
+	if !plan.Notional.IsPositive() {
+		return fmt.Errorf("plan %s: notional must be positive", plan.Id)
+	}
+	if plan.Schedule == nil {
+		return fmt.Errorf("plan %s: schedule is required", plan.Id)
+	}
+	if plan.LimitOffset.IsNegative() || plan.LimitOffset.GreaterThanOrEqual(decimal.NewFromInt(1)) {
+		return fmt.Errorf("plan %s: limit offset must be in [0, 1)", plan.Id)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.plans[plan.Id]; ok {
+		return fmt.Errorf("plan %s already exists", plan.Id)
+	}
+	state := &dcaPlanState{plan: plan, stop: make(chan struct{})}
+	s.plans[plan.Id] = state
+	if s.started {
+		s.run(state)
+	}
+	return nil
+}
+
+// RemovePlan stops and removes a plan, its summary is no longer available
+func (s *DCAScheduler) RemovePlan(planId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.plans[planId]
+	if !ok {
+		return fmt.Errorf("plan %s not found", planId)
+	}
+	close(state.stop)
+	delete(s.plans, planId)
+	return nil
+}
+
+// Skip skips the next count tranches of a plan
+func (s *DCAScheduler) Skip(planId string, count int) error {
+	if count <= 0 {
+		return fmt.Errorf("skip count must be positive")
+	}
+	return s.update(planId, func(state *dcaPlanState) { state.skip += count })
+}
+
+// Pause skips the tranches of a plan until Resume is called
+func (s *DCAScheduler) Pause(planId string) error {
+	return s.update(planId, func(state *dcaPlanState) { state.paused = true })
+}
+
+// Resume resumes a paused plan and clears its pending skips, the next tranche runs at its regular time
+func (s *DCAScheduler) Resume(planId string) error {
+	return s.update(planId, func(state *dcaPlanState) {
+		state.paused = false
+		state.skip = 0
+	})
+}
+
+// Summary returns the executed tranches of a plan
+func (s *DCAScheduler) Summary(planId string) (DCASummary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.plans[planId]
+	if !ok {
+		return DCASummary{}, fmt.Errorf("plan %s not found", planId)
+	}
+	summary := DCASummary{
+		PlanId:   planId,
+		Paused:   state.paused,
+		NextAt:   state.nextAt,
+		Tranches: append([]DCATranche(nil), state.tranches...),
+	}
+	for _, tranche := range state.tranches {
+		switch {
+		case tranche.Skipped:
+			summary.Skipped++
+		case tranche.Err != nil:
+			summary.Failed++
+		default:
+			summary.Executed++
+			summary.Size = summary.Size.Add(tranche.Size)
+			summary.Notional = summary.Notional.Add(tranche.Size.Mul(tranche.Price))
+		}
+	}
+	if summary.Size.IsPositive() {
+		summary.AveragePrice = summary.Notional.Div(summary.Size)
+	}
+	return summary, nil
+}
+
+// Start runs the plans until Stop is called
+func (s *DCAScheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.started {
+		return
+	}
+	s.started = true
+	for _, state := range s.plans {
+		s.run(state)
+	}
+}
+
+// Stop stops all plans and waits for tranches in flight
+func (s *DCAScheduler) Stop() {
+	s.once.Do(func() { close(s.done) })
+	s.wg.Wait()
+}
+
+// run starts the loop of a plan, s.mu must be held
+func (s *DCAScheduler) run(state *dcaPlanState) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			next := state.plan.Schedule.Next(time.Now())
+			if next.IsZero() {
+				return
+			}
+			s.mu.Lock()
+			state.nextAt = next
+			s.mu.Unlock()
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-s.done:
+				timer.Stop()
+				return
+			case <-state.stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			s.tranche(state, next)
+		}
+	}()
+}
+
+// tranche executes or skips one tranche of a plan
+func (s *DCAScheduler) tranche(state *dcaPlanState, scheduledAt time.Time) {
+	plan := state.plan
+	tranche := DCATranche{PlanId: plan.Id, ScheduledAt: scheduledAt}
+
+	s.mu.Lock()
+	skipped := state.paused || state.skip > 0
+	if !state.paused && state.skip > 0 {
+		state.skip--
+	}
+	s.mu.Unlock()
+
+	if skipped {
+		tranche.Skipped = true
+	} else {
+		tranche.ExecutedAt = time.Now()
+		tranche.ClientOrderId = fmt.Sprintf("dca-%s-%d", plan.Id, scheduledAt.Unix())
+		if len(tranche.ClientOrderId) > MaxClientOrderIdLength {
+			tranche.ClientOrderId = ""
+		}
+		tranche.Err = s.execute(plan, &tranche)
+		if tranche.Err != nil {
+			s.report(plan.Id, tranche.Err)
+		}
+	}
+
+	s.mu.Lock()
+	state.tranches = append(state.tranches, tranche)
+	s.mu.Unlock()
+	if s.config.OnTranche != nil {
+		s.config.OnTranche(tranche)
+	}
+}
+
+// execute sends the order of a tranche, sized from the reference price
+func (s *DCAScheduler) execute(plan DCAPlan, tranche *DCATranche) error {
+	price, err := s.config.PriceSource(plan.ExchangeId)
+	if err != nil {
+		return fmt.Errorf("failed to get reference price: %w", err)
+	}
+	if !price.IsPositive() {
+		return fmt.Errorf("invalid reference price %s", price)
+	}
+	tranche.Price = price
+
+	exchange, err := s.client.GetExchange(plan.ExchangeId)
+	if err != nil {
+		return err
+	}
+	tranche.Size = plan.Notional.Div(price).RoundFloor(exchange.StepSizeScale)
+	if !tranche.Size.IsPositive() {
+		return fmt.Errorf("notional %s is below one step at price %s", plan.Notional, price)
+	}
+
+	builder := NewOrderBuilder(exchange, plan.SubaccountId).Side(plan.IsBuy).Size(tranche.Size)
+	if plan.LimitOffset.IsZero() {
+		builder.Market()
+	} else if plan.IsBuy {
+		builder.Limit(price.Mul(decimal.NewFromInt(1).Sub(plan.LimitOffset)).RoundFloor(exchange.TickSizeScale))
+	} else {
+		builder.Limit(price.Mul(decimal.NewFromInt(1).Add(plan.LimitOffset)).RoundCeil(exchange.TickSizeScale))
+	}
+	if tranche.ClientOrderId != "" {
+		builder.ClientOrderId(tranche.ClientOrderId)
+	}
+	order, err := builder.Build()
+	if err != nil {
+		return err
+	}
+	tranche.TxHash, err = s.client.CreateOrder(order)
+	return err
+}
+
+// lastPrice returns the close of the latest 1-minute last price K-line
+func (s *DCAScheduler) lastPrice(exchangeId string) (decimal.Decimal, error) {
+	resp, err := s.client.GetKline(types.GetKLineReq{
+		ExchangeId: exchangeId,
+		KlineType:  constants.KlineTypeMinute1,
+		PriceType:  constants.PriceTypeLast,
+		Size:       1,
+	})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if len(resp.Data.KlineList) == 0 {
+		return decimal.Zero, fmt.Errorf("no K-line for exchange %s", exchangeId)
+	}
+	return decimal.NewFromString(resp.Data.KlineList[0].Close)
+}
+
+// update applies a control change to a plan
+func (s *DCAScheduler) update(planId string, apply func(*dcaPlanState)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.plans[planId]
+	if !ok {
+		return fmt.Errorf("plan %s not found", planId)
+	}
+	apply(state)
+	return nil
+}
+
+// report passes an error to the configured handler
+func (s *DCAScheduler) report(planId string, err error) {
+	if s.config.ErrorHandler != nil {
+		s.config.ErrorHandler(fmt.Errorf("plan %s: %w", planId, err))
+		return
+	}
+	s.client.Logger().Errorf("dca scheduler: plan %s: %v", planId, err)
+}
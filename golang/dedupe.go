@@ -0,0 +1,221 @@
+package sdk
+
+import (
+	"container/list"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/store"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// dedupeStorePrefix prefix of the store keys of the deduplication cache, followed by "<subaccount>/<client order ID>"
+const dedupeStorePrefix = "dedupe/"
+
+// DefaultDedupeCapacity default number of client order IDs remembered by the deduplication cache
+const DefaultDedupeCapacity = 10000
+
+// ErrDuplicateOrder CreateOrder was called again with a client order ID within the deduplication window
+var ErrDuplicateOrder = errors.New("duplicate client order ID")
+
+// dedupeCache LRU of recently submitted client order IDs, the least recently submitted ID is evicted beyond capacity.
+// With a store set, the IDs are also written to it so that they survive restarts.
+type dedupeCache struct {
+	window   time.Duration
+	capacity int
+
+	mu      sync.Mutex
+	order   *list.List // Entries, most recently submitted first
+	entries map[string]*list.Element
+	store   store.Store
+}
+
+// dedupeEntry client order ID and its submission time
+type dedupeEntry struct {
+	key string
+	at  time.Time
+}
+
+// newDedupeCache creates a deduplication cache, nil when window is not positive
+func newDedupeCache(window time.Duration, capacity int) *dedupeCache {
+	if window <= 0 {
+		return nil
+	}
+	if capacity <= 0 {
+		capacity = DefaultDedupeCapacity
+	}
+	return &dedupeCache{
+		window:   window,
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// add records keys submitted now, it records none and returns the first key submitted within the window if any
+func (d *dedupeCache) add(keys ...string) (string, error) {
+	now := time.Now()
+	d.mu.Lock()
+	for _, key := range keys {
+		if e, ok := d.entries[key]; ok && now.Sub(e.Value.(*dedupeEntry).at) < d.window {
+			d.mu.Unlock()
+			return key, nil
+		}
+	}
+	puts := make(map[string][]byte, len(keys))
+	for _, key := range keys {
+		if e, ok := d.entries[key]; ok {
+			e.Value.(*dedupeEntry).at = now
+			d.order.MoveToFront(e)
+		} else {
+			d.entries[key] = d.order.PushFront(&dedupeEntry{key: key, at: now})
+		}
+		puts[dedupeStorePrefix+key] = []byte(strconv.FormatInt(now.UnixNano(), 10))
+	}
+	// Entries are ordered by submission time, drop the expired and those beyond capacity from the back
+	var deletes []string
+	for e := d.order.Back(); e != nil; e = d.order.Back() {
+		entry := e.Value.(*dedupeEntry)
+		if d.order.Len() <= d.capacity && now.Sub(entry.at) < d.window {
+			break
+		}
+		d.order.Remove(e)
+		delete(d.entries, entry.key)
+		deletes = append(deletes, dedupeStorePrefix+entry.key)
+	}
+	s := d.store
+	d.mu.Unlock()
+
+	// The keys are stored before the orders are signed, so a restart right after the broadcast still rejects them
+	if s != nil {
+		if err := store.Apply(s, puts, deletes); err != nil {
+			d.remove(keys...)
+			return "", fmt.Errorf("failed to store client order IDs: %w", err)
+		}
+	}
+	return "", nil
+}
+
+// remove forgets keys, e.g. of a submission that failed
+func (d *dedupeCache) remove(keys ...string) {
+	if d == nil || len(keys) == 0 {
+		return
+	}
+	d.mu.Lock()
+	deletes := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if e, ok := d.entries[key]; ok {
+			d.order.Remove(e)
+			delete(d.entries, key)
+		}
+		deletes = append(deletes, dedupeStorePrefix+key)
+	}
+	s := d.store
+	d.mu.Unlock()
+
+	if s != nil {
+		// A key left in the store only rejects its order again after a restart within the window
+		store.Apply(s, nil, deletes)
+	}
+}
+
+// setStore persists the cache in s, loading the keys stored within the window and deleting the expired ones
+func (d *dedupeCache) setStore(s store.Store) error {
+	if s == nil {
+		d.mu.Lock()
+		d.store = nil
+		d.mu.Unlock()
+		return nil
+	}
+	now := time.Now()
+	var loaded []*dedupeEntry
+	var expired []string
+	err := s.Scan(dedupeStorePrefix, func(key string, value []byte) error {
+		at, err := strconv.ParseInt(string(value), 10, 64)
+		if err != nil || now.Sub(time.Unix(0, at)) >= d.window {
+			expired = append(expired, key)
+			return nil
+		}
+		loaded = append(loaded, &dedupeEntry{key: strings.TrimPrefix(key, dedupeStorePrefix), at: time.Unix(0, at)})
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to load client order IDs: %w", err)
+	}
+	// Oldest first, so that each pushed to the front leaves the most recent first
+	sort.Slice(loaded, func(i, j int) bool { return loaded[i].at.Before(loaded[j].at) })
+	if len(loaded) > d.capacity {
+		for _, entry := range loaded[:len(loaded)-d.capacity] {
+			expired = append(expired, dedupeStorePrefix+entry.key)
+		}
+		loaded = loaded[len(loaded)-d.capacity:]
+	}
+
+	d.mu.Lock()
+	for _, entry := range loaded {
+		if _, ok := d.entries[entry.key]; !ok {
+			d.entries[entry.key] = d.order.PushFront(entry)
+		}
+	}
+	d.store = s
+	d.mu.Unlock()
+
+	if len(expired) > 0 {
+		if err := store.Apply(s, nil, expired); err != nil {
+			return fmt.Errorf("failed to delete expired client order IDs: %w", err)
+		}
+	}
+	return nil
+}
+
+// releaseOrders forgets the client order IDs of a failed submission when the transaction cannot have reached the
+// mempool, so the orders may be submitted again. They stay recorded after a timeout or a 5xx response to the broadcast,
+// when the transaction may have been accepted.
+func (c *AntxClient) releaseOrders(keys []string, err error) {
+	if submissionRejected(err) {
+		c.dedupe.remove(keys...)
+	}
+}
+
+// submissionRejected reports whether a submission failed for certain: before the broadcast, e.g. on a signing error or
+// ErrReadOnly, or with a gateway response code rejecting the transaction on CheckTx
+func submissionRejected(err error) bool {
+	var broadcastErr *broadcastError
+	if !errors.As(err, &broadcastErr) {
+		return true
+	}
+	var apiErr *types.APIError
+	return errors.As(err, &apiErr) && apiErr.HTTPStatus < http.StatusInternalServerError
+}
+
+// dedupeOrders records the client order IDs of a submission, rejecting it when one was submitted within the window.
+// It returns the keys to release with releaseOrders when the submission fails.
+func (c *AntxClient) dedupeOrders(subaccountId uint64, clientOrderIds ...string) ([]string, error) {
+	if c.dedupe == nil {
+		return nil, nil
+	}
+	keys := make([]string, 0, len(clientOrderIds))
+	for _, clientOrderId := range clientOrderIds {
+		if clientOrderId != "" {
+			keys = append(keys, fmt.Sprintf("%d/%s", subaccountId, clientOrderId))
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	key, err := c.dedupe.add(keys...)
+	if err != nil {
+		return nil, err
+	}
+	if key != "" {
+		c.addCounter(MetricOrderDuplicatesSuppressed, 1, nil)
+		return nil, fmt.Errorf("order %s: %w", key, ErrDuplicateOrder)
+	}
+	return keys, nil
+}
@@ -0,0 +1,240 @@
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+const (
+	// DefaultDownloadMaxRetries default number of retries of a failed page
+	DefaultDownloadMaxRetries = 5
+	// DefaultDownloadRetryBackoff default delay before the first retry, doubled on each retry
+	DefaultDownloadRetryBackoff = time.Second
+	// maxDownloadRetryBackoff upper bound of the retry delay
+	maxDownloadRetryBackoff = 30 * time.Second
+)
+
+// DownloadCheckpoint resumable progress of one download
+type DownloadCheckpoint struct {
+	OffsetData                string `json:"offsetData,omitempty"`                // Next page offset of cursor paginated series
+	PageOffsetDataCreatedTime string `json:"pageOffsetDataCreatedTime,omitempty"` // Next page offset creation time of indexer paginated series
+	PageOffsetDataItemId      string `json:"pageOffsetDataItemId,omitempty"`      // Next page offset itemId of indexer paginated series
+	Records                   int64  `json:"records"`                             // Records delivered so far
+	Done                      bool   `json:"done"`                                // Whether the download completed
+	UpdatedTime               int64  `json:"updatedTime"`                         // Last update time, unit: milliseconds
+}
+
+// Downloader pulls long histories page by page and persists a checkpoint after each page, so that an interrupted download resumes where it stopped
+type Downloader struct {
+	CheckpointPath string        // JSON file holding the checkpoints of all downloads
+	PageSize       uint32        // Records per page, defaults to 100
+	MaxRetries     int           // Retries of a failed page, defaults to DefaultDownloadMaxRetries
+	RetryBackoff   time.Duration // Delay before the first retry, defaults to DefaultDownloadRetryBackoff
+
+	client *AntxClient
+	mu     sync.Mutex
+}
+
+// NewDownloader creates a downloader persisting checkpoints to checkpointPath
+func (c *AntxClient) NewDownloader(checkpointPath string) *Downloader {
+	return &Downloader{
+		CheckpointPath: checkpointPath,
+		PageSize:       100,
+		MaxRetries:     DefaultDownloadMaxRetries,
+		RetryBackoff:   DefaultDownloadRetryBackoff,
+		client:         c,
+	}
+}
+
+// DownloadKlines downloads K-lines matching req, sink is called once per page and a page may be delivered again after an interruption
+func (d *Downloader) DownloadKlines(req types.GetKLineReq, sink func([]types.KLine) error) error {
+	key := fmt.Sprintf("kline/%s/%s/%s/%d-%d", req.ExchangeId, req.PriceType, req.KlineType, req.FilterBeginKlineTimeInclusive, req.FilterEndKlineTimeExclusive)
+	return d.run(key, func(checkpoint *DownloadCheckpoint) (bool, int, error) {
+		req.Size = d.PageSize
+		req.OffsetData = checkpoint.OffsetData
+		var resp *types.GetKLineResp
+		err := d.retry(key, func() (err error) {
+			resp, err = d.client.GetKline(req)
+			return err
+		})
+		if err != nil {
+			return false, 0, err
+		}
+		if err := sink(resp.Data.KlineList); err != nil {
+			return false, 0, err
+		}
+		checkpoint.OffsetData = resp.Data.NextPageOffsetData
+		return resp.Data.NextPageOffsetData == "", len(resp.Data.KlineList), nil
+	})
+}
+
+// DownloadFundingHistory downloads funding rates matching req, sink is called once per page and a page may be delivered again after an interruption
+func (d *Downloader) DownloadFundingHistory(req types.GetFundingHistoryReq, sink func([]types.FundingRate) error) error {
+	key := fmt.Sprintf("funding/%s/%t/%d-%d", req.ExchangeId, req.FilterSettlementFundingRate, req.FilterBeginTimeInclusive, req.FilterEndTimeExclusive)
+	return d.run(key, func(checkpoint *DownloadCheckpoint) (bool, int, error) {
+		req.Size = d.PageSize
+		req.OffsetData = checkpoint.OffsetData
+		var resp *types.GetFundingHistoryResp
+		err := d.retry(key, func() (err error) {
+			resp, err = d.client.GetFundingHistory(req)
+			return err
+		})
+		if err != nil {
+			return false, 0, err
+		}
+		if err := sink(resp.Data.FundingRateList); err != nil {
+			return false, 0, err
+		}
+		checkpoint.OffsetData = resp.Data.NextPageOffsetData
+		return resp.Data.NextPageOffsetData == "", len(resp.Data.FundingRateList), nil
+	})
+}
+
+// DownloadFills downloads order fills matching req, sink is called once per page and a page may be delivered again after an interruption
+func (d *Downloader) DownloadFills(req types.GetHistoryOrderFillTransactionReq, sink func([]types.OrderFillTransaction) error) error {
+	key := fmt.Sprintf("fills/%s/%s/%s/%d-%d", req.SubaccountId, req.FilterExchangeIdList, req.FilterCoinIdList, req.FilterStartCreatedTimeInclusive, req.FilterEndCreatedTimeExclusive)
+	return d.run(key, func(checkpoint *DownloadCheckpoint) (bool, int, error) {
+		req.Size = d.PageSize
+		req.PageOffsetDataCreatedTime = checkpoint.PageOffsetDataCreatedTime
+		req.PageOffsetDataItemId = checkpoint.PageOffsetDataItemId
+		var resp *types.GetHistoryOrderFillTransactionResp
+		err := d.retry(key, func() (err error) {
+			resp, err = d.client.GetHistoryOrderFillTransaction(req)
+			return err
+		})
+		if err != nil {
+			return false, 0, err
+		}
+		if err := sink(resp.Data.OrderFillTransactionList); err != nil {
+			return false, 0, err
+		}
+		next := resp.Data.PageOffsetData
+		checkpoint.PageOffsetDataCreatedTime = next.CreateTime
+		checkpoint.PageOffsetDataItemId = next.ItemId
+		done := next.ItemId == "" || len(resp.Data.OrderFillTransactionList) < int(req.Size)
+		return done, len(resp.Data.OrderFillTransactionList), nil
+	})
+}
+
+// checkpoint returns the checkpoint of a download key
+func (d *Downloader) checkpoint(key string) (DownloadCheckpoint, bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	checkpoints, err := d.load()
+	if err != nil {
+		return DownloadCheckpoint{}, false, err
+	}
+	checkpoint, ok := checkpoints[key]
+	return checkpoint, ok, nil
+}
+
+// Reset removes all checkpoints so that every download starts from scratch
+func (d *Downloader) Reset() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := os.Remove(d.CheckpointPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove checkpoint file: %w", err)
+	}
+	return nil
+}
+
+// run fetches pages from the checkpoint of key until the last page, saving the checkpoint after each page
+func (d *Downloader) run(key string, page func(checkpoint *DownloadCheckpoint) (done bool, records int, err error)) error {
+	checkpoint, _, err := d.checkpoint(key)
+	if err != nil {
+		return err
+	}
+	for !checkpoint.Done {
+		done, records, err := page(&checkpoint)
+		if err != nil {
+			return fmt.Errorf("download %s stopped after %d records: %w", key, checkpoint.Records, err)
+		}
+		checkpoint.Records += int64(records)
+		checkpoint.Done = done
+		checkpoint.UpdatedTime = time.Now().UnixMilli()
+		if err := d.save(key, checkpoint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retry calls fn until it succeeds or the retries are exhausted, with exponential backoff
+func (d *Downloader) retry(key string, fn func() error) error {
+	backoff := d.RetryBackoff
+	if backoff <= 0 {
+		backoff = DefaultDownloadRetryBackoff
+	}
+	var err error
+	for attempt := 0; ; attempt++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if attempt >= d.MaxRetries {
+			return err
+		}
+		d.client.Logger().Infof("download %s: page failed, retrying in %s: %v", key, backoff, err)
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > maxDownloadRetryBackoff {
+			backoff = maxDownloadRetryBackoff
+		}
+	}
+}
+
+// load reads all checkpoints, a missing file means no checkpoints
+func (d *Downloader) load() (map[string]DownloadCheckpoint, error) {
+	checkpoints := make(map[string]DownloadCheckpoint)
+	data, err := os.ReadFile(d.CheckpointPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint file: %w", err)
+	}
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint file: %w", err)
+	}
+	return checkpoints, nil
+}
+
+// save writes the checkpoint of key, replacing the file atomically so that a crash never leaves it truncated
+func (d *Downloader) save(key string, checkpoint DownloadCheckpoint) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	checkpoints, err := d.load()
+	if err != nil {
+		return err
+	}
+	checkpoints[key] = checkpoint
+	data, err := json.MarshalIndent(checkpoints, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoints: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(d.CheckpointPath), filepath.Base(d.CheckpointPath)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), d.CheckpointPath); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	return nil
+}
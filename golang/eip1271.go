@@ -0,0 +1,57 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// eip1271MagicValue bytes4(keccak256("isValidSignature(bytes32,bytes)")), returned by isValidSignature for a valid signature
+var eip1271MagicValue = []byte{0x16, 0x26, 0xba, 0x7e}
+
+// VerifyEIP1271Signature calls isValidSignature(hash, sig) on a smart-contract wallet through caller, e.g. an *ethclient.Client,
+// and reports whether the wallet accepts the signature
+func VerifyEIP1271Signature(ctx context.Context, caller ethereum.ContractCaller, contractAddress string, hash []byte, sig []byte) (bool, error) {
+	if len(hash) != common.HashLength {
+		return false, fmt.Errorf("hash must be %d bytes, got %d", common.HashLength, len(hash))
+	}
+	if err := ValidateEthAddress(contractAddress); err != nil {
+		return false, err
+	}
+	contract := common.HexToAddress(contractAddress)
+	output, err := caller.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: encodeIsValidSignature(hash, sig)}, nil)
+	if err != nil {
+		return false, fmt.Errorf("isValidSignature call failed: %w", err)
+	}
+	return len(output) >= 4 && bytes.Equal(output[:4], eip1271MagicValue), nil
+}
+
+// VerifyEthPersonalSignatureWithContract verifies a personal_sign signature of data by address, accepting EOA signatures
+// and, when caller is not nil, signatures of smart-contract wallets through EIP-1271
+func VerifyEthPersonalSignatureWithContract(ctx context.Context, caller ethereum.ContractCaller, address string, data []byte, sig []byte) (bool, error) {
+	// VerifyEthPersonalSignature normalizes v in place
+	if len(sig) == 65 && VerifyEthPersonalSignature(address, data, append([]byte(nil), sig...)) {
+		return true, nil
+	}
+	if caller == nil {
+		return false, nil
+	}
+	return VerifyEIP1271Signature(ctx, caller, address, accounts.TextHash(data), sig)
+}
+
+// encodeIsValidSignature ABI encodes the call data of isValidSignature(bytes32,bytes)
+func encodeIsValidSignature(hash []byte, sig []byte) []byte {
+	padded := (len(sig) + 31) / 32 * 32
+	data := make([]byte, 0, 4+32*3+padded)
+	data = append(data, eip1271MagicValue...)
+	data = append(data, hash...)
+	data = append(data, common.LeftPadBytes(big.NewInt(64).Bytes(), 32)...)
+	data = append(data, common.LeftPadBytes(big.NewInt(int64(len(sig))).Bytes(), 32)...)
+	data = append(data, sig...)
+	return append(data, make([]byte, padded-len(sig))...)
+}
@@ -0,0 +1,119 @@
+package sdk
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// Time tags of the asset snapshots
+const (
+	AssetSnapshotHourly = "0" // One snapshot per hour
+	AssetSnapshotDaily  = "1" // One snapshot per day
+)
+
+// EquityPoint equity of one asset snapshot with its change over the previous snapshot
+type EquityPoint struct {
+	Time             uint64          // Snapshot time, unit: milliseconds
+	Equity           decimal.Decimal // Total equity
+	Change           decimal.Decimal // Equity change since the previous snapshot
+	NetDeposit       decimal.Decimal // Deposits minus withdrawals of the period
+	Pnl              decimal.Decimal // Equity change not caused by deposits or withdrawals
+	Return           decimal.Decimal // Pnl relative to the previous equity plus the period deposits
+	CumulativeReturn decimal.Decimal // Compounded return since the first snapshot
+	Drawdown         decimal.Decimal // Decline of the compounded return from its peak, as a fraction of the peak
+}
+
+// EquityCurve deposit-adjusted equity series of a subaccount coin, oldest first
+type EquityCurve struct {
+	SubaccountId string          // Subaccount ID
+	CoinId       string          // Coin ID
+	Points       []EquityPoint   // Points, oldest first
+	TotalPnl     decimal.Decimal // Sum of the period PnL
+	NetDeposit   decimal.Decimal // Sum of the period deposits minus withdrawals
+	TotalReturn  decimal.Decimal // Compounded return over the series
+	MaxDrawdown  decimal.Decimal // Largest drawdown over the series
+}
+
+// GetEquityCurve reads the asset snapshots of a coin of a subaccount with a time tag, e.g. AssetSnapshotDaily, created
+// in [start, end) (unit: milliseconds, 0 for an open bound) and builds their equity curve
+func (c *AntxClient) GetEquityCurve(subaccountId, coinId, timeTag string, start, end uint64) (*EquityCurve, error) {
+	req := types.GetAssetSnapshotReq{
+		SubaccountId:                    subaccountId,
+		Size:                            100,
+		FilterCoinId:                    coinId,
+		FilterTimeTag:                   timeTag,
+		FilterStartCreatedTimeInclusive: start,
+		FilterEndCreatedTimeExclusive:   end,
+	}
+	snapshots, err := types.FetchAllPages(req.Size, func(cursor types.Cursor) ([]types.AssetSnapshot, types.IndexerPageOffsetData, error) {
+		req.PageOffsetDataCreatedTime, req.PageOffsetDataItemId = cursor.CreatedTime, cursor.ItemId
+		resp, err := c.GetAssetSnapshot(req)
+		if err != nil {
+			return nil, types.IndexerPageOffsetData{}, err
+		}
+		return resp.Data.AssetSnapshotList, resp.Data.PageOffsetData, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return BuildEquityCurve(snapshots)
+}
+
+// BuildEquityCurve computes the equity curve of the asset snapshots of one subaccount coin, in any order. The change of
+// each period is split into the net deposit (TermDepositAmount minus TermWithdrawAmount) and the PnL, the return of a
+// period is its PnL relative to the previous equity plus its deposits, and the drawdown follows the compounded return so
+// that withdrawals do not count as losses.
+func BuildEquityCurve(snapshots []types.AssetSnapshot) (*EquityCurve, error) {
+	curve := &EquityCurve{}
+	if len(snapshots) == 0 {
+		return curve, nil
+	}
+	sorted := append([]types.AssetSnapshot(nil), snapshots...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].SnapshotTime < sorted[j].SnapshotTime })
+	curve.SubaccountId, curve.CoinId = sorted[0].SubaccountId, sorted[0].CoinId
+
+	one := decimal.NewFromInt(1)
+	growth, peak := one, one
+	for i, snapshot := range sorted {
+		if snapshot.SubaccountId != curve.SubaccountId || snapshot.CoinId != curve.CoinId {
+			return nil, fmt.Errorf("snapshots of subaccount %s coin %s mixed with subaccount %s coin %s",
+				curve.SubaccountId, curve.CoinId, snapshot.SubaccountId, snapshot.CoinId)
+		}
+		var values [3]decimal.Decimal
+		for j, value := range []string{snapshot.TotalEquity, snapshot.TermDepositAmount, snapshot.TermWithdrawAmount} {
+			var err error
+			if values[j], err = parseOptionalDecimal(value); err != nil {
+				return nil, fmt.Errorf("failed to parse snapshot at %d: %w", snapshot.SnapshotTime, err)
+			}
+		}
+		point := EquityPoint{Time: snapshot.SnapshotTime, Equity: values[0]}
+		if i > 0 {
+			// The flows of the first snapshot happened before the series starts
+			previous := curve.Points[i-1].Equity
+			deposit, withdraw := values[1], values[2]
+			point.Change = point.Equity.Sub(previous)
+			point.NetDeposit = deposit.Sub(withdraw)
+			point.Pnl = point.Change.Sub(point.NetDeposit)
+			if base := previous.Add(deposit); base.IsPositive() {
+				point.Return = point.Pnl.Div(base)
+			}
+			growth = growth.Mul(one.Add(point.Return))
+		}
+		if growth.GreaterThan(peak) {
+			peak = growth
+		}
+		point.CumulativeReturn = growth.Sub(one)
+		point.Drawdown = peak.Sub(growth).Div(peak)
+		if point.Drawdown.GreaterThan(curve.MaxDrawdown) {
+			curve.MaxDrawdown = point.Drawdown
+		}
+		curve.TotalPnl = curve.TotalPnl.Add(point.Pnl)
+		curve.NetDeposit = curve.NetDeposit.Add(point.NetDeposit)
+		curve.Points = append(curve.Points, point)
+	}
+	curve.TotalReturn = growth.Sub(one)
+	return curve, nil
+}
@@ -0,0 +1,201 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/query"
+)
+
+// Topic event bus topic, the type of the event data depends on the topic
+type Topic string
+
+// Event bus topics
+const (
+	TopicOrder        Topic = "order"        // Order update, data is a types.Order
+	TopicFill         Topic = "fill"         // Order fill, data is a types.OrderFillTransaction
+	TopicPosition     Topic = "position"     // Position update, data is a types.PerpetualPosition
+	TopicTicker       Topic = "ticker"       // Ticker push, data is a types.TickerData
+	TopicDepth        Topic = "depth"        // Depth snapshot or update, data is a types.DepthData
+	TopicConnectivity Topic = "connectivity" // WebSocket connection lost or restored, data is a Connectivity
+	TopicIndexerLag   Topic = "indexer_lag"  // Indexer lag check, data is an IndexerLag
+	TopicAlert        Topic = "alert"        // Risk alert, data is an Alert
+)
+
+// Event event published on the event bus
+type Event struct {
+	Topic        Topic       // Topic
+	SubaccountId string      // Subaccount ID of account events, empty otherwise
+	ExchangeId   string      // Exchange ID, empty for events not tied to an exchange
+	Time         time.Time   // Publication time
+	Data         interface{} // Event data, its type depends on the topic
+}
+
+// Connectivity data of a connectivity event
+type Connectivity struct {
+	Source    string // Component owning the connection, e.g. "event_stream" or "fill_stream"
+	Connected bool   // Whether the connection was restored, false when it was lost
+	Err       error  // Cause of the loss, nil when connected
+}
+
+// AlertKind kind of alert event
+type AlertKind string
+
+// Alert kinds
+const (
+	AlertInventoryBreach AlertKind = "inventory_breach" // Inventory limit exceeded, data is an InventoryBreach
+	AlertRiskLimit       AlertKind = "risk_limit"       // Order rejected by the pre-trade risk limits, data is the rejection error
+)
+
+// Alert data of an alert event
+type Alert struct {
+	Kind    AlertKind   // Alert kind
+	Message string      // Human readable description
+	Data    interface{} // Alert data, its type depends on the kind
+}
+
+// EventBus publishes typed events to the subscribers of their topics
+type EventBus struct {
+	mu          sync.RWMutex
+	lastId      uint64
+	subscribers map[uint64]*eventSubscriber
+}
+
+// eventSubscriber handler of the events of some topics, all topics when topics is empty
+type eventSubscriber struct {
+	topics map[Topic]bool
+	fn     func(Event)
+}
+
+// NewEventBus creates an event bus without subscribers
+func NewEventBus() *EventBus {
+	return &EventBus{subscribers: make(map[uint64]*eventSubscriber)}
+}
+
+// Subscribe registers a handler of the events of topics, all topics when none is given, and returns the function
+// removing it. Handlers run on the publishing goroutine, they must not block nor subscribe or unsubscribe.
+func (b *EventBus) Subscribe(fn func(Event), topics ...Topic) (unsubscribe func()) {
+	subscriber := &eventSubscriber{fn: fn}
+	if len(topics) > 0 {
+		subscriber.topics = make(map[Topic]bool, len(topics))
+		for _, topic := range topics {
+			subscriber.topics[topic] = true
+		}
+	}
+	b.mu.Lock()
+	b.lastId++
+	id := b.lastId
+	b.subscribers[id] = subscriber
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, id)
+			b.mu.Unlock()
+		})
+	}
+}
+
+// SubscribeChan delivers the events of topics to a channel buffering size events, dropping events while it is full.
+// The returned function unsubscribes and closes the channel.
+func (b *EventBus) SubscribeChan(size int, topics ...Topic) (<-chan Event, func()) {
+	events := make(chan Event, size)
+	unsubscribe := b.Subscribe(func(event Event) {
+		select {
+		case events <- event:
+		default:
+		}
+	}, topics...)
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			// Publish sends under the read lock, so no send is in flight once the subscriber is removed
+			unsubscribe()
+			close(events)
+		})
+	}
+}
+
+// Publish passes an event to the subscribers of its topic, Time defaults to now
+func (b *EventBus) Publish(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, subscriber := range b.subscribers {
+		if subscriber.topics == nil || subscriber.topics[event.Topic] {
+			subscriber.fn(event)
+		}
+	}
+}
+
+// Events returns the event bus the client and its components publish to, created on first use
+func (c *AntxClient) Events() *EventBus {
+	c.eventsMu.Lock()
+	defer c.eventsMu.Unlock()
+	if c.events == nil {
+		c.events = NewEventBus()
+	}
+	return c.events
+}
+
+// publish publishes an event on the event bus if it was created
+func (c *AntxClient) publish(topic Topic, subaccountId, exchangeId string, data interface{}) {
+	c.eventsMu.Lock()
+	events := c.events
+	c.eventsMu.Unlock()
+	if events != nil {
+		events.Publish(Event{Topic: topic, SubaccountId: subaccountId, ExchangeId: exchangeId, Data: data})
+	}
+}
+
+// publishMarketData publishes the ticker and depth pushes of a WebSocket message, skipping the parsing while the event
+// bus is not created
+func (c *AntxClient) publishMarketData(msg []byte) {
+	c.eventsMu.Lock()
+	events := c.events
+	c.eventsMu.Unlock()
+	if events == nil {
+		return
+	}
+	var resp query.WsRespBase
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return
+	}
+	switch {
+	case strings.HasPrefix(resp.Channel, "ticker."):
+		ticker, err := c.ParseTickerData(msg)
+		if err != nil {
+			return
+		}
+		c.publish(TopicTicker, "", ticker.ExchangeId, *ticker)
+	case strings.HasPrefix(resp.Channel, "depth."):
+		depth, err := c.ParseDepthData(msg)
+		if err != nil {
+			return
+		}
+		c.publish(TopicDepth, "", depth.ExchangeId, *depth)
+	}
+}
+
+// ConnectWebSocket establishes the connection of the Subscribe methods, see query.Client.ConnectWebSocket. Its ticker
+// and depth pushes are published on the event bus.
+func (c *AntxClient) ConnectWebSocket(messageHandler func([]byte), errorHandler func(error)) error {
+	return c.ConnectWebSocketContext(context.Background(), messageHandler, errorHandler)
+}
+
+// ConnectWebSocketContext is ConnectWebSocket with a context bounding the handshake
+func (c *AntxClient) ConnectWebSocketContext(ctx context.Context, messageHandler func([]byte), errorHandler func(error)) error {
+	return c.Client.ConnectWebSocketContext(ctx, func(msg []byte) {
+		c.publishMarketData(msg)
+		if messageHandler != nil {
+			messageHandler(msg)
+		}
+	}, errorHandler)
+}
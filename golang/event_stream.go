@@ -0,0 +1,178 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/query"
+)
+
+const (
+	// DefaultEventStreamReconnectInterval default delay before reconnecting the event stream after a disconnection
+	DefaultEventStreamReconnectInterval = 5 * time.Second
+	// DefaultEventDepthLevel default depth level of the depth channels of the event stream
+	DefaultEventDepthLevel = "15"
+)
+
+// EventStreamConfig event stream configuration
+type EventStreamConfig struct {
+	Account           bool          // Whether to publish the order, fill and position events of the client address
+	ExchangeIds       []string      // Exchanges whose ticker and depth are published
+	DepthLevel        string        // Depth level of the depth channels, defaults to DefaultEventDepthLevel
+	ReconnectInterval time.Duration // Delay before reconnecting after a disconnection, defaults to DefaultEventStreamReconnectInterval
+	ErrorHandler      func(error)   // Called on connection errors, errors are logged when nil
+}
+
+// EventStream publishes the private account events and the market data of exchanges on the event bus of the client
+// from a connection of its own, reconnecting after it drops. Fills missed during an outage are not replayed, use a
+// FillStream for gap-free fill delivery.
+type EventStream struct {
+	client *AntxClient
+	config EventStreamConfig
+
+	mu sync.Mutex
+	ws *query.WebSocketClient
+
+	disconnected chan error
+	done         chan struct{}
+	wg           sync.WaitGroup
+	once         sync.Once
+}
+
+// NewEventStream creates an event stream publishing on the event bus of the client
+func (c *AntxClient) NewEventStream(config EventStreamConfig) (*EventStream, error) {
+	if !config.Account && len(config.ExchangeIds) == 0 {
+		return nil, fmt.Errorf("account events or exchanges are required")
+	}
+	if config.DepthLevel == "" {
+		config.DepthLevel = DefaultEventDepthLevel
+	}
+	if config.ReconnectInterval <= 0 {
+		config.ReconnectInterval = DefaultEventStreamReconnectInterval
+	}
+	return &EventStream{
+		client:       c,
+		config:       config,
+		disconnected: make(chan error, 1),
+		done:         make(chan struct{}),
+	}, nil
+}
+
+// Start connects and publishes events until Stop is called
+func (s *EventStream) Start() error {
+	if err := s.connect(); err != nil {
+		return err
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			var cause error
+			select {
+			case <-s.done:
+				return
+			case cause = <-s.disconnected:
+			}
+			s.client.publish(TopicConnectivity, "", "", Connectivity{Source: "event_stream", Err: cause})
+			s.client.addCounter(MetricWebSocketReconnects, 1, map[string]string{"stream": "event_stream"})
+			s.report(fmt.Errorf("event stream disconnected, reconnecting: %w", cause))
+			for {
+				select {
+				case <-s.done:
+					return
+				case <-time.After(s.config.ReconnectInterval):
+				}
+				if err := s.connect(); err != nil {
+					s.report(err)
+					continue
+				}
+				break
+			}
+			s.client.publish(TopicConnectivity, "", "", Connectivity{Source: "event_stream", Connected: true})
+		}
+	}()
+	return nil
+}
+
+// Stop disconnects and stops publishing
+func (s *EventStream) Stop() {
+	s.once.Do(func() { close(s.done) })
+	s.mu.Lock()
+	ws := s.ws
+	s.mu.Unlock()
+	if ws != nil {
+		_ = ws.Disconnect()
+	}
+	s.wg.Wait()
+}
+
+// connect dials a new connection and subscribes to the configured channels
+func (s *EventStream) connect() error {
+	ws, err := s.client.NewWebSocket(s.handleMessage, func(err error) {
+		select {
+		case s.disconnected <- err:
+		default:
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if err := ws.Connect(); err != nil {
+		return err
+	}
+	var subscriptions []query.WsRegisterReq
+	if s.config.Account {
+		subscriptions = append(subscriptions, query.WsRegisterReq{Channel: "tradeData", ChainType: 1, ChainAddress: s.client.GetEthAddress()})
+	}
+	for _, exchangeId := range s.config.ExchangeIds {
+		subscriptions = append(subscriptions,
+			query.WsRegisterReq{Channel: fmt.Sprintf("ticker.%s", exchangeId)},
+			query.WsRegisterReq{Channel: fmt.Sprintf("depth.%s.%s", exchangeId, s.config.DepthLevel)},
+		)
+	}
+	if err := ws.Resubscribe(subscriptions); err != nil {
+		_ = ws.Disconnect()
+		return err
+	}
+	s.mu.Lock()
+	s.ws = ws
+	s.mu.Unlock()
+	return nil
+}
+
+// handleMessage publishes the events of a message by channel
+func (s *EventStream) handleMessage(msg []byte) {
+	var resp query.WsRespBase
+	if err := json.Unmarshal(msg, &resp); err != nil {
+		return
+	}
+	c := s.client
+	if resp.Channel != "tradeData" {
+		c.publishMarketData(msg)
+		return
+	}
+	event, err := c.ParseTradeDataEvent(msg)
+	if err != nil {
+		return
+	}
+	for _, order := range event.OrderList {
+		c.publish(TopicOrder, order.SubaccountId, order.ExchangeId, order)
+	}
+	for _, fill := range event.OrderFillTransactionList {
+		c.publish(TopicFill, fill.SubaccountId, fill.ExchangeId, fill)
+	}
+	for _, position := range event.PositionList {
+		c.publish(TopicPosition, position.SubaccountId, position.ExchangeId, position)
+	}
+}
+
+// report passes an error to the configured handler
+func (s *EventStream) report(err error) {
+	if s.config.ErrorHandler != nil {
+		s.config.ErrorHandler(err)
+		return
+	}
+	s.client.Logger().Errorf("event stream: %v", err)
+}
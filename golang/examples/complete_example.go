@@ -9,6 +9,7 @@ import (
 
 	sdk "github.com/antxprotocol/antx-sdk-golang"
 	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/query"
 	"github.com/antxprotocol/antx-sdk-golang/types"
 	"github.com/shopspring/decimal"
 )
@@ -23,6 +24,7 @@ var (
 	ethPrivateKey   = ""
 	agentPrivateKey = ""
 	ethAddress      = ""
+	appToken        = "" // X-App-Token of gateways behind a WAF, from APP_TOKEN
 
 	// Example default parameters
 	defaultExchangeId = "200001"
@@ -38,20 +40,24 @@ func init() {
 	if key, exist := os.LookupEnv("ETH_ADDRESS"); exist {
 		ethAddress = key
 	}
+	if token, exist := os.LookupEnv("APP_TOKEN"); exist {
+		appToken = token
+	}
 }
 
 func main() {
 	// Create SDK client (using top-level unified variables)
-	client, err := sdk.NewAntxClient(sdk.Config{
-		GatewayHost:     gatewayURL,
-		ChainID:         chainID,
-		EthPrivateKey:   ethPrivateKey,
-		AgentPrivateKey: agentPrivateKey,
-	})
+	client, err := sdk.NewAntxClient(
+		sdk.WithGateway(gatewayURL),
+		sdk.WithWebSocketURL(wsURL),
+		sdk.WithChainID(chainID),
+		sdk.WithEthPrivateKey(ethPrivateKey),
+		sdk.WithAgentPrivateKey(agentPrivateKey),
+		sdk.WithHeaders(map[string]string{query.HeaderAppToken: appToken}),
+	)
 	if err != nil {
 		log.Fatalf("Failed to create client: %v", err)
 	}
-	client.SetGateway(gatewayURL, wsURL)
 
 	fmt.Println("=== Antx SDK Complete Example ===")
 	fmt.Printf("SDK client created, base URL: %s\n", gatewayURL)
@@ -238,7 +244,7 @@ func demoTradingFunctions(client *sdk.AntxClient) {
 
 	// Dynamically get a valid subaccount ID
 	testSubaccountId := ""
-	subList, err := client.GetSubaccountList(1, ethAddress, agentAddress)
+	subList, err := client.Subaccounts()
 	if err != nil {
 		log.Printf("Failed to get subaccount list: %v", err)
 		fmt.Println("Skipping trading functions demo")
@@ -266,7 +272,7 @@ func demoTradingFunctions(client *sdk.AntxClient) {
 
 	// 4.1 Create limit buy order
 	fmt.Println("\n4.1 Creating limit buy order:")
-	createOrderReq := types.CreateOrderParam{
+	createOrderReq := sdk.CreateOrderParam{
 		SubaccountId:      subaccountIdUint,
 		ExchangeId:        exchangeIdUint,
 		MarginMode:        1, // Full margin mode
@@ -304,7 +310,7 @@ func demoTradingFunctions(client *sdk.AntxClient) {
 
 	// 4.2 Create market sell order
 	fmt.Println("\n4.2 Creating market sell order:")
-	marketOrderReq := types.CreateOrderParam{
+	marketOrderReq := sdk.CreateOrderParam{
 		SubaccountId:      subaccountIdUint,
 		ExchangeId:        exchangeIdUint,
 		MarginMode:        1, // Full margin mode
@@ -356,13 +362,13 @@ func demoTradingFunctions(client *sdk.AntxClient) {
 
 	// 4.4 Create batch orders
 	fmt.Println("\n4.4 Creating batch orders:")
-	batchOrderReq := types.CreateOrderBatchParam{
+	batchOrderReq := sdk.CreateOrderBatchParam{
 		AgentAddress: client.GetAgentAddress(),
 		SubaccountId: subaccountIdUint,
 		ExchangeId:   exchangeIdUint,
 		MarginMode:   1,
 		Leverage:     1,
-		CreateOrderParam: []*types.CreateOrderBatchDetail{
+		CreateOrderParam: []*sdk.CreateOrderBatchDetail{
 			{
 				IsBuy:             true,
 				PriceScale:        2,
@@ -418,8 +424,7 @@ func demoTradingQueries(client *sdk.AntxClient) {
 
 	// Re-fetch subaccount (if not obtained in trading functions demo)
 	var testSubaccountId string
-	agentAddress := client.GetAgentAddress()
-	subList, err := client.GetSubaccountList(1, ethAddress, agentAddress)
+	subList, err := client.Subaccounts()
 	if err != nil {
 		log.Printf("Failed to get subaccount list, skipping subsequent trading queries: %v", err)
 	} else if len(subList) == 0 {
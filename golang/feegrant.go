@@ -0,0 +1,41 @@
+package sdk
+
+import (
+	"fmt"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// GetFeeGrant gets the fee allowance of a granter to a grantee
+func (c *AntxClient) GetFeeGrant(granter, grantee string) (*types.FeeGrant, error) {
+	var result types.GetFeeGrantResp
+	path := fmt.Sprintf("/cosmos/feegrant/v1beta1/allowance/%s/%s", granter, grantee)
+	if err := c.nodeGet(path, nil, &result, &result.NodeResp); err != nil {
+		return nil, err
+	}
+	return &result.Allowance, nil
+}
+
+// GetFeeGrants gets the fee allowances granted to a grantee, e.g. the agent address
+func (c *AntxClient) GetFeeGrants(grantee string) ([]types.FeeGrant, error) {
+	return c.getFeeGrantList("/cosmos/feegrant/v1beta1/allowances/" + grantee)
+}
+
+// GetIssuedFeeGrants gets the fee allowances issued by a granter
+func (c *AntxClient) GetIssuedFeeGrants(granter string) ([]types.FeeGrant, error) {
+	return c.getFeeGrantList("/cosmos/feegrant/v1beta1/issued/" + granter)
+}
+
+// getFeeGrantList reads all pages of a fee allowance list
+func (c *AntxClient) getFeeGrantList(path string) ([]types.FeeGrant, error) {
+	var grants []types.FeeGrant
+	err := nodeGetPages(func(params map[string]string) (*types.NodePageResp, error) {
+		var result types.GetFeeGrantListResp
+		if err := c.nodeGet(path, params, &result, &result.NodeResp); err != nil {
+			return nil, err
+		}
+		grants = append(grants, result.Allowances...)
+		return result.Pagination, nil
+	})
+	return grants, err
+}
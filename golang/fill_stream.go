@@ -0,0 +1,336 @@
+package sdk
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/query"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+const (
+	// DefaultFillStreamReconnectInterval default delay before reconnecting the fill stream after a disconnection
+	DefaultFillStreamReconnectInterval = 5 * time.Second
+	// fillStreamSeenWindow time a delivered fill is remembered to drop it when both the stream and the replay carry it
+	fillStreamSeenWindow = 10 * time.Minute
+	// fillStreamClockSkew tolerated skew between the client clock and the fill creation times
+	fillStreamClockSkew = time.Minute
+)
+
+// FillStreamConfig fill stream configuration
+type FillStreamConfig struct {
+	SubaccountId      string                           // Subaccount whose fills are streamed
+	Since             uint64                           // Creation time from which fills are replayed on Start, e.g. LastFillTime persisted before a restart, 0 to start with the fills from now on, unit: milliseconds
+	OnFill            func(types.OrderFillTransaction) // Called with each fill once, oldest first, never concurrently, on a goroutine of the stream so it may call LastFillTime
+	ReconnectInterval time.Duration                    // Delay before reconnecting after a disconnection, defaults to DefaultFillStreamReconnectInterval
+	ErrorHandler      func(error)                      // Called on connection and replay errors, errors are logged when nil
+}
+
+// FillStream delivers the fills of a subaccount from the private WebSocket on a connection of its own. After the
+// connection drops it reconnects and replays the fills created since the last delivered one from the fill history
+// before resuming live delivery, so no fill is silently lost during the outage.
+type FillStream struct {
+	client *AntxClient
+	config FillStreamConfig
+
+	mu         sync.Mutex
+	ws         *query.WebSocketClient
+	recovering bool                         // whether live fills are held back until the replay is delivered
+	pending    []types.OrderFillTransaction // live fills received during the replay
+	seen       map[string]uint64            // creation times of the recently delivered fills by fill ID
+	lastTime   uint64                       // creation time of the last delivered fill, or the replay start
+	queue      []types.OrderFillTransaction // delivered fills waiting for the handler
+	handled    uint64                       // creation time of the last fill the handler returned from, or the replay start
+
+	queued       chan struct{}
+	disconnected chan struct{}
+	done         chan struct{}
+	wg           sync.WaitGroup
+	once         sync.Once
+}
+
+// NewFillStream creates a fill stream of a subaccount
+func (c *AntxClient) NewFillStream(config FillStreamConfig) (*FillStream, error) {
+	if config.SubaccountId == "" {
+		return nil, fmt.Errorf("subaccount ID is required")
+	}
+	if config.OnFill == nil {
+		return nil, fmt.Errorf("fill handler is required")
+	}
+	if config.ReconnectInterval <= 0 {
+		config.ReconnectInterval = DefaultFillStreamReconnectInterval
+	}
+	return &FillStream{
+		client:       c,
+		config:       config,
+		seen:         make(map[string]uint64),
+		queued:       make(chan struct{}, 1),
+		disconnected: make(chan struct{}, 1),
+		done:         make(chan struct{}),
+	}, nil
+}
+
+// Start connects, replays the fills since the configured time and delivers fills until Stop is called
+func (s *FillStream) Start() error {
+	s.mu.Lock()
+	s.lastTime = s.config.Since
+	if s.lastTime == 0 {
+		s.lastTime = uint64(time.Now().Add(-fillStreamClockSkew).UnixMilli())
+	}
+	s.handled = s.lastTime
+	// Hold the live fills until the replay, if any, is delivered
+	s.recovering = s.config.Since > 0
+	s.mu.Unlock()
+
+	if err := s.connect(); err != nil {
+		return err
+	}
+	s.wg.Add(2)
+	go s.handleFills()
+	go func() {
+		defer s.wg.Done()
+		replay := s.config.Since > 0
+		for {
+			if replay {
+				if err := s.replay(); err != nil {
+					s.report(err)
+					if !s.wait() {
+						return
+					}
+					continue
+				}
+				replay = false
+			}
+			select {
+			case <-s.done:
+				return
+			case <-s.disconnected:
+			}
+			s.client.publish(TopicConnectivity, s.config.SubaccountId, "", Connectivity{Source: "fill_stream"})
+			s.client.addCounter(MetricWebSocketReconnects, 1, map[string]string{"stream": "fill_stream"})
+			s.report(fmt.Errorf("private stream disconnected, reconnecting"))
+			s.mu.Lock()
+			s.recovering = true
+			s.mu.Unlock()
+			for {
+				if !s.wait() {
+					return
+				}
+				if err := s.connect(); err != nil {
+					s.report(err)
+					continue
+				}
+				break
+			}
+			s.client.publish(TopicConnectivity, s.config.SubaccountId, "", Connectivity{Source: "fill_stream", Connected: true})
+			replay = true
+		}
+	}()
+	return nil
+}
+
+// Stop disconnects and stops delivering fills, the fills queued for the handler are delivered first
+func (s *FillStream) Stop() {
+	s.once.Do(func() { close(s.done) })
+	s.mu.Lock()
+	ws := s.ws
+	s.mu.Unlock()
+	if ws != nil {
+		_ = ws.Disconnect()
+	}
+	s.wg.Wait()
+}
+
+// LastFillTime returns the creation time of the last fill the handler returned from, the Since of a stream resuming
+// after a restart
+func (s *FillStream) LastFillTime() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.handled
+}
+
+// connect dials a new connection and subscribes to the private events
+func (s *FillStream) connect() error {
+	ws, err := s.client.NewWebSocket(s.handleMessage, func(error) {
+		select {
+		case s.disconnected <- struct{}{}:
+		default:
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if err := ws.Connect(); err != nil {
+		return err
+	}
+	subscription := query.WsRegisterReq{Channel: "tradeData", ChainType: 1, ChainAddress: s.client.GetEthAddress()}
+	if err := ws.Resubscribe([]query.WsRegisterReq{subscription}); err != nil {
+		_ = ws.Disconnect()
+		return err
+	}
+	s.mu.Lock()
+	s.ws = ws
+	s.mu.Unlock()
+	return nil
+}
+
+// replay delivers the fills created since the last delivered fill, then the live fills held back meanwhile
+func (s *FillStream) replay() error {
+	s.mu.Lock()
+	since := s.lastTime
+	s.mu.Unlock()
+
+	req := types.GetHistoryOrderFillTransactionReq{
+		SubaccountId:                    s.config.SubaccountId,
+		Size:                            100,
+		FilterStartCreatedTimeInclusive: since,
+	}
+	fills, err := types.FetchAllPages(req.Size, func(cursor types.Cursor) ([]types.OrderFillTransaction, types.IndexerPageOffsetData, error) {
+		req.PageOffsetDataCreatedTime, req.PageOffsetDataItemId = cursor.CreatedTime, cursor.ItemId
+		resp, err := s.client.GetHistoryOrderFillTransaction(req)
+		if err != nil {
+			return nil, types.IndexerPageOffsetData{}, err
+		}
+		return resp.Data.OrderFillTransactionList, resp.Data.PageOffsetData, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to replay fills since %d: %w", since, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fills = append(fills, s.pending...)
+	sortFills(fills)
+	for _, fill := range fills {
+		s.deliver(fill)
+	}
+	s.pending = nil
+	s.recovering = false
+	return nil
+}
+
+// handleMessage delivers the fills of a private event, or holds them back during a replay
+func (s *FillStream) handleMessage(msg []byte) {
+	event, err := s.client.ParseTradeDataEvent(msg)
+	if err != nil {
+		// Subscription acknowledgements and other channels carry no trade data
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, fill := range event.OrderFillTransactionList {
+		if fill.SubaccountId != s.config.SubaccountId {
+			continue
+		}
+		if s.recovering {
+			s.pending = append(s.pending, fill)
+			continue
+		}
+		s.deliver(fill)
+	}
+}
+
+// deliver queues a fill for the handler unless it was delivered before, must be called with the lock held
+func (s *FillStream) deliver(fill types.OrderFillTransaction) {
+	if _, ok := s.seen[fill.Id]; ok {
+		return
+	}
+	s.seen[fill.Id] = fill.CreatedTime
+	if fill.CreatedTime > s.lastTime {
+		s.lastTime = fill.CreatedTime
+	}
+	if window := uint64(fillStreamSeenWindow.Milliseconds()); s.lastTime > window {
+		cutoff := s.lastTime - window
+		for id, createdTime := range s.seen {
+			if createdTime < cutoff {
+				delete(s.seen, id)
+			}
+		}
+	}
+	s.queue = append(s.queue, fill)
+	select {
+	case s.queued <- struct{}{}:
+	default:
+	}
+}
+
+// handleFills passes the queued fills to the handler outside the lock, so a slow handler does not block the connection
+// nor the replay, until the stream is stopped
+func (s *FillStream) handleFills() {
+	defer s.wg.Done()
+	for {
+		select {
+		case <-s.queued:
+			s.flush()
+		case <-s.done:
+			s.flush()
+			return
+		}
+	}
+}
+
+// flush passes the queued fills to the handler, in order
+func (s *FillStream) flush() {
+	for {
+		s.mu.Lock()
+		fills := s.queue
+		s.queue = nil
+		s.mu.Unlock()
+		if len(fills) == 0 {
+			return
+		}
+		for _, fill := range fills {
+			s.config.OnFill(fill)
+			s.mu.Lock()
+			if fill.CreatedTime > s.handled {
+				s.handled = fill.CreatedTime
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// wait sleeps for the reconnect interval, it returns false when the stream is stopped
+func (s *FillStream) wait() bool {
+	select {
+	case <-s.done:
+		return false
+	case <-time.After(s.config.ReconnectInterval):
+		return true
+	}
+}
+
+// report passes an error to the configured handler
+func (s *FillStream) report(err error) {
+	if s.config.ErrorHandler != nil {
+		s.config.ErrorHandler(err)
+		return
+	}
+	s.client.Logger().Errorf("fill stream: subaccount %s: %v", s.config.SubaccountId, err)
+}
+
+// sortFills orders fills by creation time, then by chain position
+func sortFills(fills []types.OrderFillTransaction) {
+	sort.SliceStable(fills, func(i, j int) bool {
+		a, b := &fills[i], &fills[j]
+		if a.CreatedTime != b.CreatedTime {
+			return a.CreatedTime < b.CreatedTime
+		}
+		if a.BlockHeight != b.BlockHeight {
+			return a.BlockHeight < b.BlockHeight
+		}
+		if a.TransactionIndex != b.TransactionIndex {
+			return parseIndex(a.TransactionIndex) < parseIndex(b.TransactionIndex)
+		}
+		return parseIndex(a.EventIndex) < parseIndex(b.EventIndex)
+	})
+}
+
+// parseIndex parses a transaction or event index, 0 when it is not a number
+func parseIndex(index string) uint64 {
+	n, _ := strconv.ParseUint(index, 10, 64)
+	return n
+}
@@ -0,0 +1,235 @@
+package sdk
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// FundingSchedule funding settlement schedule of a perpetual exchange
+type FundingSchedule struct {
+	ExchangeId string        // Exchange ID
+	Interval   time.Duration // Funding settlement interval
+
+	mu     sync.RWMutex
+	anchor time.Time // Settlement time reported by the gateway, zero means aligned to interval boundaries
+}
+
+// NewFundingSchedule creates a funding schedule from exchange information
+func NewFundingSchedule(exchange *types.Exchange) (*FundingSchedule, error) {
+	if exchange.Perpetual.FundingRateIntervalMinutes == 0 {
+		return nil, fmt.Errorf("exchange %s has no funding rate interval", exchange.Id)
+	}
+	return &FundingSchedule{
+		ExchangeId: exchange.Id,
+		Interval:   time.Duration(exchange.Perpetual.FundingRateIntervalMinutes) * time.Minute,
+	}, nil
+}
+
+// UpdateFromTicker anchors the schedule to the next funding time reported in ticker data
+func (s *FundingSchedule) UpdateFromTicker(ticker *types.TickerData) error {
+	if ticker.NextFundingTime == "" || ticker.NextFundingTime == "0" {
+		return nil
+	}
+	nextFundingTime, err := strconv.ParseInt(ticker.NextFundingTime, 10, 64)
+	if err != nil {
+		return fmt.Errorf("failed to parse next funding time: %w", err)
+	}
+
+	s.mu.Lock()
+	s.anchor = time.UnixMilli(nextFundingTime)
+	s.mu.Unlock()
+	return nil
+}
+
+// NextFundingAt returns the first funding settlement time strictly after now
+func (s *FundingSchedule) NextFundingAt(now time.Time) time.Time {
+	s.mu.RLock()
+	anchor := s.anchor
+	s.mu.RUnlock()
+	if anchor.IsZero() {
+		anchor = time.Unix(0, 0)
+	}
+
+	// Floor division, so that an anchor in the future is walked back as well
+	elapsed := now.Sub(anchor)
+	n := elapsed / s.Interval
+	if elapsed < 0 && elapsed%s.Interval != 0 {
+		n--
+	}
+	return anchor.Add((n + 1) * s.Interval)
+}
+
+// TimeToFunding returns the duration until the next funding settlement
+func (s *FundingSchedule) TimeToFunding() time.Duration {
+	now := time.Now()
+	return s.NextFundingAt(now).Sub(now)
+}
+
+// Watch calls fn lead before each funding settlement until the returned stop function is called
+func (s *FundingSchedule) Watch(lead time.Duration, fn func(exchangeId string, fundingTime time.Time)) (stop func()) {
+	done := make(chan struct{})
+	var once sync.Once
+
+	go func() {
+		var fired time.Time
+		for {
+			fundingTime := s.NextFundingAt(time.Now())
+			// Still inside the lead window of a settlement that was already notified
+			if fundingTime.Equal(fired) {
+				fundingTime = s.NextFundingAt(fundingTime)
+			}
+
+			timer := time.NewTimer(time.Until(fundingTime.Add(-lead)))
+			select {
+			case <-done:
+				timer.Stop()
+				return
+			case <-timer.C:
+				fired = fundingTime
+				fn(s.ExchangeId, fundingTime)
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// GetFundingSchedule gets the funding settlement schedule of an exchange, anchored to the next funding time of its
+// ticker
+func (c *AntxClient) GetFundingSchedule(exchangeId string) (*FundingSchedule, error) {
+	schedule, _, err := c.fundingSchedule(exchangeId)
+	return schedule, err
+}
+
+// fundingSchedule gets the funding settlement schedule of an exchange anchored to its ticker, and the ticker
+func (c *AntxClient) fundingSchedule(exchangeId string) (*FundingSchedule, *types.TickerData, error) {
+	exchange, err := c.GetExchange(exchangeId)
+	if err != nil {
+		return nil, nil, err
+	}
+	schedule, err := NewFundingSchedule(exchange)
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := c.GetTicker(types.GetTickerReq{ExchangeId: exchangeId})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(resp.Data.TickerList) == 0 {
+		return nil, nil, fmt.Errorf("no ticker for exchange %s", exchangeId)
+	}
+	ticker := &resp.Data.TickerList[0]
+	if err := schedule.UpdateFromTicker(ticker); err != nil {
+		return nil, nil, err
+	}
+	return schedule, ticker, nil
+}
+
+// NextFundingAt returns the next funding settlement time of an exchange
+func (c *AntxClient) NextFundingAt(exchangeId string) (time.Time, error) {
+	schedule, err := c.GetFundingSchedule(exchangeId)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.NextFundingAt(time.Now()), nil
+}
+
+// TimeToFunding returns the duration until the next funding settlement of an exchange
+func (c *AntxClient) TimeToFunding(exchangeId string) (time.Duration, error) {
+	schedule, err := c.GetFundingSchedule(exchangeId)
+	if err != nil {
+		return 0, err
+	}
+	return schedule.TimeToFunding(), nil
+}
+
+// OnFundingSettlement calls fn lead before each funding settlement of an exchange until the returned stop function is called
+func (c *AntxClient) OnFundingSettlement(exchangeId string, lead time.Duration, fn func(exchangeId string, fundingTime time.Time)) (func(), error) {
+	schedule, err := c.GetFundingSchedule(exchangeId)
+	if err != nil {
+		return nil, err
+	}
+	return schedule.Watch(lead, fn), nil
+}
+
+// FundingSettlement projected funding of a position at one settlement
+type FundingSettlement struct {
+	FundingTime time.Time       // Settlement time
+	Payment     decimal.Decimal // Funding received, negative when paid
+	Cumulative  decimal.Decimal // Funding received from the first projected settlement through this one
+}
+
+// FundingProjection projected funding of an open position over the next settlements
+type FundingProjection struct {
+	ExchangeId  string              // Exchange ID
+	OpenSize    decimal.Decimal     // Position size, positive for long, negative for short
+	Price       decimal.Decimal     // Price the position value is taken at
+	Rate        decimal.Decimal     // Funding rate assumed at every settlement
+	Settlements []FundingSettlement // Projected settlements, earliest first
+	Total       decimal.Decimal     // Funding received over all settlements, negative when paid
+}
+
+// ProjectFunding projects the funding of a position over the next intervals settlements of its schedule after now,
+// assuming a constant position, price and funding rate. Longs pay a positive rate to shorts: each settlement moves
+// openSize * price * rate from longs to shorts.
+func ProjectFunding(position *types.PerpetualPosition, price, predictedRate decimal.Decimal, schedule *FundingSchedule, intervals int, now time.Time) (*FundingProjection, error) {
+	size, err := parseOptionalDecimal(position.OpenSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse position of exchange %s: %w", position.ExchangeId, err)
+	}
+	projection := &FundingProjection{
+		ExchangeId: position.ExchangeId,
+		OpenSize:   size,
+		Price:      price,
+		Rate:       predictedRate,
+	}
+	payment := size.Mul(price).Mul(predictedRate).Neg()
+	fundingTime := now
+	for i := 0; i < intervals; i++ {
+		fundingTime = schedule.NextFundingAt(fundingTime)
+		projection.Total = projection.Total.Add(payment)
+		projection.Settlements = append(projection.Settlements, FundingSettlement{
+			FundingTime: fundingTime,
+			Payment:     payment,
+			Cumulative:  projection.Total,
+		})
+	}
+	return projection, nil
+}
+
+// ProjectPositionFunding projects the funding of every open position of a subaccount over the next intervals
+// settlements, at the mark price and the current funding rate of the ticker of each exchange
+func (c *AntxClient) ProjectPositionFunding(subaccountId string, intervals int) ([]FundingProjection, error) {
+	asset, err := c.GetPerpetualAccountAsset(types.GetPerpetualAccountAssetReq{SubaccountId: subaccountId})
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	projections := make([]FundingProjection, 0, len(asset.Data.PositionList))
+	for i := range asset.Data.PositionList {
+		position := &asset.Data.PositionList[i]
+		schedule, ticker, err := c.fundingSchedule(position.ExchangeId)
+		if err != nil {
+			return nil, err
+		}
+		var values [2]decimal.Decimal
+		for j, value := range []string{ticker.MarkPrice, ticker.FundingRate} {
+			if values[j], err = parseOptionalDecimal(value); err != nil {
+				return nil, fmt.Errorf("failed to parse ticker of exchange %s: %w", position.ExchangeId, err)
+			}
+		}
+		projection, err := ProjectFunding(position, values[0], values[1], schedule, intervals, now)
+		if err != nil {
+			return nil, err
+		}
+		projections = append(projections, *projection)
+	}
+	return projections, nil
+}
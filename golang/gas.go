@@ -0,0 +1,29 @@
+package sdk
+
+import (
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/sign"
+)
+
+// DefaultGasLimits default gas limit by message type, message types missing from the table use sign.DefaultGasLimit
+var DefaultGasLimits = map[string]uint64{
+	constants.MsgCreateOrderTypeURL:           200000,
+	constants.MsgCreateOrderBatchTypeURL:      1000000,
+	constants.MsgCancelOrderTypeURL:           150000,
+	constants.MsgCancelOrderByClientIdTypeURL: 150000,
+	constants.MsgCancelAllOrderTypeURL:        600000,
+	constants.MsgCloseAllPositionTypeURL:      1000000,
+	constants.MsgBindAgentTypeURL:             200000,
+	constants.MsgUnbindAgentTypeURL:           200000,
+}
+
+// GasLimit returns the gas limit of a message type: Config.GasLimits, then DefaultGasLimits, then sign.DefaultGasLimit
+func (c *AntxClient) GasLimit(typeURL string) uint64 {
+	if gasLimit, ok := c.gasLimits[typeURL]; ok && gasLimit > 0 {
+		return gasLimit
+	}
+	if gasLimit, ok := DefaultGasLimits[typeURL]; ok && gasLimit > 0 {
+		return gasLimit
+	}
+	return sign.DefaultGasLimit
+}
@@ -0,0 +1,174 @@
+package sdk
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+const (
+	// DefaultHistoryWindow default length of the windows a history range is split into
+	DefaultHistoryWindow = 24 * time.Hour
+	// historyPageSize records per page of the history endpoints, their maximum
+	historyPageSize = 100
+)
+
+// TimeWindow time range [Start, End)
+type TimeWindow struct {
+	Start time.Time // Inclusive start
+	End   time.Time // Exclusive end
+}
+
+// HistoryRange splitting of a long history query into windows
+type HistoryRange struct {
+	Window      time.Duration // Length of the windows, defaults to DefaultHistoryWindow
+	Overlap     time.Duration // Time each window also covers before its start, records seen in two windows are returned once
+	Parallelism int           // Windows fetched at once, defaults to 1
+}
+
+// SplitTimeRange splits [start, end) into consecutive windows of at most window, the last one ending at end
+func SplitTimeRange(start, end time.Time, window time.Duration) []TimeWindow {
+	if window <= 0 {
+		window = DefaultHistoryWindow
+	}
+	var windows []TimeWindow
+	for from := start; from.Before(end); from = from.Add(window) {
+		to := from.Add(window)
+		if to.After(end) {
+			to = end
+		}
+		windows = append(windows, TimeWindow{Start: from, End: to})
+	}
+	return windows
+}
+
+// FetchHistoryRange fetches [start, end) window by window, in parallel up to r.Parallelism, and stitches the records
+// oldest first, keeping the first record of each ID. fetch returns all the records of a window, paging through it.
+func FetchHistoryRange[T any](start, end time.Time, r HistoryRange, fetch func(TimeWindow) ([]T, error), id func(T) string, createdTime func(T) uint64) ([]T, error) {
+	windows := SplitTimeRange(start, end, r.Window)
+	for i := range windows {
+		if windows[i].Start = windows[i].Start.Add(-r.Overlap); windows[i].Start.Before(start) {
+			windows[i].Start = start
+		}
+	}
+	parallelism := r.Parallelism
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+
+	results := make([][]T, len(windows))
+	errs := make([]error, len(windows))
+	slots := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for i, window := range windows {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, window TimeWindow) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i], errs[i] = fetch(window)
+		}(i, window)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch window %s - %s: %w", windows[i].Start.Format(time.RFC3339), windows[i].End.Format(time.RFC3339), err)
+		}
+	}
+
+	var records []T
+	seen := make(map[string]bool)
+	for _, result := range results {
+		for _, record := range result {
+			if key := id(record); !seen[key] {
+				seen[key] = true
+				records = append(records, record)
+			}
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool { return createdTime(records[i]) < createdTime(records[j]) })
+	return records, nil
+}
+
+// GetHistoryOrderFillTransactionRange gets the fills matching req created in [start, end), splitting the range into
+// windows, the page and time filters of req are ignored
+func (c *AntxClient) GetHistoryOrderFillTransactionRange(req types.GetHistoryOrderFillTransactionReq, start, end time.Time, r HistoryRange) ([]types.OrderFillTransaction, error) {
+	return FetchHistoryRange(start, end, r, func(window TimeWindow) ([]types.OrderFillTransaction, error) {
+		req := req
+		req.Size = historyPageSize
+		req.FilterStartCreatedTimeInclusive = uint64(window.Start.UnixMilli())
+		req.FilterEndCreatedTimeExclusive = uint64(window.End.UnixMilli())
+		return types.FetchAllPages(req.Size, func(cursor types.Cursor) ([]types.OrderFillTransaction, types.IndexerPageOffsetData, error) {
+			req.PageOffsetDataCreatedTime, req.PageOffsetDataItemId = cursor.CreatedTime, cursor.ItemId
+			resp, err := c.GetHistoryOrderFillTransaction(req)
+			if err != nil {
+				return nil, types.IndexerPageOffsetData{}, err
+			}
+			return resp.Data.OrderFillTransactionList, resp.Data.PageOffsetData, nil
+		})
+	}, func(fill types.OrderFillTransaction) string { return fill.Id },
+		func(fill types.OrderFillTransaction) uint64 { return fill.CreatedTime })
+}
+
+// GetCollateralTransactionRange gets the collateral transactions matching req created in [start, end), splitting the
+// range into windows, the page and time filters of req are ignored
+func (c *AntxClient) GetCollateralTransactionRange(req types.GetCollateralTransactionReq, start, end time.Time, r HistoryRange) ([]types.CollateralTransaction, error) {
+	return FetchHistoryRange(start, end, r, func(window TimeWindow) ([]types.CollateralTransaction, error) {
+		req := req
+		req.Size = historyPageSize
+		req.FilterStartCreatedTimeInclusive = uint64(window.Start.UnixMilli())
+		req.FilterEndCreatedTimeExclusive = uint64(window.End.UnixMilli())
+		return types.FetchAllPages(req.Size, func(cursor types.Cursor) ([]types.CollateralTransaction, types.IndexerPageOffsetData, error) {
+			req.PageOffsetDataCreatedTime, req.PageOffsetDataItemId = cursor.CreatedTime, cursor.ItemId
+			resp, err := c.GetCollateralTransaction(req)
+			if err != nil {
+				return nil, types.IndexerPageOffsetData{}, err
+			}
+			return resp.Data.CollateralTransactionList, resp.Data.PageOffsetData, nil
+		})
+	}, func(tx types.CollateralTransaction) string { return tx.Id },
+		func(tx types.CollateralTransaction) uint64 { return tx.CreatedTime })
+}
+
+// GetPositionTransactionRange gets the position transactions matching req created in [start, end), splitting the
+// range into windows, the page and time filters of req are ignored
+func (c *AntxClient) GetPositionTransactionRange(req types.GetPositionTransactionReq, start, end time.Time, r HistoryRange) ([]types.PerpetualPositionTransaction, error) {
+	return FetchHistoryRange(start, end, r, func(window TimeWindow) ([]types.PerpetualPositionTransaction, error) {
+		req := req
+		req.Size = historyPageSize
+		req.FilterStartCreatedTimeInclusive = uint64(window.Start.UnixMilli())
+		req.FilterEndCreatedTimeExclusive = uint64(window.End.UnixMilli())
+		return types.FetchAllPages(req.Size, func(cursor types.Cursor) ([]types.PerpetualPositionTransaction, types.IndexerPageOffsetData, error) {
+			req.PageOffsetDataCreatedTime, req.PageOffsetDataItemId = cursor.CreatedTime, cursor.ItemId
+			resp, err := c.GetPositionTransaction(req)
+			if err != nil {
+				return nil, types.IndexerPageOffsetData{}, err
+			}
+			return resp.Data.PositionTransactionList, resp.Data.PageOffsetData, nil
+		})
+	}, func(tx types.PerpetualPositionTransaction) string { return tx.Id },
+		func(tx types.PerpetualPositionTransaction) uint64 { return tx.CreatedTime })
+}
+
+// GetHistoryOrderRange gets the history orders matching req created in [start, end), splitting the range into
+// windows, the page and time filters of req are ignored
+func (c *AntxClient) GetHistoryOrderRange(req types.GetHistoryOrderReq, start, end time.Time, r HistoryRange) ([]types.Order, error) {
+	return FetchHistoryRange(start, end, r, func(window TimeWindow) ([]types.Order, error) {
+		req := req
+		req.Size = historyPageSize
+		req.FilterStartCreatedTimeInclusive = uint64(window.Start.UnixMilli())
+		req.FilterEndCreatedTimeExclusive = uint64(window.End.UnixMilli())
+		return types.FetchAllPages(req.Size, func(cursor types.Cursor) ([]types.Order, types.IndexerPageOffsetData, error) {
+			req.PageOffsetDataCreatedTime, req.PageOffsetDataItemId = cursor.CreatedTime, cursor.ItemId
+			resp, err := c.GetHistoryOrder(req)
+			if err != nil {
+				return nil, types.IndexerPageOffsetData{}, err
+			}
+			return resp.Data.OrderList, resp.Data.PageOffsetData, nil
+		})
+	}, func(order types.Order) string { return order.Id },
+		func(order types.Order) uint64 { return order.CreatedTime })
+}
@@ -0,0 +1,188 @@
+package sdk
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+const (
+	// DefaultIndexerLagInterval default interval between indexer lag checks
+	DefaultIndexerLagInterval = 5 * time.Second
+	// DefaultMaxIndexerTimeLag default age of the last handled block above which the indexer is stale
+	DefaultMaxIndexerTimeLag = 30 * time.Second
+)
+
+// ChainHead latest block of the chain
+type ChainHead struct {
+	Height uint64 // Block height
+	Time   uint64 // Block time, unit: milliseconds
+}
+
+// ChainHeadSource returns the latest block of the chain
+type ChainHeadSource func() (ChainHead, error)
+
+// IndexerLag staleness of the indexer at one check
+type IndexerLag struct {
+	HandledBlockHeight uint64        // Last block height handled by the indexer
+	HandledBlockTime   uint64        // Last block time handled by the indexer, unit: milliseconds
+	ChainBlockHeight   uint64        // Latest chain block height, 0 when no chain head source is configured
+	Blocks             uint64        // Blocks the indexer is behind the chain
+	Time               time.Duration // Age of the last handled block
+	Stale              bool          // Whether the lag exceeds a threshold
+	CheckedAt          time.Time     // Check time
+}
+
+// IndexerLagConfig indexer lag monitor configuration
+type IndexerLagConfig struct {
+	SubaccountId string           // Subaccount whose account asset is polled
+	Interval     time.Duration    // Interval between checks, defaults to DefaultIndexerLagInterval
+	MaxBlockLag  uint64           // Blocks behind the chain head above which the indexer is stale, zero disables, requires ChainHead
+	MaxTimeLag   time.Duration    // Age of the last handled block above which the indexer is stale, defaults to DefaultMaxIndexerTimeLag
+	ChainHead    ChainHeadSource  // Latest chain block source, the age is measured against the local clock when nil
+	OnStale      func(IndexerLag) // Called when the indexer becomes stale
+	OnRecover    func(IndexerLag) // Called when the indexer catches up again
+	ErrorHandler func(error)      // Called on check errors, errors are logged when nil
+}
+
+// IndexerLagMonitor polls the last handled block of the indexer and flags it stale when it falls behind the chain,
+// so that balances and positions read from the gateway are not trusted while it catches up
+type IndexerLagMonitor struct {
+	client *AntxClient
+	config IndexerLagConfig
+
+	mu  sync.Mutex
+	lag IndexerLag
+
+	done chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// NewIndexerLagMonitor creates an indexer lag monitor
+func (c *AntxClient) NewIndexerLagMonitor(config IndexerLagConfig) (*IndexerLagMonitor, error) {
+	if config.SubaccountId == "" {
+		return nil, fmt.Errorf("subaccount ID is required")
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultIndexerLagInterval
+	}
+	if config.MaxTimeLag <= 0 {
+		config.MaxTimeLag = DefaultMaxIndexerTimeLag
+	}
+	if config.MaxBlockLag > 0 && config.ChainHead == nil {
+		return nil, fmt.Errorf("max block lag requires a chain head source")
+	}
+	return &IndexerLagMonitor{
+		client: c,
+		config: config,
+		done:   make(chan struct{}),
+	}, nil
+}
+
+// Start checks the indexer lag periodically until Stop is called
+func (m *IndexerLagMonitor) Start() {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.config.Interval)
+		defer ticker.Stop()
+		for {
+			if _, err := m.Check(); err != nil {
+				m.report(err)
+			}
+			select {
+			case <-m.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the periodic checks
+func (m *IndexerLagMonitor) Stop() {
+	m.once.Do(func() { close(m.done) })
+	m.wg.Wait()
+}
+
+// Check measures the indexer lag now, reports it to the metrics collector and the event bus, and fires the stale and recover callbacks on transitions
+func (m *IndexerLagMonitor) Check() (IndexerLag, error) {
+	resp, err := m.client.GetPerpetualAccountAsset(types.GetPerpetualAccountAssetReq{SubaccountId: m.config.SubaccountId})
+	if err != nil {
+		return IndexerLag{}, fmt.Errorf("failed to get indexer height: %w", err)
+	}
+	var head ChainHead
+	if m.config.ChainHead != nil {
+		if head, err = m.config.ChainHead(); err != nil {
+			return IndexerLag{}, fmt.Errorf("failed to get chain head: %w", err)
+		}
+	}
+
+	now := time.Now()
+	lag := IndexerLag{
+		HandledBlockHeight: resp.Data.LastHandledBlockHeight,
+		HandledBlockTime:   resp.Data.LastHandledBlockTime,
+		ChainBlockHeight:   head.Height,
+		CheckedAt:          now,
+	}
+	if head.Height > lag.HandledBlockHeight {
+		lag.Blocks = head.Height - lag.HandledBlockHeight
+	}
+	reference := uint64(now.UnixMilli())
+	if m.config.ChainHead != nil {
+		reference = head.Time
+	}
+	if reference > lag.HandledBlockTime {
+		lag.Time = time.Duration(reference-lag.HandledBlockTime) * time.Millisecond
+	}
+	lag.Stale = lag.Time > m.config.MaxTimeLag || (m.config.MaxBlockLag > 0 && lag.Blocks > m.config.MaxBlockLag)
+
+	m.mu.Lock()
+	wasStale := m.lag.Stale
+	m.lag = lag
+	m.mu.Unlock()
+
+	labels := map[string]string{"subaccount_id": m.config.SubaccountId}
+	m.client.setGauge(MetricIndexerLagBlocks, float64(lag.Blocks), labels)
+	m.client.setGauge(MetricIndexerLagSeconds, lag.Time.Seconds(), labels)
+	stale := 0.0
+	if lag.Stale {
+		stale = 1
+	}
+	m.client.setGauge(MetricIndexerStale, stale, labels)
+	m.client.publish(TopicIndexerLag, m.config.SubaccountId, "", lag)
+
+	if lag.Stale && !wasStale && m.config.OnStale != nil {
+		m.config.OnStale(lag)
+	}
+	if !lag.Stale && wasStale && m.config.OnRecover != nil {
+		m.config.OnRecover(lag)
+	}
+	return lag, nil
+}
+
+// Lag returns the result of the last check
+func (m *IndexerLagMonitor) Lag() IndexerLag {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lag
+}
+
+// Stale reports whether the last check found the indexer stale
+func (m *IndexerLagMonitor) Stale() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lag.Stale
+}
+
+// report passes an error to the configured handler
+func (m *IndexerLagMonitor) report(err error) {
+	if m.config.ErrorHandler != nil {
+		m.config.ErrorHandler(err)
+		return
+	}
+	m.client.Logger().Errorf("indexer lag monitor: subaccount %s: %v", m.config.SubaccountId, err)
+}
@@ -0,0 +1,453 @@
+package sdk
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultMaxSeenFills default number of applied fill IDs an inventory tracker remembers to skip replayed fills
+const DefaultMaxSeenFills = 10000
+
+// Inventory net position and exposure of a subaccount on one exchange
+type Inventory struct {
+	ExchangeId  string          `json:"exchangeId"`  // Exchange ID
+	NetPosition decimal.Decimal `json:"netPosition"` // Net position, positive for long and negative for short
+	AvgPrice    decimal.Decimal `json:"avgPrice"`    // Average entry price of the net position
+	MarkPrice   decimal.Decimal `json:"markPrice"`   // Price used for notional exposure, the last fill price unless set explicitly
+	Notional    decimal.Decimal `json:"notional"`    // Absolute notional exposure, |NetPosition| * MarkPrice
+	RealizedPnl decimal.Decimal `json:"realizedPnl"` // Realized PnL accumulated from fills
+	Fees        decimal.Decimal `json:"fees"`        // Fees accumulated from fills
+}
+
+// InventorySnapshot serialized state of an inventory tracker
+type InventorySnapshot struct {
+	SubaccountId string      `json:"subaccountId"` // Subaccount ID
+	Inventories  []Inventory `json:"inventories"`  // Inventories of all exchanges
+	SeenFills    []string    `json:"seenFills"`    // IDs of the last applied fills, oldest first, so that replayed fills are not counted twice
+}
+
+// InventoryLimits exposure limits of an inventory tracker, zero values disable a limit
+type InventoryLimits struct {
+	MaxPosition      decimal.Decimal // Maximum absolute net position per exchange
+	MaxNotional      decimal.Decimal // Maximum notional exposure per exchange
+	MaxTotalNotional decimal.Decimal // Maximum notional exposure across all exchanges
+}
+
+// InventoryBreach an inventory limit that was exceeded
+type InventoryBreach struct {
+	ExchangeId string          // Exchange ID, empty for the total notional limit
+	Limit      string          // Name of the exceeded limit
+	Value      decimal.Decimal // Current value
+	Threshold  decimal.Decimal // Configured limit
+}
+
+// Error formats the breach as an error message
+func (b InventoryBreach) Error() string {
+	if b.ExchangeId == "" {
+		return fmt.Sprintf("inventory limit %s exceeded: %s > %s", b.Limit, b.Value.String(), b.Threshold.String())
+	}
+	return fmt.Sprintf("inventory limit %s exceeded on exchange %s: %s > %s", b.Limit, b.ExchangeId, b.Value.String(), b.Threshold.String())
+}
+
+// InventoryTracker aggregates net position, average price and exposure per exchange from fills
+type InventoryTracker struct {
+	SubaccountId string
+	Limits       InventoryLimits
+	MaxSeenFills int // Applied fill IDs remembered to skip replayed fills, the oldest are forgotten first, defaults to DefaultMaxSeenFills
+
+	mu          sync.RWMutex
+	inventories map[string]*Inventory
+	seenFills   map[string]bool
+	seenOrder   []string // IDs of seenFills, oldest first
+	onUpdate    []func(Inventory)
+	onBreach    []func(InventoryBreach)
+	alerting    bool // Breaches are published as alerts on the event bus of a client, set by the first TrackInventory
+}
+
+// NewInventoryTracker creates an inventory tracker of a subaccount
+func NewInventoryTracker(subaccountId string, limits InventoryLimits) *InventoryTracker {
+	return &InventoryTracker{
+		SubaccountId: subaccountId,
+		Limits:       limits,
+		inventories:  make(map[string]*Inventory),
+		seenFills:    make(map[string]bool),
+	}
+}
+
+// OnUpdate registers a callback invoked after the inventory of an exchange changes
+func (t *InventoryTracker) OnUpdate(fn func(Inventory)) {
+	t.mu.Lock()
+	t.onUpdate = append(t.onUpdate, fn)
+	t.mu.Unlock()
+}
+
+// OnBreach registers a callback invoked when a limit is exceeded
+func (t *InventoryTracker) OnBreach(fn func(InventoryBreach)) {
+	t.mu.Lock()
+	t.onBreach = append(t.onBreach, fn)
+	t.mu.Unlock()
+}
+
+// Inventory returns the inventory of an exchange
+func (t *InventoryTracker) Inventory(exchangeId string) Inventory {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if inventory, ok := t.inventories[exchangeId]; ok {
+		return *inventory
+	}
+	return Inventory{ExchangeId: exchangeId}
+}
+
+// Inventories returns the inventories of all exchanges
+func (t *InventoryTracker) Inventories() []Inventory {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	inventories := make([]Inventory, 0, len(t.inventories))
+	for _, inventory := range t.inventories {
+		inventories = append(inventories, *inventory)
+	}
+	return inventories
+}
+
+// TotalNotional returns the notional exposure across all exchanges
+func (t *InventoryTracker) TotalNotional() decimal.Decimal {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.totalNotional()
+}
+
+// Breaches returns the limits currently exceeded
+func (t *InventoryTracker) Breaches() []InventoryBreach {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	var breaches []InventoryBreach
+	for _, inventory := range t.inventories {
+		breaches = append(breaches, t.exchangeBreaches(inventory)...)
+	}
+	return append(breaches, t.totalBreaches()...)
+}
+
+// Snapshot returns the inventories and applied fill IDs of the tracker
+func (t *InventoryTracker) Snapshot() InventorySnapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	snapshot := InventorySnapshot{
+		SubaccountId: t.SubaccountId,
+		Inventories:  make([]Inventory, 0, len(t.inventories)),
+		SeenFills:    append([]string(nil), t.seenOrder...),
+	}
+	for _, inventory := range t.inventories {
+		snapshot.Inventories = append(snapshot.Inventories, *inventory)
+	}
+	sort.Slice(snapshot.Inventories, func(i, j int) bool { return snapshot.Inventories[i].ExchangeId < snapshot.Inventories[j].ExchangeId })
+	return snapshot
+}
+
+// Restore replaces the inventories and applied fill IDs with those of a snapshot of the same subaccount
+func (t *InventoryTracker) Restore(snapshot InventorySnapshot) error {
+	if snapshot.SubaccountId != t.SubaccountId {
+		return fmt.Errorf("snapshot of subaccount %s cannot restore the tracker of subaccount %s", snapshot.SubaccountId, t.SubaccountId)
+	}
+	inventories := make(map[string]*Inventory, len(snapshot.Inventories))
+	for i := range snapshot.Inventories {
+		inventory := snapshot.Inventories[i]
+		inventories[inventory.ExchangeId] = &inventory
+	}
+	t.mu.Lock()
+	t.inventories = inventories
+	t.seenFills = make(map[string]bool, len(snapshot.SeenFills))
+	t.seenOrder = nil
+	for _, id := range snapshot.SeenFills {
+		t.markSeen(id)
+	}
+	t.mu.Unlock()
+	return nil
+}
+
+// ApplyFill applies an order fill, fills of other subaccounts and fills already applied are ignored
+func (t *InventoryTracker) ApplyFill(fill *types.OrderFillTransaction) error {
+	if fill.SubaccountId != "" && fill.SubaccountId != t.SubaccountId {
+		return nil
+	}
+	size, err := decimal.NewFromString(fill.FillSize)
+	if err != nil {
+		return fmt.Errorf("failed to parse fill size: %w", err)
+	}
+	if !size.IsPositive() {
+		return nil
+	}
+	value, err := parseOptionalDecimal(fill.FillValue)
+	if err != nil {
+		return fmt.Errorf("failed to parse fill value: %w", err)
+	}
+	fee, err := parseOptionalDecimal(fill.FillFee)
+	if err != nil {
+		return fmt.Errorf("failed to parse fill fee: %w", err)
+	}
+	realizePnl, err := parseOptionalDecimal(fill.RealizePnl)
+	if err != nil {
+		return fmt.Errorf("failed to parse fill realized PnL: %w", err)
+	}
+	// Fill price is for display only, derive the precise price from the fill value
+	price := value.Abs().Div(size)
+
+	t.mu.Lock()
+	if fill.Id != "" {
+		if t.seenFills[fill.Id] {
+			t.mu.Unlock()
+			return nil
+		}
+		t.markSeen(fill.Id)
+	}
+	inventory := t.inventory(fill.ExchangeId)
+
+	delta := size
+	if !fill.IsBuy {
+		delta = size.Neg()
+	}
+	net := inventory.NetPosition
+	switch {
+	case net.IsZero() || net.Sign() == delta.Sign():
+		// Opening or adding, the average price is weighted by size
+		total := net.Abs().Add(size)
+		inventory.AvgPrice = inventory.AvgPrice.Mul(net.Abs()).Add(price.Mul(size)).Div(total)
+	case size.GreaterThan(net.Abs()):
+		// Flipping, the remainder opens at the fill price
+		inventory.AvgPrice = price
+	case size.Equal(net.Abs()):
+		inventory.AvgPrice = decimal.Zero
+	}
+	inventory.NetPosition = net.Add(delta)
+	inventory.MarkPrice = price
+	inventory.RealizedPnl = inventory.RealizedPnl.Add(realizePnl)
+	inventory.Fees = inventory.Fees.Add(fee)
+	t.updateNotional(inventory)
+	notification := t.notification(inventory)
+	t.mu.Unlock()
+	notification.fire()
+	return nil
+}
+
+// markSeen records the ID of an applied fill and forgets the oldest IDs beyond MaxSeenFills
+func (t *InventoryTracker) markSeen(id string) {
+	if t.seenFills[id] {
+		return
+	}
+	t.seenFills[id] = true
+	t.seenOrder = append(t.seenOrder, id)
+	max := t.MaxSeenFills
+	if max <= 0 {
+		max = DefaultMaxSeenFills
+	}
+	for len(t.seenOrder) > max {
+		delete(t.seenFills, t.seenOrder[0])
+		t.seenOrder = t.seenOrder[1:]
+	}
+}
+
+// ApplyPosition resets the inventory of an exchange from a position snapshot
+func (t *InventoryTracker) ApplyPosition(position *types.PerpetualPosition) error {
+	if position.SubaccountId != "" && position.SubaccountId != t.SubaccountId {
+		return nil
+	}
+	openSize, err := parseOptionalDecimal(position.OpenSize)
+	if err != nil {
+		return fmt.Errorf("failed to parse position open size: %w", err)
+	}
+	openValue, err := parseOptionalDecimal(position.OpenValue)
+	if err != nil {
+		return fmt.Errorf("failed to parse position open value: %w", err)
+	}
+
+	t.mu.Lock()
+	inventory := t.inventory(position.ExchangeId)
+	inventory.NetPosition = openSize
+	inventory.AvgPrice = decimal.Zero
+	if !openSize.IsZero() {
+		inventory.AvgPrice = openValue.Abs().Div(openSize.Abs())
+		if inventory.MarkPrice.IsZero() {
+			inventory.MarkPrice = inventory.AvgPrice
+		}
+	}
+	t.updateNotional(inventory)
+	notification := t.notification(inventory)
+	t.mu.Unlock()
+	notification.fire()
+	return nil
+}
+
+// SetMarkPrice sets the price used for the notional exposure of an exchange, e.g. from the ticker mark price
+func (t *InventoryTracker) SetMarkPrice(exchangeId string, markPrice decimal.Decimal) {
+	t.mu.Lock()
+	inventory := t.inventory(exchangeId)
+	inventory.MarkPrice = markPrice
+	t.updateNotional(inventory)
+	notification := t.notification(inventory)
+	t.mu.Unlock()
+	notification.fire()
+}
+
+// ApplyTradeData applies positions of a snapshot and fills of an account event
+func (t *InventoryTracker) ApplyTradeData(event *types.TradeDataEvent) error {
+	if event.IsSnapshot {
+		for i := range event.PositionList {
+			if err := t.ApplyPosition(&event.PositionList[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for i := range event.OrderFillTransactionList {
+		if err := t.ApplyFill(&event.OrderFillTransactionList[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TrackInventory feeds an inventory tracker from the private fill stream until the returned stop function is called
+func (c *AntxClient) TrackInventory(tracker *InventoryTracker) (stop func(), err error) {
+	if err := c.registerInventoryTracker(tracker); err != nil {
+		return nil, err
+	}
+	tradeDataChan, err := c.SubscribeToTradeData()
+	if err != nil {
+		return nil, err
+	}
+	tracker.mu.Lock()
+	if !tracker.alerting {
+		tracker.alerting = true
+		tracker.onBreach = append(tracker.onBreach, func(breach InventoryBreach) {
+			c.publish(TopicAlert, tracker.SubaccountId, breach.ExchangeId, Alert{Kind: AlertInventoryBreach, Message: breach.Error(), Data: breach})
+		})
+	}
+	tracker.mu.Unlock()
+
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case msg := <-tradeDataChan:
+				event, err := c.ParseTradeDataEvent(msg)
+				if err != nil {
+					c.Logger().Errorf("inventory tracker: %v", err)
+					continue
+				}
+				if err := tracker.ApplyTradeData(event); err != nil {
+					c.Logger().Errorf("inventory tracker: %v", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(done) })
+	}, nil
+}
+
+// inventory returns the inventory of an exchange, creating it if needed, must be called with the lock held
+func (t *InventoryTracker) inventory(exchangeId string) *Inventory {
+	inventory, ok := t.inventories[exchangeId]
+	if !ok {
+		inventory = &Inventory{ExchangeId: exchangeId}
+		t.inventories[exchangeId] = inventory
+	}
+	return inventory
+}
+
+// updateNotional recomputes the notional exposure of an inventory
+func (t *InventoryTracker) updateNotional(inventory *Inventory) {
+	inventory.Notional = inventory.NetPosition.Abs().Mul(inventory.MarkPrice)
+}
+
+// inventoryNotification callbacks to invoke after an inventory change, outside the lock
+type inventoryNotification struct {
+	inventory Inventory
+	breaches  []InventoryBreach
+	onUpdate  []func(Inventory)
+	onBreach  []func(InventoryBreach)
+}
+
+// notification collects the callbacks for an inventory change, must be called with the lock held
+func (t *InventoryTracker) notification(inventory *Inventory) *inventoryNotification {
+	return &inventoryNotification{
+		inventory: *inventory,
+		breaches:  append(t.exchangeBreaches(inventory), t.totalBreaches()...),
+		onUpdate:  t.onUpdate,
+		onBreach:  t.onBreach,
+	}
+}
+
+// fire invokes the update and breach callbacks
+func (n *inventoryNotification) fire() {
+	for _, fn := range n.onUpdate {
+		fn(n.inventory)
+	}
+	for _, breach := range n.breaches {
+		for _, fn := range n.onBreach {
+			fn(breach)
+		}
+	}
+}
+
+// exchangeBreaches checks the per exchange limits of an inventory
+func (t *InventoryTracker) exchangeBreaches(inventory *Inventory) []InventoryBreach {
+	var breaches []InventoryBreach
+	if t.Limits.MaxPosition.IsPositive() && inventory.NetPosition.Abs().GreaterThan(t.Limits.MaxPosition) {
+		breaches = append(breaches, InventoryBreach{
+			ExchangeId: inventory.ExchangeId,
+			Limit:      "MaxPosition",
+			Value:      inventory.NetPosition.Abs(),
+			Threshold:  t.Limits.MaxPosition,
+		})
+	}
+	if t.Limits.MaxNotional.IsPositive() && inventory.Notional.GreaterThan(t.Limits.MaxNotional) {
+		breaches = append(breaches, InventoryBreach{
+			ExchangeId: inventory.ExchangeId,
+			Limit:      "MaxNotional",
+			Value:      inventory.Notional,
+			Threshold:  t.Limits.MaxNotional,
+		})
+	}
+	return breaches
+}
+
+// totalBreaches checks the limits across all exchanges
+func (t *InventoryTracker) totalBreaches() []InventoryBreach {
+	if !t.Limits.MaxTotalNotional.IsPositive() {
+		return nil
+	}
+	total := t.totalNotional()
+	if !total.GreaterThan(t.Limits.MaxTotalNotional) {
+		return nil
+	}
+	return []InventoryBreach{{
+		Limit:     "MaxTotalNotional",
+		Value:     total,
+		Threshold: t.Limits.MaxTotalNotional,
+	}}
+}
+
+// totalNotional sums the notional exposure of all exchanges
+func (t *InventoryTracker) totalNotional() decimal.Decimal {
+	total := decimal.Zero
+	for _, inventory := range t.inventories {
+		total = total.Add(inventory.Notional)
+	}
+	return total
+}
+
+// parseOptionalDecimal parses a decimal string, an empty string is zero
+func parseOptionalDecimal(value string) (decimal.Decimal, error) {
+	if value == "" {
+		return decimal.Zero, nil
+	}
+	return decimal.NewFromString(value)
+}
@@ -0,0 +1,144 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+)
+
+// KeyProvider supplies a private key from a secret store, so that keys never live in environment variables or plain files
+type KeyProvider interface {
+	// PrivateKey returns the private key in hexadecimal string
+	PrivateKey() (string, error)
+}
+
+// KeychainKeyProvider reads a key from a generic password item of the macOS Keychain
+type KeychainKeyProvider struct {
+	Service string // Keychain item service
+	Account string // Keychain item account
+}
+
+// PrivateKey runs security find-generic-password for the item
+func (p KeychainKeyProvider) PrivateKey() (string, error) {
+	return runSecretCommand("security", "find-generic-password", "-s", p.Service, "-a", p.Account, "-w")
+}
+
+// SecretServiceKeyProvider reads a key from the Linux secret service (GNOME Keyring, KWallet) via secret-tool
+type SecretServiceKeyProvider struct {
+	Attributes map[string]string // Attributes identifying the secret, e.g. {"service": "antx", "key": "agent"}
+}
+
+// PrivateKey runs secret-tool lookup with the attributes
+func (p SecretServiceKeyProvider) PrivateKey() (string, error) {
+	if len(p.Attributes) == 0 {
+		return "", fmt.Errorf("secret service attributes are required")
+	}
+	keys := make([]string, 0, len(p.Attributes))
+	for key := range p.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	args := []string{"lookup"}
+	for _, key := range keys {
+		args = append(args, key, p.Attributes[key])
+	}
+	return runSecretCommand("secret-tool", args...)
+}
+
+// VaultKeyProvider reads a key from a HashiCorp Vault KV secret
+type VaultKeyProvider struct {
+	Address    string       // Vault address, defaults to the VAULT_ADDR environment variable
+	Token      string       // Vault token, defaults to the VAULT_TOKEN environment variable
+	Path       string       // Secret path, e.g. "secret/data/antx" for KV version 2 or "secret/antx" for version 1
+	Field      string       // Field of the secret holding the key
+	HTTPClient *http.Client // HTTP client, defaults to a client with a 10 second timeout
+}
+
+// PrivateKey reads the field of the secret through the Vault HTTP API
+func (p VaultKeyProvider) PrivateKey() (string, error) {
+	address := p.Address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	token := p.Token
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if address == "" || token == "" || p.Path == "" || p.Field == "" {
+		return "", fmt.Errorf("vault address, token, path and field are required")
+	}
+	httpClient := p.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	req, err := http.NewRequest("GET", strings.TrimSuffix(address, "/")+"/v1/"+strings.TrimPrefix(p.Path, "/"), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to send vault request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %d for %s", resp.StatusCode, p.Path)
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("failed to parse vault response: %w", err)
+	}
+	// KV version 2 nests the fields under data.data
+	fields := secret.Data
+	if nested, ok := fields["data"].(map[string]interface{}); ok {
+		fields = nested
+	}
+	value, ok := fields[p.Field].(string)
+	if !ok || value == "" {
+		return "", fmt.Errorf("vault secret %s has no field %s", p.Path, p.Field)
+	}
+	return value, nil
+}
+
+// runSecretCommand runs a secret store command and returns its trimmed output
+func runSecretCommand(name string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(name, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s failed: %w: %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	key := strings.TrimSpace(stdout.String())
+	if key == "" {
+		return "", fmt.Errorf("%s returned an empty secret", name)
+	}
+	return key, nil
+}
+
+// resolveKey returns key, or reads it from provider when key is empty
+func resolveKey(key string, provider KeyProvider, name string) (string, error) {
+	if key != "" || provider == nil {
+		return key, nil
+	}
+	key, err := provider.PrivateKey()
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s private key: %w", name, err)
+	}
+	return key, nil
+}
@@ -0,0 +1,171 @@
+package sdk
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// KlineGap missing K-line interval, KlineTime values in [From, To) are absent, unit: milliseconds
+type KlineGap struct {
+	From uint64 // First missing K-line time
+	To   uint64 // K-line time following the last missing one
+}
+
+// klineDurations fixed length K-line types, MONTH_1 follows the calendar
+var klineDurations = map[string]time.Duration{
+	constants.KlineTypeMinute1:  time.Minute,
+	constants.KlineTypeMinute5:  5 * time.Minute,
+	constants.KlineTypeMinute15: 15 * time.Minute,
+	constants.KlineTypeMinute30: 30 * time.Minute,
+	constants.KlineTypeHour1:    time.Hour,
+	constants.KlineTypeHour2:    2 * time.Hour,
+	constants.KlineTypeHour4:    4 * time.Hour,
+	constants.KlineTypeHour6:    6 * time.Hour,
+	constants.KlineTypeHour8:    8 * time.Hour,
+	constants.KlineTypeHour12:   12 * time.Hour,
+	constants.KlineTypeDay1:     24 * time.Hour,
+	constants.KlineTypeWeek1:    7 * 24 * time.Hour,
+}
+
+// NextKlineTime returns the K-line time following klineTime for a K-line type, unit: milliseconds
+func NextKlineTime(klineTime uint64, klineType string) (uint64, error) {
+	if klineType == constants.KlineTypeMonth1 {
+		return uint64(time.UnixMilli(int64(klineTime)).UTC().AddDate(0, 1, 0).UnixMilli()), nil
+	}
+	d, ok := klineDurations[klineType]
+	if !ok {
+		return 0, fmt.Errorf("unknown kline type %s", klineType)
+	}
+	return klineTime + uint64(d.Milliseconds()), nil
+}
+
+// SortKlines sorts K-lines by time and drops duplicates of the same time, keeping the last occurrence
+func SortKlines(klines []types.KLine) []types.KLine {
+	byTime := make(map[uint64]types.KLine, len(klines))
+	for _, kline := range klines {
+		byTime[kline.KlineTime] = kline
+	}
+	sorted := make([]types.KLine, 0, len(byTime))
+	for _, kline := range byTime {
+		sorted = append(sorted, kline)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].KlineTime < sorted[j].KlineTime })
+	return sorted
+}
+
+// FindKlineGaps scans a K-line series for missing intervals
+func FindKlineGaps(klines []types.KLine, klineType string) ([]KlineGap, error) {
+	sorted := SortKlines(klines)
+	var gaps []KlineGap
+	for i := 1; i < len(sorted); i++ {
+		expected, err := NextKlineTime(sorted[i-1].KlineTime, klineType)
+		if err != nil {
+			return nil, err
+		}
+		if sorted[i].KlineTime > expected {
+			gaps = append(gaps, KlineGap{From: expected, To: sorted[i].KlineTime})
+		}
+	}
+	return gaps, nil
+}
+
+// ValidateKlineSeries checks that a K-line series is sorted, contiguous and has consistent prices
+func ValidateKlineSeries(klines []types.KLine, klineType string) error {
+	for i, kline := range klines {
+		if i > 0 {
+			expected, err := NextKlineTime(klines[i-1].KlineTime, klineType)
+			if err != nil {
+				return err
+			}
+			if kline.KlineTime != expected {
+				return fmt.Errorf("kline %d: expected time %d, got %d", i, expected, kline.KlineTime)
+			}
+		}
+		if err := validateKlinePrices(&kline); err != nil {
+			return fmt.Errorf("kline %d at %d: %w", i, kline.KlineTime, err)
+		}
+	}
+	return nil
+}
+
+// BackfillKlines refetches the missing intervals of a K-line series from the REST API and returns the merged series sorted by time,
+// gaps the API cannot fill are returned as well
+func (c *AntxClient) BackfillKlines(klines []types.KLine, exchangeId, priceType, klineType string) ([]types.KLine, []KlineGap, error) {
+	gaps, err := FindKlineGaps(klines, klineType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	merged := append([]types.KLine(nil), klines...)
+	for _, gap := range gaps {
+		req := types.GetKLineReq{
+			ExchangeId:                    exchangeId,
+			KlineType:                     klineType,
+			PriceType:                     priceType,
+			Size:                          100,
+			FilterBeginKlineTimeInclusive: int64(gap.From),
+			FilterEndKlineTimeExclusive:   int64(gap.To),
+		}
+		for {
+			resp, err := c.GetKline(req)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to backfill klines %d-%d: %w", gap.From, gap.To, err)
+			}
+			merged = append(merged, resp.Data.KlineList...)
+			if resp.Data.NextPageOffsetData == "" {
+				break
+			}
+			req.OffsetData = resp.Data.NextPageOffsetData
+		}
+	}
+
+	merged = SortKlines(merged)
+	remaining, err := FindKlineGaps(merged, klineType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(remaining) == 0 {
+		if err := ValidateKlineSeries(merged, klineType); err != nil {
+			return nil, nil, fmt.Errorf("invalid kline series after backfill: %w", err)
+		}
+		return merged, nil, nil
+	}
+	for i := range merged {
+		if err := validateKlinePrices(&merged[i]); err != nil {
+			return nil, nil, fmt.Errorf("invalid kline at %d after backfill: %w", merged[i].KlineTime, err)
+		}
+	}
+	return merged, remaining, nil
+}
+
+// validateKlinePrices checks that high and low bound open and close
+func validateKlinePrices(kline *types.KLine) error {
+	open, err := decimal.NewFromString(kline.Open)
+	if err != nil {
+		return fmt.Errorf("failed to parse open: %w", err)
+	}
+	high, err := decimal.NewFromString(kline.High)
+	if err != nil {
+		return fmt.Errorf("failed to parse high: %w", err)
+	}
+	low, err := decimal.NewFromString(kline.Low)
+	if err != nil {
+		return fmt.Errorf("failed to parse low: %w", err)
+	}
+	closePrice, err := decimal.NewFromString(kline.Close)
+	if err != nil {
+		return fmt.Errorf("failed to parse close: %w", err)
+	}
+	if high.LessThan(decimal.Max(open, closePrice)) {
+		return fmt.Errorf("high %s is below open %s or close %s", kline.High, kline.Open, kline.Close)
+	}
+	if low.GreaterThan(decimal.Min(open, closePrice)) {
+		return fmt.Errorf("low %s is above open %s or close %s", kline.Low, kline.Open, kline.Close)
+	}
+	return nil
+}
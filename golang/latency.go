@@ -0,0 +1,69 @@
+package sdk
+
+import (
+	"strconv"
+	"time"
+)
+
+// LatencyBreakdown time spent in each phase of sending a transaction
+type LatencyBreakdown struct {
+	TypeURL  string        // Message type of the transaction
+	SentAt   time.Time     // Time sending started
+	Sequence time.Duration // Fetching the account sequence, zero for unordered transactions
+	Simulate time.Duration // Signing and simulating the transaction, zero unless SimulateBeforeSend is set
+	Build    time.Duration // Building the unsigned transaction, zero unless the signer is a sign.PhasedTxSigner
+	Sign     time.Duration // Signing, including building and encoding unless the signer is a sign.PhasedTxSigner
+	Encode   time.Duration // Encoding the signed transaction, zero unless the signer is a sign.PhasedTxSigner
+	HTTP     time.Duration // Round trip of the broadcast request
+	Gateway  time.Duration // Gateway processing time reported in the response, zero when not reported
+	Total    time.Duration // Total time until the gateway acknowledged the transaction
+}
+
+// LastLatencyBreakdown returns the phase timing of the last transaction the client sent successfully
+func (c *AntxClient) LastLatencyBreakdown() LatencyBreakdown {
+	c.latencyMu.Lock()
+	defer c.latencyMu.Unlock()
+	return c.lastLatency
+}
+
+// recordLatency stores the phase timing of a transaction and reports it to the metrics collector
+func (c *AntxClient) recordLatency(latency LatencyBreakdown) {
+	c.latencyMu.Lock()
+	c.lastLatency = latency
+	c.latencyMu.Unlock()
+
+	if c.metrics == nil {
+		return
+	}
+	phases := []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"sequence", latency.Sequence},
+		{"simulate", latency.Simulate},
+		{"build", latency.Build},
+		{"sign", latency.Sign},
+		{"encode", latency.Encode},
+		{"http", latency.HTTP},
+		{"gateway", latency.Gateway},
+		{"total", latency.Total},
+	}
+	for _, phase := range phases {
+		if phase.duration > 0 {
+			c.observeHistogram(MetricTxPhaseSeconds, phase.duration.Seconds(), map[string]string{"phase": phase.name, "type_url": latency.TypeURL})
+		}
+	}
+}
+
+// gatewayProcessingTime returns the processing time between the millisecond request and response times of a gateway response
+func gatewayProcessingTime(requestTime, responseTime string) time.Duration {
+	request, err := strconv.ParseInt(requestTime, 10, 64)
+	if err != nil {
+		return 0
+	}
+	response, err := strconv.ParseInt(responseTime, 10, 64)
+	if err != nil || response < request {
+		return 0
+	}
+	return time.Duration(response-request) * time.Millisecond
+}
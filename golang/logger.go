@@ -0,0 +1,41 @@
+package sdk
+
+import (
+	"github.com/antxprotocol/antx-sdk-golang/query"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// Logger receives the log messages of the client and its components, see query.Logger. Set it with Config.Logger or
+// SetLogger, query.NopLogger() disables logging.
+type Logger = query.Logger
+
+// logxLogger default logger of the client, writing to go-zero logx
+type logxLogger struct{}
+
+func (logxLogger) Debugf(format string, args ...interface{}) { logx.Debugf(format, args...) }
+func (logxLogger) Infof(format string, args ...interface{})  { logx.Infof(format, args...) }
+func (logxLogger) Errorf(format string, args ...interface{}) { logx.Errorf(format, args...) }
+
+// LogxLogger returns the logger writing to go-zero logx, the default of the trading and read-only clients. A
+// query.Client created on its own defaults to the standard library logger instead.
+func LogxLogger() Logger {
+	return logxLogger{}
+}
+
+// orLogx returns the logger, go-zero logx when nil
+func orLogx(logger Logger) Logger {
+	if logger == nil {
+		return logxLogger{}
+	}
+	return logger
+}
+
+// SetLogger sets the logger of the client, of the WebSockets it creates afterwards and of its components, nil
+// restores go-zero logx
+func (c *AntxClient) SetLogger(logger Logger) {
+	logger = orLogx(logger)
+	c.Client.SetLogger(logger)
+	if c.node != nil {
+		c.node.SetLogger(logger)
+	}
+}
@@ -0,0 +1,141 @@
+package sdk
+
+import (
+	"fmt"
+
+	exchangetypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/exchange"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// MarginSummary cross margin of a subaccount at mark prices
+type MarginSummary struct {
+	SubaccountId      string                     // Subaccount ID
+	CrossEquity       decimal.Decimal            // Collateral plus the mark value of the cross positions
+	MaintenanceMargin decimal.Decimal            // Maintenance margin of the cross positions at their risk tier
+	OrderMargin       decimal.Decimal            // Margin reserved by the active orders that may open positions, their notional over their leverage
+	Available         decimal.Decimal            // Equity left to open new orders, never negative
+	MarkPrices        map[string]decimal.Decimal // Mark prices used, by exchange ID
+}
+
+// AvailableToTrade returns the collateral a subaccount can still commit to new orders: the cross equity at mark prices
+// less the maintenance margin of the cross positions and the margin reserved by the active orders
+func (c *AntxClient) AvailableToTrade(subaccountId string) (decimal.Decimal, error) {
+	summary, err := c.GetMarginSummary(subaccountId)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return summary.Available, nil
+}
+
+// GetMarginSummary reads the account, active orders and mark prices of a subaccount and computes its cross margin
+func (c *AntxClient) GetMarginSummary(subaccountId string) (*MarginSummary, error) {
+	state, err := c.GetAccountState(subaccountId)
+	if err != nil {
+		return nil, err
+	}
+	exchangeIds := make([]string, 0, len(state.PositionList)+len(state.OrderList))
+	for _, position := range state.PositionList {
+		exchangeIds = append(exchangeIds, position.ExchangeId)
+	}
+	for _, order := range state.OrderList {
+		exchangeIds = append(exchangeIds, order.ExchangeId)
+	}
+	marks, err := c.markPrices(exchangeIds)
+	if err != nil {
+		return nil, err
+	}
+	exchanges := make(map[string]*types.Exchange)
+	for _, position := range state.PositionList {
+		if _, ok := exchanges[position.ExchangeId]; ok {
+			continue
+		}
+		if exchanges[position.ExchangeId], err = c.GetExchange(position.ExchangeId); err != nil {
+			return nil, err
+		}
+	}
+	return ComputeMarginSummary(state, exchanges, marks)
+}
+
+// ComputeMarginSummary computes the cross margin of an account state from the exchanges of its positions and the mark
+// prices of its positions and orders. Isolated positions carry their own collateral and are left out.
+func ComputeMarginSummary(state *AccountState, exchanges map[string]*types.Exchange, marks map[string]decimal.Decimal) (*MarginSummary, error) {
+	summary := &MarginSummary{SubaccountId: state.SubaccountId, MarkPrices: marks}
+	for _, collateral := range state.CollateralList {
+		amount, err := parseOptionalDecimal(collateral.Amount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse collateral of coin %s: %w", collateral.CoinId, err)
+		}
+		summary.CrossEquity = summary.CrossEquity.Add(amount)
+	}
+
+	for _, position := range state.PositionList {
+		if position.MarginMode == uint32(exchangetypes.MarginMode_MARGIN_MODE_ISOLATED) {
+			continue
+		}
+		size, err := parseOptionalDecimal(position.OpenSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse position of exchange %s: %w", position.ExchangeId, err)
+		}
+		mark, ok := marks[position.ExchangeId]
+		if !ok {
+			return nil, fmt.Errorf("no mark price for exchange %s", position.ExchangeId)
+		}
+		exchange, ok := exchanges[position.ExchangeId]
+		if !ok {
+			return nil, fmt.Errorf("exchange %s not found", position.ExchangeId)
+		}
+		value := size.Mul(mark)
+		ratio, err := maintenanceMarginRatio(exchange, value.Abs())
+		if err != nil {
+			return nil, err
+		}
+		summary.CrossEquity = summary.CrossEquity.Add(value)
+		summary.MaintenanceMargin = summary.MaintenanceMargin.Add(value.Abs().Mul(ratio))
+	}
+
+	for _, order := range state.OrderList {
+		if order.ReduceOnly {
+			continue
+		}
+		var values [3]decimal.Decimal
+		for i, value := range []string{order.Price, order.Size, order.CumFillSize} {
+			var err error
+			if values[i], err = parseOptionalDecimal(value); err != nil {
+				return nil, fmt.Errorf("failed to parse order %s: %w", order.Id, err)
+			}
+		}
+		price := values[0]
+		if price.IsZero() {
+			price = marks[order.ExchangeId]
+		}
+		leverage := decimal.NewFromInt(int64(order.Leverage))
+		if order.Leverage == 0 {
+			leverage = decimal.NewFromInt(1)
+		}
+		remaining := values[1].Sub(values[2])
+		summary.OrderMargin = summary.OrderMargin.Add(remaining.Mul(price).Div(leverage))
+	}
+
+	summary.Available = decimal.Max(decimal.Zero, summary.CrossEquity.Sub(summary.MaintenanceMargin).Sub(summary.OrderMargin))
+	return summary, nil
+}
+
+// maintenanceMarginRatio returns the maintenance margin ratio of the risk tier of a position value
+func maintenanceMarginRatio(exchange *types.Exchange, value decimal.Decimal) (decimal.Decimal, error) {
+	tiers := exchange.Perpetual.RiskTierList
+	if len(tiers) == 0 {
+		return decimal.Zero, fmt.Errorf("exchange %s has no risk tiers", exchange.Id)
+	}
+	for _, tier := range tiers {
+		bound, err := parseOptionalDecimal(tier.PositionValueUpperBound)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("failed to parse risk tier of exchange %s: %w", exchange.Id, err)
+		}
+		if bound.IsZero() || value.LessThanOrEqual(bound) {
+			return decimal.New(int64(tier.MaintenanceMarginRatioPpm), -6), nil
+		}
+	}
+	// Beyond the last bound the last tier applies
+	return decimal.New(int64(tiers[len(tiers)-1].MaintenanceMarginRatioPpm), -6), nil
+}
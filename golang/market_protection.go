@@ -0,0 +1,91 @@
+package sdk
+
+import (
+	"fmt"
+
+	ordertypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/order"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// PlaceMarketWithProtection sends a market order as an IOC limit order priced maxSlippageBps (1 bps = 0.01%) beyond the
+// best opposite price of the book, or the mark price when that side is empty, so it never fills worse than the budget.
+// The limit is rounded to the tick size toward the reference price.
+func (c *AntxClient) PlaceMarketWithProtection(subaccountId uint64, exchangeId string, isBuy bool, size decimal.Decimal, maxSlippageBps uint32) (string, error) {
+	order, err := c.BuildProtectedMarketOrder(subaccountId, exchangeId, isBuy, size, maxSlippageBps)
+	if err != nil {
+		return "", err
+	}
+	return c.CreateOrder(order)
+}
+
+// BuildProtectedMarketOrder builds the IOC limit order sent by PlaceMarketWithProtection
+func (c *AntxClient) BuildProtectedMarketOrder(subaccountId uint64, exchangeId string, isBuy bool, size decimal.Decimal, maxSlippageBps uint32) (*CreateOrderParam, error) {
+	exchange, err := c.GetExchange(exchangeId)
+	if err != nil {
+		return nil, err
+	}
+	reference, err := c.protectionReferencePrice(exchangeId, isBuy)
+	if err != nil {
+		return nil, err
+	}
+	slippage := decimal.New(int64(maxSlippageBps), -4)
+	limit := reference.Mul(decimal.NewFromInt(1).Add(slippage))
+	if !isBuy {
+		limit = reference.Mul(decimal.NewFromInt(1).Sub(slippage))
+	}
+	limit, err = RoundPriceToTick(limit, exchange, PassiveRounding(isBuy))
+	if err != nil {
+		return nil, err
+	}
+	if !limit.IsPositive() {
+		return nil, fmt.Errorf("slippage of %d bps leaves no positive limit price below %s", maxSlippageBps, reference)
+	}
+	return NewOrderBuilder(exchange, subaccountId).
+		Side(isBuy).
+		Size(size).
+		Limit(limit).
+		TimeInForce(ordertypes.TimeInForce_TIME_IN_FORCE_IMMEDIATE_OR_CANCEL).
+		Build()
+}
+
+// protectionReferencePrice returns the best ask for a buy or the best bid for a sell, the mark price when the book side
+// is empty
+func (c *AntxClient) protectionReferencePrice(exchangeId string, isBuy bool) (decimal.Decimal, error) {
+	depth, err := c.GetDepth(types.GetDepthReq{ExchangeId: exchangeId, Level: 1})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	for _, data := range depth.Data.DepthList {
+		if data.ExchangeId != "" && data.ExchangeId != exchangeId {
+			continue
+		}
+		side := data.Bids
+		if isBuy {
+			side = data.Asks
+		}
+		if len(side) > 0 {
+			price, err := decimal.NewFromString(side[0].Price)
+			if err != nil {
+				return decimal.Zero, fmt.Errorf("failed to parse book price %q: %w", side[0].Price, err)
+			}
+			if price.IsPositive() {
+				return price, nil
+			}
+		}
+	}
+
+	ticker, err := c.GetTicker(types.GetTickerReq{ExchangeId: exchangeId})
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if len(ticker.Data.TickerList) == 0 {
+		return decimal.Zero, fmt.Errorf("no book or mark price for exchange %s", exchangeId)
+	}
+	markPrice := ticker.Data.TickerList[0].MarkPrice
+	price, err := decimal.NewFromString(markPrice)
+	if err != nil || !price.IsPositive() {
+		return decimal.Zero, fmt.Errorf("invalid mark price %q for exchange %s", markPrice, exchangeId)
+	}
+	return price, nil
+}
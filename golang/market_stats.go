@@ -0,0 +1,110 @@
+package sdk
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultTopMovers default number of exchanges listed as top gainers and losers
+const DefaultTopMovers = 5
+
+// ExchangeStats 24h statistics of one exchange
+type ExchangeStats struct {
+	ExchangeId         string          // Exchange ID
+	Symbol             string          // Exchange symbol
+	Volume             decimal.Decimal // 24h volume
+	Turnover           decimal.Decimal // 24h turnover
+	Trades             int64           // 24h number of trades
+	PriceChangePercent decimal.Decimal // 24h price change percentage
+	OpenInterest       decimal.Decimal // Open interest
+	OpenInterestValue  decimal.Decimal // Open interest at the mark price
+	OpenInterestChange decimal.Decimal // Open interest change since the previous snapshot, zero without one
+}
+
+// MarketStats 24h statistics of all exchanges with their aggregate
+type MarketStats struct {
+	Snapshot          *types.MarketSnapshot // Snapshot the statistics are computed from, the previous snapshot of the next call
+	Exchanges         []ExchangeStats       // Statistics by exchange, by turnover descending
+	Turnover          decimal.Decimal       // Total 24h turnover
+	Trades            int64                 // Total 24h number of trades
+	OpenInterestValue decimal.Decimal       // Total open interest at the mark prices
+	TopGainers        []ExchangeStats       // Exchanges with the largest price increase
+	TopLosers         []ExchangeStats       // Exchanges with the largest price decrease
+}
+
+// GetMarketStats captures a market snapshot and computes its statistics, the open interest change is taken against
+// previous when it is not nil, e.g. the Snapshot of the previous call
+func (c *AntxClient) GetMarketStats(previous *types.MarketSnapshot, top int) (*MarketStats, error) {
+	snapshot, err := c.MarketSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return ComputeMarketStats(snapshot, previous, top)
+}
+
+// ComputeMarketStats computes the statistics of a market snapshot with the top movers, top defaults to
+// DefaultTopMovers. Exchanges whose snapshot failed are left out.
+func ComputeMarketStats(snapshot, previous *types.MarketSnapshot, top int) (*MarketStats, error) {
+	if top <= 0 {
+		top = DefaultTopMovers
+	}
+	stats := &MarketStats{Snapshot: snapshot}
+	for exchangeId, entry := range snapshot.Markets {
+		if entry.Error != "" {
+			continue
+		}
+		ticker := &entry.Ticker
+		var values [5]decimal.Decimal
+		for i, value := range []string{ticker.Size, ticker.Value, ticker.PriceChangePercent, entry.OpenInterest, ticker.MarkPrice} {
+			var err error
+			if values[i], err = parseOptionalDecimal(value); err != nil {
+				return nil, fmt.Errorf("failed to parse ticker of exchange %s: %w", exchangeId, err)
+			}
+		}
+		trades, err := parseOptionalDecimal(ticker.Trades)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ticker of exchange %s: %w", exchangeId, err)
+		}
+		exchange := ExchangeStats{
+			ExchangeId:         exchangeId,
+			Symbol:             entry.Exchange.Symbol,
+			Volume:             values[0],
+			Turnover:           values[1],
+			Trades:             trades.IntPart(),
+			PriceChangePercent: values[2],
+			OpenInterest:       values[3],
+			OpenInterestValue:  values[3].Mul(values[4]),
+		}
+		if previous != nil {
+			if before, ok := previous.Markets[exchangeId]; ok && before.Error == "" {
+				openInterest, err := parseOptionalDecimal(before.OpenInterest)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse previous open interest of exchange %s: %w", exchangeId, err)
+				}
+				exchange.OpenInterestChange = exchange.OpenInterest.Sub(openInterest)
+			}
+		}
+		stats.Exchanges = append(stats.Exchanges, exchange)
+		stats.Turnover = stats.Turnover.Add(exchange.Turnover)
+		stats.Trades += exchange.Trades
+		stats.OpenInterestValue = stats.OpenInterestValue.Add(exchange.OpenInterestValue)
+	}
+	sort.Slice(stats.Exchanges, func(i, j int) bool {
+		return stats.Exchanges[i].Turnover.GreaterThan(stats.Exchanges[j].Turnover)
+	})
+
+	movers := append([]ExchangeStats(nil), stats.Exchanges...)
+	sort.SliceStable(movers, func(i, j int) bool {
+		return movers[i].PriceChangePercent.GreaterThan(movers[j].PriceChangePercent)
+	})
+	for i := 0; i < len(movers) && i < top && movers[i].PriceChangePercent.IsPositive(); i++ {
+		stats.TopGainers = append(stats.TopGainers, movers[i])
+	}
+	for i := len(movers) - 1; i >= 0 && len(movers)-1-i < top && movers[i].PriceChangePercent.IsNegative(); i-- {
+		stats.TopLosers = append(stats.TopLosers, movers[i])
+	}
+	return stats, nil
+}
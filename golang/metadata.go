@@ -0,0 +1,89 @@
+package sdk
+
+import (
+	"fmt"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// GetExchange gets exchange information by exchange ID, the exchange list is cached after the first query
+func (c *AntxClient) GetExchange(exchangeId string) (*types.Exchange, error) {
+	c.metaMu.RLock()
+	exchange, ok := c.exchangeCache[exchangeId]
+	c.metaMu.RUnlock()
+	if ok {
+		return &exchange, nil
+	}
+
+	// Cache miss, the exchange may have been listed after the last refresh
+	if err := c.RefreshExchangeCache(); err != nil {
+		return nil, err
+	}
+
+	c.metaMu.RLock()
+	exchange, ok = c.exchangeCache[exchangeId]
+	c.metaMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("exchange %s not found", exchangeId)
+	}
+	return &exchange, nil
+}
+
+// RefreshExchangeCache reloads the cached exchange list from the gateway
+func (c *AntxClient) RefreshExchangeCache() error {
+	exchangeList, err := c.GetExchangeList()
+	if err != nil {
+		return err
+	}
+
+	cache := make(map[string]types.Exchange, len(exchangeList))
+	for _, exchange := range exchangeList {
+		cache[exchange.Id] = exchange
+	}
+
+	c.metaMu.Lock()
+	c.exchangeCache = cache
+	c.metaMu.Unlock()
+	return nil
+}
+
+// GetCoin gets coin information by coin ID, the coin list is cached after the first query
+func (c *AntxClient) GetCoin(coinId string) (*types.Coin, error) {
+	c.metaMu.RLock()
+	coin, ok := c.coinCache[coinId]
+	c.metaMu.RUnlock()
+	if ok {
+		return &coin, nil
+	}
+
+	// Cache miss, the coin may have been listed after the last refresh
+	if err := c.RefreshCoinCache(); err != nil {
+		return nil, err
+	}
+
+	c.metaMu.RLock()
+	coin, ok = c.coinCache[coinId]
+	c.metaMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("coin %s not found", coinId)
+	}
+	return &coin, nil
+}
+
+// RefreshCoinCache reloads the cached coin list from the gateway
+func (c *AntxClient) RefreshCoinCache() error {
+	coinList, err := c.GetCoinList()
+	if err != nil {
+		return err
+	}
+
+	cache := make(map[string]types.Coin, len(coinList))
+	for _, coin := range coinList {
+		cache[coin.Id] = coin
+	}
+
+	c.metaMu.Lock()
+	c.coinCache = cache
+	c.metaMu.Unlock()
+	return nil
+}
@@ -0,0 +1,108 @@
+package sdk
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/query"
+)
+
+// MetricsCollector receives metrics reported by the SDK, implementations must be safe for concurrent use
+type MetricsCollector interface {
+	// SetGauge sets the current value of a gauge
+	SetGauge(name string, value float64, labels map[string]string)
+}
+
+// HistogramCollector optional interface of a MetricsCollector receiving histogram observations
+type HistogramCollector interface {
+	// ObserveHistogram records one observation of a histogram
+	ObserveHistogram(name string, value float64, labels map[string]string)
+}
+
+// CounterCollector optional interface of a MetricsCollector receiving counters
+type CounterCollector interface {
+	// AddCounter adds a non-negative delta to a counter
+	AddCounter(name string, delta float64, labels map[string]string)
+}
+
+// Metric names reported by the SDK
+const (
+	MetricIndexerLagBlocks  = "antx_indexer_lag_blocks"  // Blocks the indexer is behind the chain
+	MetricIndexerLagSeconds = "antx_indexer_lag_seconds" // Age of the last block handled by the indexer
+	MetricIndexerStale      = "antx_indexer_stale"       // 1 while the indexer lag exceeds its threshold, 0 otherwise
+	MetricTxPhaseSeconds    = "antx_tx_phase_seconds"    // Duration of each phase of sending a transaction
+
+	MetricHTTPRequestSeconds        = "antx_http_request_seconds"              // Duration of each gateway HTTP request attempt, by method and path
+	MetricHTTPRequests              = "antx_http_requests_total"               // Gateway HTTP request attempts by method, path and status, "error" on transport failures, counter
+	MetricHTTPRequestErrors         = "antx_http_request_errors_total"         // Gateway HTTP request attempts failing with a transport error or a non-2xx status, by method and path, counter
+	MetricOrderDuplicatesSuppressed = "antx_order_duplicates_suppressed_total" // Orders rejected by the deduplication cache, counter
+	MetricTxBroadcastFailures       = "antx_tx_broadcast_failures_total"       // Signed transactions the gateway failed to accept, by message type, counter
+	MetricWebSocketReconnects       = "antx_ws_reconnects_total"               // Reconnections of the fill and event streams, by stream, counter
+	MetricWebSocketDropped          = "antx_ws_dropped_messages_total"         // WebSocket messages dropped on full subscriber channels, by channel, counter
+)
+
+// SetMetricsCollector sets the collector receiving SDK metrics, nil disables metrics. Gateway requests, transaction
+// broadcasts, stream reconnections and dropped WebSocket messages are reported without further setup, counters and
+// histograms require the collector to implement CounterCollector and HistogramCollector.
+func (c *AntxClient) SetMetricsCollector(collector MetricsCollector) {
+	c.metrics = collector
+}
+
+// setGauge reports a gauge to the metrics collector if one is set
+func (c *AntxClient) setGauge(name string, value float64, labels map[string]string) {
+	if c.metrics != nil {
+		c.metrics.SetGauge(name, value, labels)
+	}
+}
+
+// observeHistogram reports a histogram observation if the metrics collector implements HistogramCollector
+func (c *AntxClient) observeHistogram(name string, value float64, labels map[string]string) {
+	if histograms, ok := c.metrics.(HistogramCollector); ok {
+		histograms.ObserveHistogram(name, value, labels)
+	}
+}
+
+// addCounter adds to a counter if the metrics collector implements CounterCollector
+func (c *AntxClient) addCounter(name string, delta float64, labels map[string]string) {
+	if counters, ok := c.metrics.(CounterCollector); ok {
+		counters.AddCounter(name, delta, labels)
+	}
+}
+
+// httpMetrics middleware reporting the count, latency and failures of the gateway requests
+func (c *AntxClient) httpMetrics(next query.RoundTripFunc) query.RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		if c.metrics == nil {
+			return next(req)
+		}
+		start := time.Now()
+		resp, err := next(req)
+		labels := map[string]string{"method": req.Method, "path": metricPath(query.RequestPath(req))}
+		c.observeHistogram(MetricHTTPRequestSeconds, time.Since(start).Seconds(), labels)
+		status := "error"
+		if err == nil {
+			status = strconv.Itoa(resp.StatusCode)
+		}
+		c.addCounter(MetricHTTPRequests, 1, map[string]string{"method": labels["method"], "path": labels["path"], "status": status})
+		if err != nil || resp.StatusCode < 200 || resp.StatusCode > 299 {
+			c.addCounter(MetricHTTPRequestErrors, 1, labels)
+		}
+		return resp, err
+	}
+}
+
+// metricPath returns the path label of a gateway path, without the hash of the explorer transaction path
+func metricPath(path string) string {
+	if strings.HasPrefix(path, constants.GetTransactionPath+"/") {
+		return constants.GetTransactionPath
+	}
+	return path
+}
+
+// countDroppedMessage reports a WebSocket message dropped on a full subscriber channel
+func (c *AntxClient) countDroppedMessage(channel string) {
+	c.addCounter(MetricWebSocketDropped, 1, map[string]string{"channel": channel})
+}
@@ -0,0 +1,52 @@
+package sdk
+
+import (
+	"context"
+
+	"github.com/antxprotocol/antx-sdk-golang/query"
+	"github.com/antxprotocol/antx-sdk-golang/sign"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// RoundTripFunc sends a gateway request, see query.RoundTripFunc
+type RoundTripFunc = query.RoundTripFunc
+
+// Middleware wraps the sending of the gateway requests, see query.Middleware and Use
+type Middleware = query.Middleware
+
+// BroadcastTx signed transaction about to be broadcast
+type BroadcastTx struct {
+	Msg     sdk.Msg                // Message of the transaction, a MsgExec under an authz grant
+	Options sign.TxOptions         // Parameters the transaction was signed with: sequence, gas limit, fee and timeout
+	Request types.SendRawTxRequest // Request sent to the gateway, a middleware may replace it, e.g. with a transaction signed again
+}
+
+// BroadcastFunc broadcasts a signed transaction and returns the gateway response
+type BroadcastFunc func(ctx context.Context, tx *BroadcastTx) (*types.SendRawTxResponse, error)
+
+// BroadcastMiddleware wraps the broadcast of every signed transaction, e.g. to record metrics, audit or hold
+// transactions. It runs with the transaction lock held, so it must not call Reload.
+type BroadcastMiddleware func(next BroadcastFunc) BroadcastFunc
+
+// UseBroadcast adds middleware around the broadcast of the transactions sent afterwards, the first added is the
+// outermost. The gateway request of the broadcast also passes through the middleware added with Use.
+func (c *AntxClient) UseBroadcast(middleware ...BroadcastMiddleware) {
+	c.broadcastMu.Lock()
+	defer c.broadcastMu.Unlock()
+	c.broadcastMiddleware = append(c.broadcastMiddleware[:len(c.broadcastMiddleware):len(c.broadcastMiddleware)], middleware...)
+}
+
+// broadcast sends a signed transaction through the broadcast middleware
+func (c *AntxClient) broadcast(ctx context.Context, tx *BroadcastTx) (*types.SendRawTxResponse, error) {
+	c.broadcastMu.RLock()
+	middleware := c.broadcastMiddleware
+	c.broadcastMu.RUnlock()
+	next := BroadcastFunc(func(ctx context.Context, tx *BroadcastTx) (*types.SendRawTxResponse, error) {
+		return c.Client.SendRawTxContext(ctx, tx.Request)
+	})
+	for i := len(middleware) - 1; i >= 0; i-- {
+		next = middleware[i](next)
+	}
+	return next(ctx, tx)
+}
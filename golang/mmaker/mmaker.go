@@ -0,0 +1,376 @@
+// Package mmaker maintains two-sided quotes on an exchange around a pluggable fair price
+package mmaker
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	sdk "github.com/antxprotocol/antx-sdk-golang"
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// DefaultDepthLevel default depth subscription level
+	DefaultDepthLevel = "200"
+	// DefaultRequoteInterval default interval at which quotes are refreshed regardless of price moves
+	DefaultRequoteInterval = 30 * time.Second
+)
+
+// Config market maker configuration
+type Config struct {
+	SubaccountId     uint64          // Subaccount ID
+	ExchangeId       string          // Exchange ID
+	DepthLevel       string          // Depth subscription level, defaults to DefaultDepthLevel
+	Spread           decimal.Decimal // Distance between the innermost bid and ask relative to the fair price, e.g. 0.002 for 20 bps
+	LevelSpacing     decimal.Decimal // Distance between successive levels relative to the fair price
+	QuoteSize        decimal.Decimal // Size of each quote, rounded down to the step size
+	Levels           int             // Quotes per side, defaults to 1
+	MaxInventory     decimal.Decimal // Absolute position at which the side adding to it stops quoting, zero disables
+	InventorySkew    decimal.Decimal // Shift of the quote center relative to the fair price at MaxInventory
+	RequoteThreshold decimal.Decimal // Relative fair price move that triggers a requote, zero requotes on every depth update
+	RequoteInterval  time.Duration   // Quotes are refreshed at least this often, defaults to DefaultRequoteInterval
+	Pricer           Pricer          // Fair price source, defaults to MidPricer
+	QuoteConfig      sdk.QuoteConfig // Order settings of the quotes
+	ErrorHandler     func(error)     // Called on quoting errors, errors are logged when nil
+}
+
+// MarketMaker keeps quotes around the fair price using the order tracker, depth stream and batch order APIs
+type MarketMaker struct {
+	client   *sdk.AntxClient
+	config   Config
+	exchange *types.Exchange
+	book     *sdk.OrderBook
+
+	mu        sync.Mutex
+	inventory decimal.Decimal
+	lastFair  decimal.Decimal
+	dirty     bool
+	halted    error
+
+	requoteMu sync.Mutex
+	done      chan struct{}
+	wg        sync.WaitGroup
+	once      sync.Once
+}
+
+// New creates a market maker, the client must be connected to the WebSocket before Start
+func New(client *sdk.AntxClient, config Config) (*MarketMaker, error) {
+	if config.SubaccountId == 0 {
+		return nil, fmt.Errorf("subaccount ID must be greater than 0")
+	}
+	if config.DepthLevel == "" {
+		config.DepthLevel = DefaultDepthLevel
+	}
+	if config.Levels <= 0 {
+		config.Levels = 1
+	}
+	if config.RequoteInterval <= 0 {
+		config.RequoteInterval = DefaultRequoteInterval
+	}
+	if config.Pricer == nil {
+		config.Pricer = MidPricer{}
+	}
+	if !config.Spread.IsPositive() {
+		return nil, fmt.Errorf("spread must be greater than 0")
+	}
+	if config.LevelSpacing.IsNegative() || config.InventorySkew.IsNegative() || config.MaxInventory.IsNegative() || config.RequoteThreshold.IsNegative() {
+		return nil, fmt.Errorf("level spacing, inventory skew, max inventory and requote threshold cannot be negative")
+	}
+
+	exchange, err := client.GetExchange(config.ExchangeId)
+	if err != nil {
+		return nil, err
+	}
+	config.QuoteSize = config.QuoteSize.RoundFloor(exchange.StepSizeScale)
+	if !config.QuoteSize.IsPositive() {
+		return nil, fmt.Errorf("quote size must be at least the step size %s", sdk.StepSize(exchange).String())
+	}
+
+	return &MarketMaker{
+		client:   client,
+		config:   config,
+		exchange: exchange,
+		book:     sdk.NewOrderBook(exchange.Id),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// Start subscribes to depth and to the private order updates and maintains quotes until Stop is called
+func (m *MarketMaker) Start() error {
+	depthChan, err := m.client.SubscribeToDepth(m.exchange.Id, m.config.DepthLevel)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to depth: %w", err)
+	}
+	tradeDataChan, err := m.client.SubscribeToTradeData()
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to trade data: %w", err)
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(m.config.RequoteInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.done:
+				return
+			case msg := <-depthChan:
+				m.onDepth(msg)
+			case msg := <-tradeDataChan:
+				m.onTradeData(msg)
+			case <-ticker.C:
+				// Fills change live order sizes, resync before the periodic refresh
+				if err := m.client.OrderTracker(m.config.SubaccountId).Sync(); err != nil {
+					m.report(err)
+					continue
+				}
+				if err := m.requote(true); err != nil {
+					m.report(err)
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops quoting and cancels the live quotes
+func (m *MarketMaker) Stop() error {
+	m.once.Do(func() { close(m.done) })
+	m.wg.Wait()
+	return m.CancelQuotes()
+}
+
+// CancelQuotes cancels all live quotes of the market maker
+func (m *MarketMaker) CancelQuotes() error {
+	m.requoteMu.Lock()
+	defer m.requoteMu.Unlock()
+	_, err := m.client.UpdateQuotesWithConfig(m.config.SubaccountId, m.exchange.Id, nil, nil, m.config.QuoteConfig)
+	return err
+}
+
+// SetInventory sets the current position used for inventory skew, positive for long and negative for short
+func (m *MarketMaker) SetInventory(position decimal.Decimal) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.inventory.Equal(position) {
+		m.inventory = position
+		m.dirty = true
+	}
+}
+
+// WatchInventory follows the inventory of the quoted exchange and halts quoting when a limit of the tracker is breached
+func (m *MarketMaker) WatchInventory(tracker *sdk.InventoryTracker) {
+	tracker.OnUpdate(func(inventory sdk.Inventory) {
+		if inventory.ExchangeId == m.exchange.Id {
+			m.SetInventory(inventory.NetPosition)
+		}
+	})
+	tracker.OnBreach(func(breach sdk.InventoryBreach) {
+		if breach.ExchangeId == "" || breach.ExchangeId == m.exchange.Id {
+			if err := m.Halt(breach); err != nil {
+				m.report(err)
+			}
+		}
+	})
+}
+
+// Halt stops quoting and cancels the live quotes until Resume is called
+func (m *MarketMaker) Halt(reason error) error {
+	m.mu.Lock()
+	alreadyHalted := m.halted != nil
+	m.halted = reason
+	m.mu.Unlock()
+	if alreadyHalted {
+		return nil
+	}
+
+	m.report(fmt.Errorf("quoting halted: %w", reason))
+	if err := m.CancelQuotes(); err != nil {
+		return fmt.Errorf("failed to cancel quotes on halt: %w", err)
+	}
+	return nil
+}
+
+// Resume resumes quoting after a halt
+func (m *MarketMaker) Resume() {
+	m.mu.Lock()
+	m.halted = nil
+	m.dirty = true
+	m.mu.Unlock()
+}
+
+// Halted returns the reason quoting is halted, nil while quoting
+func (m *MarketMaker) Halted() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.halted
+}
+
+// Book returns the local order book
+func (m *MarketMaker) Book() *sdk.OrderBook {
+	return m.book
+}
+
+// Requote recomputes and sends quotes immediately
+func (m *MarketMaker) Requote() error {
+	return m.requote(true)
+}
+
+// Quotes computes the bid and ask quotes for a fair price and inventory
+func (m *MarketMaker) Quotes(fair, inventory decimal.Decimal) (bids []sdk.Quote, asks []sdk.Quote) {
+	one := decimal.NewFromInt(1)
+	half := m.config.Spread.Div(decimal.NewFromInt(2))
+
+	// Long inventory shifts quotes down to sell more, short inventory shifts them up
+	center := fair
+	quoteBids, quoteAsks := true, true
+	if m.config.MaxInventory.IsPositive() {
+		ratio := inventory.Div(m.config.MaxInventory)
+		ratio = decimal.Max(decimal.Min(ratio, one), one.Neg())
+		center = fair.Mul(one.Sub(m.config.InventorySkew.Mul(ratio)))
+		quoteBids = inventory.LessThan(m.config.MaxInventory)
+		quoteAsks = inventory.GreaterThan(m.config.MaxInventory.Neg())
+	}
+
+	// Post-only quotes must not cross the book
+	tick := sdk.TickSize(m.exchange)
+	bestBid, hasBid := m.book.BestBid()
+	bestAsk, hasAsk := m.book.BestAsk()
+
+	for i := 0; i < m.config.Levels; i++ {
+		offset := half.Add(m.config.LevelSpacing.Mul(decimal.NewFromInt(int64(i))))
+		if quoteBids {
+			price := center.Mul(one.Sub(offset)).RoundFloor(m.exchange.TickSizeScale)
+			if hasAsk && price.GreaterThanOrEqual(bestAsk.Price) {
+				price = bestAsk.Price.Sub(tick)
+			}
+			if price.IsPositive() && (len(bids) == 0 || price.LessThan(bids[len(bids)-1].Price)) {
+				bids = append(bids, sdk.Quote{Price: price, Size: m.config.QuoteSize})
+			}
+		}
+		if quoteAsks {
+			price := center.Mul(one.Add(offset)).RoundCeil(m.exchange.TickSizeScale)
+			if hasBid && price.LessThanOrEqual(bestBid.Price) {
+				price = bestBid.Price.Add(tick)
+			}
+			if len(asks) == 0 || price.GreaterThan(asks[len(asks)-1].Price) {
+				asks = append(asks, sdk.Quote{Price: price, Size: m.config.QuoteSize})
+			}
+		}
+	}
+	return bids, asks
+}
+
+// onDepth applies a depth message and requotes when the fair price moved enough
+func (m *MarketMaker) onDepth(msg []byte) {
+	depth, err := m.client.ParseDepthData(msg)
+	if err != nil {
+		m.report(err)
+		return
+	}
+	if err := m.book.Apply(depth); err != nil {
+		m.report(err)
+		if errors.Is(err, sdk.ErrDepthGap) {
+			if err := m.client.ResubscribeToDepth(m.exchange.Id, m.config.DepthLevel); err != nil {
+				m.report(fmt.Errorf("failed to resubscribe to depth: %w", err))
+			}
+		}
+		return
+	}
+	if err := m.requote(false); err != nil {
+		m.report(err)
+	}
+}
+
+// onTradeData applies the order updates of a private event to the order tracker and requotes when a quote filled or
+// left the book, so its level is replaced without waiting for the requote interval
+func (m *MarketMaker) onTradeData(msg []byte) {
+	event, err := m.client.ParseTradeDataEvent(msg)
+	if err != nil {
+		m.report(err)
+		return
+	}
+	if err := m.client.OrderTracker(m.config.SubaccountId).ApplyTradeData(event); err != nil {
+		m.report(err)
+		return
+	}
+	subaccountId := strconv.FormatUint(m.config.SubaccountId, 10)
+	for _, order := range event.OrderList {
+		if order.SubaccountId != subaccountId || order.ExchangeId != m.exchange.Id {
+			continue
+		}
+		if order.Status != constants.OrderStatusPending || !isZero(order.CumFillSize) {
+			if err := m.requote(true); err != nil {
+				m.report(err)
+			}
+			return
+		}
+	}
+}
+
+// isZero reports whether a decimal string is empty or zero
+func isZero(s string) bool {
+	d, err := decimal.NewFromString(s)
+	return err != nil || d.IsZero()
+}
+
+// requote sends new quotes, unless not forced and neither the fair price nor the inventory moved enough
+func (m *MarketMaker) requote(force bool) error {
+	if !m.book.Ready() {
+		return nil
+	}
+	fair, err := m.config.Pricer.FairPrice(m.book)
+	if err != nil {
+		return err
+	}
+	if !fair.IsPositive() {
+		return fmt.Errorf("fair price %s must be greater than 0", fair.String())
+	}
+
+	m.mu.Lock()
+	if m.halted != nil {
+		m.mu.Unlock()
+		return nil
+	}
+	inventory := m.inventory
+	if !force && !m.dirty && !m.lastFair.IsZero() {
+		move := fair.Sub(m.lastFair).Abs().Div(m.lastFair)
+		if move.LessThan(m.config.RequoteThreshold) {
+			m.mu.Unlock()
+			return nil
+		}
+	}
+	m.mu.Unlock()
+
+	m.requoteMu.Lock()
+	defer m.requoteMu.Unlock()
+	// Halted while waiting for a concurrent update
+	if m.Halted() != nil {
+		return nil
+	}
+	bids, asks := m.Quotes(fair, inventory)
+	if _, err := m.client.UpdateQuotesWithConfig(m.config.SubaccountId, m.exchange.Id, bids, asks, m.config.QuoteConfig); err != nil {
+		return fmt.Errorf("failed to update quotes: %w", err)
+	}
+
+	m.mu.Lock()
+	m.lastFair = fair
+	m.dirty = !m.inventory.Equal(inventory)
+	m.mu.Unlock()
+	return nil
+}
+
+// report passes an error to the configured handler
+func (m *MarketMaker) report(err error) {
+	if m.config.ErrorHandler != nil {
+		m.config.ErrorHandler(err)
+		return
+	}
+	m.client.Logger().Errorf("mmaker: exchange %s: %v", m.exchange.Id, err)
+}
@@ -0,0 +1,51 @@
+package mmaker
+
+import (
+	"fmt"
+
+	sdk "github.com/antxprotocol/antx-sdk-golang"
+	"github.com/shopspring/decimal"
+)
+
+// Pricer provides the fair price quotes are centered on
+type Pricer interface {
+	// FairPrice returns the fair price from the current order book
+	FairPrice(book *sdk.OrderBook) (decimal.Decimal, error)
+}
+
+// PricerFunc adapts a function to a Pricer
+type PricerFunc func(book *sdk.OrderBook) (decimal.Decimal, error)
+
+// FairPrice calls f(book)
+func (f PricerFunc) FairPrice(book *sdk.OrderBook) (decimal.Decimal, error) {
+	return f(book)
+}
+
+// MidPricer prices at the midpoint of the best bid and ask
+type MidPricer struct{}
+
+// FairPrice returns the book mid
+func (MidPricer) FairPrice(book *sdk.OrderBook) (decimal.Decimal, error) {
+	mid, ok := book.Mid()
+	if !ok {
+		return decimal.Zero, fmt.Errorf("order book of exchange %s has no two-sided market", book.ExchangeId)
+	}
+	return mid, nil
+}
+
+// WeightedMidPricer prices at the top-of-book mid weighted by the opposite side size, leaning towards the thinner side
+type WeightedMidPricer struct{}
+
+// FairPrice returns the size-weighted mid
+func (WeightedMidPricer) FairPrice(book *sdk.OrderBook) (decimal.Decimal, error) {
+	bid, okBid := book.BestBid()
+	ask, okAsk := book.BestAsk()
+	if !okBid || !okAsk {
+		return decimal.Zero, fmt.Errorf("order book of exchange %s has no two-sided market", book.ExchangeId)
+	}
+	total := bid.Size.Add(ask.Size)
+	if !total.IsPositive() {
+		return bid.Price.Add(ask.Price).Div(decimal.NewFromInt(2)), nil
+	}
+	return bid.Price.Mul(ask.Size).Add(ask.Price.Mul(bid.Size)).Div(total), nil
+}
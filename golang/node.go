@@ -0,0 +1,38 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// nodeGet sends a GET request to the cosmos-sdk REST API of the node configured in Config.NodeAPI
+func (c *AntxClient) nodeGet(path string, params map[string]string, result interface{}, resp *types.NodeResp) error {
+	if c.node == nil {
+		return fmt.Errorf("node API is not configured")
+	}
+	if err := c.node.Do(context.Background(), http.MethodGet, path, params, nil, result); err != nil {
+		return err
+	}
+	if resp.Code != 0 {
+		return fmt.Errorf("node query %s failed: code %d: %s", path, resp.Code, resp.Message)
+	}
+	return nil
+}
+
+// nodeGetPages calls page for each page of a REST API list until the pagination of its response has no next key
+func nodeGetPages(page func(params map[string]string) (*types.NodePageResp, error)) error {
+	params := map[string]string{}
+	for {
+		pagination, err := page(params)
+		if err != nil {
+			return err
+		}
+		if pagination == nil || pagination.NextKey == "" {
+			return nil
+		}
+		params["pagination.key"] = pagination.NextKey
+	}
+}
@@ -0,0 +1,104 @@
+package sdk
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/query"
+)
+
+// Option sets a setting of the client created by NewAntxClient
+type Option func(*Config)
+
+// WithConfig sets all the settings of config, replacing those of the options before it, so options after it adjust
+// an existing configuration
+func WithConfig(config Config) Option {
+	return func(c *Config) {
+		*c = config
+	}
+}
+
+// WithChainID sets the chain ID, e.g. "antx-devnet"
+func WithChainID(chainID string) Option {
+	return func(c *Config) {
+		c.ChainID = chainID
+	}
+}
+
+// WithEthPrivateKey sets the ETH private key in hexadecimal string
+func WithEthPrivateKey(key string) Option {
+	return func(c *Config) {
+		c.EthPrivateKey = key
+	}
+}
+
+// WithAgentPrivateKey sets the agent private key in hexadecimal string
+func WithAgentPrivateKey(key string) Option {
+	return func(c *Config) {
+		c.AgentPrivateKey = key
+	}
+}
+
+// WithGateway sets the gateway URI, e.g. "http://127.0.0.1:8080"
+func WithGateway(host string) Option {
+	return func(c *Config) {
+		c.GatewayHost = host
+	}
+}
+
+// WithWebSocketURL sets the gateway WebSocket URI, e.g. "ws://127.0.0.1:8080/ws"
+func WithWebSocketURL(wsURL string) Option {
+	return func(c *Config) {
+		c.WebSocketURL = wsURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client sending the gateway requests, e.g. with a custom transport
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithTimeout sets the time limit of each gateway request, replacing query.DefaultHTTPTimeout
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.HTTPTimeout = timeout
+	}
+}
+
+// WithLogger sets the logger receiving the log messages of the client and its components
+func WithLogger(logger Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// WithHeaders adds headers sent with every gateway request and WebSocket handshake, an empty value removes a default
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Config) {
+		merged := make(map[string]string, len(c.Headers)+len(headers))
+		for k, v := range c.Headers {
+			merged[k] = v
+		}
+		for k, v := range headers {
+			merged[k] = v
+		}
+		c.Headers = merged
+	}
+}
+
+// WithRetryPolicy sets the retries of the gateway requests, replacing query.DefaultRetryPolicy
+func WithRetryPolicy(policy query.RetryPolicy) Option {
+	return func(c *Config) {
+		c.RetryPolicy = &policy
+	}
+}
+
+// WithMockTransport routes the gateway requests to an in-memory fake gateway instead of the network, for tests and dry
+// runs, see query.NewMockTransport
+func WithMockTransport(mock *query.MockTransport) Option {
+	return func(c *Config) {
+		c.MockTransport = mock
+	}
+}
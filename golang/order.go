@@ -1,14 +1,50 @@
 package sdk
 
 import (
+	"context"
+
 	ordertypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/order"
 	"github.com/antxprotocol/antx-sdk-golang/constants"
 	"github.com/antxprotocol/antx-sdk-golang/types"
 )
 
 // CreateOrder creates an order
-func (c *AntxClient) CreateOrder(order *types.CreateOrderParam) (string, error) {
-	msg := ordertypes.MsgCreateOrder{
+func (c *AntxClient) CreateOrder(order *CreateOrderParam) (string, error) {
+	return c.CreateOrderContext(context.Background(), order)
+}
+
+// CreateOrderContext is CreateOrder with a context canceling the account and broadcast requests
+func (c *AntxClient) CreateOrderContext(ctx context.Context, order *CreateOrderParam) (string, error) {
+	if err := ValidateCreateOrderParam(order); err != nil {
+		return "", err
+	}
+	sizeValue, err := c.applyReduceOnlyPolicy(order)
+	if err != nil {
+		return "", err
+	}
+	if err := c.CheckOrderRisk(order); err != nil {
+		return "", err
+	}
+	dedupeKeys, err := c.dedupeOrders(order.SubaccountId, order.ClientOrderId)
+	if err != nil {
+		return "", err
+	}
+
+	msg := c.createOrderMsg(order)
+	msg.SizeValue = sizeValue
+
+	txHash, err := c.signAndSendTx(ctx, constants.MsgCreateOrderTypeURL, &msg, true, order.GasLimit)
+	if err != nil {
+		c.releaseOrders(dedupeKeys, err)
+		return "", err
+	}
+
+	return txHash, nil
+}
+
+// createOrderMsg builds the message creating an order
+func (c *AntxClient) createOrderMsg(order *CreateOrderParam) ordertypes.MsgCreateOrder {
+	return ordertypes.MsgCreateOrder{
 		AgentAddress:      c.GetAgentAddress(),
 		SubaccountId:      order.SubaccountId,
 		ExchangeId:        order.ExchangeId,
@@ -34,25 +70,42 @@ func (c *AntxClient) CreateOrder(order *types.CreateOrderParam) (string, error)
 		IsSetOpenSl:       order.IsSetOpenSl,
 		OpenSlParam:       &order.OpenSlParam,
 	}
+}
+
+// CreateOrderBatch creates orders in batch
+func (c *AntxClient) CreateOrderBatch(orders *CreateOrderBatchParam) (string, error) {
+	return c.CreateOrderBatchContext(context.Background(), orders)
+}
 
-	txHash, err := c.signAndSendTx(constants.MsgCreateOrderTypeURL, &msg, true)
+// CreateOrderBatchContext is CreateOrderBatch with a context canceling the account and broadcast requests
+func (c *AntxClient) CreateOrderBatchContext(ctx context.Context, orders *CreateOrderBatchParam) (string, error) {
+	if err := ValidateCreateOrderBatchParam(orders); err != nil {
+		return "", err
+	}
+	sizeValues, err := c.applyReduceOnlyPolicyBatch(orders)
+	if err != nil {
+		return "", err
+	}
+	if err := c.checkBatchRisk(orders); err != nil {
+		return "", err
+	}
+	clientOrderIds := make([]string, 0, len(orders.CreateOrderParam))
+	for _, order := range orders.CreateOrderParam {
+		clientOrderIds = append(clientOrderIds, order.ClientOrderId)
+	}
+	dedupeKeys, err := c.dedupeOrders(orders.SubaccountId, clientOrderIds...)
 	if err != nil {
 		return "", err
 	}
 
-	return txHash, nil
-}
-
-// CreateOrderBatch creates orders in batch
-func (c *AntxClient) CreateOrderBatch(orders *types.CreateOrderBatchParam) (string, error) {
 	batchList := make([]*ordertypes.CreateOrderParam, 0, len(orders.CreateOrderParam))
-	for _, order := range orders.CreateOrderParam {
+	for i, order := range orders.CreateOrderParam {
 		batchList = append(batchList, &ordertypes.CreateOrderParam{
 			IsBuy:             order.IsBuy,
 			PriceScale:        order.PriceScale,
 			PriceValue:        order.PriceValue,
 			SizeScale:         order.SizeScale,
-			SizeValue:         order.SizeValue,
+			SizeValue:         sizeValues[i],
 			ClientOrderId:     order.ClientOrderId,
 			TimeInForce:       order.TimeInForce,
 			ReduceOnly:        order.ReduceOnly,
@@ -79,8 +132,9 @@ func (c *AntxClient) CreateOrderBatch(orders *types.CreateOrderBatchParam) (stri
 		CreateOrderParam: batchList,
 	}
 
-	txHash, err := c.signAndSendTx(constants.MsgCreateOrderBatchTypeURL, &msg, true)
+	txHash, err := c.signAndSendTx(ctx, constants.MsgCreateOrderBatchTypeURL, &msg, true, orders.GasLimit)
 	if err != nil {
+		c.releaseOrders(dedupeKeys, err)
 		return "", err
 	}
 
@@ -89,13 +143,18 @@ func (c *AntxClient) CreateOrderBatch(orders *types.CreateOrderBatchParam) (stri
 
 // CancelOrder cancels an order
 func (c *AntxClient) CancelOrder(order *types.CancelOrderParam) (string, error) {
+	return c.CancelOrderContext(context.Background(), order)
+}
+
+// CancelOrderContext is CancelOrder with a context canceling the account and broadcast requests
+func (c *AntxClient) CancelOrderContext(ctx context.Context, order *types.CancelOrderParam) (string, error) {
 	msg := ordertypes.MsgCancelOrder{
 		AgentAddress: c.GetAgentAddress(),
 		SubaccountId: order.SubaccountId,
 		OrderId:      order.OrderIdList,
 	}
 
-	txHash, err := c.signAndSendTx(constants.MsgCancelOrderTypeURL, &msg, true)
+	txHash, err := c.signAndSendTx(ctx, constants.MsgCancelOrderTypeURL, &msg, true, order.GasLimit)
 	if err != nil {
 		return "", err
 	}
@@ -105,13 +164,18 @@ func (c *AntxClient) CancelOrder(order *types.CancelOrderParam) (string, error)
 
 // CancelOrderByClientId cancels an order by client ID
 func (c *AntxClient) CancelOrderByClientId(order *types.CancelOrderByClientIdParam) (string, error) {
+	return c.CancelOrderByClientIdContext(context.Background(), order)
+}
+
+// CancelOrderByClientIdContext is CancelOrderByClientId with a context canceling the account and broadcast requests
+func (c *AntxClient) CancelOrderByClientIdContext(ctx context.Context, order *types.CancelOrderByClientIdParam) (string, error) {
 	msg := ordertypes.MsgCancelOrderByClientId{
 		AgentAddress:  c.GetAgentAddress(),
 		SubaccountId:  order.SubaccountId,
 		ClientOrderId: order.ClientOrderIdList,
 	}
 
-	txHash, err := c.signAndSendTx(constants.MsgCancelOrderByClientIdTypeURL, &msg, true)
+	txHash, err := c.signAndSendTx(ctx, constants.MsgCancelOrderByClientIdTypeURL, &msg, true, order.GasLimit)
 	if err != nil {
 		return "", err
 	}
@@ -121,13 +185,18 @@ func (c *AntxClient) CancelOrderByClientId(order *types.CancelOrderByClientIdPar
 
 // CancelAllOrder cancels all orders
 func (c *AntxClient) CancelAllOrder(order *types.CancelAllOrderParam) (string, error) {
+	return c.CancelAllOrderContext(context.Background(), order)
+}
+
+// CancelAllOrderContext is CancelAllOrder with a context canceling the account and broadcast requests
+func (c *AntxClient) CancelAllOrderContext(ctx context.Context, order *types.CancelAllOrderParam) (string, error) {
 	msg := ordertypes.MsgCancelAllOrder{
 		AgentAddress:     c.GetAgentAddress(),
 		SubaccountId:     order.SubaccountId,
 		FilterExchangeId: order.FilterExchangeIdList,
 	}
 
-	txHash, err := c.signAndSendTx(constants.MsgCancelAllOrderTypeURL, &msg, true)
+	txHash, err := c.signAndSendTx(ctx, constants.MsgCancelAllOrderTypeURL, &msg, true, order.GasLimit)
 	if err != nil {
 		return "", err
 	}
@@ -137,13 +206,18 @@ func (c *AntxClient) CancelAllOrder(order *types.CancelAllOrderParam) (string, e
 
 // CloseAllPosition closes all positions
 func (c *AntxClient) CloseAllPosition(order *types.CloseAllPositionParam) (string, error) {
+	return c.CloseAllPositionContext(context.Background(), order)
+}
+
+// CloseAllPositionContext is CloseAllPosition with a context canceling the account and broadcast requests
+func (c *AntxClient) CloseAllPositionContext(ctx context.Context, order *types.CloseAllPositionParam) (string, error) {
 	msg := ordertypes.MsgCloseAllPosition{
 		AgentAddress:     c.GetAgentAddress(),
 		SubaccountId:     order.SubaccountId,
 		FilterExchangeId: order.FilterExchangeIdList,
 	}
 
-	txHash, err := c.signAndSendTx(constants.MsgCloseAllPositionTypeURL, &msg, true)
+	txHash, err := c.signAndSendTx(ctx, constants.MsgCloseAllPositionTypeURL, &msg, true, order.GasLimit)
 	if err != nil {
 		return "", err
 	}
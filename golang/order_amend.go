@@ -0,0 +1,370 @@
+package sdk
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	exchangetypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/exchange"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+const (
+	// DefaultAmendFlushInterval default interval between amendment queue flushes
+	DefaultAmendFlushInterval = 100 * time.Millisecond
+	// DefaultAmendMessagesPerSecond default per-subaccount message rate cap of the amendment queue
+	DefaultAmendMessagesPerSecond = 10
+)
+
+// OrderAmendment replaces a live order, identified by client order ID, with a new order
+type OrderAmendment struct {
+	SubaccountId  uint64                   // Subaccount ID
+	ExchangeId    uint64                   // Exchange ID
+	MarginMode    exchangetypes.MarginMode // Margin mode of the replacement order
+	Leverage      uint32                   // Leverage of the replacement order
+	ClientOrderId string                   // Client order ID of the live order to cancel, empty to only create
+	Order         *CreateOrderBatchDetail  // Replacement order, nil to only cancel
+}
+
+// AmendQueueConfig amendment queue configuration
+type AmendQueueConfig struct {
+	FlushInterval     time.Duration                            // Interval between flushes, defaults to DefaultAmendFlushInterval
+	MessagesPerSecond int                                      // Per-subaccount message rate cap, defaults to DefaultAmendMessagesPerSecond
+	MaxBatchSize      int                                      // Maximum orders per batch message, 0 means unlimited
+	ResultHandler     func(subaccountId uint64, txHash string) // Called with the transaction hash of each flushed message
+	ErrorHandler      func(error)                              // Called when a flushed message fails, errors are logged when nil
+}
+
+// AmendQueue coalesces rapid successive amendments of the same order and flushes them as batch messages under a per-subaccount rate cap
+type AmendQueue struct {
+	client *AntxClient
+	config AmendQueueConfig
+
+	mu       sync.Mutex
+	pending  []*OrderAmendment
+	byCancel map[amendKey]*OrderAmendment // pending amendments by the client order ID they cancel
+	byCreate map[amendKey]*OrderAmendment // pending amendments by the client order ID they create
+	buckets  map[uint64]*amendBucket
+
+	flushMu sync.Mutex
+	done    chan struct{}
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// amendKey client order IDs are unique per subaccount
+type amendKey struct {
+	subaccountId  uint64
+	clientOrderId string
+}
+
+// amendGroup orders sharing one batch message
+type amendGroup struct {
+	exchangeId uint64
+	marginMode exchangetypes.MarginMode
+	leverage   uint32
+}
+
+// amendBucket token bucket of a subaccount
+type amendBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// amendFlush messages of one subaccount taken from the queue in a flush
+type amendFlush struct {
+	subaccountId uint64
+	cancels      []string
+	batches      []*CreateOrderBatchParam
+}
+
+// NewAmendQueue creates an amendment queue, call Start to flush periodically or Flush to flush manually
+func (c *AntxClient) NewAmendQueue(config AmendQueueConfig) *AmendQueue {
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultAmendFlushInterval
+	}
+	if config.MessagesPerSecond <= 0 {
+		config.MessagesPerSecond = DefaultAmendMessagesPerSecond
+	}
+	return &AmendQueue{
+		client:   c,
+		config:   config,
+		byCancel: make(map[amendKey]*OrderAmendment),
+		byCreate: make(map[amendKey]*OrderAmendment),
+		buckets:  make(map[uint64]*amendBucket),
+		done:     make(chan struct{}),
+	}
+}
+
+// Start flushes the queue every flush interval until Stop is called
+func (q *AmendQueue) Start() {
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		ticker := time.NewTicker(q.config.FlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-q.done:
+				return
+			case <-ticker.C:
+				q.Flush()
+			}
+		}
+	}()
+}
+
+// Stop stops periodic flushing, pending amendments stay queued until Flush is called
+func (q *AmendQueue) Stop() {
+	q.once.Do(func() { close(q.done) })
+	q.wg.Wait()
+}
+
+// Amend queues an amendment, replacing any pending amendment of the same order that has not been sent yet
+func (q *AmendQueue) Amend(amendment OrderAmendment) error {
+	if amendment.ClientOrderId == "" && amendment.Order == nil {
+		return fmt.Errorf("invalid amendment: neither a client order ID to cancel nor a replacement order is set")
+	}
+	if amendment.Order != nil {
+		if amendment.Order.ClientOrderId != "" && amendment.Order.ClientOrderId == amendment.ClientOrderId {
+			return fmt.Errorf("invalid amendment: replacement order must use a new client order ID")
+		}
+		if err := ValidateCreateOrderBatchParam(&CreateOrderBatchParam{
+			SubaccountId:     amendment.SubaccountId,
+			ExchangeId:       amendment.ExchangeId,
+			MarginMode:       amendment.MarginMode,
+			Leverage:         amendment.Leverage,
+			CreateOrderParam: []*CreateOrderBatchDetail{amendment.Order},
+		}); err != nil {
+			return fmt.Errorf("invalid amendment: %w", err)
+		}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	cancelKey := amendKey{amendment.SubaccountId, amendment.ClientOrderId}
+	if amendment.ClientOrderId != "" {
+		// The order to cancel has not been created yet, amend the pending creation instead
+		if prev, ok := q.byCreate[cancelKey]; ok {
+			delete(q.byCreate, cancelKey)
+			if amendment.Order == nil && prev.ClientOrderId == "" {
+				q.remove(prev)
+				return nil
+			}
+			prev.ExchangeId = amendment.ExchangeId
+			prev.MarginMode = amendment.MarginMode
+			prev.Leverage = amendment.Leverage
+			prev.Order = amendment.Order
+			q.indexCreate(prev)
+			return nil
+		}
+		// The same order was amended again before the previous amendment was sent, the latest wins
+		if prev, ok := q.byCancel[cancelKey]; ok {
+			q.unindexCreate(prev)
+			prev.ExchangeId = amendment.ExchangeId
+			prev.MarginMode = amendment.MarginMode
+			prev.Leverage = amendment.Leverage
+			prev.Order = amendment.Order
+			q.indexCreate(prev)
+			return nil
+		}
+	}
+
+	entry := amendment
+	q.pending = append(q.pending, &entry)
+	if entry.ClientOrderId != "" {
+		q.byCancel[cancelKey] = &entry
+	}
+	q.indexCreate(&entry)
+	return nil
+}
+
+// Cancel queues a cancellation of a live order by client order ID
+func (q *AmendQueue) Cancel(subaccountId, exchangeId uint64, clientOrderId string) error {
+	return q.Amend(OrderAmendment{SubaccountId: subaccountId, ExchangeId: exchangeId, ClientOrderId: clientOrderId})
+}
+
+// Pending returns the number of queued amendments
+func (q *AmendQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// Flush sends queued amendments as batch messages, amendments beyond the rate cap of their subaccount stay queued
+func (q *AmendQueue) Flush() {
+	q.flushMu.Lock()
+	defer q.flushMu.Unlock()
+
+	for _, flush := range q.take(time.Now()) {
+		if len(flush.cancels) > 0 {
+			txHash, err := q.client.CancelOrderByClientId(&types.CancelOrderByClientIdParam{
+				AgentAddress:      q.client.GetAgentAddress(),
+				SubaccountId:      flush.subaccountId,
+				ClientOrderIdList: flush.cancels,
+			})
+			q.report(flush.subaccountId, txHash, err, "cancel")
+		}
+		for _, batch := range flush.batches {
+			txHash, err := q.client.CreateOrderBatch(batch)
+			q.report(flush.subaccountId, txHash, err, "create")
+		}
+	}
+}
+
+// take removes the amendments that fit into the rate cap from the queue and groups them into messages
+func (q *AmendQueue) take(now time.Time) []*amendFlush {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var flushes []*amendFlush
+	bySubaccount := make(map[uint64]*amendFlush)
+	groups := make(map[uint64][]amendGroup)
+	grouped := make(map[uint64]map[amendGroup][]*OrderAmendment)
+	for _, entry := range q.pending {
+		if _, ok := bySubaccount[entry.SubaccountId]; !ok {
+			flush := &amendFlush{subaccountId: entry.SubaccountId}
+			bySubaccount[entry.SubaccountId] = flush
+			flushes = append(flushes, flush)
+			grouped[entry.SubaccountId] = make(map[amendGroup][]*OrderAmendment)
+		}
+		if entry.ClientOrderId != "" {
+			bySubaccount[entry.SubaccountId].cancels = append(bySubaccount[entry.SubaccountId].cancels, entry.ClientOrderId)
+		}
+		if entry.Order != nil {
+			group := amendGroup{entry.ExchangeId, entry.MarginMode, entry.Leverage}
+			if _, ok := grouped[entry.SubaccountId][group]; !ok {
+				groups[entry.SubaccountId] = append(groups[entry.SubaccountId], group)
+			}
+			grouped[entry.SubaccountId][group] = append(grouped[entry.SubaccountId][group], entry)
+		}
+	}
+
+	sent := make(map[*OrderAmendment]bool)
+	result := flushes[:0]
+	for _, flush := range flushes {
+		bucket := q.bucket(flush.subaccountId, now)
+
+		// Replacements must not be sent before their cancellations
+		if len(flush.cancels) > 0 {
+			if bucket.tokens < 1 {
+				continue
+			}
+			bucket.tokens--
+			for _, entry := range q.pending {
+				if entry.SubaccountId != flush.subaccountId || entry.ClientOrderId == "" {
+					continue
+				}
+				delete(q.byCancel, amendKey{entry.SubaccountId, entry.ClientOrderId})
+				entry.ClientOrderId = ""
+				if entry.Order == nil {
+					sent[entry] = true
+				}
+			}
+		}
+
+		for _, group := range groups[flush.subaccountId] {
+			entries := grouped[flush.subaccountId][group]
+			for len(entries) > 0 && bucket.tokens >= 1 {
+				n := len(entries)
+				if q.config.MaxBatchSize > 0 && n > q.config.MaxBatchSize {
+					n = q.config.MaxBatchSize
+				}
+				batch := &CreateOrderBatchParam{
+					AgentAddress:     q.client.GetAgentAddress(),
+					SubaccountId:     flush.subaccountId,
+					ExchangeId:       group.exchangeId,
+					MarginMode:       group.marginMode,
+					Leverage:         group.leverage,
+					CreateOrderParam: make([]*CreateOrderBatchDetail, 0, n),
+				}
+				for _, entry := range entries[:n] {
+					batch.CreateOrderParam = append(batch.CreateOrderParam, entry.Order)
+					q.unindexCreate(entry)
+					sent[entry] = true
+				}
+				flush.batches = append(flush.batches, batch)
+				bucket.tokens--
+				entries = entries[n:]
+			}
+		}
+		result = append(result, flush)
+	}
+
+	remaining := q.pending[:0]
+	for _, entry := range q.pending {
+		if !sent[entry] {
+			remaining = append(remaining, entry)
+		}
+	}
+	for i := len(remaining); i < len(q.pending); i++ {
+		q.pending[i] = nil
+	}
+	q.pending = remaining
+	return result
+}
+
+// bucket refills and returns the token bucket of a subaccount
+func (q *AmendQueue) bucket(subaccountId uint64, now time.Time) *amendBucket {
+	limit := float64(q.config.MessagesPerSecond)
+	bucket, ok := q.buckets[subaccountId]
+	if !ok {
+		bucket = &amendBucket{tokens: limit, last: now}
+		q.buckets[subaccountId] = bucket
+		return bucket
+	}
+	bucket.tokens += now.Sub(bucket.last).Seconds() * limit
+	if bucket.tokens > limit {
+		bucket.tokens = limit
+	}
+	bucket.last = now
+	return bucket
+}
+
+// remove drops a pending amendment from the queue
+func (q *AmendQueue) remove(entry *OrderAmendment) {
+	for i, pending := range q.pending {
+		if pending == entry {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			break
+		}
+	}
+	if entry.ClientOrderId != "" {
+		delete(q.byCancel, amendKey{entry.SubaccountId, entry.ClientOrderId})
+	}
+	q.unindexCreate(entry)
+}
+
+// indexCreate indexes a pending amendment by the client order ID of its replacement order
+func (q *AmendQueue) indexCreate(entry *OrderAmendment) {
+	if entry.Order != nil && entry.Order.ClientOrderId != "" {
+		q.byCreate[amendKey{entry.SubaccountId, entry.Order.ClientOrderId}] = entry
+	}
+}
+
+// unindexCreate removes the replacement order index of a pending amendment
+func (q *AmendQueue) unindexCreate(entry *OrderAmendment) {
+	if entry.Order != nil && entry.Order.ClientOrderId != "" {
+		key := amendKey{entry.SubaccountId, entry.Order.ClientOrderId}
+		if q.byCreate[key] == entry {
+			delete(q.byCreate, key)
+		}
+	}
+}
+
+// report passes the result of a flushed message to the configured handlers
+func (q *AmendQueue) report(subaccountId uint64, txHash string, err error, action string) {
+	if err != nil {
+		err = fmt.Errorf("amend queue failed to %s orders of subaccount %d: %w", action, subaccountId, err)
+		if q.config.ErrorHandler != nil {
+			q.config.ErrorHandler(err)
+		} else {
+			q.client.Logger().Errorf("%v", err)
+		}
+		return
+	}
+	if q.config.ResultHandler != nil {
+		q.config.ResultHandler(subaccountId, txHash)
+	}
+}
@@ -0,0 +1,21 @@
+package sdk
+
+// SplitOrderBatch splits a batch into batches of at most maxBatchSize orders, in order. A batch within the limit, or
+// any batch when maxBatchSize is not positive, is returned as is.
+func SplitOrderBatch(batch *CreateOrderBatchParam, maxBatchSize int) []*CreateOrderBatchParam {
+	if maxBatchSize <= 0 || len(batch.CreateOrderParam) <= maxBatchSize {
+		return []*CreateOrderBatchParam{batch}
+	}
+	var batches []*CreateOrderBatchParam
+	for orders := batch.CreateOrderParam; len(orders) > 0; {
+		n := len(orders)
+		if n > maxBatchSize {
+			n = maxBatchSize
+		}
+		part := *batch
+		part.CreateOrderParam = orders[:n:n]
+		batches = append(batches, &part)
+		orders = orders[n:]
+	}
+	return batches
+}
@@ -0,0 +1,264 @@
+package sdk
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	exchangetypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/exchange"
+	ordertypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/order"
+	pricetypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/price"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// OrderBuilder builds a create order parameter from decimal prices and sizes, scaled by the exchange tick and step sizes
+type OrderBuilder struct {
+	exchange      *types.Exchange
+	param         *CreateOrderParam
+	priceRounding RoundingMode
+	sizeRounding  RoundingMode
+	clock         *ServerClock
+	err           error
+}
+
+// NewOrderBuilder creates an order builder for a subaccount on an exchange, defaulting to a cross margin GTC limit order
+func NewOrderBuilder(exchange *types.Exchange, subaccountId uint64) *OrderBuilder {
+	b := &OrderBuilder{
+		exchange: exchange,
+		param: &CreateOrderParam{
+			SubaccountId: subaccountId,
+			MarginMode:   exchangetypes.MarginMode_MARGIN_MODE_CROSS,
+			Leverage:     exchange.Perpetual.DefaultLeverage,
+			PriceScale:   exchange.TickSizeScale,
+			SizeScale:    exchange.StepSizeScale,
+			TimeInForce:  ordertypes.TimeInForce_TIME_IN_FORCE_GOOD_TIL_CANCEL,
+		},
+	}
+	if b.param.Leverage == 0 {
+		b.param.Leverage = 1
+	}
+	exchangeId, err := strconv.ParseUint(exchange.Id, 10, 64)
+	if err != nil {
+		b.err = fmt.Errorf("failed to parse exchange ID %s: %w", exchange.Id, err)
+	}
+	b.param.ExchangeId = exchangeId
+	return b
+}
+
+// NewOrderBuilder creates an order builder using the cached exchange information
+func (c *AntxClient) NewOrderBuilder(exchangeId string, subaccountId uint64) (*OrderBuilder, error) {
+	exchange, err := c.GetExchange(exchangeId)
+	if err != nil {
+		return nil, err
+	}
+	return NewOrderBuilder(exchange, subaccountId).ServerTime(&c.serverClock), nil
+}
+
+// NewStopMarketOrder builds a stop-loss conditional order that sends a market order once the trigger price is crossed
+func NewStopMarketOrder(exchange *types.Exchange, subaccountId uint64, isBuy bool, size, triggerPrice decimal.Decimal) *OrderBuilder {
+	return NewOrderBuilder(exchange, subaccountId).
+		Side(isBuy).
+		Size(size).
+		Market().
+		Trigger(ordertypes.TriggerType_TRIGGER_TYPE_STOP_LOSS, triggerPrice)
+}
+
+// NewStopLimitOrder builds a stop-loss conditional order that places a limit order once the trigger price is crossed
+func NewStopLimitOrder(exchange *types.Exchange, subaccountId uint64, isBuy bool, size, triggerPrice, limitPrice decimal.Decimal) *OrderBuilder {
+	return NewOrderBuilder(exchange, subaccountId).
+		Side(isBuy).
+		Size(size).
+		Limit(limitPrice).
+		Trigger(ordertypes.TriggerType_TRIGGER_TYPE_STOP_LOSS, triggerPrice)
+}
+
+// NewTakeProfitMarketOrder builds a take-profit conditional order that sends a market order once the trigger price is reached
+func NewTakeProfitMarketOrder(exchange *types.Exchange, subaccountId uint64, isBuy bool, size, triggerPrice decimal.Decimal) *OrderBuilder {
+	return NewOrderBuilder(exchange, subaccountId).
+		Side(isBuy).
+		Size(size).
+		Market().
+		Trigger(ordertypes.TriggerType_TRIGGER_TYPE_TAKE_PROFIT, triggerPrice)
+}
+
+// NewTakeProfitLimitOrder builds a take-profit conditional order that places a limit order once the trigger price is reached
+func NewTakeProfitLimitOrder(exchange *types.Exchange, subaccountId uint64, isBuy bool, size, triggerPrice, limitPrice decimal.Decimal) *OrderBuilder {
+	return NewOrderBuilder(exchange, subaccountId).
+		Side(isBuy).
+		Size(size).
+		Limit(limitPrice).
+		Trigger(ordertypes.TriggerType_TRIGGER_TYPE_TAKE_PROFIT, triggerPrice)
+}
+
+// Side sets the order direction
+func (b *OrderBuilder) Side(isBuy bool) *OrderBuilder {
+	b.param.IsBuy = isBuy
+	return b
+}
+
+// Buy sets the order direction to buy
+func (b *OrderBuilder) Buy() *OrderBuilder {
+	return b.Side(true)
+}
+
+// Sell sets the order direction to sell
+func (b *OrderBuilder) Sell() *OrderBuilder {
+	return b.Side(false)
+}
+
+// Rounding sets how prices and sizes off the tick and step grid are handled by the following Limit, Trigger and Size
+// calls, by default they are rejected (RoundStrict), e.g. Rounding(PassiveRounding(isBuy), RoundDown)
+func (b *OrderBuilder) Rounding(price, size RoundingMode) *OrderBuilder {
+	b.priceRounding = price
+	b.sizeRounding = size
+	return b
+}
+
+// Limit makes the order a limit order at the given price, rounded to the tick size with the price rounding mode
+func (b *OrderBuilder) Limit(price decimal.Decimal) *OrderBuilder {
+	price, err := RoundPriceToTick(price, b.exchange, b.priceRounding)
+	if err != nil {
+		return b.fail(fmt.Errorf("invalid price: %w", err))
+	}
+	priceValue, err := ScaleDecimal(price, b.exchange.TickSizeScale)
+	if err != nil {
+		return b.fail(fmt.Errorf("invalid price: %w", err))
+	}
+	b.param.IsMarket = false
+	b.param.PriceScale = b.exchange.TickSizeScale
+	b.param.PriceValue = priceValue
+	if b.param.TimeInForce == ordertypes.TimeInForce_TIME_IN_FORCE_IMMEDIATE_OR_CANCEL {
+		b.param.TimeInForce = ordertypes.TimeInForce_TIME_IN_FORCE_GOOD_TIL_CANCEL
+	}
+	return b
+}
+
+// Market makes the order a market order, defaulting the time in force to IOC
+func (b *OrderBuilder) Market() *OrderBuilder {
+	b.param.IsMarket = true
+	b.param.PriceValue = 0
+	if b.param.TimeInForce == ordertypes.TimeInForce_TIME_IN_FORCE_GOOD_TIL_CANCEL ||
+		b.param.TimeInForce == ordertypes.TimeInForce_TIME_IN_FORCE_POST_ONLY {
+		b.param.TimeInForce = ordertypes.TimeInForce_TIME_IN_FORCE_IMMEDIATE_OR_CANCEL
+	}
+	return b
+}
+
+// Size sets the order size, rounded to the step size with the size rounding mode
+func (b *OrderBuilder) Size(size decimal.Decimal) *OrderBuilder {
+	size, err := RoundSizeToStep(size, b.exchange, b.sizeRounding)
+	if err != nil {
+		return b.fail(fmt.Errorf("invalid size: %w", err))
+	}
+	if err := checkOrderSizeMax(b.exchange, size); err != nil {
+		return b.fail(fmt.Errorf("invalid size: %w", err))
+	}
+	sizeValue, err := ScaleDecimal(size, b.exchange.StepSizeScale)
+	if err != nil {
+		return b.fail(fmt.Errorf("invalid size: %w", err))
+	}
+	b.param.SizeScale = b.exchange.StepSizeScale
+	b.param.SizeValue = sizeValue
+	return b
+}
+
+// TimeInForce sets the order execution strategy
+func (b *OrderBuilder) TimeInForce(timeInForce ordertypes.TimeInForce) *OrderBuilder {
+	b.param.TimeInForce = timeInForce
+	return b
+}
+
+// PostOnly makes the order a post-only limit order
+func (b *OrderBuilder) PostOnly() *OrderBuilder {
+	return b.TimeInForce(ordertypes.TimeInForce_TIME_IN_FORCE_POST_ONLY)
+}
+
+// ReduceOnly marks the order as reduce-only
+func (b *OrderBuilder) ReduceOnly() *OrderBuilder {
+	b.param.ReduceOnly = true
+	return b
+}
+
+// Margin sets the margin mode and leverage of the order
+func (b *OrderBuilder) Margin(marginMode exchangetypes.MarginMode, leverage uint32) *OrderBuilder {
+	b.param.MarginMode = marginMode
+	b.param.Leverage = leverage
+	return b
+}
+
+// ClientOrderId sets the client custom ID used for idempotency
+func (b *OrderBuilder) ClientOrderId(clientOrderId string) *OrderBuilder {
+	b.param.ClientOrderId = clientOrderId
+	return b
+}
+
+// ExpireAt sets the order expiration time
+func (b *OrderBuilder) ExpireAt(expireTime time.Time) *OrderBuilder {
+	b.param.ExpireTime = uint64(expireTime.UnixMilli())
+	return b
+}
+
+// ExpireAfter sets the order expiration time relative to now, in server time when a server clock is set
+func (b *OrderBuilder) ExpireAfter(d time.Duration) *OrderBuilder {
+	if b.clock != nil {
+		return b.ExpireAt(b.clock.Now().Add(d))
+	}
+	return b.ExpireAt(time.Now().Add(d))
+}
+
+// ServerTime measures relative expiration times from a server clock instead of the local clock, builders created by
+// AntxClient.NewOrderBuilder use the clock of the client
+func (b *OrderBuilder) ServerTime(clock *ServerClock) *OrderBuilder {
+	b.clock = clock
+	return b
+}
+
+// Trigger makes the order a conditional order triggered by the last price
+func (b *OrderBuilder) Trigger(triggerType ordertypes.TriggerType, triggerPrice decimal.Decimal) *OrderBuilder {
+	triggerPrice, err := RoundPriceToTick(triggerPrice, b.exchange, b.priceRounding)
+	if err != nil {
+		return b.fail(fmt.Errorf("invalid trigger price: %w", err))
+	}
+	triggerPriceValue, err := ScaleDecimal(triggerPrice, b.exchange.TickSizeScale)
+	if err != nil {
+		return b.fail(fmt.Errorf("invalid trigger price: %w", err))
+	}
+	if triggerPriceValue == 0 {
+		return b.fail(fmt.Errorf("invalid trigger price: must be greater than 0"))
+	}
+	b.param.TriggerType = triggerType
+	b.param.TriggerPriceValue = triggerPriceValue
+	if b.param.TriggerPriceType == pricetypes.PriceType_PRICE_TYPE_UNSPECIFIED {
+		b.param.TriggerPriceType = pricetypes.PriceType_PRICE_TYPE_LAST
+	}
+	return b
+}
+
+// TriggerPriceType sets the price type compared against the trigger price
+func (b *OrderBuilder) TriggerPriceType(priceType pricetypes.PriceType) *OrderBuilder {
+	b.param.TriggerPriceType = priceType
+	return b
+}
+
+// Build validates and returns the create order parameter
+func (b *OrderBuilder) Build() (*CreateOrderParam, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if err := ValidateCreateOrderParam(b.param); err != nil {
+		return nil, err
+	}
+	if err := validateOpenTpSlPrices(b.param); err != nil {
+		return nil, fmt.Errorf("invalid order: %w", err)
+	}
+	return b.param, nil
+}
+
+// fail records the first error encountered while building
+func (b *OrderBuilder) fail(err error) *OrderBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
@@ -0,0 +1,83 @@
+package sdk
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// OrderFillSummary fills of one order with their aggregates
+type OrderFillSummary struct {
+	OrderId      string                       // Order ID
+	FillList     []types.OrderFillTransaction // Fills, oldest first
+	FilledSize   decimal.Decimal              // Total fill size
+	FilledValue  decimal.Decimal              // Total fill value
+	AveragePrice decimal.Decimal              // Fill value divided by fill size, zero when nothing is filled
+	TotalFee     decimal.Decimal              // Total fill fee
+	LiquidateFee decimal.Decimal              // Total liquidation fee
+	RealizedPnl  decimal.Decimal              // Total realized PnL
+	MakerSize    decimal.Decimal              // Size filled as maker
+	FirstFillAt  uint64                       // Creation time of the first fill, unit: milliseconds
+	LastFillAt   uint64                       // Creation time of the last fill, unit: milliseconds
+}
+
+// OrderFills reads all fills of an order of a subaccount and aggregates them
+func (c *AntxClient) OrderFills(subaccountId, orderId string) (*OrderFillSummary, error) {
+	req := types.GetHistoryOrderFillTransactionReq{
+		SubaccountId:      subaccountId,
+		Size:              100,
+		FilterOrderIdList: orderId,
+	}
+	fills, err := types.FetchAllPages(req.Size, func(cursor types.Cursor) ([]types.OrderFillTransaction, types.IndexerPageOffsetData, error) {
+		req.PageOffsetDataCreatedTime, req.PageOffsetDataItemId = cursor.CreatedTime, cursor.ItemId
+		resp, err := c.GetHistoryOrderFillTransaction(req)
+		if err != nil {
+			return nil, types.IndexerPageOffsetData{}, err
+		}
+		return resp.Data.OrderFillTransactionList, resp.Data.PageOffsetData, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return SummarizeOrderFills(orderId, fills)
+}
+
+// SummarizeOrderFills aggregates the fills of an order, fills of other orders are ignored
+func SummarizeOrderFills(orderId string, fills []types.OrderFillTransaction) (*OrderFillSummary, error) {
+	summary := &OrderFillSummary{OrderId: orderId}
+	for _, fill := range fills {
+		if fill.OrderId == orderId {
+			summary.FillList = append(summary.FillList, fill)
+		}
+	}
+	sort.SliceStable(summary.FillList, func(i, j int) bool {
+		return summary.FillList[i].CreatedTime < summary.FillList[j].CreatedTime
+	})
+	for _, fill := range summary.FillList {
+		var values [5]decimal.Decimal
+		for i, value := range []string{fill.FillSize, fill.FillValue, fill.FillFee, fill.LiquidateFee, fill.RealizePnl} {
+			var err error
+			if values[i], err = parseOptionalDecimal(value); err != nil {
+				return nil, fmt.Errorf("failed to parse fill %s: %w", fill.Id, err)
+			}
+		}
+		summary.FilledSize = summary.FilledSize.Add(values[0])
+		summary.FilledValue = summary.FilledValue.Add(values[1])
+		summary.TotalFee = summary.TotalFee.Add(values[2])
+		summary.LiquidateFee = summary.LiquidateFee.Add(values[3])
+		summary.RealizedPnl = summary.RealizedPnl.Add(values[4])
+		if fill.IsMaker {
+			summary.MakerSize = summary.MakerSize.Add(values[0])
+		}
+	}
+	if n := len(summary.FillList); n > 0 {
+		summary.FirstFillAt = summary.FillList[0].CreatedTime
+		summary.LastFillAt = summary.FillList[n-1].CreatedTime
+	}
+	if !summary.FilledSize.IsZero() {
+		summary.AveragePrice = summary.FilledValue.Div(summary.FilledSize)
+	}
+	return summary, nil
+}
@@ -0,0 +1,293 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+const (
+	// DefaultOrderFutureTxTimeout default time a future waits for its transaction to be included
+	DefaultOrderFutureTxTimeout = 30 * time.Second
+	// DefaultOrderFuturePollInterval default interval at which a future polls the order state
+	DefaultOrderFuturePollInterval = time.Second
+)
+
+// OrderStage stage of an asynchronously created order
+type OrderStage int
+
+const (
+	OrderStageSubmitted OrderStage = iota // Submitted, not yet broadcast
+	OrderStageBroadcast                   // Transaction accepted by the gateway
+	OrderStageAccepted                    // Order visible among the active orders
+	OrderStageDone                        // Order filled, cancelled, expired or rejected
+)
+
+// String returns the stage name
+func (s OrderStage) String() string {
+	switch s {
+	case OrderStageSubmitted:
+		return "submitted"
+	case OrderStageBroadcast:
+		return "broadcast"
+	case OrderStageAccepted:
+		return "accepted"
+	case OrderStageDone:
+		return "done"
+	default:
+		return "unknown"
+	}
+}
+
+// OrderFutureConfig asynchronous order configuration
+type OrderFutureConfig struct {
+	TxTimeout    time.Duration // Time to wait for the transaction to be included, defaults to DefaultOrderFutureTxTimeout
+	PollInterval time.Duration // Interval at which the order state is polled, defaults to DefaultOrderFuturePollInterval
+}
+
+// OrderFuture resolves through the stages of an order created with CreateOrderAsync
+type OrderFuture struct {
+	ClientOrderId string // Client order ID of the order
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	stage   OrderStage
+	err     error
+	txHash  string
+	tracked TrackedOrder
+	order   *types.Order
+	closed  bool
+	stop    chan struct{} // Closed by Close, stops the polling goroutine
+}
+
+// CreateOrderAsync creates an order and returns a future resolving as the order is broadcast, accepted and done,
+// the order must carry a client order ID. The future polls until the order is done or the future is closed.
+func (c *AntxClient) CreateOrderAsync(order *CreateOrderParam) *OrderFuture {
+	return c.CreateOrderAsyncContext(context.Background(), order, OrderFutureConfig{})
+}
+
+// CreateOrderAsyncWithConfig creates an order asynchronously with custom polling settings
+func (c *AntxClient) CreateOrderAsyncWithConfig(order *CreateOrderParam, config OrderFutureConfig) *OrderFuture {
+	return c.CreateOrderAsyncContext(context.Background(), order, config)
+}
+
+// CreateOrderAsyncContext is CreateOrderAsyncWithConfig with a context, the future fails with the error of ctx and
+// stops polling once it is done
+func (c *AntxClient) CreateOrderAsyncContext(ctx context.Context, order *CreateOrderParam, config OrderFutureConfig) *OrderFuture {
+	if config.TxTimeout <= 0 {
+		config.TxTimeout = DefaultOrderFutureTxTimeout
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultOrderFuturePollInterval
+	}
+	f := &OrderFuture{ClientOrderId: order.ClientOrderId, stop: make(chan struct{})}
+	f.cond = sync.NewCond(&f.mu)
+	if order.ClientOrderId == "" {
+		f.fail(fmt.Errorf("client order ID is required to follow an asynchronous order"))
+		return f
+	}
+	go c.followOrder(ctx, f, order, config)
+	return f
+}
+
+// Wait blocks until the future reaches stage, fails, or timeout elapses, zero timeout waits indefinitely
+func (f *OrderFuture) Wait(stage OrderStage, timeout time.Duration) error {
+	if timeout > 0 {
+		timer := time.AfterFunc(timeout, func() {
+			f.mu.Lock()
+			f.cond.Broadcast()
+			f.mu.Unlock()
+		})
+		defer timer.Stop()
+	}
+	deadline := time.Now().Add(timeout)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for f.stage < stage && f.err == nil {
+		if timeout > 0 && !time.Now().Before(deadline) {
+			return fmt.Errorf("order %s did not reach stage %s within %s, currently %s", f.ClientOrderId, stage, timeout, f.stage)
+		}
+		f.cond.Wait()
+	}
+	if f.stage >= stage {
+		return nil
+	}
+	return f.err
+}
+
+// Stage returns the stage reached so far
+func (f *OrderFuture) Stage() OrderStage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.stage
+}
+
+// Err returns the error that stopped the future, nil while it progresses
+func (f *OrderFuture) Err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
+
+// TxHash returns the transaction hash once broadcast
+func (f *OrderFuture) TxHash() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.txHash
+}
+
+// Tracked returns the order as last seen among the active orders
+func (f *OrderFuture) Tracked() (TrackedOrder, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tracked, f.tracked.OrderId != ""
+}
+
+// Order returns the final order once done
+func (f *OrderFuture) Order() *types.Order {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.order
+}
+
+// Close stops following the order, e.g. for resting orders that are no longer of interest
+func (f *OrderFuture) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.closed {
+		close(f.stop)
+	}
+	f.closed = true
+	if f.stage < OrderStageDone && f.err == nil {
+		f.err = fmt.Errorf("order %s future closed at stage %s", f.ClientOrderId, f.stage)
+	}
+	f.cond.Broadcast()
+}
+
+// advance moves the future to a later stage
+func (f *OrderFuture) advance(stage OrderStage, update func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if update != nil {
+		update()
+	}
+	if stage > f.stage {
+		f.stage = stage
+	}
+	f.cond.Broadcast()
+}
+
+// fail stops the future with err
+func (f *OrderFuture) fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err == nil {
+		f.err = err
+	}
+	f.cond.Broadcast()
+}
+
+// isClosed reports whether the future was closed or failed
+func (f *OrderFuture) isClosed() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.closed || f.err != nil
+}
+
+// followOrder sends the order and drives the future through the transaction poller and the order tracker until the
+// order is done, the future is closed or ctx is done
+func (c *AntxClient) followOrder(ctx context.Context, f *OrderFuture, order *CreateOrderParam, config OrderFutureConfig) {
+	submittedAt := uint64(time.Now().UnixMilli())
+	txHash, err := c.CreateOrderContext(ctx, order)
+	if err != nil {
+		f.fail(err)
+		return
+	}
+	f.advance(OrderStageBroadcast, func() { f.txHash = txHash })
+
+	if _, err := c.WaitForTransactionContext(ctx, txHash, config.TxTimeout); err != nil {
+		f.fail(err)
+		return
+	}
+
+	tracker := c.OrderTracker(order.SubaccountId)
+	subaccountId := strconv.FormatUint(order.SubaccountId, 10)
+	for !f.isClosed() {
+		if err := tracker.Sync(); err != nil {
+			f.fail(err)
+			return
+		}
+		if tracked, ok := tracker.Get(order.ClientOrderId); ok && tracked.OrderId != "" {
+			f.advance(OrderStageAccepted, func() { f.tracked = tracked })
+		} else {
+			// Not active: either done, possibly without ever resting on the book, or not yet indexed
+			tracked, _ := f.Tracked()
+			done, err := c.findDoneOrder(subaccountId, order, tracked.OrderId, submittedAt)
+			if err != nil {
+				f.fail(err)
+				return
+			}
+			if done != nil {
+				f.advance(OrderStageDone, func() { f.order = done })
+				return
+			}
+		}
+		timer := time.NewTimer(config.PollInterval)
+		select {
+		case <-timer.C:
+		case <-f.stop:
+			timer.Stop()
+			return
+		case <-ctx.Done():
+			timer.Stop()
+			f.fail(ctx.Err())
+			return
+		}
+	}
+}
+
+// findDoneOrder looks up the order among the history orders created since submittedAt, by order ID when known, returns nil if it is not there yet
+func (c *AntxClient) findDoneOrder(subaccountId string, order *CreateOrderParam, orderId string, submittedAt uint64) (*types.Order, error) {
+	req := types.GetHistoryOrderReq{
+		SubaccountId:                    subaccountId,
+		Size:                            100,
+		FilterExchangeIdList:            strconv.FormatUint(order.ExchangeId, 10),
+		FilterOrderIdList:               orderId,
+		FilterStartCreatedTimeInclusive: submittedAt - uint64(time.Minute.Milliseconds()), // tolerate clock skew
+	}
+	var done *types.Order
+	err := types.ForEachPage(req.Size, func(cursor types.Cursor) ([]types.Order, types.IndexerPageOffsetData, error) {
+		req.PageOffsetDataCreatedTime, req.PageOffsetDataItemId = cursor.CreatedTime, cursor.ItemId
+		resp, err := c.GetHistoryOrder(req)
+		if err != nil {
+			return nil, types.IndexerPageOffsetData{}, err
+		}
+		return resp.Data.OrderList, resp.Data.PageOffsetData, nil
+	}, func(orders []types.Order) bool {
+		for i := range orders {
+			if orders[i].ClientOrderId == order.ClientOrderId && isFinalOrderStatus(orders[i].Status) {
+				done = &orders[i]
+				return false
+			}
+		}
+		return true
+	})
+	return done, err
+}
+
+// isFinalOrderStatus reports whether an order status is terminal
+func isFinalOrderStatus(status uint32) bool {
+	switch status {
+	case constants.OrderStatusFilled, constants.OrderStatusCancelled, constants.OrderStatusExpired,
+		constants.OrderStatusRejected, constants.OrderStatusLiquidated, constants.OrderStatusDeleveraged:
+		return true
+	default:
+		return false
+	}
+}
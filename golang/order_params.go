@@ -0,0 +1,74 @@
+package sdk
+
+import (
+	exchangetypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/exchange"
+	ordertypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/order"
+	pricetypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/price"
+)
+
+// Order creation parameters use the chain protobuf enums, which depend on the cosmos-sdk, so they live with the signing
+// side and the types and query packages stay free of it.
+
+// CreateOrderParam create order parameter
+type CreateOrderParam struct {
+	AgentAddress          string
+	SubaccountId          uint64
+	ExchangeId            uint64
+	MarginMode            exchangetypes.MarginMode
+	Leverage              uint32
+	IsBuy                 bool
+	PriceScale            int32
+	PriceValue            uint64
+	SizeScale             int32
+	SizeValue             uint64
+	ClientOrderId         string
+	TimeInForce           ordertypes.TimeInForce
+	ReduceOnly            bool
+	ExpireTime            uint64
+	IsMarket              bool
+	IsPositionTp          bool
+	IsPositionSl          bool
+	TriggerType           ordertypes.TriggerType
+	TriggerPriceType      pricetypes.PriceType
+	TriggerPriceValue     uint64
+	OpenTpslParentOrderId uint64
+	IsSetOpenTp           bool
+	OpenTpParam           ordertypes.OpenTpSlParam
+	IsSetOpenSl           bool
+	OpenSlParam           ordertypes.OpenTpSlParam
+	GasLimit              uint64
+}
+
+// CreateOrderBatchParam create order batch parameter
+type CreateOrderBatchParam struct {
+	AgentAddress     string
+	SubaccountId     uint64
+	ExchangeId       uint64
+	MarginMode       exchangetypes.MarginMode
+	Leverage         uint32
+	CreateOrderParam []*CreateOrderBatchDetail
+	GasLimit         uint64
+}
+
+// CreateOrderBatchDetail create order batch detail
+type CreateOrderBatchDetail struct {
+	IsBuy             bool
+	PriceScale        int32
+	PriceValue        uint64
+	SizeScale         int32
+	SizeValue         uint64
+	ClientOrderId     string
+	TimeInForce       ordertypes.TimeInForce
+	ReduceOnly        bool
+	ExpireTime        uint64
+	IsMarket          bool
+	IsPositionTp      bool
+	IsPositionSl      bool
+	TriggerType       ordertypes.TriggerType
+	TriggerPriceType  pricetypes.PriceType
+	TriggerPriceValue uint64
+	IsSetOpenTp       bool
+	OpenTpParam       ordertypes.OpenTpSlParam
+	IsSetOpenSl       bool
+	OpenSlParam       ordertypes.OpenTpSlParam
+}
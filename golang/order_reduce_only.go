@@ -0,0 +1,160 @@
+package sdk
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// ReduceOnlyPolicy how a reduce-only order is checked against the open position before submission
+type ReduceOnlyPolicy int
+
+const (
+	ReduceOnlyPolicyNone   ReduceOnlyPolicy = iota // No check, the chain decides
+	ReduceOnlyPolicyReject                         // Reject orders larger than the open position
+	ReduceOnlyPolicyClamp                          // Clamp order size down to the open position, each order of a batch on its own
+)
+
+// PositionSource provides the current open position of a subaccount on an exchange
+type PositionSource interface {
+	// GetPosition returns the open position, or nil if there is none
+	GetPosition(subaccountId, exchangeId string) (*types.PerpetualPosition, error)
+}
+
+// SetReduceOnlyPolicy enables checking reduce-only orders against the open position before submission,
+// positions are queried from the gateway when source is nil
+func (c *AntxClient) SetReduceOnlyPolicy(policy ReduceOnlyPolicy, source PositionSource) {
+	c.reduceOnlyPolicy = policy
+	c.positionSource = source
+}
+
+// GetPosition gets the open position of a subaccount on an exchange, returns nil if there is none
+func (c *AntxClient) GetPosition(subaccountId, exchangeId string) (*types.PerpetualPosition, error) {
+	resp, err := c.GetPerpetualAccountAsset(types.GetPerpetualAccountAssetReq{SubaccountId: subaccountId})
+	if err != nil {
+		return nil, err
+	}
+	for i := range resp.Data.PositionList {
+		if position := &resp.Data.PositionList[i]; position.SubaccountId == subaccountId && position.ExchangeId == exchangeId {
+			return position, nil
+		}
+	}
+	return nil, nil
+}
+
+// CheckReduceOnly checks a reduce-only order size against the open position, with the clamp policy the order size is reduced in place
+func CheckReduceOnly(position *types.PerpetualPosition, isBuy bool, sizeScale int32, sizeValue *uint64, policy ReduceOnlyPolicy) error {
+	openSize := decimal.Zero
+	if position != nil && position.OpenSize != "" {
+		var err error
+		openSize, err = decimal.NewFromString(position.OpenSize)
+		if err != nil {
+			return fmt.Errorf("failed to parse position open size: %w", err)
+		}
+	}
+	_, err := reduceOnlyRemaining(openSize, isBuy, sizeScale, sizeValue, policy)
+	return err
+}
+
+// reduceOnlyRemaining checks one reduce-only order and returns the open size left after it fills
+func reduceOnlyRemaining(openSize decimal.Decimal, isBuy bool, sizeScale int32, sizeValue *uint64, policy ReduceOnlyPolicy) (decimal.Decimal, error) {
+	if openSize.IsZero() {
+		return openSize, fmt.Errorf("reduce-only order rejected: no open position to reduce")
+	}
+	if isBuy && openSize.IsPositive() {
+		return openSize, fmt.Errorf("reduce-only order rejected: buy would increase the long position %s", openSize.String())
+	}
+	if !isBuy && openSize.IsNegative() {
+		return openSize, fmt.Errorf("reduce-only order rejected: sell would increase the short position %s", openSize.String())
+	}
+
+	size := UnscaleDecimal(*sizeValue, sizeScale)
+	if size.GreaterThan(openSize.Abs()) {
+		if policy != ReduceOnlyPolicyClamp {
+			return openSize, fmt.Errorf("reduce-only order rejected: size %s exceeds open position %s", size.String(), openSize.Abs().String())
+		}
+		clamped := openSize.Abs().Shift(sizeScale).Floor()
+		if clamped.IsZero() {
+			return openSize, fmt.Errorf("reduce-only order rejected: open position %s is below the minimum size", openSize.Abs().String())
+		}
+		*sizeValue = clamped.BigInt().Uint64()
+		size = UnscaleDecimal(*sizeValue, sizeScale)
+	}
+
+	if isBuy {
+		return openSize.Add(size), nil
+	}
+	return openSize.Sub(size), nil
+}
+
+// applyReduceOnlyPolicy checks a reduce-only order against the open position before it is signed and returns the size
+// to send, clamped by the clamp policy, leaving the order of the caller unchanged
+func (c *AntxClient) applyReduceOnlyPolicy(order *CreateOrderParam) (uint64, error) {
+	if c.reduceOnlyPolicy == ReduceOnlyPolicyNone || !order.ReduceOnly {
+		return order.SizeValue, nil
+	}
+	position, err := c.lookupPosition(order.SubaccountId, order.ExchangeId)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get position for reduce-only check: %w", err)
+	}
+	sizeValue := order.SizeValue
+	if err := CheckReduceOnly(position, order.IsBuy, order.SizeScale, &sizeValue, c.reduceOnlyPolicy); err != nil {
+		return 0, err
+	}
+	return sizeValue, nil
+}
+
+// applyReduceOnlyPolicyBatch checks the reduce-only orders of a batch against the open position and returns the sizes
+// to send by order, clamped by the clamp policy, leaving the orders of the caller unchanged. The reject policy checks
+// the orders cumulatively, the clamp policy clamps each one to the position since the chain reduces them again as they
+// fill.
+func (c *AntxClient) applyReduceOnlyPolicyBatch(orders *CreateOrderBatchParam) ([]uint64, error) {
+	sizeValues := make([]uint64, len(orders.CreateOrderParam))
+	for i, order := range orders.CreateOrderParam {
+		sizeValues[i] = order.SizeValue
+	}
+	if c.reduceOnlyPolicy == ReduceOnlyPolicyNone {
+		return sizeValues, nil
+	}
+
+	var openSize *decimal.Decimal
+	for i, order := range orders.CreateOrderParam {
+		if !order.ReduceOnly {
+			continue
+		}
+		if openSize == nil {
+			position, err := c.lookupPosition(orders.SubaccountId, orders.ExchangeId)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get position for reduce-only check: %w", err)
+			}
+			size := decimal.Zero
+			if position != nil && position.OpenSize != "" {
+				size, err = decimal.NewFromString(position.OpenSize)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse position open size: %w", err)
+				}
+			}
+			openSize = &size
+		}
+
+		remaining, err := reduceOnlyRemaining(*openSize, order.IsBuy, order.SizeScale, &sizeValues[i], c.reduceOnlyPolicy)
+		if err != nil {
+			return nil, fmt.Errorf("order %d: %w", i, err)
+		}
+		if c.reduceOnlyPolicy == ReduceOnlyPolicyReject {
+			openSize = &remaining
+		}
+	}
+	return sizeValues, nil
+}
+
+// lookupPosition gets the open position from the configured position source
+func (c *AntxClient) lookupPosition(subaccountId, exchangeId uint64) (*types.PerpetualPosition, error) {
+	var source PositionSource = c
+	if c.positionSource != nil {
+		source = c.positionSource
+	}
+	return source.GetPosition(strconv.FormatUint(subaccountId, 10), strconv.FormatUint(exchangeId, 10))
+}
@@ -0,0 +1,265 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultRouterMarginRefresh default time the available margin of the subaccounts is cached by a margin-aware router
+const DefaultRouterMarginRefresh = 5 * time.Second
+
+// ErrClientOrderIdNotRouted the router did not route an order with the client order ID
+var ErrClientOrderIdNotRouted = errors.New("client order ID not routed")
+
+// RoutingPolicy policy choosing the subaccount of the orders of an OrderRouter
+type RoutingPolicy int
+
+const (
+	RouteRoundRobin  RoutingPolicy = iota // Cycle through the subaccounts order by order
+	RoutePinned                           // Send all the orders of an exchange to the same subaccount, from Pins or assigned round-robin on first use
+	RouteMarginAware                      // Send each order to the subaccount with the most margin available to trade
+)
+
+// String returns the name of the policy
+func (p RoutingPolicy) String() string {
+	switch p {
+	case RouteRoundRobin:
+		return "round_robin"
+	case RoutePinned:
+		return "pinned"
+	case RouteMarginAware:
+		return "margin_aware"
+	default:
+		return fmt.Sprintf("RoutingPolicy(%d)", int(p))
+	}
+}
+
+// OrderRouterConfig order router configuration
+type OrderRouterConfig struct {
+	SubaccountIds []uint64          // Pool of subaccounts orders are routed to
+	Policy        RoutingPolicy     // Routing policy
+	Pins          map[uint64]uint64 // Subaccount by exchange ID of RoutePinned, exchanges missing are assigned on first use
+	MarginRefresh time.Duration     // Time the available margin is cached by RouteMarginAware, defaults to DefaultRouterMarginRefresh
+}
+
+// OrderRouter distributes orders across a pool of subaccounts according to a policy, e.g. to isolate strategies or to
+// spread rate limits, and remembers which subaccount owns each client order ID so cancels reach the right one.
+// Reduce-only orders whose SubaccountId is already a subaccount of the pool, i.e. those closing a position held there,
+// are sent to it unchanged, other orders always follow the policy.
+type OrderRouter struct {
+	client *AntxClient
+	config OrderRouterConfig
+
+	mu        sync.Mutex
+	refreshMu sync.Mutex // Held while the available margin is fetched, so one refresh runs at a time
+	pool      map[uint64]bool
+	next      int
+	pins      map[uint64]uint64
+	owners    map[string]uint64
+	margin    map[uint64]decimal.Decimal
+	marginAt  time.Time
+}
+
+// NewOrderRouter creates an order router over a pool of subaccounts
+func (c *AntxClient) NewOrderRouter(config OrderRouterConfig) (*OrderRouter, error) {
+	if len(config.SubaccountIds) == 0 {
+		return nil, fmt.Errorf("order router requires at least one subaccount")
+	}
+	pool := make(map[uint64]bool, len(config.SubaccountIds))
+	for _, subaccountId := range config.SubaccountIds {
+		pool[subaccountId] = true
+	}
+	pins := make(map[uint64]uint64, len(config.Pins))
+	for exchangeId, subaccountId := range config.Pins {
+		if !pool[subaccountId] {
+			return nil, fmt.Errorf("exchange %d is pinned to subaccount %d outside the pool", exchangeId, subaccountId)
+		}
+		pins[exchangeId] = subaccountId
+	}
+	if config.MarginRefresh <= 0 {
+		config.MarginRefresh = DefaultRouterMarginRefresh
+	}
+	return &OrderRouter{
+		client: c,
+		config: config,
+		pool:   pool,
+		pins:   pins,
+		owners: make(map[string]uint64),
+	}, nil
+}
+
+// Route sets the subaccount of an order according to the policy, records it as the owner of the client order ID and
+// returns it
+func (r *OrderRouter) Route(order *CreateOrderParam) (uint64, error) {
+	if r.config.Policy == RouteMarginAware {
+		if err := r.refreshMargin(); err != nil {
+			return 0, err
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// A client order ID routed before, e.g. an order being resent, keeps its subaccount
+	if owner, ok := r.owners[order.ClientOrderId]; order.ClientOrderId != "" && ok {
+		order.SubaccountId = owner
+		return owner, nil
+	}
+	subaccountId, err := r.route(order)
+	if err != nil {
+		return 0, err
+	}
+	order.SubaccountId = subaccountId
+	if order.ClientOrderId != "" {
+		r.owners[order.ClientOrderId] = subaccountId
+	}
+	return subaccountId, nil
+}
+
+// route chooses the subaccount of an order
+func (r *OrderRouter) route(order *CreateOrderParam) (uint64, error) {
+	if order.ReduceOnly && r.pool[order.SubaccountId] {
+		return order.SubaccountId, nil
+	}
+	switch r.config.Policy {
+	case RoutePinned:
+		if subaccountId, ok := r.pins[order.ExchangeId]; ok {
+			return subaccountId, nil
+		}
+		subaccountId := r.nextSubaccount()
+		r.pins[order.ExchangeId] = subaccountId
+		return subaccountId, nil
+	case RouteMarginAware:
+		return r.mostMargin(order), nil
+	default:
+		return r.nextSubaccount(), nil
+	}
+}
+
+// nextSubaccount returns the next subaccount in round-robin order
+func (r *OrderRouter) nextSubaccount() uint64 {
+	subaccountId := r.config.SubaccountIds[r.next%len(r.config.SubaccountIds)]
+	r.next++
+	return subaccountId
+}
+
+// refreshMargin fetches the available margin of the subaccounts in parallel once the cached one is older than
+// MarginRefresh, without holding the lock of the router so the orders routed meanwhile are not blocked
+func (r *OrderRouter) refreshMargin() error {
+	r.refreshMu.Lock()
+	defer r.refreshMu.Unlock()
+	r.mu.Lock()
+	fresh := r.margin != nil && time.Since(r.marginAt) < r.config.MarginRefresh
+	r.mu.Unlock()
+	if fresh {
+		return nil
+	}
+
+	available := make([]decimal.Decimal, len(r.config.SubaccountIds))
+	errs := make([]error, len(r.config.SubaccountIds))
+	var wg sync.WaitGroup
+	for i, subaccountId := range r.config.SubaccountIds {
+		wg.Add(1)
+		go func(i int, subaccountId uint64) {
+			defer wg.Done()
+			available[i], errs[i] = r.client.AvailableToTrade(strconv.FormatUint(subaccountId, 10))
+		}(i, subaccountId)
+	}
+	wg.Wait()
+	margin := make(map[uint64]decimal.Decimal, len(r.config.SubaccountIds))
+	for i, subaccountId := range r.config.SubaccountIds {
+		if errs[i] != nil {
+			return fmt.Errorf("failed to get available margin of subaccount %d: %w", subaccountId, errs[i])
+		}
+		margin[subaccountId] = available[i]
+	}
+
+	r.mu.Lock()
+	r.margin, r.marginAt = margin, time.Now()
+	r.mu.Unlock()
+	return nil
+}
+
+// mostMargin returns the subaccount with the most available margin, fetched by refreshMargin, and reserves the margin
+// of the order on it until the next refresh, so a burst of orders spreads across the pool
+func (r *OrderRouter) mostMargin(order *CreateOrderParam) uint64 {
+	best := r.config.SubaccountIds[0]
+	for _, subaccountId := range r.config.SubaccountIds[1:] {
+		if r.margin[subaccountId].GreaterThan(r.margin[best]) {
+			best = subaccountId
+		}
+	}
+	if !order.ReduceOnly && order.Leverage > 0 {
+		notional := UnscaleDecimal(order.PriceValue, order.PriceScale).Mul(UnscaleDecimal(order.SizeValue, order.SizeScale))
+		r.margin[best] = r.margin[best].Sub(notional.Div(decimal.NewFromInt(int64(order.Leverage))))
+	}
+	return best
+}
+
+// CreateOrder routes an order and creates it, the client order ID is forgotten when the creation fails
+func (r *OrderRouter) CreateOrder(order *CreateOrderParam) (string, error) {
+	if _, err := r.Route(order); err != nil {
+		return "", err
+	}
+	txHash, err := r.client.CreateOrder(order)
+	if err != nil && order.ClientOrderId != "" {
+		r.Forget(order.ClientOrderId)
+	}
+	return txHash, err
+}
+
+// Owner returns the subaccount an order with the client order ID was routed to
+func (r *OrderRouter) Owner(clientOrderId string) (uint64, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subaccountId, ok := r.owners[clientOrderId]
+	return subaccountId, ok
+}
+
+// Forget removes client order IDs from the ownership table, e.g. once their orders are filled or canceled
+func (r *OrderRouter) Forget(clientOrderIds ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, clientOrderId := range clientOrderIds {
+		delete(r.owners, clientOrderId)
+	}
+}
+
+// CancelOrderByClientId cancels orders by client order ID with one transaction per owning subaccount, returning the
+// transaction hashes. Unknown client order IDs fail with ErrClientOrderIdNotRouted before anything is sent.
+func (r *OrderRouter) CancelOrderByClientId(clientOrderIds ...string) ([]string, error) {
+	bySubaccount := make(map[uint64][]string)
+	r.mu.Lock()
+	for _, clientOrderId := range clientOrderIds {
+		subaccountId, ok := r.owners[clientOrderId]
+		if !ok {
+			r.mu.Unlock()
+			return nil, fmt.Errorf("%s: %w", clientOrderId, ErrClientOrderIdNotRouted)
+		}
+		bySubaccount[subaccountId] = append(bySubaccount[subaccountId], clientOrderId)
+	}
+	r.mu.Unlock()
+
+	subaccountIds := make([]uint64, 0, len(bySubaccount))
+	for subaccountId := range bySubaccount {
+		subaccountIds = append(subaccountIds, subaccountId)
+	}
+	sort.Slice(subaccountIds, func(i, j int) bool { return subaccountIds[i] < subaccountIds[j] })
+	txHashes := make([]string, 0, len(subaccountIds))
+	for _, subaccountId := range subaccountIds {
+		txHash, err := r.client.CancelOrderByClientId(&types.CancelOrderByClientIdParam{
+			SubaccountId:      subaccountId,
+			ClientOrderIdList: bySubaccount[subaccountId],
+		})
+		if err != nil {
+			return txHashes, fmt.Errorf("failed to cancel orders of subaccount %d: %w", subaccountId, err)
+		}
+		txHashes = append(txHashes, txHash)
+	}
+	return txHashes, nil
+}
@@ -0,0 +1,217 @@
+package sdk
+
+import (
+	"fmt"
+	"time"
+
+	ordertypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/order"
+	pricetypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/price"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// OpenTpSlBuilder builds an open take-profit/stop-loss parameter from decimal prices, attached to an opening order
+type OpenTpSlBuilder struct {
+	exchange      *types.Exchange
+	param         *ordertypes.OpenTpSlParam
+	triggerPrice  decimal.Decimal // Trigger price, rounded and scaled by Build
+	priceRounding RoundingMode
+	sizeRounding  RoundingMode
+	clock         *ServerClock
+	err           error
+}
+
+// NewOpenTpSlBuilder creates an open take-profit/stop-loss builder, by default it closes the whole order size at market once triggered
+func NewOpenTpSlBuilder(exchange *types.Exchange, triggerPrice decimal.Decimal) *OpenTpSlBuilder {
+	b := &OpenTpSlBuilder{
+		exchange: exchange,
+		param: &ordertypes.OpenTpSlParam{
+			TriggerPriceType: pricetypes.PriceType_PRICE_TYPE_LAST,
+		},
+		triggerPrice: triggerPrice,
+	}
+	return b
+}
+
+// Rounding sets how prices and sizes off the tick and step grid are handled by the following Limit and Size calls and
+// by the trigger price, rounded at Build, by default they are rejected (RoundStrict)
+func (b *OpenTpSlBuilder) Rounding(price, size RoundingMode) *OpenTpSlBuilder {
+	b.priceRounding = price
+	b.sizeRounding = size
+	return b
+}
+
+// Limit places a limit order at the given price once triggered, instead of a market order
+func (b *OpenTpSlBuilder) Limit(price decimal.Decimal) *OpenTpSlBuilder {
+	price, err := RoundPriceToTick(price, b.exchange, b.priceRounding)
+	if err != nil {
+		return b.fail(fmt.Errorf("invalid price: %w", err))
+	}
+	priceValue, err := ScaleDecimal(price, b.exchange.TickSizeScale)
+	if err != nil {
+		return b.fail(fmt.Errorf("invalid price: %w", err))
+	}
+	b.param.Price = priceValue
+	return b
+}
+
+// Size sets the size to close once triggered, by default the whole order size
+func (b *OpenTpSlBuilder) Size(size decimal.Decimal) *OpenTpSlBuilder {
+	size, err := RoundSizeToStep(size, b.exchange, b.sizeRounding)
+	if err != nil {
+		return b.fail(fmt.Errorf("invalid size: %w", err))
+	}
+	if err := checkOrderSizeMax(b.exchange, size); err != nil {
+		return b.fail(fmt.Errorf("invalid size: %w", err))
+	}
+	sizeValue, err := ScaleDecimal(size, b.exchange.StepSizeScale)
+	if err != nil {
+		return b.fail(fmt.Errorf("invalid size: %w", err))
+	}
+	b.param.Size = sizeValue
+	return b
+}
+
+// TriggerPriceType sets the price type compared against the trigger price
+func (b *OpenTpSlBuilder) TriggerPriceType(priceType pricetypes.PriceType) *OpenTpSlBuilder {
+	b.param.TriggerPriceType = priceType
+	return b
+}
+
+// ClientOrderId sets the client custom ID of the triggered order
+func (b *OpenTpSlBuilder) ClientOrderId(clientOrderId string) *OpenTpSlBuilder {
+	b.param.ClientOrderId = clientOrderId
+	return b
+}
+
+// ExpireAt sets the expiration time
+func (b *OpenTpSlBuilder) ExpireAt(expireTime time.Time) *OpenTpSlBuilder {
+	b.param.ExpireTime = uint64(expireTime.UnixMilli())
+	return b
+}
+
+// ExpireAfter sets the expiration time relative to now, in server time when a server clock is set
+func (b *OpenTpSlBuilder) ExpireAfter(d time.Duration) *OpenTpSlBuilder {
+	if d <= 0 {
+		return b.fail(fmt.Errorf("invalid expiry: duration must be positive, got %s", d))
+	}
+	if b.clock != nil {
+		return b.ExpireAt(b.clock.Now().Add(d))
+	}
+	return b.ExpireAt(time.Now().Add(d))
+}
+
+// ServerTime measures relative expiration times from a server clock, e.g. AntxClient.ServerClock, instead of the
+// local clock
+func (b *OpenTpSlBuilder) ServerTime(clock *ServerClock) *OpenTpSlBuilder {
+	b.clock = clock
+	return b
+}
+
+// Build validates and returns the open take-profit/stop-loss parameter
+func (b *OpenTpSlBuilder) Build() (*ordertypes.OpenTpSlParam, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	triggerPrice, err := RoundPriceToTick(b.triggerPrice, b.exchange, b.priceRounding)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trigger price: %w", err)
+	}
+	triggerPriceValue, err := ScaleDecimal(triggerPrice, b.exchange.TickSizeScale)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trigger price: %w", err)
+	}
+	if triggerPriceValue == 0 {
+		return nil, fmt.Errorf("invalid trigger price: must be greater than 0")
+	}
+	b.param.TriggerPriceValue = triggerPriceValue
+	if err := validateOpenTpSlParam(b.param); err != nil {
+		return nil, err
+	}
+	return b.param, nil
+}
+
+// fail records the first error encountered while building
+func (b *OpenTpSlBuilder) fail(err error) *OpenTpSlBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// OpenTakeProfit attaches an open take-profit to the order
+func (b *OrderBuilder) OpenTakeProfit(tp *OpenTpSlBuilder) *OrderBuilder {
+	param, err := tp.Build()
+	if err != nil {
+		return b.fail(fmt.Errorf("invalid open take-profit: %w", err))
+	}
+	b.param.IsSetOpenTp = true
+	copyOpenTpSlParam(&b.param.OpenTpParam, param)
+	return b
+}
+
+// OpenStopLoss attaches an open stop-loss to the order
+func (b *OrderBuilder) OpenStopLoss(sl *OpenTpSlBuilder) *OrderBuilder {
+	param, err := sl.Build()
+	if err != nil {
+		return b.fail(fmt.Errorf("invalid open stop-loss: %w", err))
+	}
+	b.param.IsSetOpenSl = true
+	copyOpenTpSlParam(&b.param.OpenSlParam, param)
+	return b
+}
+
+// validateOpenTpSlPrices checks that open take-profit/stop-loss trigger prices lie on the profitable/losing side of a limit order price
+func validateOpenTpSlPrices(order *CreateOrderParam) error {
+	if order.IsMarket || order.PriceValue == 0 {
+		return nil
+	}
+	if order.IsSetOpenTp {
+		if order.IsBuy && order.OpenTpParam.TriggerPriceValue <= order.PriceValue {
+			return fmt.Errorf("open take-profit trigger price must be above the buy order price")
+		}
+		if !order.IsBuy && order.OpenTpParam.TriggerPriceValue >= order.PriceValue {
+			return fmt.Errorf("open take-profit trigger price must be below the sell order price")
+		}
+		if order.OpenTpParam.Size > order.SizeValue {
+			return fmt.Errorf("open take-profit size cannot exceed the order size")
+		}
+	}
+	if order.IsSetOpenSl {
+		if order.IsBuy && order.OpenSlParam.TriggerPriceValue >= order.PriceValue {
+			return fmt.Errorf("open stop-loss trigger price must be below the buy order price")
+		}
+		if !order.IsBuy && order.OpenSlParam.TriggerPriceValue <= order.PriceValue {
+			return fmt.Errorf("open stop-loss trigger price must be above the sell order price")
+		}
+		if order.OpenSlParam.Size > order.SizeValue {
+			return fmt.Errorf("open stop-loss size cannot exceed the order size")
+		}
+	}
+	return nil
+}
+
+// checkOrderSizeMax checks a size against the maximum order size of the exchange
+func checkOrderSizeMax(exchange *types.Exchange, size decimal.Decimal) error {
+	if exchange.OrderSizeMax == "" {
+		return nil
+	}
+	orderSizeMax, err := decimal.NewFromString(exchange.OrderSizeMax)
+	if err != nil || !orderSizeMax.IsPositive() {
+		return nil
+	}
+	if size.GreaterThan(orderSizeMax) {
+		return fmt.Errorf("size %s exceeds maximum order size %s", size.String(), orderSizeMax.String())
+	}
+	return nil
+}
+
+// copyOpenTpSlParam copies the fields of an open take-profit/stop-loss parameter without copying the message state
+func copyOpenTpSlParam(dst, src *ordertypes.OpenTpSlParam) {
+	dst.Price = src.Price
+	dst.Size = src.Size
+	dst.ClientOrderId = src.ClientOrderId
+	dst.TriggerPriceType = src.TriggerPriceType
+	dst.TriggerPriceValue = src.TriggerPriceValue
+	dst.ExpireTime = src.ExpireTime
+}
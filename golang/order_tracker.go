@@ -0,0 +1,411 @@
+package sdk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/store"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultPendingOrderTTL how long a submitted order is kept while it is not yet visible on the gateway
+const DefaultPendingOrderTTL = 10 * time.Second
+
+// TrackedOrder a live order of a subaccount
+type TrackedOrder struct {
+	OrderId       string          `json:"orderId,omitempty"`     // Order ID, empty until the order is visible on the gateway
+	ClientOrderId string          `json:"clientOrderId"`         // Client custom ID
+	ExchangeId    string          `json:"exchangeId"`            // Exchange ID
+	IsBuy         bool            `json:"isBuy"`                 // Whether it is a buy order
+	Price         decimal.Decimal `json:"price"`                 // Order price, zero for market orders
+	Size          decimal.Decimal `json:"size"`                  // Remaining unfilled size
+	ReduceOnly    bool            `json:"reduceOnly"`            // Whether it is a reduce-only order
+	IsConditional bool            `json:"isConditional"`         // Whether it is a conditional or position take-profit/stop-loss order
+	SubmittedAt   time.Time       `json:"submittedAt,omitempty"` // Submission time, zero for orders loaded from the gateway
+}
+
+// OrderTracker tracks the live orders of a subaccount from gateway snapshots and local submissions
+type OrderTracker struct {
+	SubaccountId uint64
+	PendingTTL   time.Duration // How long submitted orders are kept before they are visible on the gateway
+
+	client *AntxClient
+	store  store.Store
+	mu     sync.RWMutex
+	orders map[string]*TrackedOrder
+	stored map[string][]byte // Encoded orders as last written to the store, by key
+	synced bool
+}
+
+// OrderTracker returns the order tracker of a subaccount, trackers are created on first use and shared. With a store
+// set, a new tracker starts from the persisted orders, which Sync then reconciles with the gateway.
+func (c *AntxClient) OrderTracker(subaccountId uint64) *OrderTracker {
+	c.trackerMu.Lock()
+	defer c.trackerMu.Unlock()
+	if c.orderTrackers == nil {
+		c.orderTrackers = make(map[uint64]*OrderTracker)
+	}
+	tracker, ok := c.orderTrackers[subaccountId]
+	if !ok {
+		tracker = &OrderTracker{
+			SubaccountId: subaccountId,
+			PendingTTL:   DefaultPendingOrderTTL,
+			client:       c,
+			store:        c.store,
+			orders:       make(map[string]*TrackedOrder),
+			stored:       make(map[string][]byte),
+		}
+		tracker.load()
+		c.orderTrackers[subaccountId] = tracker
+	}
+	return tracker
+}
+
+// Sync replaces the tracked orders with the active orders on the gateway, recently submitted orders are kept until their
+// TTL expires. The active orders that are new or changed since the last sync are published on the event bus.
+func (t *OrderTracker) Sync() error {
+	active, err := t.client.getAllActiveOrders(strconv.FormatUint(t.SubaccountId, 10))
+	if err != nil {
+		return fmt.Errorf("failed to sync active orders: %w", err)
+	}
+	orders := make(map[string]*TrackedOrder)
+	gatewayOrders := make(map[string]*types.Order)
+	for i := range active {
+		order, err := trackedOrderFromOrder(&active[i])
+		if err != nil {
+			return fmt.Errorf("failed to sync active orders: %w", err)
+		}
+		if order.Size.IsPositive() {
+			orders[order.key()] = order
+			gatewayOrders[order.key()] = &active[i]
+		}
+	}
+
+	t.mu.Lock()
+	var changed []*types.Order
+	for key, order := range orders {
+		if previous, ok := t.orders[key]; !ok || previous.OrderId != order.OrderId || !previous.Size.Equal(order.Size) {
+			changed = append(changed, gatewayOrders[key])
+		}
+	}
+	now := time.Now()
+	for key, order := range t.orders {
+		if _, ok := orders[key]; ok || order.SubmittedAt.IsZero() {
+			continue
+		}
+		if now.Sub(order.SubmittedAt) < t.PendingTTL {
+			orders[key] = order
+		}
+	}
+	t.orders = orders
+	t.synced = true
+	t.persistChanges()
+	t.mu.Unlock()
+	for _, order := range changed {
+		t.client.publish(TopicOrder, order.SubaccountId, order.ExchangeId, *order)
+	}
+	return nil
+}
+
+// OrderTrackerSnapshot serialized state of an order tracker
+type OrderTrackerSnapshot struct {
+	SubaccountId uint64         `json:"subaccountId"` // Subaccount ID
+	Orders       []TrackedOrder `json:"orders"`       // Tracked orders
+}
+
+// Snapshot returns the tracked orders
+func (t *OrderTracker) Snapshot() OrderTrackerSnapshot {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	snapshot := OrderTrackerSnapshot{SubaccountId: t.SubaccountId, Orders: make([]TrackedOrder, 0, len(t.orders))}
+	for _, order := range t.orders {
+		snapshot.Orders = append(snapshot.Orders, *order)
+	}
+	sort.Slice(snapshot.Orders, func(i, j int) bool { return snapshot.Orders[i].key() < snapshot.Orders[j].key() })
+	return snapshot
+}
+
+// Restore replaces the tracked orders with those of a snapshot, the tracker is not synced until the next Sync
+func (t *OrderTracker) Restore(snapshot OrderTrackerSnapshot) error {
+	if snapshot.SubaccountId != t.SubaccountId {
+		return fmt.Errorf("snapshot of subaccount %d cannot restore the tracker of subaccount %d", snapshot.SubaccountId, t.SubaccountId)
+	}
+	orders := make(map[string]*TrackedOrder, len(snapshot.Orders))
+	for i := range snapshot.Orders {
+		order := snapshot.Orders[i]
+		orders[order.key()] = &order
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.orders = orders
+	t.synced = false
+	t.persistChanges()
+	return nil
+}
+
+// Synced reports whether the tracker has loaded a gateway snapshot
+func (t *OrderTracker) Synced() bool {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.synced
+}
+
+// Orders returns the tracked orders of an exchange, or of all exchanges when exchangeId is empty
+func (t *OrderTracker) Orders(exchangeId string) []TrackedOrder {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	orders := make([]TrackedOrder, 0, len(t.orders))
+	for _, order := range t.orders {
+		if exchangeId == "" || order.ExchangeId == exchangeId {
+			orders = append(orders, *order)
+		}
+	}
+	return orders
+}
+
+// Get returns a tracked order by client order ID
+func (t *OrderTracker) Get(clientOrderId string) (TrackedOrder, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	order, ok := t.orders[clientOrderId]
+	if !ok {
+		return TrackedOrder{}, false
+	}
+	return *order, true
+}
+
+// TrackSubmitted tracks an order that was just submitted and is not yet visible on the gateway
+func (t *OrderTracker) TrackSubmitted(exchangeId string, order *CreateOrderBatchDetail) {
+	tracked := &TrackedOrder{
+		ClientOrderId: order.ClientOrderId,
+		ExchangeId:    exchangeId,
+		IsBuy:         order.IsBuy,
+		Price:         UnscaleDecimal(order.PriceValue, order.PriceScale),
+		Size:          UnscaleDecimal(order.SizeValue, order.SizeScale),
+		ReduceOnly:    order.ReduceOnly,
+		IsConditional: order.TriggerPriceValue != 0 || order.IsPositionTp || order.IsPositionSl,
+		SubmittedAt:   time.Now(),
+	}
+	if order.IsMarket {
+		tracked.Price = decimal.Zero
+	}
+
+	t.mu.Lock()
+	// The push of the new order may arrive before the submission returns, it already carries the order ID
+	if _, ok := t.orders[tracked.key()]; !ok {
+		t.orders[tracked.key()] = tracked
+		t.persist(tracked)
+	}
+	t.mu.Unlock()
+}
+
+// Untrack stops tracking an order, e.g. after it was cancelled
+func (t *OrderTracker) Untrack(order TrackedOrder) {
+	t.mu.Lock()
+	delete(t.orders, order.key())
+	if _, ok := t.stored[order.key()]; ok {
+		if err := t.store.Delete(t.storeKey(order.key())); err != nil {
+			t.report(err)
+		}
+		delete(t.stored, order.key())
+	}
+	t.mu.Unlock()
+}
+
+// ApplyOrder applies an order update of the private stream: an order in a final status or fully filled stops being
+// tracked, an active order is tracked with its remaining size
+func (t *OrderTracker) ApplyOrder(order *types.Order) error {
+	tracked, err := trackedOrderFromOrder(order)
+	if err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if isFinalOrderStatus(order.Status) || !tracked.Size.IsPositive() {
+		delete(t.orders, tracked.key())
+	} else {
+		t.orders[tracked.key()] = tracked
+	}
+	t.persistChanges()
+	return nil
+}
+
+// ApplyTradeData applies the order updates of the subaccount in a private event
+func (t *OrderTracker) ApplyTradeData(event *types.TradeDataEvent) error {
+	subaccountId := strconv.FormatUint(t.SubaccountId, 10)
+	for i := range event.OrderList {
+		if event.OrderList[i].SubaccountId != subaccountId {
+			continue
+		}
+		if err := t.ApplyOrder(&event.OrderList[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TrackOrders keeps the order tracker of a subaccount up to date from the private order updates until the returned
+// stop function is called, so orders filled or cancelled elsewhere leave it without waiting for the next Sync
+func (c *AntxClient) TrackOrders(subaccountId uint64) (stop func(), err error) {
+	tradeDataChan, err := c.SubscribeToTradeData()
+	if err != nil {
+		return nil, err
+	}
+	tracker := c.OrderTracker(subaccountId)
+
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case msg := <-tradeDataChan:
+				event, err := c.ParseTradeDataEvent(msg)
+				if err != nil {
+					c.Logger().Errorf("order tracker: %v", err)
+					continue
+				}
+				if err := tracker.ApplyTradeData(event); err != nil {
+					c.Logger().Errorf("order tracker: %v", err)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(done) })
+	}, nil
+}
+
+// storeKey returns the store key of a tracked order
+func (t *OrderTracker) storeKey(key string) string {
+	return fmt.Sprintf("orders/%d/%s", t.SubaccountId, key)
+}
+
+// load restores the persisted orders of a new tracker
+func (t *OrderTracker) load() {
+	if t.store == nil {
+		return
+	}
+	err := t.store.Scan(t.storeKey(""), func(key string, value []byte) error {
+		var order TrackedOrder
+		if err := json.Unmarshal(value, &order); err != nil {
+			return fmt.Errorf("failed to parse stored order %s: %w", key, err)
+		}
+		t.orders[order.key()] = &order
+		t.stored[order.key()] = value
+		return nil
+	})
+	if err != nil {
+		t.report(err)
+	}
+}
+
+// persist stores a tracked order, must be called with the lock held
+func (t *OrderTracker) persist(order *TrackedOrder) {
+	if t.store == nil {
+		return
+	}
+	value, err := json.Marshal(order)
+	if err == nil {
+		err = t.store.Put(t.storeKey(order.key()), value)
+	}
+	if err != nil {
+		t.report(err)
+		return
+	}
+	t.stored[order.key()] = value
+}
+
+// persistChanges writes the orders added, changed or removed since the last write in one batch, must be called with
+// the lock held
+func (t *OrderTracker) persistChanges() {
+	if t.store == nil {
+		return
+	}
+	puts := make(map[string][]byte)
+	var deletes []string
+	for key := range t.stored {
+		if _, ok := t.orders[key]; !ok {
+			deletes = append(deletes, t.storeKey(key))
+		}
+	}
+	for key, order := range t.orders {
+		value, err := json.Marshal(order)
+		if err != nil {
+			t.report(err)
+			continue
+		}
+		if !bytes.Equal(t.stored[key], value) {
+			puts[t.storeKey(key)] = value
+		}
+	}
+	if len(puts) == 0 && len(deletes) == 0 {
+		return
+	}
+	if err := store.Apply(t.store, puts, deletes); err != nil {
+		// The changes are written again on the next call
+		t.report(err)
+		return
+	}
+	for _, key := range deletes {
+		delete(t.stored, strings.TrimPrefix(key, t.storeKey("")))
+	}
+	for key, value := range puts {
+		t.stored[strings.TrimPrefix(key, t.storeKey(""))] = value
+	}
+}
+
+// report logs a store error, the tracker keeps working from memory
+func (t *OrderTracker) report(err error) {
+	t.client.Logger().Errorf("order tracker: subaccount %d: %v", t.SubaccountId, err)
+}
+
+// key identifies a tracked order by client order ID, or by order ID when it has none
+func (o *TrackedOrder) key() string {
+	if o.ClientOrderId != "" {
+		return o.ClientOrderId
+	}
+	return "#" + o.OrderId
+}
+
+// trackedOrderFromOrder converts a gateway order to a tracked order
+func trackedOrderFromOrder(order *types.Order) (*TrackedOrder, error) {
+	price := decimal.Zero
+	if order.Price != "" {
+		var err error
+		price, err = decimal.NewFromString(order.Price)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse price of order %s: %w", order.Id, err)
+		}
+	}
+	size, err := decimal.NewFromString(order.Size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse size of order %s: %w", order.Id, err)
+	}
+	if order.CumFillSize != "" {
+		filled, err := decimal.NewFromString(order.CumFillSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse filled size of order %s: %w", order.Id, err)
+		}
+		size = size.Sub(filled)
+	}
+	return &TrackedOrder{
+		OrderId:       order.Id,
+		ClientOrderId: order.ClientOrderId,
+		ExchangeId:    order.ExchangeId,
+		IsBuy:         order.IsBuy,
+		Price:         price,
+		Size:          size,
+		ReduceOnly:    order.ReduceOnly,
+		IsConditional: order.TriggerType != 0 || order.IsPositionTp || order.IsPositionSl,
+	}, nil
+}
@@ -0,0 +1,221 @@
+package sdk
+
+import (
+	"fmt"
+
+	exchangetypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/exchange"
+	ordertypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/order"
+	pricetypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/price"
+)
+
+// MaxClientOrderIdLength maximum length of a client order ID
+const MaxClientOrderIdLength = 64
+
+// orderFields order fields shared by single and batch order creation
+type orderFields struct {
+	PriceValue        uint64
+	SizeValue         uint64
+	ClientOrderId     string
+	TimeInForce       ordertypes.TimeInForce
+	ReduceOnly        bool
+	IsMarket          bool
+	IsPositionTp      bool
+	IsPositionSl      bool
+	TriggerType       ordertypes.TriggerType
+	TriggerPriceType  pricetypes.PriceType
+	TriggerPriceValue uint64
+	IsSetOpenTp       bool
+	OpenTpParam       *ordertypes.OpenTpSlParam
+	IsSetOpenSl       bool
+	OpenSlParam       *ordertypes.OpenTpSlParam
+}
+
+// ValidateCreateOrderParam checks a create order parameter for invalid field combinations before signing
+func ValidateCreateOrderParam(order *CreateOrderParam) error {
+	if order == nil {
+		return fmt.Errorf("invalid order: order is nil")
+	}
+	if err := validateAccountFields(order.SubaccountId, order.ExchangeId, order.MarginMode, order.Leverage); err != nil {
+		return fmt.Errorf("invalid order: %w", err)
+	}
+	if err := validateOrderFields(&orderFields{
+		PriceValue:        order.PriceValue,
+		SizeValue:         order.SizeValue,
+		ClientOrderId:     order.ClientOrderId,
+		TimeInForce:       order.TimeInForce,
+		ReduceOnly:        order.ReduceOnly,
+		IsMarket:          order.IsMarket,
+		IsPositionTp:      order.IsPositionTp,
+		IsPositionSl:      order.IsPositionSl,
+		TriggerType:       order.TriggerType,
+		TriggerPriceType:  order.TriggerPriceType,
+		TriggerPriceValue: order.TriggerPriceValue,
+		IsSetOpenTp:       order.IsSetOpenTp,
+		OpenTpParam:       &order.OpenTpParam,
+		IsSetOpenSl:       order.IsSetOpenSl,
+		OpenSlParam:       &order.OpenSlParam,
+	}); err != nil {
+		return fmt.Errorf("invalid order: %w", err)
+	}
+	return nil
+}
+
+// ValidateCreateOrderBatchParam checks every order of a batch for invalid field combinations before signing
+func ValidateCreateOrderBatchParam(orders *CreateOrderBatchParam) error {
+	if orders == nil {
+		return fmt.Errorf("invalid order batch: batch is nil")
+	}
+	if len(orders.CreateOrderParam) == 0 {
+		return fmt.Errorf("invalid order batch: no orders")
+	}
+	if err := validateAccountFields(orders.SubaccountId, orders.ExchangeId, orders.MarginMode, orders.Leverage); err != nil {
+		return fmt.Errorf("invalid order batch: %w", err)
+	}
+
+	clientOrderIds := make(map[string]int, len(orders.CreateOrderParam))
+	for i, order := range orders.CreateOrderParam {
+		if order == nil {
+			return fmt.Errorf("invalid order batch: order %d is nil", i)
+		}
+		if order.ClientOrderId != "" {
+			if j, ok := clientOrderIds[order.ClientOrderId]; ok {
+				return fmt.Errorf("invalid order batch: orders %d and %d share client order ID %q", j, i, order.ClientOrderId)
+			}
+			clientOrderIds[order.ClientOrderId] = i
+		}
+		if err := validateOrderFields(&orderFields{
+			PriceValue:        order.PriceValue,
+			SizeValue:         order.SizeValue,
+			ClientOrderId:     order.ClientOrderId,
+			TimeInForce:       order.TimeInForce,
+			ReduceOnly:        order.ReduceOnly,
+			IsMarket:          order.IsMarket,
+			IsPositionTp:      order.IsPositionTp,
+			IsPositionSl:      order.IsPositionSl,
+			TriggerType:       order.TriggerType,
+			TriggerPriceType:  order.TriggerPriceType,
+			TriggerPriceValue: order.TriggerPriceValue,
+			IsSetOpenTp:       order.IsSetOpenTp,
+			OpenTpParam:       &order.OpenTpParam,
+			IsSetOpenSl:       order.IsSetOpenSl,
+			OpenSlParam:       &order.OpenSlParam,
+		}); err != nil {
+			return fmt.Errorf("invalid order batch: order %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateAccountFields checks the subaccount, exchange and margin settings of an order
+func validateAccountFields(subaccountId, exchangeId uint64, marginMode exchangetypes.MarginMode, leverage uint32) error {
+	if subaccountId == 0 {
+		return fmt.Errorf("subaccount ID must be greater than 0")
+	}
+	if exchangeId == 0 {
+		return fmt.Errorf("exchange ID must be greater than 0")
+	}
+	if marginMode != exchangetypes.MarginMode_MARGIN_MODE_CROSS && marginMode != exchangetypes.MarginMode_MARGIN_MODE_ISOLATED {
+		return fmt.Errorf("margin mode must be MARGIN_MODE_CROSS or MARGIN_MODE_ISOLATED, got %s", marginMode)
+	}
+	if leverage == 0 {
+		return fmt.Errorf("leverage must be greater than 0")
+	}
+	return nil
+}
+
+// validateOrderFields checks order type, time in force and trigger settings for nonsensical combinations
+func validateOrderFields(order *orderFields) error {
+	if order.SizeValue == 0 {
+		return fmt.Errorf("size must be greater than 0")
+	}
+	if len(order.ClientOrderId) > MaxClientOrderIdLength {
+		return fmt.Errorf("client order ID length %d exceeds maximum %d", len(order.ClientOrderId), MaxClientOrderIdLength)
+	}
+
+	// Order type and time in force
+	switch order.TimeInForce {
+	case ordertypes.TimeInForce_TIME_IN_FORCE_GOOD_TIL_CANCEL,
+		ordertypes.TimeInForce_TIME_IN_FORCE_FILL_OR_KILL,
+		ordertypes.TimeInForce_TIME_IN_FORCE_IMMEDIATE_OR_CANCEL,
+		ordertypes.TimeInForce_TIME_IN_FORCE_POST_ONLY:
+	default:
+		return fmt.Errorf("time in force must be GOOD_TIL_CANCEL, FILL_OR_KILL, IMMEDIATE_OR_CANCEL or POST_ONLY, got %s", order.TimeInForce)
+	}
+	if order.IsMarket {
+		switch order.TimeInForce {
+		case ordertypes.TimeInForce_TIME_IN_FORCE_GOOD_TIL_CANCEL:
+			return fmt.Errorf("market order cannot rest on the book with GOOD_TIL_CANCEL, use IMMEDIATE_OR_CANCEL or FILL_OR_KILL")
+		case ordertypes.TimeInForce_TIME_IN_FORCE_POST_ONLY:
+			return fmt.Errorf("market order cannot be POST_ONLY, use a limit order with a price instead")
+		}
+	} else if order.PriceValue == 0 {
+		return fmt.Errorf("limit order requires a price greater than 0, set IsMarket for a market order")
+	}
+
+	// Conditional order trigger
+	switch order.TriggerType {
+	case ordertypes.TriggerType_TRIGGER_TYPE_UNSPECIFIED:
+		if order.TriggerPriceValue != 0 {
+			return fmt.Errorf("trigger price is set but trigger type is unspecified, set TriggerType to STOP_LOSS or TAKE_PROFIT")
+		}
+		if order.TriggerPriceType != pricetypes.PriceType_PRICE_TYPE_UNSPECIFIED {
+			return fmt.Errorf("trigger price type is set but trigger type is unspecified, set TriggerType to STOP_LOSS or TAKE_PROFIT")
+		}
+	case ordertypes.TriggerType_TRIGGER_TYPE_STOP_LOSS, ordertypes.TriggerType_TRIGGER_TYPE_TAKE_PROFIT:
+		if order.TriggerPriceValue == 0 {
+			return fmt.Errorf("%s order requires a trigger price greater than 0", order.TriggerType)
+		}
+		if order.TriggerPriceType == pricetypes.PriceType_PRICE_TYPE_UNSPECIFIED {
+			return fmt.Errorf("%s order requires a trigger price type, e.g. PRICE_TYPE_LAST or PRICE_TYPE_ORACLE", order.TriggerType)
+		}
+	default:
+		return fmt.Errorf("unknown trigger type %s", order.TriggerType)
+	}
+
+	// Position take-profit/stop-loss
+	if order.IsPositionTp && order.IsPositionSl {
+		return fmt.Errorf("order cannot be both a position take-profit and a position stop-loss")
+	}
+	if order.IsPositionTp && order.TriggerType != ordertypes.TriggerType_TRIGGER_TYPE_TAKE_PROFIT {
+		return fmt.Errorf("position take-profit order requires TriggerType TAKE_PROFIT, got %s", order.TriggerType)
+	}
+	if order.IsPositionSl && order.TriggerType != ordertypes.TriggerType_TRIGGER_TYPE_STOP_LOSS {
+		return fmt.Errorf("position stop-loss order requires TriggerType STOP_LOSS, got %s", order.TriggerType)
+	}
+
+	// Open take-profit/stop-loss attached to an opening order
+	if (order.IsSetOpenTp || order.IsSetOpenSl) && order.ReduceOnly {
+		return fmt.Errorf("reduce-only order cannot carry open take-profit/stop-loss, they only apply to opening orders")
+	}
+	if (order.IsSetOpenTp || order.IsSetOpenSl) && (order.IsPositionTp || order.IsPositionSl) {
+		return fmt.Errorf("position take-profit/stop-loss order cannot carry open take-profit/stop-loss")
+	}
+	if order.IsSetOpenTp {
+		if err := validateOpenTpSlParam(order.OpenTpParam); err != nil {
+			return fmt.Errorf("open take-profit: %w", err)
+		}
+	}
+	if order.IsSetOpenSl {
+		if err := validateOpenTpSlParam(order.OpenSlParam); err != nil {
+			return fmt.Errorf("open stop-loss: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateOpenTpSlParam checks an open take-profit/stop-loss parameter
+func validateOpenTpSlParam(param *ordertypes.OpenTpSlParam) error {
+	if param == nil {
+		return fmt.Errorf("parameter is not set")
+	}
+	if param.TriggerPriceValue == 0 {
+		return fmt.Errorf("trigger price must be greater than 0")
+	}
+	if param.TriggerPriceType == pricetypes.PriceType_PRICE_TYPE_UNSPECIFIED {
+		return fmt.Errorf("trigger price type must be set")
+	}
+	if len(param.ClientOrderId) > MaxClientOrderIdLength {
+		return fmt.Errorf("client order ID length %d exceeds maximum %d", len(param.ClientOrderId), MaxClientOrderIdLength)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/query"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+const (
+	// DefaultOrderWaitPollInterval interval at which WaitForOrderStatus polls the order without a private stream
+	DefaultOrderWaitPollInterval = time.Second
+	// orderWaitStreamPollInterval interval at which WaitForOrderStatus still polls while listening to the private stream,
+	// in case an event is dropped
+	orderWaitStreamPollInterval = 5 * time.Second
+)
+
+// WaitForOrderStatus waits until an order reaches one of the target statuses or a final status and returns it. Without
+// target statuses it waits for a final status. The private stream is listened to when the WebSocket is connected, the
+// order is polled otherwise. An order not indexed yet is waited for, the context bounds the wait.
+func (c *AntxClient) WaitForOrderStatus(ctx context.Context, subaccountId, orderId string, targetStatuses ...uint32) (*types.Order, error) {
+	reached := func(order *types.Order) bool {
+		if isFinalOrderStatus(order.Status) {
+			return true
+		}
+		for _, status := range targetStatuses {
+			if order.Status == status {
+				return true
+			}
+		}
+		return false
+	}
+
+	interval := DefaultOrderWaitPollInterval
+	events, stop := c.watchOrderEvents()
+	if events != nil {
+		defer stop()
+		interval = orderWaitStreamPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		order, err := c.GetOrderById(subaccountId, orderId)
+		if err == nil && reached(order) {
+			return order, nil
+		}
+		if err != nil && !errors.Is(err, ErrOrderNotFound) {
+			return nil, err
+		}
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				return nil, fmt.Errorf("order %s: %w", orderId, ctx.Err())
+			case <-ticker.C:
+				break wait
+			case event := <-events:
+				if event.Id == orderId && event.SubaccountId == subaccountId && reached(&event) {
+					return &event, nil
+				}
+			}
+		}
+	}
+}
+
+// GetOrderById finds an order among the active orders of a subaccount and its history orders, ErrOrderNotFound when
+// there is none
+func (c *AntxClient) GetOrderById(subaccountId, orderId string) (*types.Order, error) {
+	active, err := c.getAllActiveOrders(subaccountId)
+	if err != nil {
+		return nil, err
+	}
+	for i := range active {
+		if active[i].Id == orderId {
+			return &active[i], nil
+		}
+	}
+	resp, err := c.GetHistoryOrder(types.GetHistoryOrderReq{
+		SubaccountId:      subaccountId,
+		Size:              1,
+		FilterOrderIdList: orderId,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range resp.Data.OrderList {
+		if resp.Data.OrderList[i].Id == orderId {
+			return &resp.Data.OrderList[i], nil
+		}
+	}
+	return nil, fmt.Errorf("order %s: %w", orderId, ErrOrderNotFound)
+}
+
+// watchOrderEvents returns the orders of the private events of the client address, nil when the WebSocket is not
+// connected. The connection is subscribed once and its events are shared by all watchers, stop unregisters the watcher.
+func (c *AntxClient) watchOrderEvents() (<-chan types.Order, func()) {
+	ws := c.WebSocket()
+	if ws == nil || !ws.IsConnected() {
+		return nil, nil
+	}
+	c.orderWatchMu.Lock()
+	defer c.orderWatchMu.Unlock()
+	if c.orderWatchWS != ws {
+		tradeDataChan, err := c.SubscribeToTradeData()
+		if err != nil {
+			return nil, nil
+		}
+		c.orderWatchWS = ws
+		go c.dispatchOrderEvents(ws, tradeDataChan)
+	}
+	if c.orderWatchers == nil {
+		c.orderWatchers = make(map[chan types.Order]struct{})
+	}
+	events := make(chan types.Order, 16)
+	c.orderWatchers[events] = struct{}{}
+	return events, func() {
+		c.orderWatchMu.Lock()
+		delete(c.orderWatchers, events)
+		c.orderWatchMu.Unlock()
+	}
+}
+
+// dispatchOrderEvents passes the orders of the private events of a connection to the registered watchers, dropping
+// them for a watcher that falls behind, until another connection is watched
+func (c *AntxClient) dispatchOrderEvents(ws *query.WebSocketClient, tradeDataChan <-chan []byte) {
+	for msg := range tradeDataChan {
+		event, err := c.ParseTradeDataEvent(msg)
+		if err != nil {
+			continue
+		}
+		c.orderWatchMu.Lock()
+		if c.orderWatchWS != ws {
+			c.orderWatchMu.Unlock()
+			return
+		}
+		for i := range event.OrderList {
+			for events := range c.orderWatchers {
+				select {
+				case events <- event.OrderList[i]:
+				default:
+				}
+			}
+		}
+		c.orderWatchMu.Unlock()
+	}
+}
@@ -0,0 +1,229 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// ErrDepthGap an incremental depth update does not continue the local book, resubscribe to receive a new snapshot
+var ErrDepthGap = errors.New("depth update version gap")
+
+// PriceLevel aggregated size at one price of the order book
+type PriceLevel struct {
+	Price decimal.Decimal // Price
+	Size  decimal.Decimal // Total size at the price
+}
+
+// OrderBook local order book maintained from depth snapshots and incremental updates
+type OrderBook struct {
+	ExchangeId string
+
+	mu        sync.RWMutex
+	bids      map[string]PriceLevel
+	asks      map[string]PriceLevel
+	version   uint64
+	ready     bool
+	updatedAt time.Time
+	onMetrics []metricsListener
+}
+
+// NewOrderBook creates an empty order book, it becomes ready after the first snapshot is applied
+func NewOrderBook(exchangeId string) *OrderBook {
+	return &OrderBook{
+		ExchangeId: exchangeId,
+		bids:       make(map[string]PriceLevel),
+		asks:       make(map[string]PriceLevel),
+	}
+}
+
+// Apply applies a depth snapshot or incremental update, returns ErrDepthGap when an update does not continue the book
+func (b *OrderBook) Apply(depth *types.DepthData) error {
+	if err := b.apply(depth); err != nil {
+		return err
+	}
+	b.notifyMetrics()
+	return nil
+}
+
+// apply applies a depth snapshot or incremental update to the levels
+func (b *OrderBook) apply(depth *types.DepthData) error {
+	if depth.ExchangeId != "" && depth.ExchangeId != b.ExchangeId {
+		return fmt.Errorf("depth of exchange %s applied to order book of exchange %s", depth.ExchangeId, b.ExchangeId)
+	}
+	startVersion, err := parseDepthVersion(depth.StartVersion)
+	if err != nil {
+		return err
+	}
+	endVersion, err := parseDepthVersion(depth.EndVersion)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if depth.IsSnapshot {
+		b.bids = make(map[string]PriceLevel, len(depth.Bids))
+		b.asks = make(map[string]PriceLevel, len(depth.Asks))
+	} else {
+		if !b.ready {
+			return fmt.Errorf("depth update received before snapshot: %w", ErrDepthGap)
+		}
+		if startVersion != 0 && b.version != 0 && startVersion != b.version+1 {
+			b.ready = false
+			return fmt.Errorf("expected version %d, got %d: %w", b.version+1, startVersion, ErrDepthGap)
+		}
+	}
+
+	if err := applyBookOrders(b.bids, depth.Bids); err != nil {
+		b.ready = false
+		return err
+	}
+	if err := applyBookOrders(b.asks, depth.Asks); err != nil {
+		b.ready = false
+		return err
+	}
+	if endVersion != 0 {
+		b.version = endVersion
+	}
+	b.ready = true
+	b.updatedAt = time.Now()
+	return nil
+}
+
+// Ready reports whether the book holds a consistent snapshot
+func (b *OrderBook) Ready() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.ready
+}
+
+// Version returns the depth version of the last applied update
+func (b *OrderBook) Version() uint64 {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.version
+}
+
+// UpdatedAt returns the time the last update was applied
+func (b *OrderBook) UpdatedAt() time.Time {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.updatedAt
+}
+
+// Bids returns up to n bid levels from best to worst, all levels when n <= 0
+func (b *OrderBook) Bids(n int) []PriceLevel {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return sortedLevels(b.bids, n, true)
+}
+
+// Asks returns up to n ask levels from best to worst, all levels when n <= 0
+func (b *OrderBook) Asks(n int) []PriceLevel {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return sortedLevels(b.asks, n, false)
+}
+
+// BestBid returns the highest bid level
+func (b *OrderBook) BestBid() (PriceLevel, bool) {
+	levels := b.Bids(1)
+	if len(levels) == 0 {
+		return PriceLevel{}, false
+	}
+	return levels[0], true
+}
+
+// BestAsk returns the lowest ask level
+func (b *OrderBook) BestAsk() (PriceLevel, bool) {
+	levels := b.Asks(1)
+	if len(levels) == 0 {
+		return PriceLevel{}, false
+	}
+	return levels[0], true
+}
+
+// Mid returns the midpoint of the best bid and ask
+func (b *OrderBook) Mid() (decimal.Decimal, bool) {
+	bid, ok := b.BestBid()
+	if !ok {
+		return decimal.Zero, false
+	}
+	ask, ok := b.BestAsk()
+	if !ok {
+		return decimal.Zero, false
+	}
+	return bid.Price.Add(ask.Price).Div(decimal.NewFromInt(2)), true
+}
+
+// ResubscribeToDepth resubscribes to depth so that the gateway pushes a new snapshot, e.g. after ErrDepthGap
+func (c *AntxClient) ResubscribeToDepth(exchangeId, level string) error {
+	ws := c.WebSocket()
+	if ws == nil {
+		return ErrNotConnected
+	}
+	channel := fmt.Sprintf("depth.%s.%s", exchangeId, level)
+	if err := ws.Unsubscribe(channel); err != nil {
+		return err
+	}
+	return ws.Subscribe(channel)
+}
+
+// applyBookOrders applies price levels to one side of the book, a zero size removes the level
+func applyBookOrders(side map[string]PriceLevel, orders []types.BookOrder) error {
+	for _, order := range orders {
+		price, err := decimal.NewFromString(order.Price)
+		if err != nil {
+			return fmt.Errorf("failed to parse depth price: %w", err)
+		}
+		size, err := decimal.NewFromString(order.Size)
+		if err != nil {
+			return fmt.Errorf("failed to parse depth size: %w", err)
+		}
+		key := price.String()
+		if size.IsZero() {
+			delete(side, key)
+			continue
+		}
+		side[key] = PriceLevel{Price: price, Size: size}
+	}
+	return nil
+}
+
+// sortedLevels sorts one side of the book from best to worst
+func sortedLevels(side map[string]PriceLevel, n int, descending bool) []PriceLevel {
+	levels := make([]PriceLevel, 0, len(side))
+	for _, level := range side {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool {
+		if descending {
+			return levels[i].Price.GreaterThan(levels[j].Price)
+		}
+		return levels[i].Price.LessThan(levels[j].Price)
+	})
+	if n > 0 && len(levels) > n {
+		levels = levels[:n]
+	}
+	return levels
+}
+
+// parseDepthVersion parses a depth version, an empty version is 0
+func parseDepthVersion(version string) (uint64, error) {
+	if version == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseUint(version, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse depth version %s: %w", version, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,99 @@
+package sdk
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// BookMetrics order book metrics over the best levels of each side
+type BookMetrics struct {
+	ExchangeId  string          // Exchange ID
+	Version     uint64          // Depth version the metrics reflect
+	UpdatedAt   time.Time       // Time the depth version was applied
+	Levels      int             // Number of levels per side the volumes cover
+	BidVolume   decimal.Decimal // Total size of the bid levels
+	AskVolume   decimal.Decimal // Total size of the ask levels
+	Imbalance   decimal.Decimal // (BidVolume - AskVolume) / (BidVolume + AskVolume), from -1 (all asks) to 1 (all bids)
+	Spread      decimal.Decimal // Best ask minus best bid
+	Mid         decimal.Decimal // Midpoint of the best bid and ask
+	Microprice  decimal.Decimal // Best bid and ask weighted by the opposite top sizes, leaning toward the thinner side
+	WeightedMid decimal.Decimal // Midpoint of the size-weighted average bid and ask prices over the levels
+}
+
+// metricsListener callback of OnMetrics with its level count
+type metricsListener struct {
+	levels int
+	fn     func(BookMetrics)
+}
+
+// OnMetrics registers a callback invoked with the metrics over levels levels per side after each applied update,
+// while both sides of the book are quoted
+func (b *OrderBook) OnMetrics(levels int, fn func(BookMetrics)) {
+	b.mu.Lock()
+	b.onMetrics = append(b.onMetrics, metricsListener{levels: levels, fn: fn})
+	b.mu.Unlock()
+}
+
+// Metrics computes the metrics over up to levels levels per side, all levels when levels <= 0, false when either side
+// is empty
+func (b *OrderBook) Metrics(levels int) (BookMetrics, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.metrics(levels)
+}
+
+// notifyMetrics passes the metrics of the current book to the OnMetrics callbacks
+func (b *OrderBook) notifyMetrics() {
+	b.mu.RLock()
+	if len(b.onMetrics) == 0 {
+		b.mu.RUnlock()
+		return
+	}
+	listeners := append([]metricsListener(nil), b.onMetrics...)
+	metrics := make([]BookMetrics, len(listeners))
+	ok := true
+	for i, listener := range listeners {
+		if metrics[i], ok = b.metrics(listener.levels); !ok {
+			break
+		}
+	}
+	b.mu.RUnlock()
+	if !ok {
+		return
+	}
+	for i, listener := range listeners {
+		listener.fn(metrics[i])
+	}
+}
+
+// metrics computes the book metrics, must be called with the lock held
+func (b *OrderBook) metrics(levels int) (BookMetrics, bool) {
+	bids := sortedLevels(b.bids, levels, true)
+	asks := sortedLevels(b.asks, levels, false)
+	if len(bids) == 0 || len(asks) == 0 {
+		return BookMetrics{}, false
+	}
+	m := BookMetrics{ExchangeId: b.ExchangeId, Version: b.version, UpdatedAt: b.updatedAt, Levels: levels}
+	bidValue, askValue := decimal.Zero, decimal.Zero
+	for _, level := range bids {
+		m.BidVolume = m.BidVolume.Add(level.Size)
+		bidValue = bidValue.Add(level.Price.Mul(level.Size))
+	}
+	for _, level := range asks {
+		m.AskVolume = m.AskVolume.Add(level.Size)
+		askValue = askValue.Add(level.Price.Mul(level.Size))
+	}
+	two := decimal.NewFromInt(2)
+	bestBid, bestAsk := bids[0], asks[0]
+	m.Spread = bestAsk.Price.Sub(bestBid.Price)
+	m.Mid = bestBid.Price.Add(bestAsk.Price).Div(two)
+	if total := m.BidVolume.Add(m.AskVolume); total.IsPositive() {
+		m.Imbalance = m.BidVolume.Sub(m.AskVolume).Div(total)
+		m.WeightedMid = bidValue.Div(m.BidVolume).Add(askValue.Div(m.AskVolume)).Div(two)
+	}
+	if top := bestBid.Size.Add(bestAsk.Size); top.IsPositive() {
+		m.Microprice = bestBid.Price.Mul(bestAsk.Size).Add(bestAsk.Price.Mul(bestBid.Size)).Div(top)
+	}
+	return m, true
+}
@@ -0,0 +1,16 @@
+package sdk
+
+import "github.com/antxprotocol/antx-sdk-golang/store"
+
+// SetStore sets the store persisting the order trackers and the client order IDs of Config.DedupeWindow across
+// restarts, nil keeps them in memory only. The client order IDs stored within the window are loaded, trackers created
+// before the store is set are not persisted.
+func (c *AntxClient) SetStore(s store.Store) error {
+	c.trackerMu.Lock()
+	c.store = s
+	c.trackerMu.Unlock()
+	if c.dedupe != nil {
+		return c.dedupe.setStore(s)
+	}
+	return nil
+}
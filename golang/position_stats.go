@@ -0,0 +1,120 @@
+package sdk
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// MarketTermStats closed position terms of one exchange
+type MarketTermStats struct {
+	ExchangeId string          // Exchange ID
+	Closed     int             // Number of closed terms
+	Wins       int             // Closed terms with a positive net PnL
+	Pnl        decimal.Decimal // Net PnL of the closed terms, after fees and funding
+	Fees       decimal.Decimal // Open, close and liquidation fees of the closed terms, negative when paid
+	Funding    decimal.Decimal // Funding fees of the closed terms, negative when paid
+}
+
+// PositionTermStats statistics of the closed position terms of a subaccount
+type PositionTermStats struct {
+	Terms                int                         // Number of terms read, open ones included
+	Closed               int                         // Number of closed terms the statistics cover
+	Wins                 int                         // Closed terms with a positive net PnL
+	WinRate              decimal.Decimal             // Wins over closed terms, zero when none is closed
+	Pnl                  decimal.Decimal             // Net PnL of the closed terms
+	AverageHolding       time.Duration               // Average time between opening and closing a term
+	AverageCloseLeverage decimal.Decimal             // Average leverage at complete close
+	Markets              map[string]*MarketTermStats // Statistics by exchange ID
+}
+
+// GetPositionTermStats reads the position terms of a subaccount created in [start, end) (unit: milliseconds, 0 for an
+// open bound) and computes their statistics
+func (c *AntxClient) GetPositionTermStats(subaccountId string, start, end uint64) (*PositionTermStats, error) {
+	req := types.GetHistoryPositionTermReq{
+		SubaccountId:                    subaccountId,
+		Size:                            100,
+		FilterStartCreatedTimeInclusive: start,
+		FilterEndCreatedTimeExclusive:   end,
+	}
+	terms, err := types.FetchAllPages(req.Size, func(cursor types.Cursor) ([]types.PerpetualPositionTerm, types.IndexerPageOffsetData, error) {
+		req.PageOffsetDataCreatedTime, req.PageOffsetDataItemId = cursor.CreatedTime, cursor.ItemId
+		resp, err := c.GetHistoryPositionTerm(req)
+		if err != nil {
+			return nil, types.IndexerPageOffsetData{}, err
+		}
+		return resp.Data.PositionTermList, resp.Data.PageOffsetData, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return AnalyzePositionTerms(terms)
+}
+
+// AnalyzePositionTerms computes the win rate, holding time, leverage at close and per-market PnL of the closed terms.
+// The net PnL of a term is its close value less its open value, reversed for shorts, plus its fees and funding fee, which
+// are collateral changes and so negative when paid.
+func AnalyzePositionTerms(terms []types.PerpetualPositionTerm) (*PositionTermStats, error) {
+	stats := &PositionTermStats{Terms: len(terms), Markets: make(map[string]*MarketTermStats)}
+	var holding time.Duration
+	leverageSum, leverageCount := decimal.Zero, 0
+	for _, term := range terms {
+		var values [8]decimal.Decimal
+		for i, value := range []string{term.CumOpenSize, term.CumOpenValue, term.CumOpenFee, term.CumCloseSize,
+			term.CumCloseValue, term.CumCloseFee, term.CumFundingFee, term.CumLiquidateFee} {
+			var err error
+			if values[i], err = parseOptionalDecimal(value); err != nil {
+				return nil, fmt.Errorf("failed to parse term %d of exchange %s: %w", term.TermCount, term.ExchangeId, err)
+			}
+		}
+		openSize, openValue, closeSize, closeValue := values[0], values[1], values[3], values[4]
+		if openSize.IsZero() || closeSize.Abs().LessThan(openSize.Abs()) {
+			// Still open
+			continue
+		}
+
+		pnl := closeValue.Abs().Sub(openValue.Abs())
+		if openSize.IsNegative() {
+			pnl = pnl.Neg()
+		}
+		fees := values[2].Add(values[5]).Add(values[7])
+		pnl = pnl.Add(fees).Add(values[6])
+
+		market, ok := stats.Markets[term.ExchangeId]
+		if !ok {
+			market = &MarketTermStats{ExchangeId: term.ExchangeId}
+			stats.Markets[term.ExchangeId] = market
+		}
+		market.Closed++
+		market.Pnl = market.Pnl.Add(pnl)
+		market.Fees = market.Fees.Add(fees)
+		market.Funding = market.Funding.Add(values[6])
+		stats.Closed++
+		stats.Pnl = stats.Pnl.Add(pnl)
+		if pnl.IsPositive() {
+			market.Wins++
+			stats.Wins++
+		}
+		if term.UpdatedTime > term.CreatedTime {
+			holding += time.Duration(term.UpdatedTime-term.CreatedTime) * time.Millisecond
+		}
+		if term.CloseLeverage != "" {
+			leverage, err := decimal.NewFromString(term.CloseLeverage)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse close leverage of term %d of exchange %s: %w", term.TermCount, term.ExchangeId, err)
+			}
+			leverageSum = leverageSum.Add(leverage)
+			leverageCount++
+		}
+	}
+	if stats.Closed > 0 {
+		stats.WinRate = decimal.NewFromInt(int64(stats.Wins)).Div(decimal.NewFromInt(int64(stats.Closed)))
+		stats.AverageHolding = holding / time.Duration(stats.Closed)
+	}
+	if leverageCount > 0 {
+		stats.AverageCloseLeverage = leverageSum.Div(decimal.NewFromInt(int64(leverageCount)))
+	}
+	return stats, nil
+}
@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// ScaleDecimal converts a decimal to an integer value with the given scale, i.e. value = d * 10^scale
+func ScaleDecimal(d decimal.Decimal, scale int32) (uint64, error) {
+	if d.IsNegative() {
+		return 0, fmt.Errorf("value %s cannot be negative", d.String())
+	}
+	scaled := d.Shift(scale)
+	if !scaled.IsInteger() {
+		return 0, fmt.Errorf("value %s is not a multiple of %s", d.String(), decimal.New(1, -scale).String())
+	}
+	value := scaled.BigInt()
+	if !value.IsUint64() {
+		return 0, fmt.Errorf("value %s overflows scale %d", d.String(), scale)
+	}
+	return value.Uint64(), nil
+}
+
+// UnscaleDecimal converts an integer value with the given scale back to a decimal, i.e. d = value * 10^-scale
+func UnscaleDecimal(value uint64, scale int32) decimal.Decimal {
+	return decimal.NewFromBigInt(new(big.Int).SetUint64(value), -scale)
+}
+
+// TickSize returns the minimum price unit of an exchange
+func TickSize(exchange *types.Exchange) decimal.Decimal {
+	return decimal.New(1, -exchange.TickSizeScale)
+}
+
+// StepSize returns the minimum size unit of an exchange
+func StepSize(exchange *types.Exchange) decimal.Decimal {
+	return decimal.New(1, -exchange.StepSizeScale)
+}
+
+// RoundingMode how a price or size off the tick or step grid is handled
+type RoundingMode int
+
+const (
+	RoundStrict  RoundingMode = iota // Reject a value off the grid
+	RoundDown                        // Round toward zero, e.g. buy prices and sizes, never more aggressive than requested
+	RoundUp                          // Round away from zero, e.g. sell prices
+	RoundNearest                     // Round to the nearest grid value, half away from zero
+)
+
+// PassiveRounding returns the price rounding that never makes an order more aggressive: down for buys, up for sells
+func PassiveRounding(isBuy bool) RoundingMode {
+	if isBuy {
+		return RoundDown
+	}
+	return RoundUp
+}
+
+// RoundPriceToTick rounds a price to the tick size of an exchange, RoundStrict returns an error when it is off the grid
+func RoundPriceToTick(price decimal.Decimal, exchange *types.Exchange, mode RoundingMode) (decimal.Decimal, error) {
+	return roundToScale(price, exchange.TickSizeScale, mode)
+}
+
+// RoundSizeToStep rounds a size to the step size of an exchange, RoundStrict returns an error when it is off the grid
+func RoundSizeToStep(size decimal.Decimal, exchange *types.Exchange, mode RoundingMode) (decimal.Decimal, error) {
+	return roundToScale(size, exchange.StepSizeScale, mode)
+}
+
+// roundToScale rounds a value to a multiple of 10^-scale
+func roundToScale(d decimal.Decimal, scale int32, mode RoundingMode) (decimal.Decimal, error) {
+	switch mode {
+	case RoundStrict:
+		if !d.Shift(scale).IsInteger() {
+			return d, fmt.Errorf("value %s is not a multiple of %s", d.String(), decimal.New(1, -scale).String())
+		}
+		return d, nil
+	case RoundDown:
+		return d.RoundDown(scale), nil
+	case RoundUp:
+		return d.RoundUp(scale), nil
+	case RoundNearest:
+		return d.Round(scale), nil
+	default:
+		return d, fmt.Errorf("unknown rounding mode %d", mode)
+	}
+}
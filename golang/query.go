@@ -0,0 +1,110 @@
+package sdk
+
+import (
+	"errors"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/query"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// The gateway queries and WebSocket client live in the query package, which builds without the cosmos-sdk and
+// go-ethereum dependencies. The names below keep existing imports of this package working.
+
+// WebSocketClient WebSocket client, see query.WebSocketClient
+type WebSocketClient = query.WebSocketClient
+
+// WsReqBase WebSocket request base structure, see query.WsReqBase
+type WsReqBase = query.WsReqBase
+
+// WsRegisterReq WebSocket subscription registration request, see query.WsRegisterReq
+type WsRegisterReq = query.WsRegisterReq
+
+// WsSubscribeReq WebSocket subscription request, see query.WsSubscribeReq
+type WsSubscribeReq = query.WsSubscribeReq
+
+// WsRespBase WebSocket response base structure, see query.WsRespBase
+type WsRespBase = query.WsRespBase
+
+// WsMessage pooled WebSocket message, see query.WsMessage
+type WsMessage = query.WsMessage
+
+// NewWebSocketClient creates a WebSocket client, see query.NewWebSocketClient
+func NewWebSocketClient(url string, messageHandler func([]byte), errorHandler func(error)) *WebSocketClient {
+	return query.NewWebSocketClient(url, messageHandler, errorHandler)
+}
+
+// ParseTickerData parses Ticker data, see query.ParseTickerData
+func ParseTickerData(data []byte) (*types.TickerData, error) {
+	return query.ParseTickerData(data)
+}
+
+// ParseKlineData parses K-line data, see query.ParseKlineData
+func ParseKlineData(data []byte) (*types.KLine, error) {
+	return query.ParseKlineData(data)
+}
+
+// TxError failed transaction, see query.TxError
+type TxError = query.TxError
+
+// Errors of failed transactions, see query.ErrTxFailed
+var (
+	ErrTxFailed             = query.ErrTxFailed
+	ErrTxInsufficientMargin = query.ErrTxInsufficientMargin
+	ErrTxPriceBand          = query.ErrTxPriceBand
+	ErrTxReduceOnly         = query.ErrTxReduceOnly
+	ErrTxInvalidOrder       = query.ErrTxInvalidOrder
+	ErrTxDuplicateOrder     = query.ErrTxDuplicateOrder
+	ErrTxTradingDisabled    = query.ErrTxTradingDisabled
+	ErrTxOraclePrice        = query.ErrTxOraclePrice
+	ErrTxOrderLimit         = query.ErrTxOrderLimit
+	ErrTxLiquidating        = query.ErrTxLiquidating
+)
+
+// Errors of the client matched by errors.Is, see query.ErrNotConnected. ErrInsufficientMargin is ErrTxInsufficientMargin.
+var (
+	ErrNotConnected       = query.ErrNotConnected
+	ErrGatewayUnset       = query.ErrGatewayUnset
+	ErrThrottled          = query.ErrThrottled
+	ErrTxTimeout          = query.ErrTxTimeout
+	ErrInsufficientMargin = query.ErrTxInsufficientMargin
+)
+
+// TxResultError returns the *TxError of a failed transaction result, see query.TxResultError
+func TxResultError(result *types.GetTransactionResultRespData) error {
+	return query.TxResultError(result)
+}
+
+// RegisterTxError maps a chain error code to an error, see query.RegisterTxError
+func RegisterTxError(codespace string, code uint32, err error) {
+	query.RegisterTxError(codespace, code, err)
+}
+
+// APIError gateway response with a response code other than "0", see types.APIError
+type APIError = types.APIError
+
+// HTTPStatusError gateway response with a non-2xx HTTP status, see query.HTTPStatusError
+type HTTPStatusError = query.HTTPStatusError
+
+// RetryAfter returns the delay the gateway asked for before retrying a failed request, see query.RetryAfter
+func RetryAfter(err error) time.Duration {
+	return query.RetryAfter(err)
+}
+
+// IsRateLimited reports whether a request was throttled by the gateway, see query.IsRateLimited
+func IsRateLimited(err error) bool {
+	return query.IsRateLimited(err)
+}
+
+// IsNotFound reports whether a request failed because the order, subaccount or resource it names does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrOrderNotFound) || errors.Is(err, ErrSubaccountNotFound) || query.IsNotFound(err)
+}
+
+// IsSequenceMismatch reports whether a transaction was rejected for its account sequence, see query.IsSequenceMismatch
+func IsSequenceMismatch(err error) bool {
+	return query.IsSequenceMismatch(err)
+}
+
+// ResponseMeta raw gateway response, see query.ResponseMeta
+type ResponseMeta = query.ResponseMeta
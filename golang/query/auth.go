@@ -0,0 +1,76 @@
+package query
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Headers of the authentication, the app token of gateways behind a WAF is set as a custom header, e.g.
+// SetHeader(HeaderAppToken, token)
+const (
+	HeaderAppToken     = "X-App-Token"
+	HeaderAPIKey       = "X-Api-Key"
+	HeaderAPITimestamp = "X-Api-Timestamp"
+	HeaderAPISignature = "X-Api-Signature"
+)
+
+// APICredentials API key of gateways fronted by API key authentication
+type APICredentials struct {
+	Key    string // API key, sent in the X-Api-Key header
+	Secret string // API secret signing each request with SignAPIRequest, empty when requests are not signed
+}
+
+// SetAPICredentials authenticates the HTTP requests and the WebSocket connections with an API key, an empty key
+// removes it
+func (c *Client) SetAPICredentials(credentials APICredentials) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.credentials = credentials
+}
+
+// apiCredentials returns the API key of the client
+func (c *Client) apiCredentials() APICredentials {
+	c.settingsMu.RLock()
+	defer c.settingsMu.RUnlock()
+	return c.credentials
+}
+
+// SignAPIRequest returns the hex HMAC-SHA256 with the API secret of the timestamp in milliseconds, the method, the
+// request URI (path and query) and the body, concatenated
+func SignAPIRequest(secret, timestamp, method, requestURI string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + method + requestURI))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// setGatewayHeaders sets the headers expected by the gateway on a request with body
+func (c *Client) setGatewayHeaders(req *http.Request, body []byte) {
+	setGatewayHeader(req.Header, c.apiCredentials(), c.UserAgent(), c.Headers(), req.Method, req.URL.RequestURI(), body)
+	req.Header.Set("Accept", "application/json")
+}
+
+// setGatewayHeader sets the client headers, the custom headers and the authentication headers of a request, in that
+// order, so custom headers override the defaults but not the API key signature
+func setGatewayHeader(header http.Header, credentials APICredentials, userAgent string, custom map[string]string, method, requestURI string, body []byte) {
+	header.Set("User-Agent", userAgent)
+	for key, value := range custom {
+		if value == "" {
+			header.Del(key)
+		} else {
+			header.Set(key, value)
+		}
+	}
+	if credentials.Key != "" {
+		header.Set(HeaderAPIKey, credentials.Key)
+		if credentials.Secret != "" {
+			timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+			header.Set(HeaderAPITimestamp, timestamp)
+			header.Set(HeaderAPISignature, SignAPIRequest(credentials.Secret, timestamp, method, requestURI, body))
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package query
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChaosConfig fault injection schedule of a Chaos, rates are probabilities in [0, 1] drawn per HTTP request or per
+// received WebSocket message
+type ChaosConfig struct {
+	Seed            int64         // Seed of the schedule, the same seed injects the same faults into the same sequence of requests and messages
+	LatencyRate     float64       // Rate of requests and messages delayed by up to MaxLatency
+	MaxLatency      time.Duration // Maximum injected delay
+	ServerErrorRate float64       // Rate of HTTP requests answered with a 5xx status without reaching the gateway
+	DropRate        float64       // Rate of HTTP requests failing with a connection error and of messages closing the WebSocket connection
+	DuplicateRate   float64       // Rate of WebSocket messages delivered twice
+	ReorderRate     float64       // Rate of WebSocket messages held back and delivered after the next one
+}
+
+// ChaosStats number of faults injected by a Chaos
+type ChaosStats struct {
+	Delayed      int64 // Delayed requests and messages
+	ServerErrors int64 // HTTP requests answered with a 5xx status
+	Dropped      int64 // Failed HTTP requests and closed WebSocket connections
+	Duplicated   int64 // Duplicated WebSocket messages
+	Reordered    int64 // WebSocket messages delivered out of order
+}
+
+// Chaos injects latency, dropped connections, 5xx responses and duplicated or reordered WebSocket messages into the
+// transports of a client following a seeded schedule, to exercise the recovery paths of strategies and of the SDK
+type Chaos struct {
+	config ChaosConfig
+
+	mu       sync.Mutex
+	rand     *rand.Rand
+	disabled bool
+	stats    ChaosStats
+}
+
+// chaosFault faults drawn for one request or message
+type chaosFault struct {
+	delay       time.Duration
+	drop        bool
+	serverError int
+	duplicate   bool
+	reorder     bool
+}
+
+// chaosServerErrors statuses of the injected server errors
+var chaosServerErrors = []int{
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// NewChaos creates a fault injector, enabled
+func NewChaos(config ChaosConfig) *Chaos {
+	return &Chaos{config: config, rand: rand.New(rand.NewSource(config.Seed))}
+}
+
+// SetEnabled enables or disables fault injection, e.g. to check that a strategy recovers once faults stop. The
+// schedule does not advance while disabled.
+func (ch *Chaos) SetEnabled(enabled bool) {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.disabled = !enabled
+}
+
+// Stats returns the number of faults injected so far
+func (ch *Chaos) Stats() ChaosStats {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	return ch.stats
+}
+
+// RoundTripper wraps an HTTP transport with fault injection, next defaults to http.DefaultTransport
+func (ch *Chaos) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &chaosTransport{chaos: ch, next: next}
+}
+
+// draw draws the faults of the next request or message. Every draw consumes the same random numbers whatever the
+// rates, so the schedule of a seed only depends on the sequence of requests and messages.
+func (ch *Chaos) draw(ws bool) chaosFault {
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	if ch.disabled {
+		return chaosFault{}
+	}
+	c := ch.config
+	var values [6]float64
+	for i := range values {
+		values[i] = ch.rand.Float64()
+	}
+	var fault chaosFault
+	if values[0] < c.LatencyRate && c.MaxLatency > 0 {
+		fault.delay = time.Duration(values[1] * float64(c.MaxLatency))
+		ch.stats.Delayed++
+	}
+	if values[2] < c.DropRate {
+		fault.drop = true
+		ch.stats.Dropped++
+		return fault
+	}
+	if ws {
+		if values[3] < c.DuplicateRate {
+			fault.duplicate = true
+			ch.stats.Duplicated++
+		}
+		if values[4] < c.ReorderRate {
+			fault.reorder = true
+			ch.stats.Reordered++
+		}
+	} else if values[3] < c.ServerErrorRate {
+		fault.serverError = chaosServerErrors[int(values[5]*float64(len(chaosServerErrors)))]
+		ch.stats.ServerErrors++
+	}
+	return fault
+}
+
+// chaosTransport HTTP transport injecting faults before sending requests
+type chaosTransport struct {
+	chaos *Chaos
+	next  http.RoundTripper
+}
+
+// RoundTrip sends a request unless a fault answers it
+func (t *chaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fault := t.chaos.draw(false)
+	if fault.delay > 0 {
+		timer := time.NewTimer(fault.delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+	if fault.drop {
+		return nil, fmt.Errorf("connection dropped: %w", ErrInjectedFault)
+	}
+	if fault.serverError != 0 {
+		body := fmt.Sprintf("%d %s (%v)", fault.serverError, http.StatusText(fault.serverError), ErrInjectedFault)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", fault.serverError, http.StatusText(fault.serverError)),
+			StatusCode:    fault.serverError,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{"text/plain"}},
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return t.next.RoundTrip(req)
+}
+
+// chaosMessage WebSocket message queued for delivery
+type chaosMessage struct {
+	messageType int
+	data        []byte
+}
+
+// chaosConn WebSocket connection injecting faults into received messages, read from a single goroutine
+type chaosConn struct {
+	wsConn
+	chaos   *Chaos
+	held    *chaosMessage  // Message held back until the next one is delivered
+	pending []chaosMessage // Duplicated and released messages delivered before reading more
+}
+
+// NextReader returns the next message after applying its faults
+func (c *chaosConn) NextReader() (int, io.Reader, error) {
+	if len(c.pending) > 0 {
+		message := c.pending[0]
+		c.pending = c.pending[1:]
+		return message.messageType, bytes.NewReader(message.data), nil
+	}
+	for {
+		messageType, r, err := c.wsConn.NextReader()
+		if err != nil {
+			return messageType, r, err
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return messageType, nil, err
+		}
+		fault := c.chaos.draw(true)
+		if fault.delay > 0 {
+			time.Sleep(fault.delay)
+		}
+		if fault.drop {
+			c.wsConn.Close()
+			return messageType, nil, fmt.Errorf("connection dropped: %w", ErrInjectedFault)
+		}
+		message := chaosMessage{messageType: messageType, data: data}
+		if fault.reorder && c.held == nil {
+			c.held = &message
+			continue
+		}
+		if fault.duplicate {
+			c.pending = append(c.pending, message)
+		}
+		if c.held != nil {
+			c.pending = append(c.pending, *c.held)
+			c.held = nil
+		}
+		return messageType, bytes.NewReader(data), nil
+	}
+}
+
+// SetChaos injects faults into the HTTP requests of the client and the messages of the WebSocket connections it dials
+// afterwards, nil stops injecting into HTTP requests and future connections
+func (c *Client) SetChaos(chaos *Chaos) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.chaos = chaos
+	httpClient := http.Client{Timeout: DefaultHTTPTimeout}
+	if c.httpClient != nil {
+		httpClient = *c.httpClient
+	}
+	transport := httpClient.Transport
+	if t, ok := transport.(*chaosTransport); ok {
+		transport = t.next
+	}
+	if chaos != nil {
+		transport = chaos.RoundTripper(transport)
+	}
+	httpClient.Transport = transport
+	c.httpClient = &httpClient
+}
+
+// SetChaos injects faults into the messages of the connections dialed afterwards, nil disables it
+func (c *WebSocketClient) SetChaos(chaos *Chaos) {
+	c.chaos = chaos
+}
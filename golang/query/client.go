@@ -0,0 +1,981 @@
+// Package query reads the Antx gateway over REST and WebSocket without signing, it does not import the cosmos-sdk
+// or go-ethereum. It also builds for js/wasm, where the WebSocket goes through the browser API, so browser dashboards
+// can use the same typed client.
+// The sdk package embeds its Client and adds transaction signing on top.
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// DefaultHTTPTimeout default time limit of each gateway request
+const DefaultHTTPTimeout = 30 * time.Second
+
+// defaultHTTPClient HTTP client of the clients without one, e.g. a zero Client
+var defaultHTTPClient = &http.Client{Timeout: DefaultHTTPTimeout}
+
+// Client gateway client for REST queries and WebSocket market data, it does not sign transactions. It is safe for
+// concurrent use, settings changed while requests are in flight apply to the requests sent afterwards.
+type Client struct {
+	wsMu     sync.RWMutex
+	wsClient *WebSocketClient
+	// hand-written market data decoders
+	fastJSON bool
+	// request settings, guarded by settingsMu
+	settingsMu      sync.RWMutex
+	httpClient      *http.Client
+	baseURL         string
+	wsURL           string
+	pathOverrides   map[string]string
+	apiPrefix       string
+	responseHook    func(*ResponseMeta)
+	credentials     APICredentials
+	userAgent       string
+	compressMinSize int
+	chaos           *Chaos
+	logger          Logger
+	middleware      []Middleware
+	wsDropHandler   func(channel string)
+	rateLimiter     *RateLimiter
+	failover        *failover
+	retry           RetryPolicy
+	headers         map[string]string
+	transport       *TransportConfig
+	noCompression   bool // whether compressed responses and WebSocket messages are not requested
+}
+
+// NewClient creates a query client for the gateway HTTP and WebSocket addresses
+func NewClient(baseURL, wsURL string) *Client {
+	return &Client{
+		baseURL:    baseURL,
+		wsURL:      wsURL,
+		httpClient: &http.Client{Timeout: DefaultHTTPTimeout},
+		fastJSON:   fastJSONDefault,
+		retry:      DefaultRetryPolicy(),
+	}
+}
+
+// SetGateway sets the HTTP and WebSocket gateway addresses, requests in flight complete on the previous gateway and
+// connected WebSockets stay on it until they reconnect
+func (c *Client) SetGateway(baseURL, wsURL string) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.baseURL = baseURL
+	c.wsURL = wsURL
+}
+
+// Gateway returns the HTTP and WebSocket gateway addresses
+func (c *Client) Gateway() (baseURL, wsURL string) {
+	c.settingsMu.RLock()
+	defer c.settingsMu.RUnlock()
+	return c.baseURL, c.wsURL
+}
+
+// SetHTTPClient sets the HTTP client sending the gateway requests, e.g. one with a custom transport or proxy, nil
+// restores the default. Fault injection set with SetChaos keeps wrapping its transport.
+func (c *Client) SetHTTPClient(httpClient *http.Client) {
+	c.settingsMu.Lock()
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: DefaultHTTPTimeout}
+	}
+	copied := *httpClient
+	c.httpClient = &copied
+	chaos := c.chaos
+	c.settingsMu.Unlock()
+	if chaos != nil {
+		c.SetChaos(chaos)
+	}
+}
+
+// SetHTTPTimeout sets the time limit of each gateway request, including reading the response body, 0 means no limit
+func (c *Client) SetHTTPTimeout(timeout time.Duration) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	httpClient := http.Client{}
+	if c.httpClient != nil {
+		httpClient = *c.httpClient
+	}
+	httpClient.Timeout = timeout
+	c.httpClient = &httpClient
+}
+
+// =============================== HTTP Request Methods ===============================
+
+// getJSON sends a GET request and decodes the JSON response into result, returning the response for the errors of
+// its response code
+func (c *Client) getJSON(ctx context.Context, path string, params map[string]string, result interface{}) (*ResponseMeta, error) {
+	if baseURL, _ := c.Gateway(); baseURL == "" {
+		return nil, ErrGatewayUnset
+	}
+	meta, err := c.send(ctx, "GET", path, params, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	captureMeta(ctx, meta)
+	if err := json.Unmarshal(meta.Body, result); err != nil {
+		return meta, fmt.Errorf("failed to unmarshal response: %w, body: %s", err, truncateBody(meta.Body))
+	}
+	meta.setBaseResp(result)
+	return meta, nil
+}
+
+// postJSON sends data as JSON and decodes the JSON response into result, returning the response for the errors of its
+// response code
+func (c *Client) postJSON(ctx context.Context, path string, data interface{}, result interface{}) (*ResponseMeta, error) {
+	if baseURL, _ := c.Gateway(); baseURL == "" {
+		return nil, ErrGatewayUnset
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request data: %w", err)
+	}
+	meta, err := c.send(ctx, "POST", path, nil, b)
+	if err != nil {
+		return nil, err
+	}
+
+	captureMeta(ctx, meta)
+	if err := json.Unmarshal(meta.Body, result); err != nil {
+		return meta, fmt.Errorf("failed to unmarshal response: %w, body: %s", err, truncateBody(meta.Body))
+	}
+	meta.setBaseResp(result)
+	return meta, nil
+}
+
+// send sends a request with the retry policy and returns the response. Statuses other than 429 and 5xx whose body is
+// a gateway response carrying a code are not errors, the code describes the failure. Other non-2xx responses, e.g. the
+// HTML error pages of a proxy, are returned as a *HTTPStatusError.
+func (c *Client) send(ctx context.Context, method, path string, params map[string]string, payload []byte) (*ResponseMeta, error) {
+	meta, err := c.doRetrying(ctx, method, path, params, payload)
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode != http.StatusTooManyRequests && statusErr.StatusCode < 500 && meta != nil {
+		if meta.parseBaseResp(); meta.HasBaseResp {
+			return meta, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// GetAccountNumberAndSequence gets the account number and sequence
+func (c *Client) GetAccountNumberAndSequence(address string) (string, string, error) {
+	return c.GetAccountNumberAndSequenceContext(context.Background(), address)
+}
+
+// GetAccountNumberAndSequenceContext is GetAccountNumberAndSequence with a context canceling the request
+func (c *Client) GetAccountNumberAndSequenceContext(ctx context.Context, address string) (string, string, error) {
+	var result types.GetAccountNumberAndSequenceResponse
+	params := map[string]string{
+		"address": address,
+	}
+	meta, err := c.getJSON(ctx, constants.GetAddressInfoPath, params, &result)
+	if err != nil {
+		return "", "", err
+	}
+
+	if result.BaseResp.Code != "0" {
+		return "", "", apiError(meta, result.BaseResp)
+	}
+
+	return result.Data.AccountNumber, result.Data.Sequence, nil
+}
+
+// SendRawTx sends a raw transaction
+func (c *Client) SendRawTx(req types.SendRawTxRequest) (*types.SendRawTxResponse, error) {
+	return c.SendRawTxContext(context.Background(), req)
+}
+
+// SendRawTxContext is SendRawTx with a context canceling the request
+func (c *Client) SendRawTxContext(ctx context.Context, req types.SendRawTxRequest) (*types.SendRawTxResponse, error) {
+	var result types.SendRawTxResponse
+	meta, err := c.postJSON(ctx, constants.SendTransactionPath, req, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "" && result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+
+	// Add debug information
+	if result.Data.TxHash != "" {
+		c.Logger().Debugf("SendRawTx response: txHash=%s", result.Data.TxHash)
+	}
+
+	return &result, nil
+}
+
+// =============================== Market Data and Trading Queries ===============================
+
+// GetCoinList gets the coin list
+func (c *Client) GetCoinList() ([]types.Coin, error) {
+	return c.GetCoinListContext(context.Background())
+}
+
+// GetCoinListContext is GetCoinList with a context canceling the request
+func (c *Client) GetCoinListContext(ctx context.Context) ([]types.Coin, error) {
+	var result types.GetCoinListResponse
+	meta, err := c.getJSON(ctx, constants.GetCoinListPath, map[string]string{}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+	return result.Data.CoinList, nil
+}
+
+// GetSubaccountList gets the subaccount list
+func (c *Client) GetSubaccountList(chainType int32, chainAddress, agentAddress string) ([]types.Subaccount, error) {
+	return c.GetSubaccountListContext(context.Background(), chainType, chainAddress, agentAddress)
+}
+
+// GetSubaccountListContext is GetSubaccountList with a context canceling the request
+func (c *Client) GetSubaccountListContext(ctx context.Context, chainType int32, chainAddress, agentAddress string) ([]types.Subaccount, error) {
+	var result types.GetSubaccountListResponse
+	params := map[string]string{
+		"chainType":    strconv.FormatInt(int64(chainType), 10),
+		"chainAddress": chainAddress,
+		"agentAddress": agentAddress,
+	}
+	meta, err := c.getJSON(ctx, constants.GetSubaccountPath, params, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+	return result.Data.SubaccountList, nil
+}
+
+// GetExchangeList gets the exchange list
+func (c *Client) GetExchangeList() ([]types.Exchange, error) {
+	return c.GetExchangeListContext(context.Background())
+}
+
+// GetExchangeListContext is GetExchangeList with a context canceling the request
+func (c *Client) GetExchangeListContext(ctx context.Context) ([]types.Exchange, error) {
+	var result types.GetExchangeListResponse
+	meta, err := c.getJSON(ctx, constants.GetExchangeListPath, map[string]string{}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+	return result.Data.ExchangeList, nil
+}
+
+// GetKline gets K-line data
+func (c *Client) GetKline(req types.GetKLineReq) (*types.GetKLineResp, error) {
+	return c.GetKlineContext(context.Background(), req)
+}
+
+// GetKlineContext is GetKline with a context canceling the request
+func (c *Client) GetKlineContext(ctx context.Context, req types.GetKLineReq) (*types.GetKLineResp, error) {
+	var result types.GetKLineResp
+	params := map[string]string{
+		"exchangeId": req.ExchangeId,
+		"klineType":  req.KlineType,
+		"priceType":  req.PriceType,
+	}
+	if req.Size > 0 {
+		params["size"] = strconv.FormatUint(uint64(req.Size), 10)
+	}
+	if req.OffsetData != "" {
+		params["offsetData"] = req.OffsetData
+	}
+	if req.FilterBeginKlineTimeInclusive > 0 {
+		params["filterBeginKlineTimeInclusive"] = strconv.FormatInt(req.FilterBeginKlineTimeInclusive, 10)
+	}
+	if req.FilterEndKlineTimeExclusive > 0 {
+		params["filterEndKlineTimeExclusive"] = strconv.FormatInt(req.FilterEndKlineTimeExclusive, 10)
+	}
+	meta, err := c.getJSON(ctx, constants.GetKlinePath, params, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+	return &result, nil
+}
+
+// GetTicker gets the 24h ticker of an exchange
+func (c *Client) GetTicker(req types.GetTickerReq) (*types.GetTickerResp, error) {
+	return c.GetTickerContext(context.Background(), req)
+}
+
+// GetTickerContext is GetTicker with a context canceling the request
+func (c *Client) GetTickerContext(ctx context.Context, req types.GetTickerReq) (*types.GetTickerResp, error) {
+	var result types.GetTickerResp
+	params := map[string]string{
+		"exchangeId": req.ExchangeId,
+	}
+	meta, err := c.getJSON(ctx, constants.GetTickerPath, params, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+	return &result, nil
+}
+
+// GetDepth gets the order book depth of an exchange
+func (c *Client) GetDepth(req types.GetDepthReq) (*types.GetDepthResp, error) {
+	return c.GetDepthContext(context.Background(), req)
+}
+
+// GetDepthContext is GetDepth with a context canceling the request
+func (c *Client) GetDepthContext(ctx context.Context, req types.GetDepthReq) (*types.GetDepthResp, error) {
+	var result types.GetDepthResp
+	params := map[string]string{
+		"exchangeId": req.ExchangeId,
+	}
+	if req.Level > 0 {
+		params["level"] = strconv.FormatUint(uint64(req.Level), 10)
+	}
+	meta, err := c.getJSON(ctx, constants.GetDepthPath, params, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+	return &result, nil
+}
+
+// GetFundingHistory gets funding rate history
+func (c *Client) GetFundingHistory(req types.GetFundingHistoryReq) (*types.GetFundingHistoryResp, error) {
+	return c.GetFundingHistoryContext(context.Background(), req)
+}
+
+// GetFundingHistoryContext is GetFundingHistory with a context canceling the request
+func (c *Client) GetFundingHistoryContext(ctx context.Context, req types.GetFundingHistoryReq) (*types.GetFundingHistoryResp, error) {
+	var result types.GetFundingHistoryResp
+	params := map[string]string{
+		"exchangeId": req.ExchangeId,
+		"size":       strconv.FormatUint(uint64(req.Size), 10),
+	}
+	if req.OffsetData != "" {
+		params["offsetData"] = req.OffsetData
+	}
+	if req.FilterSettlementFundingRate {
+		params["filterSettlementFundingRate"] = "true"
+	}
+	if req.FilterBeginTimeInclusive > 0 {
+		params["filterBeginTimeInclusive"] = strconv.FormatUint(req.FilterBeginTimeInclusive, 10)
+	}
+	if req.FilterEndTimeExclusive > 0 {
+		params["filterEndTimeExclusive"] = strconv.FormatUint(req.FilterEndTimeExclusive, 10)
+	}
+	meta, err := c.getJSON(ctx, constants.GetFundingHistoryPath, params, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+	return &result, nil
+}
+
+// GetActiveOrder gets active orders
+func (c *Client) GetActiveOrder(req types.GetActiveOrderReq) (*types.GetActiveOrderResp, error) {
+	return c.GetActiveOrderContext(context.Background(), req)
+}
+
+// GetActiveOrderContext is GetActiveOrder with a context canceling the request
+func (c *Client) GetActiveOrderContext(ctx context.Context, req types.GetActiveOrderReq) (*types.GetActiveOrderResp, error) {
+	var result types.GetActiveOrderResp
+	params := map[string]string{
+		"subaccountId": req.SubaccountId,
+		"size":         strconv.FormatUint(uint64(req.Size), 10),
+	}
+	if req.OffsetData != "" {
+		params["offsetData"] = req.OffsetData
+	}
+	if req.PageOffsetDataCreatedTime != "" {
+		params["pageOffsetDataCreatedTime"] = req.PageOffsetDataCreatedTime
+	}
+	if req.PageOffsetDataItemId != "" {
+		params["pageOffsetDataItemId"] = req.PageOffsetDataItemId
+	}
+	if req.FilterExchangeIdList != "" {
+		params["filterExchangeIdList"] = req.FilterExchangeIdList
+	}
+	if req.FilterOrderStatusList != "" {
+		params["filterOrderStatusList"] = req.FilterOrderStatusList
+	}
+	if req.FilterIsLiquidateList != "" {
+		params["filterIsLiquidateList"] = req.FilterIsLiquidateList
+	}
+	if req.FilterIsDeleverageList != "" {
+		params["filterIsDeleverageList"] = req.FilterIsDeleverageList
+	}
+	if req.FilterIsPositionTpslList != "" {
+		params["filterIsPositionTpslList"] = req.FilterIsPositionTpslList
+	}
+	if req.FilterOrderIdList != "" {
+		params["filterOrderIdList"] = req.FilterOrderIdList
+	}
+	if req.FilterStartCreatedTimeInclusive > 0 {
+		params["filterStartCreatedTimeInclusive"] = strconv.FormatUint(req.FilterStartCreatedTimeInclusive, 10)
+	}
+	if req.FilterEndCreatedTimeExclusive > 0 {
+		params["filterEndCreatedTimeExclusive"] = strconv.FormatUint(req.FilterEndCreatedTimeExclusive, 10)
+	}
+	// Add debug information
+	c.Logger().Debugf("GetActiveOrder request params: %+v", params)
+
+	meta, err := c.getJSON(ctx, constants.GetActiveOrderPath, params, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+	return &result, nil
+}
+
+// GetHistoryOrder gets history orders
+func (c *Client) GetHistoryOrder(req types.GetHistoryOrderReq) (*types.GetHistoryOrderResp, error) {
+	return c.GetHistoryOrderContext(context.Background(), req)
+}
+
+// GetHistoryOrderContext is GetHistoryOrder with a context canceling the request
+func (c *Client) GetHistoryOrderContext(ctx context.Context, req types.GetHistoryOrderReq) (*types.GetHistoryOrderResp, error) {
+	var result types.GetHistoryOrderResp
+	params := map[string]string{
+		"subaccountId": req.SubaccountId,
+		"size":         strconv.FormatUint(uint64(req.Size), 10),
+	}
+	if req.OffsetData != "" {
+		params["offsetData"] = req.OffsetData
+	}
+	if req.PageOffsetDataCreatedTime != "" {
+		params["pageOffsetDataCreatedTime"] = req.PageOffsetDataCreatedTime
+	}
+	if req.PageOffsetDataItemId != "" {
+		params["pageOffsetDataItemId"] = req.PageOffsetDataItemId
+	}
+	if req.FilterExchangeIdList != "" {
+		params["filterExchangeIdList"] = req.FilterExchangeIdList
+	}
+	if req.FilterOrderStatusList != "" {
+		params["filterOrderStatusList"] = req.FilterOrderStatusList
+	}
+	if req.FilterIsLiquidateList != "" {
+		params["filterIsLiquidateList"] = req.FilterIsLiquidateList
+	}
+	if req.FilterIsDeleverageList != "" {
+		params["filterIsDeleverageList"] = req.FilterIsDeleverageList
+	}
+	if req.FilterIsPositionTpslList != "" {
+		params["filterIsPositionTpslList"] = req.FilterIsPositionTpslList
+	}
+	if req.FilterOrderIdList != "" {
+		params["filterOrderIdList"] = req.FilterOrderIdList
+	}
+	if req.FilterStartCreatedTimeInclusive > 0 {
+		params["filterStartCreatedTimeInclusive"] = strconv.FormatUint(req.FilterStartCreatedTimeInclusive, 10)
+	}
+	if req.FilterEndCreatedTimeExclusive > 0 {
+		params["filterEndCreatedTimeExclusive"] = strconv.FormatUint(req.FilterEndCreatedTimeExclusive, 10)
+	}
+	meta, err := c.getJSON(ctx, constants.GetHistoryOrderPath, params, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+	return &result, nil
+}
+
+// GetPerpetualAccountAsset gets perpetual contract account assets
+func (c *Client) GetPerpetualAccountAsset(req types.GetPerpetualAccountAssetReq) (*types.GetPerpetualAccountAssetResp, error) {
+	return c.GetPerpetualAccountAssetContext(context.Background(), req)
+}
+
+// GetPerpetualAccountAssetContext is GetPerpetualAccountAsset with a context canceling the request
+func (c *Client) GetPerpetualAccountAssetContext(ctx context.Context, req types.GetPerpetualAccountAssetReq) (*types.GetPerpetualAccountAssetResp, error) {
+	var result types.GetPerpetualAccountAssetResp
+	params := map[string]string{"subaccountId": req.SubaccountId}
+	meta, err := c.getJSON(ctx, constants.GetPerpetualAccountAssetPath, params, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+	return &result, nil
+}
+
+// GetPositionTransaction gets position transactions
+func (c *Client) GetPositionTransaction(req types.GetPositionTransactionReq) (*types.GetPositionTransactionResp, error) {
+	return c.GetPositionTransactionContext(context.Background(), req)
+}
+
+// GetPositionTransactionContext is GetPositionTransaction with a context canceling the request
+func (c *Client) GetPositionTransactionContext(ctx context.Context, req types.GetPositionTransactionReq) (*types.GetPositionTransactionResp, error) {
+	var result types.GetPositionTransactionResp
+	params := map[string]string{
+		"subaccountId": req.SubaccountId,
+		"size":         strconv.FormatUint(uint64(req.Size), 10),
+	}
+	if req.PageOffsetDataCreatedTime != "" {
+		params["pageOffsetDataCreatedTime"] = req.PageOffsetDataCreatedTime
+	}
+	if req.PageOffsetDataItemId != "" {
+		params["pageOffsetDataItemId"] = req.PageOffsetDataItemId
+	}
+	if req.FilterExchangeIdList != "" {
+		params["filterExchangeIdList"] = req.FilterExchangeIdList
+	}
+	if req.FilterTypeList != "" {
+		params["filterTypeList"] = req.FilterTypeList
+	}
+	if req.FilterMarginModeList != "" {
+		params["filterMarginModeList"] = req.FilterMarginModeList
+	}
+	if req.FilterStartCreatedTimeInclusive > 0 {
+		params["filterStartCreatedTimeInclusive"] = strconv.FormatUint(req.FilterStartCreatedTimeInclusive, 10)
+	}
+	if req.FilterEndCreatedTimeExclusive > 0 {
+		params["filterEndCreatedTimeExclusive"] = strconv.FormatUint(req.FilterEndCreatedTimeExclusive, 10)
+	}
+	meta, err := c.getJSON(ctx, constants.GetPositionTransactionPath, params, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+	return &result, nil
+}
+
+// GetCollateralTransaction gets collateral transactions
+func (c *Client) GetCollateralTransaction(req types.GetCollateralTransactionReq) (*types.GetCollateralTransactionResp, error) {
+	return c.GetCollateralTransactionContext(context.Background(), req)
+}
+
+// GetCollateralTransactionContext is GetCollateralTransaction with a context canceling the request
+func (c *Client) GetCollateralTransactionContext(ctx context.Context, req types.GetCollateralTransactionReq) (*types.GetCollateralTransactionResp, error) {
+	var result types.GetCollateralTransactionResp
+	params := map[string]string{
+		"subaccountId": req.SubaccountId,
+		"size":         strconv.FormatUint(uint64(req.Size), 10),
+	}
+	if req.PageOffsetDataCreatedTime != "" {
+		params["pageOffsetDataCreatedTime"] = req.PageOffsetDataCreatedTime
+	}
+	if req.PageOffsetDataItemId != "" {
+		params["pageOffsetDataItemId"] = req.PageOffsetDataItemId
+	}
+	if req.FilterCoinId != "" {
+		params["filterCoinId"] = req.FilterCoinId
+	}
+	if req.FilterTypeList != "" {
+		params["filterTypeList"] = req.FilterTypeList
+	}
+	if req.FilterStartCreatedTimeInclusive > 0 {
+		params["filterStartCreatedTimeInclusive"] = strconv.FormatUint(req.FilterStartCreatedTimeInclusive, 10)
+	}
+	if req.FilterEndCreatedTimeExclusive > 0 {
+		params["filterEndCreatedTimeExclusive"] = strconv.FormatUint(req.FilterEndCreatedTimeExclusive, 10)
+	}
+	meta, err := c.getJSON(ctx, constants.GetCollateralTransactionPath, params, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+	return &result, nil
+}
+
+// GetAssetSnapshot gets asset snapshots
+func (c *Client) GetAssetSnapshot(req types.GetAssetSnapshotReq) (*types.GetAssetSnapshotResp, error) {
+	return c.GetAssetSnapshotContext(context.Background(), req)
+}
+
+// GetAssetSnapshotContext is GetAssetSnapshot with a context canceling the request
+func (c *Client) GetAssetSnapshotContext(ctx context.Context, req types.GetAssetSnapshotReq) (*types.GetAssetSnapshotResp, error) {
+	var result types.GetAssetSnapshotResp
+	params := map[string]string{
+		"subaccountId": req.SubaccountId,
+		"size":         strconv.FormatUint(uint64(req.Size), 10),
+	}
+	if req.PageOffsetDataCreatedTime != "" {
+		params["pageOffsetDataCreatedTime"] = req.PageOffsetDataCreatedTime
+	}
+	if req.PageOffsetDataItemId != "" {
+		params["pageOffsetDataItemId"] = req.PageOffsetDataItemId
+	}
+	if req.FilterCoinId != "" {
+		params["filterCoinId"] = req.FilterCoinId
+	}
+	if req.FilterTimeTag != "" {
+		params["filterTimeTag"] = req.FilterTimeTag
+	}
+	if req.FilterStartCreatedTimeInclusive > 0 {
+		params["filterStartCreatedTimeInclusive"] = strconv.FormatUint(req.FilterStartCreatedTimeInclusive, 10)
+	}
+	if req.FilterEndCreatedTimeExclusive > 0 {
+		params["filterEndCreatedTimeExclusive"] = strconv.FormatUint(req.FilterEndCreatedTimeExclusive, 10)
+	}
+	meta, err := c.getJSON(ctx, constants.GetAssetSnapshotPath, params, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+	return &result, nil
+}
+
+// GetHistoryOrderFillTransaction gets history order fill transactions
+func (c *Client) GetHistoryOrderFillTransaction(req types.GetHistoryOrderFillTransactionReq) (*types.GetHistoryOrderFillTransactionResp, error) {
+	return c.GetHistoryOrderFillTransactionContext(context.Background(), req)
+}
+
+// GetHistoryOrderFillTransactionContext is GetHistoryOrderFillTransaction with a context canceling the request
+func (c *Client) GetHistoryOrderFillTransactionContext(ctx context.Context, req types.GetHistoryOrderFillTransactionReq) (*types.GetHistoryOrderFillTransactionResp, error) {
+	var result types.GetHistoryOrderFillTransactionResp
+	params := map[string]string{
+		"subaccountId": req.SubaccountId,
+		"size":         strconv.FormatUint(uint64(req.Size), 10),
+	}
+	if req.PageOffsetDataCreatedTime != "" {
+		params["pageOffsetDataCreatedTime"] = req.PageOffsetDataCreatedTime
+	}
+	if req.PageOffsetDataItemId != "" {
+		params["pageOffsetDataItemId"] = req.PageOffsetDataItemId
+	}
+	if req.FilterExchangeIdList != "" {
+		params["filterExchangeIdList"] = req.FilterExchangeIdList
+	}
+	if req.FilterCoinIdList != "" {
+		params["filterCoinIdList"] = req.FilterCoinIdList
+	}
+	if req.FilterOrderIdList != "" {
+		params["filterOrderIdList"] = req.FilterOrderIdList
+	}
+	if req.FilterStartCreatedTimeInclusive > 0 {
+		params["filterStartCreatedTimeInclusive"] = strconv.FormatUint(req.FilterStartCreatedTimeInclusive, 10)
+	}
+	if req.FilterEndCreatedTimeExclusive > 0 {
+		params["filterEndCreatedTimeExclusive"] = strconv.FormatUint(req.FilterEndCreatedTimeExclusive, 10)
+	}
+	meta, err := c.getJSON(ctx, constants.GetHistoryOrderFillTransactionPath, params, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+	return &result, nil
+}
+
+// GetHistoryPositionTerm gets history position terms
+func (c *Client) GetHistoryPositionTerm(req types.GetHistoryPositionTermReq) (*types.GetHistoryPositionTermResp, error) {
+	return c.GetHistoryPositionTermContext(context.Background(), req)
+}
+
+// GetHistoryPositionTermContext is GetHistoryPositionTerm with a context canceling the request
+func (c *Client) GetHistoryPositionTermContext(ctx context.Context, req types.GetHistoryPositionTermReq) (*types.GetHistoryPositionTermResp, error) {
+	var result types.GetHistoryPositionTermResp
+	params := map[string]string{
+		"subaccountId": req.SubaccountId,
+		"size":         strconv.FormatUint(uint64(req.Size), 10),
+	}
+	if req.PageOffsetDataCreatedTime != "" {
+		params["pageOffsetDataCreatedTime"] = req.PageOffsetDataCreatedTime
+	}
+	if req.PageOffsetDataItemId != "" {
+		params["pageOffsetDataItemId"] = req.PageOffsetDataItemId
+	}
+	if req.FilterExchangeIdList != "" {
+		params["filterExchangeIdList"] = req.FilterExchangeIdList
+	}
+	if req.FilterStartCreatedTimeInclusive > 0 {
+		params["filterStartCreatedTimeInclusive"] = strconv.FormatUint(req.FilterStartCreatedTimeInclusive, 10)
+	}
+	if req.FilterEndCreatedTimeExclusive > 0 {
+		params["filterEndCreatedTimeExclusive"] = strconv.FormatUint(req.FilterEndCreatedTimeExclusive, 10)
+	}
+	meta, err := c.getJSON(ctx, constants.GetHistoryPositionTermPath, params, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.BaseResp.Code != "0" {
+		return nil, apiError(meta, result.BaseResp)
+	}
+	return &result, nil
+}
+
+// =============================== WebSocket Integration and Parsing ===============================
+
+// ConnectWebSocket establishes connection
+func (c *Client) ConnectWebSocket(messageHandler func([]byte), errorHandler func(error)) error {
+	return c.ConnectWebSocketContext(context.Background(), messageHandler, errorHandler)
+}
+
+// ConnectWebSocketContext is ConnectWebSocket with a context bounding the handshake
+func (c *Client) ConnectWebSocketContext(ctx context.Context, messageHandler func([]byte), errorHandler func(error)) error {
+	wsClient, err := c.NewWebSocket(messageHandler, errorHandler)
+	if err != nil {
+		return err
+	}
+	c.wsMu.Lock()
+	previous := c.wsClient
+	c.wsClient = wsClient
+	c.wsMu.Unlock()
+	if previous != nil {
+		_ = previous.Disconnect()
+	}
+	return wsClient.ConnectContext(ctx)
+}
+
+// NewWebSocket creates a WebSocket client of the gateway with the client credentials, User-Agent, headers, proxy, TLS
+// and compression settings, not connected, for a connection separate from the one of ConnectWebSocket
+func (c *Client) NewWebSocket(messageHandler func([]byte), errorHandler func(error)) (*WebSocketClient, error) {
+	_, wsURL := c.Gateway()
+	if wsURL == "" {
+		return nil, fmt.Errorf("wsURL is not set")
+	}
+	wsClient := NewWebSocketClient(wsURL, messageHandler, errorHandler)
+	wsClient.SetAPICredentials(c.apiCredentials())
+	wsClient.SetUserAgent(c.UserAgent())
+	wsClient.SetHeaders(c.Headers())
+	c.settingsMu.RLock()
+	wsClient.SetChaos(c.chaos)
+	wsClient.SetDropHandler(c.wsDropHandler)
+	wsClient.transport = c.transport
+	wsClient.noCompression = c.noCompression
+	c.settingsMu.RUnlock()
+	wsClient.connectHook = func(err error) { c.observeGateway("", wsURL, err != nil) }
+	wsClient.SetLogger(c.Logger())
+	return wsClient, nil
+}
+
+// SetWebSocketDropHandler sets the drop handler of the WebSockets created afterwards, see
+// WebSocketClient.SetDropHandler
+func (c *Client) SetWebSocketDropHandler(handler func(channel string)) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.wsDropHandler = handler
+}
+
+// SubscribeContext subscribes to a channel until ctx is done, see WebSocketClient.SubscribeContext
+func (c *Client) SubscribeContext(ctx context.Context, channel string) (<-chan []byte, error) {
+	wsClient := c.WebSocket()
+	if wsClient == nil {
+		return nil, ErrNotConnected
+	}
+	return wsClient.SubscribeContext(ctx, channel)
+}
+
+// SubscribeToTicker subscribes to Ticker
+func (c *Client) SubscribeToTicker(exchangeId string) (<-chan []byte, error) {
+	wsClient := c.WebSocket()
+	if wsClient == nil {
+		return nil, ErrNotConnected
+	}
+	return wsClient.SubscribeToTicker(exchangeId)
+}
+
+// SubscribeToKline subscribes to K-line
+func (c *Client) SubscribeToKline(priceType, exchangeId, klineType string) (<-chan []byte, error) {
+	wsClient := c.WebSocket()
+	if wsClient == nil {
+		return nil, ErrNotConnected
+	}
+	return wsClient.SubscribeToKline(priceType, exchangeId, klineType)
+}
+
+// SubscribeToDepth subscribes to depth
+func (c *Client) SubscribeToDepth(exchangeId, level string) (<-chan []byte, error) {
+	wsClient := c.WebSocket()
+	if wsClient == nil {
+		return nil, ErrNotConnected
+	}
+	return wsClient.SubscribeToDepth(exchangeId, level)
+}
+
+// SubscribeToTradeData subscribes to private account events of an ETH address
+func (c *Client) SubscribeToTradeData(ethAddress string) (<-chan []byte, error) {
+	wsClient := c.WebSocket()
+	if wsClient == nil {
+		return nil, ErrNotConnected
+	}
+	return wsClient.SubscribeToTradeData(ethAddress)
+}
+
+// SubscribePooled subscribes to a channel with zero-copy delivery of pooled messages, see WebSocketClient.SubscribePooled
+func (c *Client) SubscribePooled(channel string) (<-chan *WsMessage, error) {
+	wsClient := c.WebSocket()
+	if wsClient == nil {
+		return nil, ErrNotConnected
+	}
+	return wsClient.SubscribePooled(channel)
+}
+
+// WebSocket returns the connected WebSocket client, nil before ConnectWebSocket
+func (c *Client) WebSocket() *WebSocketClient {
+	c.wsMu.RLock()
+	defer c.wsMu.RUnlock()
+	return c.wsClient
+}
+
+// DisconnectWebSocket disconnects
+func (c *Client) DisconnectWebSocket() error {
+	if wsClient := c.WebSocket(); wsClient != nil {
+		return wsClient.Disconnect()
+	}
+	return nil
+}
+
+// ParseTickerData parses Ticker
+func (c *Client) ParseTickerData(data []byte) (*types.TickerData, error) {
+	if c.fastJSON {
+		return decodeTickerPush(data)
+	}
+	// WebSocket push format: {"channel":"...","event":"payload","data":[{...}]}
+	var wsResp struct {
+		Channel string             `json:"channel"`
+		Event   string             `json:"event"`
+		Data    []types.TickerData `json:"data"`
+	}
+	if err := json.Unmarshal(data, &wsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse websocket response: %w", err)
+	}
+	if len(wsResp.Data) == 0 {
+		return nil, fmt.Errorf("no ticker data in response")
+	}
+	return &wsResp.Data[0], nil
+}
+
+// ParseKlineData parses K-line
+func (c *Client) ParseKlineData(data []byte) (*types.KLine, error) {
+	if c.fastJSON {
+		return decodeKlinePush(data)
+	}
+	// WebSocket push format: {"channel":"...","event":"payload","data":[{...}]}
+	var wsResp struct {
+		Channel string        `json:"channel"`
+		Event   string        `json:"event"`
+		Data    []types.KLine `json:"data"`
+	}
+	if err := json.Unmarshal(data, &wsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse websocket response: %w", err)
+	}
+	if len(wsResp.Data) == 0 {
+		return nil, fmt.Errorf("no kline data in response")
+	}
+	return &wsResp.Data[0], nil
+}
+
+// ParseDepthData parses depth
+func (c *Client) ParseDepthData(data []byte) (*types.DepthData, error) {
+	if c.fastJSON {
+		return decodeDepthPush(data)
+	}
+	// WebSocket push format: {"channel":"...","event":"payload","data":[{...}]}
+	var wsResp struct {
+		Channel string            `json:"channel"`
+		Event   string            `json:"event"`
+		Data    []types.DepthData `json:"data"`
+	}
+	if err := json.Unmarshal(data, &wsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse websocket response: %w", err)
+	}
+	if len(wsResp.Data) == 0 {
+		return nil, fmt.Errorf("no depth data in response")
+	}
+	return &wsResp.Data[0], nil
+}
+
+// ParseTradeDataEvent parses private account event
+func (c *Client) ParseTradeDataEvent(data []byte) (*types.TradeDataEvent, error) {
+	// WebSocket push format: {"channel":"tradeData","user":"...","data":{...}}
+	var wsResp struct {
+		Channel string                `json:"channel"`
+		User    string                `json:"user"`
+		Data    *types.TradeDataEvent `json:"data"`
+	}
+	if err := json.Unmarshal(data, &wsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse websocket response: %w", err)
+	}
+	if wsResp.Data == nil {
+		return nil, fmt.Errorf("no trade data in response")
+	}
+	return wsResp.Data, nil
+}
+
+// ParseTradeData parses trade
+func (c *Client) ParseTradeData(data []byte) (*types.Ticket, error) {
+	if c.fastJSON {
+		return decodeTradePush(data)
+	}
+	// WebSocket push format: {"channel":"...","event":"payload","data":[{...}]}
+	var wsResp struct {
+		Channel string         `json:"channel"`
+		Event   string         `json:"event"`
+		Data    []types.Ticket `json:"data"`
+	}
+	if err := json.Unmarshal(data, &wsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse websocket response: %w", err)
+	}
+	if len(wsResp.Data) == 0 {
+		return nil, fmt.Errorf("no trade data in response")
+	}
+	return &wsResp.Data[0], nil
+}
+
+// ParseFundingRateData parses funding rate
+func (c *Client) ParseFundingRateData(data []byte) (*types.FundingRate, error) {
+	// WebSocket push format: {"channel":"...","event":"payload","data":[{...}]}
+	var wsResp struct {
+		Channel string              `json:"channel"`
+		Event   string              `json:"event"`
+		Data    []types.FundingRate `json:"data"`
+	}
+	if err := json.Unmarshal(data, &wsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse websocket response: %w", err)
+	}
+	if len(wsResp.Data) == 0 {
+		return nil, fmt.Errorf("no funding rate data in response")
+	}
+	return &wsResp.Data[0], nil
+}
+
+// ParsePriceData parses price
+func (c *Client) ParsePriceData(data []byte) (*types.Price, error) {
+	// WebSocket push format: {"channel":"...","event":"payload","data":[{...}]}
+	var wsResp struct {
+		Channel string        `json:"channel"`
+		Event   string        `json:"event"`
+		Data    []types.Price `json:"data"`
+	}
+	if err := json.Unmarshal(data, &wsResp); err != nil {
+		return nil, fmt.Errorf("failed to parse websocket response: %w", err)
+	}
+	if len(wsResp.Data) == 0 {
+		return nil, fmt.Errorf("no price data in response")
+	}
+	return &wsResp.Data[0], nil
+}
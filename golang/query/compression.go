@@ -0,0 +1,128 @@
+package query
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// acceptEncoding encodings of the gateway responses requested while response compression is enabled
+const acceptEncoding = "gzip, deflate"
+
+// SetRequestCompression gzips the JSON bodies of at least minSize bytes, e.g. large order batches, 0 disables request
+// compression. Only enable it for gateways accepting Content-Encoding: gzip. Response compression is set with
+// SetResponseCompression.
+func (c *Client) SetRequestCompression(minSize int) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.compressMinSize = minSize
+}
+
+// SetResponseCompression sets whether gzip or deflate gateway responses are requested and decompressed transparently,
+// and permessage-deflate negotiated by the WebSockets created afterwards. It is enabled by default, disable it when
+// the bandwidth saved on large history queries costs more CPU than it is worth, e.g. next to the gateway.
+func (c *Client) SetResponseCompression(enabled bool) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.noCompression = !enabled
+}
+
+// ResponseCompression reports whether compressed gateway responses and WebSocket messages are requested
+func (c *Client) ResponseCompression() bool {
+	c.settingsMu.RLock()
+	defer c.settingsMu.RUnlock()
+	return !c.noCompression
+}
+
+// SetCompression sets whether permessage-deflate is negotiated by the connections dialed afterwards, it is enabled by
+// default. On js/wasm the browser negotiates it.
+func (c *WebSocketClient) SetCompression(enabled bool) {
+	c.noCompression = !enabled
+}
+
+// newGatewayRequest creates a request with the gateway headers and payload as its JSON body, gzipped when request
+// compression applies. The API signature covers the body as sent.
+func (c *Client) newGatewayRequest(ctx context.Context, method, rawURL string, payload []byte) (*http.Request, error) {
+	c.settingsMu.RLock()
+	minSize := c.compressMinSize
+	compressResponse := !c.noCompression
+	c.settingsMu.RUnlock()
+	compressed := false
+	if minSize > 0 && len(payload) >= minSize {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(payload); err != nil {
+			return nil, fmt.Errorf("failed to compress request body: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress request body: %w", err)
+		}
+		payload, compressed = buf.Bytes(), true
+	}
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s request: %w", method, err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if compressed {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	// An explicit Accept-Encoding keeps the native transport from decompressing, readResponseBody does it for both
+	if compressResponse {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	} else {
+		req.Header.Set("Accept-Encoding", "identity")
+	}
+	c.setGatewayHeaders(req, payload)
+	return req, nil
+}
+
+// readResponseBody reads a response body, decompressing a gzip or deflate one. Bodies the transport already
+// decompressed, e.g. those of the browser on js/wasm which keeps the Content-Encoding header, are returned as is.
+func readResponseBody(resp *http.Response) ([]byte, error) {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip", "x-gzip":
+		// JSON never starts with the gzip magic number
+		if len(body) < 2 || body[0] != 0x1f || body[1] != 0x8b {
+			return body, nil
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return io.ReadAll(zr)
+	case "deflate":
+		// HTTP deflate is zlib wrapped, some servers send it raw. JSON never starts with the 0x78 byte of a zlib header.
+		if len(body) == 0 || body[0] == '{' || body[0] == '[' {
+			return body, nil
+		}
+		var zr io.ReadCloser
+		if body[0] == 0x78 {
+			if zr, err = zlib.NewReader(bytes.NewReader(body)); err != nil {
+				return nil, err
+			}
+		} else {
+			zr = flate.NewReader(bytes.NewReader(body))
+		}
+		defer zr.Close()
+		return io.ReadAll(zr)
+	}
+	return body, nil
+}
@@ -0,0 +1,23 @@
+package query
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestNoSigningDeps checks that the package and its imports stay free of the cosmos-sdk, go-ethereum and the
+// antx-proto messages, which belong to the signing side
+func TestNoSigningDeps(t *testing.T) {
+	out, err := exec.Command("go", "list", "-deps", ".").Output()
+	if err != nil {
+		t.Fatalf("go list: %v", err)
+	}
+	for _, dep := range strings.Fields(string(out)) {
+		for _, forbidden := range []string{"github.com/cosmos/", "github.com/ethereum/", "github.com/antxprotocol/antx-proto/"} {
+			if strings.HasPrefix(dep, forbidden) {
+				t.Errorf("query depends on %s", dep)
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// MaxErrorBodySize size in bytes of the response body kept in errors, longer bodies such as the HTML pages of proxies
+// are truncated
+const MaxErrorBodySize = 512
+
+// HTTPStatusError gateway response with a non-2xx HTTP status
+type HTTPStatusError struct {
+	Method     string        // Request method
+	Path       string        // Request path
+	StatusCode int           // HTTP status code
+	Body       string        // Response body, truncated to MaxErrorBodySize bytes
+	RetryAfter time.Duration // Delay of the Retry-After header, e.g. of a 429 or 503 response, 0 when absent
+}
+
+// Error returns the status and the response body
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("%s %s: HTTP %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+// Unwrap returns ErrThrottled for a 429 status
+func (e *HTTPStatusError) Unwrap() error {
+	if e.StatusCode == http.StatusTooManyRequests {
+		return ErrThrottled
+	}
+	return nil
+}
+
+// GatewayError gateway response with a code other than "0", see types.APIError
+type GatewayError = types.APIError
+
+// Do sends a request to any gateway path with the gateway headers and decodes the JSON response into out, so endpoints
+// without a typed method can be called. params are sent as the query string, body as JSON when it is not nil. A non-2xx
+// status is returned as a *HTTPStatusError and a response code other than "0" as a *types.APIError. Failed requests are
+// retried according to the RetryPolicy of the client.
+func (c *Client) Do(ctx context.Context, method, path string, params map[string]string, body interface{}, out interface{}) error {
+	_, err := c.DoWithMeta(ctx, method, path, params, body, out)
+	return err
+}
+
+// DoWithMeta sends a request like Do and also returns the raw response of the last attempt, nil when no response was
+// received
+func (c *Client) DoWithMeta(ctx context.Context, method, path string, params map[string]string, body interface{}, out interface{}) (*ResponseMeta, error) {
+	if baseURL, _ := c.Gateway(); baseURL == "" {
+		return nil, ErrGatewayUnset
+	}
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return nil, fmt.Errorf("failed to marshal request data: %w", err)
+		}
+	}
+	meta, err := c.doRetrying(ctx, method, path, params, payload)
+	if err != nil {
+		return meta, err
+	}
+	return meta, decodeGatewayResponse(meta, out)
+}
+
+// gatewayURL returns the URL of a path on the current gateway with params as the query string
+func (c *Client) gatewayURL(baseURL, path string, params map[string]string) (string, error) {
+	u, err := url.Parse(baseURL + c.ResolvePath(path))
+	if err != nil {
+		return "", fmt.Errorf("failed to parse URL: %w", err)
+	}
+	if len(params) > 0 {
+		q := u.Query()
+		for k, v := range params {
+			q.Set(k, v)
+		}
+		u.RawQuery = q.Encode()
+	}
+	return u.String(), nil
+}
+
+// do sends one request to the current gateway and returns the response, retryable reports whether the failure may be
+// transient
+func (c *Client) do(ctx context.Context, method, path string, params map[string]string, payload []byte) (*ResponseMeta, bool, error) {
+	if limiter := c.RateLimiter(); limiter != nil {
+		if err := limiter.Wait(ctx, EndpointGroupOf(path)); err != nil {
+			return nil, false, err
+		}
+	}
+	baseURL, _ := c.Gateway()
+	rawURL, err := c.gatewayURL(baseURL, path, params)
+	if err != nil {
+		return nil, false, err
+	}
+	req, err := c.newGatewayRequest(context.WithValue(ctx, requestPathKey{}, path), method, rawURL, payload)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resp, err := c.roundTrip(req)
+	if err != nil {
+		if ctx.Err() == nil {
+			c.observeGateway(baseURL, "", true)
+		}
+		return nil, ctx.Err() == nil, fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+	defer resp.Body.Close()
+	respBody, err := readResponseBody(resp)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to read response body: %w", err)
+	}
+	meta := c.observe(method, path, resp, respBody)
+	c.observeGateway(baseURL, "", resp.StatusCode >= 500)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return meta, retryable, &HTTPStatusError{
+			Method:     method,
+			Path:       path,
+			StatusCode: resp.StatusCode,
+			Body:       truncateBody(respBody),
+			RetryAfter: retryAfter(meta),
+		}
+	}
+	return meta, false, nil
+}
+
+// truncateBody returns a response body for an error message, truncated to MaxErrorBodySize bytes
+func truncateBody(body []byte) string {
+	if len(body) <= MaxErrorBodySize {
+		return string(body)
+	}
+	return strings.ToValidUTF8(string(body[:MaxErrorBodySize]), "") + "..."
+}
+
+// decodeGatewayResponse checks the response code of the gateway, when present, and decodes the body into out
+func decodeGatewayResponse(meta *ResponseMeta, out interface{}) error {
+	meta.parseBaseResp()
+	if meta.HasBaseResp && meta.BaseResp.Code != "0" {
+		return apiError(meta, meta.BaseResp)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(meta.Body, out); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w, body: %s", err, truncateBody(meta.Body))
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package query
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// Errors matched by errors.Is on the errors of the client
+var (
+	ErrNotConnected  = errors.New("websocket not connected")
+	ErrGatewayUnset  = errors.New("gateway baseURL is not set")
+	ErrThrottled     = errors.New("request throttled by the gateway")
+	ErrTxTimeout     = errors.New("transaction not included in time")
+	ErrInjectedFault = errors.New("fault injected by chaos")
+)
+
+// IsRateLimited reports whether a request was throttled by the gateway, with a 429 status
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrThrottled) || hasHTTPStatus(err, http.StatusTooManyRequests)
+}
+
+// RetryAfter returns the delay the gateway asked for in the Retry-After header of a failed request, 0 when it did not
+func RetryAfter(err error) time.Duration {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.RetryAfter
+	}
+	return 0
+}
+
+// IsNotFound reports whether a request failed with a 404 status because the resource it names does not exist
+func IsNotFound(err error) bool {
+	return hasHTTPStatus(err, http.StatusNotFound)
+}
+
+// IsSequenceMismatch reports whether a transaction was rejected for an account sequence other than the expected one,
+// with the ErrWrongSequence code of the sdk codespace, resending it with a fresh sequence may succeed
+func IsSequenceMismatch(err error) bool {
+	if errors.Is(err, ErrTxWrongSequence) {
+		return true
+	}
+	var apiErr *types.APIError
+	return errors.As(err, &apiErr) && apiErr.Code == strconv.FormatUint(uint64(TxCodeWrongSequence), 10)
+}
+
+// hasHTTPStatus reports whether a request failed with an HTTP status, with or without a gateway response code
+func hasHTTPStatus(err error, status int) bool {
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == status {
+		return true
+	}
+	var apiErr *types.APIError
+	return errors.As(err, &apiErr) && apiErr.HTTPStatus == status
+}
@@ -0,0 +1,60 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// txPollInterval interval at which WaitForTransaction polls the transaction result
+const txPollInterval = 500 * time.Millisecond
+
+// GetTransactionResult gets the result of a transaction by hash
+func (c *Client) GetTransactionResult(hash string) (*types.GetTransactionResultRespData, error) {
+	return c.GetTransactionResultContext(context.Background(), hash)
+}
+
+// GetTransactionResultContext is GetTransactionResult with a context canceling the request
+func (c *Client) GetTransactionResultContext(ctx context.Context, hash string) (*types.GetTransactionResultRespData, error) {
+	var result types.GetTransactionResultResponse
+	meta, err := c.getJSON(ctx, constants.GetTransactionPath+"/"+url.PathEscape(hash), nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.Code != "0" {
+		return nil, apiError(meta, types.BaseResp{Code: result.Code, Msg: result.Msg})
+	}
+	return &result.Data, nil
+}
+
+// WaitForTransaction polls the result of a transaction until it is included in a block or timeout elapses,
+// a transaction included with a failed status is returned together with a *TxError
+func (c *Client) WaitForTransaction(hash string, timeout time.Duration) (*types.GetTransactionResultRespData, error) {
+	return c.WaitForTransactionContext(context.Background(), hash, timeout)
+}
+
+// WaitForTransactionContext is WaitForTransaction with a context stopping the polling
+func (c *Client) WaitForTransactionContext(ctx context.Context, hash string, timeout time.Duration) (*types.GetTransactionResultRespData, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		result, err := c.GetTransactionResultContext(ctx, hash)
+		if err == nil && result.Block > 0 {
+			return result, TxResultError(result)
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return nil, fmt.Errorf("transaction %s not found within %s: %w: %w", hash, timeout, ErrTxTimeout, err)
+			}
+			return nil, fmt.Errorf("transaction %s not included within %s: %w", hash, timeout, ErrTxTimeout)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(txPollInterval):
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+)
+
+const (
+	// DefaultFailoverThreshold default number of consecutive failures of the active gateway before failing over
+	DefaultFailoverThreshold = 3
+	// DefaultFailoverProbeInterval default interval between probes of the primary gateway while on a fallback
+	DefaultFailoverProbeInterval = 30 * time.Second
+	// failoverProbeTimeout time limit of a probe of the primary gateway
+	failoverProbeTimeout = 5 * time.Second
+)
+
+// GatewayEndpoint HTTP and WebSocket addresses of a gateway
+type GatewayEndpoint struct {
+	BaseURL string // HTTP address, e.g. "https://gateway.example.com"
+	WsURL   string // WebSocket address, empty when the gateway serves no WebSocket
+}
+
+// FailoverConfig gateways a client fails over between
+type FailoverConfig struct {
+	Endpoints        []GatewayEndpoint              // Gateways in order of preference, the first is the primary
+	FailureThreshold int                            // Consecutive transport errors, 5xx statuses or WebSocket dial errors of the active gateway before failing over, defaults to DefaultFailoverThreshold
+	ProbeInterval    time.Duration                  // Interval between probes of the primary while on a fallback, defaults to DefaultFailoverProbeInterval
+	OnSwitch         func(from, to GatewayEndpoint) // Called after the client switched gateway, may be nil
+}
+
+// failover state of the gateway failover of a client
+type failover struct {
+	config FailoverConfig
+
+	mu        sync.Mutex
+	active    int
+	failures  int
+	lastProbe time.Time
+	probing   bool
+}
+
+// SetFailover makes the client fail over to the next gateway of the endpoints when the active one fails
+// FailureThreshold times in a row, and fail back to the primary once a probe of it succeeds. Probes are sent while
+// requests are, at most every ProbeInterval. WebSockets connect to the active gateway, connected ones stay on their
+// gateway until they reconnect. The client switches to the primary at once, a config without endpoints disables
+// failover and keeps the active gateway.
+func (c *Client) SetFailover(config FailoverConfig) error {
+	for i, endpoint := range config.Endpoints {
+		if endpoint.BaseURL == "" {
+			return fmt.Errorf("gateway endpoint %d has no base URL", i)
+		}
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = DefaultFailoverThreshold
+	}
+	if config.ProbeInterval <= 0 {
+		config.ProbeInterval = DefaultFailoverProbeInterval
+	}
+	var f *failover
+	if len(config.Endpoints) > 0 {
+		f = &failover{config: config}
+	}
+	c.settingsMu.Lock()
+	c.failover = f
+	c.settingsMu.Unlock()
+	if f != nil {
+		c.SetGateway(config.Endpoints[0].BaseURL, config.Endpoints[0].WsURL)
+	}
+	return nil
+}
+
+// ActiveEndpoint returns the gateway the client sends requests to and its index in the failover endpoints, -1 when
+// failover is not set
+func (c *Client) ActiveEndpoint() (GatewayEndpoint, int) {
+	c.settingsMu.RLock()
+	f := c.failover
+	c.settingsMu.RUnlock()
+	if f == nil {
+		baseURL, wsURL := c.Gateway()
+		return GatewayEndpoint{BaseURL: baseURL, WsURL: wsURL}, -1
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.config.Endpoints[f.active], f.active
+}
+
+// observeGateway records the outcome of a request to the gateway of baseURL or a WebSocket dial of wsURL, failing over
+// on repeated failures and probing the primary while on a fallback. Outcomes of a previous gateway are ignored.
+func (c *Client) observeGateway(baseURL, wsURL string, failed bool) {
+	c.settingsMu.RLock()
+	f := c.failover
+	c.settingsMu.RUnlock()
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	active := f.config.Endpoints[f.active]
+	if (baseURL == "" || baseURL != active.BaseURL) && (wsURL == "" || wsURL != active.WsURL) {
+		return
+	}
+	if !failed {
+		f.failures = 0
+	} else if f.failures++; f.failures >= f.config.FailureThreshold && len(f.config.Endpoints) > 1 {
+		c.switchGateway(f, (f.active+1)%len(f.config.Endpoints))
+	}
+	if f.active != 0 && !f.probing && time.Since(f.lastProbe) >= f.config.ProbeInterval {
+		f.probing, f.lastProbe = true, time.Now()
+		go c.probePrimary(f)
+	}
+}
+
+// switchGateway makes an endpoint the active gateway, must be called with the failover lock held
+func (c *Client) switchGateway(f *failover, index int) {
+	from, to := f.config.Endpoints[f.active], f.config.Endpoints[index]
+	f.active, f.failures = index, 0
+	c.SetGateway(to.BaseURL, to.WsURL)
+	c.Logger().Infof("gateway failover from %s to %s", from.BaseURL, to.BaseURL)
+	if f.config.OnSwitch != nil {
+		go f.config.OnSwitch(from, to)
+	}
+}
+
+// probePrimary sends a lightweight request to the primary gateway and fails back to it when it succeeds
+func (c *Client) probePrimary(f *failover) {
+	primary := f.config.Endpoints[0]
+	ok := false
+	defer func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.probing = false
+		// The failover config may have been replaced while probing
+		c.settingsMu.RLock()
+		current := c.failover == f
+		c.settingsMu.RUnlock()
+		if ok && current && f.active != 0 {
+			c.switchGateway(f, 0)
+		}
+	}()
+	rawURL, err := c.gatewayURL(primary.BaseURL, constants.GetCoinListPath, nil)
+	if err != nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), failoverProbeTimeout)
+	defer cancel()
+	req, err := c.newGatewayRequest(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return
+	}
+	resp, err := c.roundTrip(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+	ok = resp.StatusCode >= 200 && resp.StatusCode <= 299
+}
@@ -0,0 +1,563 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// Hand-written decoders for the market data pushes that dominate WebSocket traffic. They skip the reflection of
+// encoding/json and copy each string once. Field names are matched case-insensitively like encoding/json does, and
+// escaped strings fall back to encoding/json, so both decode a push the same way, see fastjson_test.go.
+// They are used by the Parse* functions when the SDK is built with the antxfastjson tag or after SetFastJSON(true).
+
+var nullLiteral = []byte("null")
+
+// SetFastJSON enables or disables the hand-written decoders for ticker, K-line, depth and trade pushes
+func (c *Client) SetFastJSON(enabled bool) {
+	c.fastJSON = enabled
+}
+
+// jsonLexer minimal JSON reader
+type jsonLexer struct {
+	data []byte
+	pos  int
+	key  []byte // Folded key of the object field being read
+}
+
+// errorf returns a syntax error at the current offset
+func (l *jsonLexer) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid json at offset %d: %s", l.pos, fmt.Sprintf(format, args...))
+}
+
+// skipSpace skips whitespace
+func (l *jsonLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		switch l.data[l.pos] {
+		case ' ', '\t', '\r', '\n':
+			l.pos++
+		default:
+			return
+		}
+	}
+}
+
+// consume consumes c if it is the next token
+func (l *jsonLexer) consume(c byte) bool {
+	l.skipSpace()
+	if l.pos < len(l.data) && l.data[l.pos] == c {
+		l.pos++
+		return true
+	}
+	return false
+}
+
+// peek reports whether c is the next token, without consuming it
+func (l *jsonLexer) peek(c byte) bool {
+	l.skipSpace()
+	return l.pos < len(l.data) && l.data[l.pos] == c
+}
+
+// expect consumes c, which must be the next token
+func (l *jsonLexer) expect(c byte) error {
+	if !l.consume(c) {
+		return l.errorf("expected %q", c)
+	}
+	return nil
+}
+
+// null consumes a null literal if it is the next token
+func (l *jsonLexer) null() bool {
+	l.skipSpace()
+	if bytes.HasPrefix(l.data[l.pos:], nullLiteral) {
+		l.pos += len(nullLiteral)
+		return true
+	}
+	return false
+}
+
+// rawString reads a string and returns its contents as they appear in the input, and whether they contain escapes
+func (l *jsonLexer) rawString() ([]byte, bool, error) {
+	if err := l.expect('"'); err != nil {
+		return nil, false, err
+	}
+	start := l.pos
+	escaped := false
+	for l.pos < len(l.data) {
+		switch l.data[l.pos] {
+		case '"':
+			raw := l.data[start:l.pos]
+			l.pos++
+			return raw, escaped, nil
+		case '\\':
+			escaped = true
+			l.pos += 2
+		default:
+			l.pos++
+		}
+	}
+	return nil, false, l.errorf("unterminated string")
+}
+
+// readString reads a string into dst, null leaves dst unchanged
+func (l *jsonLexer) readString(dst *string) error {
+	if l.null() {
+		return nil
+	}
+	raw, escaped, err := l.rawString()
+	if err != nil {
+		return err
+	}
+	if !escaped {
+		*dst = string(raw)
+		return nil
+	}
+	return json.Unmarshal(l.data[l.pos-len(raw)-2:l.pos], dst)
+}
+
+// readUint reads an unsigned integer of at most bits bits into dst, null leaves dst unchanged
+func (l *jsonLexer) readUint(dst *uint64, bits uint) error {
+	if l.null() {
+		return nil
+	}
+	max := uint64(1)<<bits - 1
+	start := l.pos
+	var v uint64
+	for ; l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '9'; l.pos++ {
+		d := uint64(l.data[l.pos] - '0')
+		if v > (max-d)/10 {
+			return l.errorf("number overflows uint%d", bits)
+		}
+		v = v*10 + d
+	}
+	if l.pos == start || (l.pos < len(l.data) && !isJSONDelimiter(l.data[l.pos])) {
+		return l.errorf("expected unsigned integer")
+	}
+	*dst = v
+	return nil
+}
+
+// readBool reads a boolean into dst, null leaves dst unchanged
+func (l *jsonLexer) readBool(dst *bool) error {
+	if l.null() {
+		return nil
+	}
+	switch {
+	case bytes.HasPrefix(l.data[l.pos:], []byte("true")):
+		*dst = true
+		l.pos += 4
+	case bytes.HasPrefix(l.data[l.pos:], []byte("false")):
+		*dst = false
+		l.pos += 5
+	default:
+		return l.errorf("expected boolean")
+	}
+	return nil
+}
+
+// foldKey folds an object key read by rawString into the key buffer, lowercasing it so it matches the lowercase field
+// names case-insensitively like encoding/json, with the same folding of the Unicode letters matching ASCII ones
+func (l *jsonLexer) foldKey(raw []byte, escaped bool) ([]byte, error) {
+	if escaped {
+		var key string
+		if err := json.Unmarshal(l.data[l.pos-len(raw)-2:l.pos], &key); err != nil {
+			return nil, err
+		}
+		raw = []byte(key)
+	}
+	l.key = l.key[:0]
+	for i := 0; i < len(raw); {
+		if c := raw[i]; c < utf8.RuneSelf {
+			if 'A' <= c && c <= 'Z' {
+				c += 'a' - 'A'
+			}
+			l.key = append(l.key, c)
+			i++
+			continue
+		}
+		r, size := utf8.DecodeRune(raw[i:])
+		l.key = utf8.AppendRune(l.key, unicode.ToLower(unicode.ToUpper(r)))
+		i += size
+	}
+	return l.key, nil
+}
+
+// readObject reads an object, calling field with each key, folded by foldKey, positioned before its value, null is an
+// empty object
+func (l *jsonLexer) readObject(field func(key []byte) error) error {
+	if l.null() {
+		return nil
+	}
+	if err := l.expect('{'); err != nil {
+		return err
+	}
+	if l.consume('}') {
+		return nil
+	}
+	for {
+		raw, escaped, err := l.rawString()
+		if err != nil {
+			return err
+		}
+		key, err := l.foldKey(raw, escaped)
+		if err != nil {
+			return err
+		}
+		if err := l.expect(':'); err != nil {
+			return err
+		}
+		if err := field(key); err != nil {
+			return err
+		}
+		if l.consume(',') {
+			continue
+		}
+		return l.expect('}')
+	}
+}
+
+// readArray reads an array, calling elem positioned before each element, null is an empty array
+func (l *jsonLexer) readArray(elem func() error) error {
+	if l.null() {
+		return nil
+	}
+	if err := l.expect('['); err != nil {
+		return err
+	}
+	if l.consume(']') {
+		return nil
+	}
+	for {
+		if err := elem(); err != nil {
+			return err
+		}
+		if l.consume(',') {
+			continue
+		}
+		return l.expect(']')
+	}
+}
+
+// skipValue skips the next value
+func (l *jsonLexer) skipValue() error {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return l.errorf("unexpected end of input")
+	}
+	switch l.data[l.pos] {
+	case '"':
+		_, _, err := l.rawString()
+		return err
+	case '{':
+		return l.readObject(func([]byte) error { return l.skipValue() })
+	case '[':
+		return l.readArray(l.skipValue)
+	}
+	start := l.pos
+	for l.pos < len(l.data) && !isJSONDelimiter(l.data[l.pos]) {
+		l.pos++
+	}
+	if l.pos == start {
+		return l.errorf("unexpected %q", l.data[l.pos])
+	}
+	return nil
+}
+
+// isJSONDelimiter reports whether c ends a number or literal
+func isJSONDelimiter(c byte) bool {
+	switch c {
+	case ',', '}', ']', ' ', '\t', '\r', '\n':
+		return true
+	}
+	return false
+}
+
+// pushHeader routing fields of a WebSocket message, read once per message to dispatch it to the subscriptions
+type pushHeader struct {
+	Channel  string // Channel
+	User     string // ETH address of the tradeData pushes
+	Snapshot bool   // isSnapshot of the first data element, set on the depth snapshots
+}
+
+// readPushHeader reads the routing fields of a message, skipping the rest, with the key matching of encoding/json
+func readPushHeader(data []byte) (pushHeader, error) {
+	var header pushHeader
+	l := &jsonLexer{data: data}
+	err := l.readObject(func(key []byte) error {
+		switch string(key) {
+		case "channel":
+			return l.readString(&header.Channel)
+		case "user":
+			return l.readString(&header.User)
+		case "data":
+			if !l.peek('[') {
+				// tradeData pushes carry an object
+				return l.skipValue()
+			}
+			first := true
+			return l.readArray(func() error {
+				if !first || !l.peek('{') {
+					return l.skipValue()
+				}
+				first = false
+				return l.readObject(func(key []byte) error {
+					if string(key) == "issnapshot" && (l.peek('t') || l.peek('f')) {
+						return l.readBool(&header.Snapshot)
+					}
+					return l.skipValue()
+				})
+			})
+		}
+		return l.skipValue()
+	})
+	if err == nil {
+		if l.skipSpace(); l.pos != len(l.data) {
+			err = l.errorf("unexpected data after top-level value")
+		}
+	}
+	return header, err
+}
+
+// decodeFirstPush decodes the first element of the data array of a push message with decode
+func decodeFirstPush(data []byte, name string, decode func(l *jsonLexer) error) error {
+	l := &jsonLexer{data: data}
+	found := false
+	err := l.readObject(func(key []byte) error {
+		if string(key) != "data" {
+			return l.skipValue()
+		}
+		return l.readArray(func() error {
+			if found {
+				return l.skipValue()
+			}
+			found = true
+			return decode(l)
+		})
+	})
+	if err == nil {
+		if l.skipSpace(); l.pos != len(l.data) {
+			err = l.errorf("unexpected data after top-level value")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse websocket response: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("no %s data in response", name)
+	}
+	return nil
+}
+
+// decodeTickerPush decodes the first ticker of a push message
+func decodeTickerPush(data []byte) (*types.TickerData, error) {
+	ticker := &types.TickerData{}
+	if err := decodeFirstPush(data, "ticker", func(l *jsonLexer) error { return decodeTicker(l, ticker) }); err != nil {
+		return nil, err
+	}
+	return ticker, nil
+}
+
+// decodeKlinePush decodes the first K-line of a push message
+func decodeKlinePush(data []byte) (*types.KLine, error) {
+	kline := &types.KLine{}
+	if err := decodeFirstPush(data, "kline", func(l *jsonLexer) error { return decodeKline(l, kline) }); err != nil {
+		return nil, err
+	}
+	return kline, nil
+}
+
+// decodeDepthPush decodes the first depth update of a push message
+func decodeDepthPush(data []byte) (*types.DepthData, error) {
+	depth := &types.DepthData{}
+	if err := decodeFirstPush(data, "depth", func(l *jsonLexer) error { return decodeDepth(l, depth) }); err != nil {
+		return nil, err
+	}
+	return depth, nil
+}
+
+// decodeTradePush decodes the first trade of a push message
+func decodeTradePush(data []byte) (*types.Ticket, error) {
+	trade := &types.Ticket{}
+	if err := decodeFirstPush(data, "trade", func(l *jsonLexer) error { return decodeTrade(l, trade) }); err != nil {
+		return nil, err
+	}
+	return trade, nil
+}
+
+// decodeTicker decodes a TickerData object
+func decodeTicker(l *jsonLexer, t *types.TickerData) error {
+	return l.readObject(func(key []byte) error {
+		switch string(key) {
+		case "exchangeid":
+			return l.readString(&t.ExchangeId)
+		case "lastprice":
+			return l.readString(&t.LastPrice)
+		case "markprice":
+			return l.readString(&t.MarkPrice)
+		case "indexprice":
+			return l.readString(&t.IndexPrice)
+		case "oracleprice":
+			return l.readString(&t.OraclePrice)
+		case "pricechange":
+			return l.readString(&t.PriceChange)
+		case "pricechangepercent":
+			return l.readString(&t.PriceChangePercent)
+		case "high":
+			return l.readString(&t.High)
+		case "low":
+			return l.readString(&t.Low)
+		case "open":
+			return l.readString(&t.Open)
+		case "close":
+			return l.readString(&t.Close)
+		case "size":
+			return l.readString(&t.Size)
+		case "value":
+			return l.readString(&t.Value)
+		case "openinterest":
+			return l.readString(&t.OpenInterest)
+		case "fundingrate":
+			return l.readString(&t.FundingRate)
+		case "fundingtime":
+			return l.readString(&t.FundingTime)
+		case "nextfundingtime":
+			return l.readString(&t.NextFundingTime)
+		case "starttime":
+			return l.readString(&t.StartTime)
+		case "endtime":
+			return l.readString(&t.EndTime)
+		case "hightime":
+			return l.readString(&t.HighTime)
+		case "lowtime":
+			return l.readString(&t.LowTime)
+		case "trades":
+			return l.readString(&t.Trades)
+		default:
+			return l.skipValue()
+		}
+	})
+}
+
+// decodeKline decodes a KLine object
+func decodeKline(l *jsonLexer, k *types.KLine) error {
+	return l.readObject(func(key []byte) error {
+		switch string(key) {
+		case "klineid":
+			return l.readString(&k.KlineId)
+		case "exchangeid":
+			return l.readString(&k.ExchangeId)
+		case "klinetype":
+			return l.readString(&k.KlineType)
+		case "pricetype":
+			return l.readString(&k.PriceType)
+		case "klinetime":
+			return l.readUint(&k.KlineTime, 64)
+		case "trades":
+			return l.readString(&k.Trades)
+		case "size":
+			return l.readString(&k.Size)
+		case "value":
+			return l.readString(&k.Value)
+		case "high":
+			return l.readString(&k.High)
+		case "low":
+			return l.readString(&k.Low)
+		case "open":
+			return l.readString(&k.Open)
+		case "close":
+			return l.readString(&k.Close)
+		case "makerbuysize":
+			return l.readString(&k.MakerBuySize)
+		case "makerbuyvalue":
+			return l.readString(&k.MakerBuyValue)
+		default:
+			return l.skipValue()
+		}
+	})
+}
+
+// decodeDepth decodes a DepthData object
+func decodeDepth(l *jsonLexer, d *types.DepthData) error {
+	return l.readObject(func(key []byte) error {
+		switch string(key) {
+		case "issnapshot":
+			return l.readBool(&d.IsSnapshot)
+		case "startversion":
+			return l.readString(&d.StartVersion)
+		case "endversion":
+			return l.readString(&d.EndVersion)
+		case "level":
+			var level uint64
+			if err := l.readUint(&level, 32); err != nil {
+				return err
+			}
+			d.Level = uint32(level)
+			return nil
+		case "exchangeid":
+			return l.readString(&d.ExchangeId)
+		case "bids":
+			return decodeBookOrders(l, &d.Bids)
+		case "asks":
+			return decodeBookOrders(l, &d.Asks)
+		case "updatedtime":
+			return l.readUint(&d.UpdatedTime, 64)
+		default:
+			return l.skipValue()
+		}
+	})
+}
+
+// decodeBookOrders decodes an array of BookOrder objects
+func decodeBookOrders(l *jsonLexer, orders *[]types.BookOrder) error {
+	if l.null() {
+		*orders = nil
+		return nil
+	}
+	// An empty array is an empty slice, not nil, as with encoding/json
+	list := []types.BookOrder{}
+	err := l.readArray(func() error {
+		list = append(list, types.BookOrder{})
+		o := &list[len(list)-1]
+		return l.readObject(func(key []byte) error {
+			switch string(key) {
+			case "price":
+				return l.readString(&o.Price)
+			case "size":
+				return l.readString(&o.Size)
+			default:
+				return l.skipValue()
+			}
+		})
+	})
+	if err != nil {
+		return err
+	}
+	*orders = list
+	return nil
+}
+
+// decodeTrade decodes a Ticket object
+func decodeTrade(l *jsonLexer, t *types.Ticket) error {
+	return l.readObject(func(key []byte) error {
+		switch string(key) {
+		case "exchangeid":
+			return l.readString(&t.ExchangeId)
+		case "price":
+			return l.readString(&t.Price)
+		case "size":
+			return l.readString(&t.Size)
+		case "value":
+			return l.readString(&t.Value)
+		case "isbuy":
+			return l.readBool(&t.IsBuy)
+		case "time":
+			return l.readString(&t.Time)
+		default:
+			return l.skipValue()
+		}
+	})
+}
@@ -0,0 +1,6 @@
+//go:build !antxfastjson
+
+package query
+
+// fastJSONDefault whether clients decode market data pushes with the hand-written decoders by default
+const fastJSONDefault = false
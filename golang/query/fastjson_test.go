@@ -0,0 +1,146 @@
+package query
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// Market data pushes recorded from the gateway, and variants of them exercising the differences between a hand-written
+// decoder and encoding/json: key case, escapes, nulls, unknown fields and whitespace
+var (
+	tickerPushes = []string{
+		`{"channel":"ticker.200001","event":"payload","data":[{"exchangeId":"200001","lastPrice":"97312.5","markPrice":"97310.12","indexPrice":"97305.88","oraclePrice":"97306.01","priceChange":"-412.5","priceChangePercent":"-0.004221","high":"98120","low":"96800.5","open":"97725","close":"97312.5","size":"1523.417","value":"148265004.31","openInterest":"812.33","fundingRate":"0.0000125","fundingTime":"1760601600000","nextFundingTime":"1760605200000","startTime":"1760515260000","endTime":"1760601660000","highTime":"1760540100000","lowTime":"1760588220000","trades":"48211"}]}`,
+		`{"channel":"ticker.200002","event":"payload","data":[{"exchangeId":"200002","lastPrice":"3521.07","markPrice":"3520.9","fundingRate":"-0.0000031","nextFundingTime":"1760605200000","trades":"9120"},{"exchangeId":"200003","lastPrice":"1"}]}`,
+		`{"ExchangeId":"x","data":[{"ExchangeID":"200001","LASTPRICE":"1.5","markprice":"1.4","NextFundingTime":"1760605200000"}]}`,
+		`{"data":[{"exchangeId":"200001","lastPrice":null,"markPrice":"1.5","indexPrice":"a\"b","extra":{"nested":[1,2,{"x":null}]},"flag":true}]}`,
+		` { "data" : [ { "exchangeId" : "200001" , "lastPrice" : "2" } ] , "channel" : "ticker.200001" } `,
+		`{"data":[{"exchangeId":"200001","lastPrice":"3"}]}`,
+		`{"data":[{"exchangeId":"200001","Key":"unknown","laſtPrice":"4"}]}`,
+	}
+	klinePushes = []string{
+		`{"channel":"kline.PRICE_TYPE_LAST.200001.MINUTE_1","event":"payload","data":[{"klineId":"1760601600000","exchangeId":"200001","klineType":"MINUTE_1","priceType":"PRICE_TYPE_LAST","klineTime":1760601600000,"trades":"57","size":"3.214","value":"312770.45","high":"97330","low":"97290.5","open":"97301","close":"97312.5","makerBuySize":"1.8","makerBuyValue":"175151.2"}]}`,
+		`{"data":[{"KlineTime":18446744073709551615,"KLINETYPE":"HOUR_1"}]}`,
+		`{"data":[{"klineTime":null,"klineId":"1"}]}`,
+	}
+	depthPushes = []string{
+		`{"channel":"depth.200001.15","event":"payload","data":[{"isSnapshot":true,"startVersion":"0","endVersion":"8812031","level":15,"exchangeId":"200001","bids":[{"price":"97310","size":"0.412"},{"price":"97309.5","size":"1.2"}],"asks":[{"price":"97311","size":"0.05"}],"updatedTime":1760601661234}]}`,
+		`{"channel":"depth.200001.15","event":"payload","data":[{"isSnapshot":false,"startVersion":"8812032","endVersion":"8812035","level":15,"exchangeId":"200001","bids":[],"asks":[{"price":"97311","size":"0"}],"updatedTime":1760601661301}]}`,
+		`{"data":[{"IsSnapshot":true,"Level":4294967295,"Bids":null,"Asks":[{"Price":"1","SIZE":"2","id":7}]}]}`,
+	}
+	tradePushes = []string{
+		`{"channel":"trade.200001","event":"payload","data":[{"exchangeId":"200001","price":"97312.5","size":"0.031","value":"3016.6875","isBuy":true,"time":"1760601661290"},{"exchangeId":"200001","price":"97312","size":"0.5","value":"48656","isBuy":false,"time":"1760601661290"}]}`,
+		`{"data":[{"IsBuy":false,"Price":"1","tradeId":"x"}]}`,
+	}
+	invalidPushes = []string{
+		`{"data":[]}`,
+		`{"data":null}`,
+		`{"channel":"ticker.200001"}`,
+		`{"data":[{"exchangeId":200001}]}`,
+		`{"data":[{"klineTime":-1}]}`,
+		`{"data":[{"klineTime":1e3}]}`,
+		`{"data":[{"level":4294967296}]}`,
+		`{"data":[{"isBuy":"true"}]}`,
+		`{"data":[{"exchangeId":"1"}]`,
+		`{"data":[{"exchangeId":"1"}]} x`,
+		`{"data":[{"exchangeId":"1}]}`,
+		``,
+	}
+)
+
+// testClients returns a client decoding with encoding/json and one decoding with the hand-written decoders
+func testClients() (std, fast *Client) {
+	std, fast = NewClient("", ""), NewClient("", "")
+	std.SetFastJSON(false)
+	fast.SetFastJSON(true)
+	return std, fast
+}
+
+// testEquivalence checks that both clients decode each push to the same value, or both fail
+func testEquivalence[T any](t *testing.T, pushes []string, parse func(c *Client, data []byte) (*T, error)) {
+	t.Helper()
+	std, fast := testClients()
+	for _, push := range pushes {
+		want, wantErr := parse(std, []byte(push))
+		got, err := parse(fast, []byte(push))
+		if (err != nil) != (wantErr != nil) {
+			t.Errorf("%s: fast error %v, encoding/json error %v", push, err, wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: fast decoded %+v, encoding/json decoded %+v", push, got, want)
+		}
+	}
+}
+
+func TestFastJSONTicker(t *testing.T) {
+	testEquivalence(t, append(tickerPushes, invalidPushes...), (*Client).ParseTickerData)
+}
+
+func TestFastJSONKline(t *testing.T) {
+	testEquivalence(t, append(klinePushes, invalidPushes...), (*Client).ParseKlineData)
+}
+
+func TestFastJSONDepth(t *testing.T) {
+	testEquivalence(t, append(depthPushes, invalidPushes...), (*Client).ParseDepthData)
+}
+
+func TestFastJSONTrade(t *testing.T) {
+	testEquivalence(t, append(tradePushes, invalidPushes...), (*Client).ParseTradeData)
+}
+
+func TestReadPushHeader(t *testing.T) {
+	pushes := append(append(append(append([]string{}, tickerPushes...), klinePushes...), depthPushes...), tradePushes...)
+	pushes = append(pushes,
+		`{"channel":"tradeData","user":"0xAbC","data":{"orderList":[{"id":"1"}]}}`,
+		`{"CHANNEL":"depth.1.15","data":[{"isSnapshot":"yes"}]}`,
+		`{"channel":"a\u002eb","User":null}`,
+	)
+	for _, push := range pushes {
+		var want struct {
+			WsRespBase
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal([]byte(push), &want); err != nil {
+			t.Fatalf("%s: %v", push, err)
+		}
+		var depth struct {
+			Data []struct {
+				IsSnapshot bool `json:"isSnapshot"`
+			} `json:"data"`
+		}
+		snapshot := json.Unmarshal(want.Data, &depth.Data) == nil && len(depth.Data) > 0 && depth.Data[0].IsSnapshot
+		got, err := readPushHeader([]byte(push))
+		if err != nil {
+			t.Errorf("%s: %v", push, err)
+			continue
+		}
+		if got.Channel != want.Channel || got.User != want.User || got.Snapshot != snapshot {
+			t.Errorf("%s: read %+v, encoding/json decoded %+v snapshot %v", push, got, want.WsRespBase, snapshot)
+		}
+	}
+}
+
+// benchmarkParse decodes a push with the decoders of a client
+func benchmarkParse[T any](b *testing.B, fastJSON bool, push string, parse func(c *Client, data []byte) (*T, error)) {
+	c := NewClient("", "")
+	c.SetFastJSON(fastJSON)
+	data := []byte(push)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := parse(c, data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseTickerData(b *testing.B) {
+	b.Run("encoding/json", func(b *testing.B) { benchmarkParse(b, false, tickerPushes[0], (*Client).ParseTickerData) })
+	b.Run("fast", func(b *testing.B) { benchmarkParse(b, true, tickerPushes[0], (*Client).ParseTickerData) })
+}
+
+func BenchmarkParseDepthData(b *testing.B) {
+	b.Run("encoding/json", func(b *testing.B) { benchmarkParse(b, false, depthPushes[0], (*Client).ParseDepthData) })
+	b.Run("fast", func(b *testing.B) { benchmarkParse(b, true, depthPushes[0], (*Client).ParseDepthData) })
+}
@@ -0,0 +1,53 @@
+package query
+
+import "net/http"
+
+// SetHeader sets a header sent with every gateway request and WebSocket handshake, e.g. the token of a proxy or the
+// tracing headers of the application, or SetHeader(HeaderAppToken, token) behind a WAF expecting an app token. It
+// overrides the default headers, an empty value removes the header. The API key headers cannot be overridden.
+func (c *Client) SetHeader(key, value string) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	if c.headers == nil {
+		c.headers = make(map[string]string)
+	}
+	c.headers[http.CanonicalHeaderKey(key)] = value
+}
+
+// SetHeaders replaces the custom headers of the client, see SetHeader, nil removes them all
+func (c *Client) SetHeaders(headers map[string]string) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.headers = copyHeaders(headers)
+}
+
+// Headers returns a copy of the custom headers of the client
+func (c *Client) Headers() map[string]string {
+	c.settingsMu.RLock()
+	defer c.settingsMu.RUnlock()
+	return copyHeaders(c.headers)
+}
+
+// RemoveHeader removes a custom header, restoring its default
+func (c *Client) RemoveHeader(key string) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	delete(c.headers, http.CanonicalHeaderKey(key))
+}
+
+// SetHeaders sets the custom headers sent with the handshake, see Client.SetHeader
+func (c *WebSocketClient) SetHeaders(headers map[string]string) {
+	c.headers = copyHeaders(headers)
+}
+
+// copyHeaders copies headers with canonical keys, nil when empty
+func copyHeaders(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	copied := make(map[string]string, len(headers))
+	for key, value := range headers {
+		copied[http.CanonicalHeaderKey(key)] = value
+	}
+	return copied
+}
@@ -0,0 +1,74 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// DefaultBulkConcurrency default number of exchanges GetKlinesForExchanges fetches at the same time
+const DefaultBulkConcurrency = 4
+
+// GetKlinesForExchanges gets the last price K-lines of several exchanges between begin (inclusive) and end (exclusive),
+// unit: milliseconds, 0 for an open bound. At most concurrency exchanges are fetched at the same time, defaults to
+// DefaultBulkConcurrency, and a throttled page is retried with the retry policy backoff. The K-lines of the exchanges
+// that succeeded are returned with an error joining the failures of the others.
+func (c *Client) GetKlinesForExchanges(exchangeIds []string, klineType string, begin, end int64, concurrency int) (map[string][]types.KLine, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultBulkConcurrency
+	}
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[string][]types.KLine, len(exchangeIds))
+		errs    []error
+	)
+	slots := make(chan struct{}, concurrency)
+	for _, exchangeId := range exchangeIds {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(exchangeId string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			klines, err := c.getKlineRange(types.GetKLineReq{
+				ExchangeId:                    exchangeId,
+				KlineType:                     klineType,
+				PriceType:                     constants.PriceTypeLast,
+				Size:                          100,
+				FilterBeginKlineTimeInclusive: begin,
+				FilterEndKlineTimeExclusive:   end,
+			})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("exchange %s: %w", exchangeId, err))
+				return
+			}
+			results[exchangeId] = klines
+		}(exchangeId)
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return results, fmt.Errorf("failed to get klines of %d exchanges: %w", len(errs), errors.Join(errs...))
+	}
+	return results, nil
+}
+
+// getKlineRange gets all pages of a K-line request, throttled pages are retried by the retry policy
+func (c *Client) getKlineRange(req types.GetKLineReq) ([]types.KLine, error) {
+	var klines []types.KLine
+	for {
+		resp, err := c.GetKline(req)
+		if err != nil {
+			return nil, err
+		}
+		klines = append(klines, resp.Data.KlineList...)
+		if resp.Data.NextPageOffsetData == "" {
+			return klines, nil
+		}
+		req.OffsetData = resp.Data.NextPageOffsetData
+	}
+}
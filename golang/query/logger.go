@@ -0,0 +1,133 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+)
+
+// LogLevel severity of a log message
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota // Request details and connection progress
+	LogInfo                  // Notable events, e.g. an agent rotation or a retried page
+	LogError                 // Failures not returned to a caller, e.g. those of background components
+	LogOff                   // No message
+)
+
+// Logger receives the log messages of the SDK, set it with SetLogger to route them to the logging of the application
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// nopLogger discards all messages
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...interface{}) {}
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
+
+// NopLogger returns a logger discarding all messages, disabling the logging of the SDK
+func NopLogger() Logger {
+	return nopLogger{}
+}
+
+// stdLogger writes the messages of a level to a standard library logger
+type stdLogger struct {
+	logger *log.Logger
+	level  LogLevel
+}
+
+// NewStdLogger returns a logger writing the messages from level up to a standard library logger, log.Default() when nil
+func NewStdLogger(logger *log.Logger, level LogLevel) Logger {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &stdLogger{logger: logger, level: level}
+}
+
+func (l *stdLogger) Debugf(format string, args ...interface{}) { l.printf(LogDebug, format, args...) }
+func (l *stdLogger) Infof(format string, args ...interface{})  { l.printf(LogInfo, format, args...) }
+func (l *stdLogger) Errorf(format string, args ...interface{}) { l.printf(LogError, format, args...) }
+
+// printf writes a message of level when it is enabled
+func (l *stdLogger) printf(level LogLevel, format string, args ...interface{}) {
+	if level >= l.level {
+		l.logger.Printf(format, args...)
+	}
+}
+
+// slogLogger writes the messages to a slog handler
+type slogLogger struct {
+	handler slog.Handler
+}
+
+// NewSlogLogger returns a logger writing to a slog handler, e.g. slog.Default().Handler(), whose level filters the
+// messages
+func NewSlogLogger(handler slog.Handler) Logger {
+	return &slogLogger{handler: handler}
+}
+
+func (l *slogLogger) Debugf(format string, args ...interface{}) {
+	l.log(slog.LevelDebug, format, args...)
+}
+
+func (l *slogLogger) Infof(format string, args ...interface{}) {
+	l.log(slog.LevelInfo, format, args...)
+}
+
+func (l *slogLogger) Errorf(format string, args ...interface{}) {
+	l.log(slog.LevelError, format, args...)
+}
+
+// log writes a message of level when the handler enables it
+func (l *slogLogger) log(level slog.Level, format string, args ...interface{}) {
+	ctx := context.Background()
+	if l.handler.Enabled(ctx, level) {
+		slog.New(l.handler).Log(ctx, level, fmt.Sprintf(format, args...))
+	}
+}
+
+// defaultLogger logger of new clients, info and errors to the standard library logger
+var defaultLogger = NewStdLogger(nil, LogInfo)
+
+// SetLogger sets the logger of the client and of the WebSockets it creates afterwards, nil restores the default
+// standard library logger at LogInfo
+func (c *Client) SetLogger(logger Logger) {
+	if logger == nil {
+		logger = defaultLogger
+	}
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.logger = logger
+}
+
+// Logger returns the logger of the client
+func (c *Client) Logger() Logger {
+	c.settingsMu.RLock()
+	defer c.settingsMu.RUnlock()
+	if c.logger == nil {
+		return defaultLogger
+	}
+	return c.logger
+}
+
+// SetLogger sets the logger of the WebSocket client, nil restores the default
+func (c *WebSocketClient) SetLogger(logger Logger) {
+	if logger == nil {
+		logger = defaultLogger
+	}
+	c.logger = logger
+}
+
+// log returns the logger of the WebSocket client
+func (c *WebSocketClient) log() Logger {
+	if c.logger == nil {
+		return defaultLogger
+	}
+	return c.logger
+}
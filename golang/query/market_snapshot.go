@@ -0,0 +1,69 @@
+package query
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// MarketSnapshot gets the ticker, latest funding rate and open interest of every listed exchange, fetching
+// DefaultBulkConcurrency exchanges at the same time. An exchange whose requests fail keeps an entry with its Error set,
+// so one market does not fail the snapshot.
+func (c *Client) MarketSnapshot() (*types.MarketSnapshot, error) {
+	exchanges, err := c.GetExchangeList()
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &types.MarketSnapshot{
+		CapturedAt: time.Now(),
+		Markets:    make(map[string]types.MarketSnapshotEntry, len(exchanges)),
+	}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	slots := make(chan struct{}, DefaultBulkConcurrency)
+	for _, exchange := range exchanges {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(exchange types.Exchange) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			entry, err := c.marketSnapshotEntry(exchange)
+			if err != nil {
+				entry = types.MarketSnapshotEntry{Exchange: exchange, Error: err.Error()}
+			}
+			mu.Lock()
+			snapshot.Markets[exchange.Id] = entry
+			mu.Unlock()
+		}(exchange)
+	}
+	wg.Wait()
+	snapshot.Elapsed = time.Since(snapshot.CapturedAt)
+	return snapshot, nil
+}
+
+// marketSnapshotEntry gets the market data of one exchange
+func (c *Client) marketSnapshotEntry(exchange types.Exchange) (types.MarketSnapshotEntry, error) {
+	entry := types.MarketSnapshotEntry{Exchange: exchange}
+	ticker, err := c.GetTicker(types.GetTickerReq{ExchangeId: exchange.Id})
+	if err != nil {
+		return entry, err
+	}
+	if len(ticker.Data.TickerList) == 0 {
+		return entry, fmt.Errorf("no ticker for exchange %s", exchange.Id)
+	}
+	entry.Ticker = ticker.Data.TickerList[0]
+	entry.OpenInterest = entry.Ticker.OpenInterest
+
+	funding, err := c.GetFundingHistory(types.GetFundingHistoryReq{ExchangeId: exchange.Id, Size: 1})
+	if err != nil {
+		return entry, err
+	}
+	if len(funding.Data.FundingRateList) > 0 {
+		entry.Funding = &funding.Data.FundingRateList[0]
+	}
+	return entry, nil
+}
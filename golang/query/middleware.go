@@ -0,0 +1,44 @@
+package query
+
+import "net/http"
+
+// RoundTripFunc sends a gateway request and returns its response
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of the gateway requests, e.g. to record metrics, inject headers or log requests. It
+// runs once per attempt, retries included, after the gateway headers and the API signature are set, so a middleware
+// changing the body of a signed request must sign it again.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Use adds middleware around the gateway requests sent afterwards, the first added is the outermost
+func (c *Client) Use(middleware ...Middleware) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.middleware = append(c.middleware[:len(c.middleware):len(c.middleware)], middleware...)
+}
+
+// requestPathKey context key of the gateway path of a request
+type requestPathKey struct{}
+
+// RequestPath returns the gateway path of a request passed to a middleware, before the API prefix and the path
+// overrides are applied, e.g. constants.GetKlinePath, so metrics can be labeled by endpoint
+func RequestPath(req *http.Request) string {
+	path, _ := req.Context().Value(requestPathKey{}).(string)
+	return path
+}
+
+// roundTrip sends a request through the middleware and the HTTP client
+func (c *Client) roundTrip(req *http.Request) (*http.Response, error) {
+	c.settingsMu.RLock()
+	httpClient := c.httpClient
+	middleware := c.middleware
+	c.settingsMu.RUnlock()
+	if httpClient == nil {
+		httpClient = defaultHTTPClient
+	}
+	next := RoundTripFunc(httpClient.Do)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		next = middleware[i](next)
+	}
+	return next(req)
+}
@@ -0,0 +1,198 @@
+package query
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// MockGatewayURL gateway address of the clients routed to a MockTransport without a gateway address of their own
+const MockGatewayURL = "http://mock.gateway"
+
+// MockTransport in-memory fake gateway for tests and dry runs, an http.RoundTripper answering the requests without
+// the network. It accepts the transactions sent, answering with their hash, and serves the account sequence counting
+// them. Other paths answer the responses set with SetResponse or Handle, and a 404 gateway error otherwise. It is safe
+// for concurrent use.
+type MockTransport struct {
+	mu           sync.Mutex
+	handlers     map[string]http.HandlerFunc
+	transactions []types.SendRawTxRequest
+}
+
+// NewMockTransport creates a fake gateway accepting all transactions
+func NewMockTransport() *MockTransport {
+	m := &MockTransport{handlers: make(map[string]http.HandlerFunc)}
+	m.handlers[constants.GetAddressInfoPath] = m.serveAddressInfo
+	m.handlers[constants.SendTransactionPath] = m.serveSendTransaction
+	m.handlers[constants.SendSyncTransactionPath] = m.serveSendTransaction
+	return m
+}
+
+// Handle sets the handler of a gateway path, given before the API prefix and the path overrides are applied, e.g.
+// constants.GetExchangeListPath. It replaces the built-in handler of the path.
+func (m *MockTransport) Handle(path string, handler http.HandlerFunc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers[path] = handler
+}
+
+// SetResponse makes a gateway path answer response encoded as JSON, e.g. a types.GetExchangeListResponse
+func (m *MockTransport) SetResponse(path string, response interface{}) error {
+	body, err := json.Marshal(response)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mock response: %w", err)
+	}
+	m.Handle(path, func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	})
+	return nil
+}
+
+// Transactions returns the transactions sent to the fake gateway, in order
+func (m *MockTransport) Transactions() []types.SendRawTxRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]types.SendRawTxRequest(nil), m.transactions...)
+}
+
+// RoundTrip answers a request with the handler of its path
+func (m *MockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := RequestPath(req)
+	if path == "" {
+		path = req.URL.Path
+	}
+	if req.Body != nil && req.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress mock request body: %w", err)
+		}
+		req = req.Clone(req.Context())
+		req.Body = zr
+	}
+	m.mu.Lock()
+	handler, ok := m.handlers[path]
+	m.mu.Unlock()
+	if !ok {
+		handler = func(w http.ResponseWriter, _ *http.Request) {
+			writeMockJSON(w, http.StatusNotFound, types.BaseResp{Code: "404", Msg: "no mock response for " + path})
+		}
+	}
+	w := &mockResponseWriter{header: make(http.Header)}
+	handler(w, req)
+	status := w.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        w.header,
+		Body:          io.NopCloser(bytes.NewReader(w.body.Bytes())),
+		ContentLength: int64(w.body.Len()),
+		Request:       req,
+	}, nil
+}
+
+// mockResponseWriter http.ResponseWriter buffering the response of a MockTransport handler
+type mockResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// Header returns the response headers
+func (w *mockResponseWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader sets the status code, only the first call counts
+func (w *mockResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+// Write buffers response body bytes
+func (w *mockResponseWriter) Write(p []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	return w.body.Write(p)
+}
+
+// serveAddressInfo serves the account number and the sequence, which counts the transactions sent
+func (m *MockTransport) serveAddressInfo(w http.ResponseWriter, _ *http.Request) {
+	m.mu.Lock()
+	sequence := len(m.transactions)
+	m.mu.Unlock()
+	writeMockJSON(w, http.StatusOK, types.GetAccountNumberAndSequenceResponse{
+		BaseResp: types.BaseResp{Code: "0", Msg: "success"},
+		Data: types.GetAccountNumberAndSequenceResponseData{
+			Exist:         true,
+			AccountNumber: "1",
+			Sequence:      strconv.Itoa(sequence),
+		},
+	})
+}
+
+// serveSendTransaction records a transaction and answers its hash
+func (m *MockTransport) serveSendTransaction(w http.ResponseWriter, r *http.Request) {
+	var req types.SendRawTxRequest
+	body, err := io.ReadAll(r.Body)
+	if err == nil {
+		err = json.NewDecoder(bytes.NewReader(body)).Decode(&req)
+	}
+	var txBytes []byte
+	if err == nil {
+		txBytes, err = base64.StdEncoding.DecodeString(req.RawTx)
+	}
+	if err != nil {
+		writeMockJSON(w, http.StatusOK, types.BaseResp{Code: "400", Msg: err.Error()})
+		return
+	}
+	m.mu.Lock()
+	m.transactions = append(m.transactions, req)
+	m.mu.Unlock()
+	hash := sha256.Sum256(txBytes)
+	writeMockJSON(w, http.StatusOK, types.SendRawTxResponse{
+		BaseResp: types.BaseResp{Code: "0", Msg: "success"},
+		Data:     types.SendRawTxResponseData{TxHash: strings.ToUpper(hex.EncodeToString(hash[:])), RawTx: req.RawTx},
+	})
+}
+
+// writeMockJSON writes a JSON response with a status
+func writeMockJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// SetMockTransport routes the gateway requests of the client to a fake gateway instead of the network, using
+// MockGatewayURL as the gateway address when none is set. Setting the HTTP client or the transport afterwards routes
+// them to the network again.
+func (c *Client) SetMockTransport(mock *MockTransport) {
+	if baseURL, wsURL := c.Gateway(); baseURL == "" {
+		c.SetGateway(MockGatewayURL, wsURL)
+	}
+	c.settingsMu.RLock()
+	timeout := DefaultHTTPTimeout
+	if c.httpClient != nil {
+		timeout = c.httpClient.Timeout
+	}
+	c.settingsMu.RUnlock()
+	c.SetHTTPClient(&http.Client{Transport: mock, Timeout: timeout})
+}
@@ -0,0 +1,55 @@
+package query
+
+import (
+	"strings"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+)
+
+// SetAPIPrefix replaces the constants.BaseAPIPath prefix of the gateway paths, for gateways mounting the API under
+// another prefix or version, e.g. "/gateway/api/v2". An empty prefix restores constants.BaseAPIPath.
+func (c *Client) SetAPIPrefix(prefix string) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.apiPrefix = strings.TrimSuffix(prefix, "/")
+}
+
+// SetPathOverride sends the requests of a gateway path, e.g. constants.GetKlinePath, to another path. The override is
+// used as is, the API prefix does not apply to it. An empty override removes it.
+func (c *Client) SetPathOverride(path, override string) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	if override == "" {
+		delete(c.pathOverrides, path)
+		return
+	}
+	if c.pathOverrides == nil {
+		c.pathOverrides = make(map[string]string)
+	}
+	c.pathOverrides[path] = override
+}
+
+// ResetPathOverrides replaces all the path overrides with overrides, nil removes them
+func (c *Client) ResetPathOverrides(overrides map[string]string) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.pathOverrides = make(map[string]string, len(overrides))
+	for path, override := range overrides {
+		if override != "" {
+			c.pathOverrides[path] = override
+		}
+	}
+}
+
+// ResolvePath returns the path a gateway path is sent to after the overrides and the API prefix
+func (c *Client) ResolvePath(path string) string {
+	c.settingsMu.RLock()
+	defer c.settingsMu.RUnlock()
+	if override, ok := c.pathOverrides[path]; ok {
+		return override
+	}
+	if c.apiPrefix != "" && strings.HasPrefix(path, constants.BaseAPIPath+"/") {
+		return c.apiPrefix + strings.TrimPrefix(path, constants.BaseAPIPath)
+	}
+	return path
+}
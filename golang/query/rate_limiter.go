@@ -0,0 +1,153 @@
+package query
+
+import (
+	"context"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+)
+
+// EndpointGroup group of gateway endpoints sharing a rate limit
+type EndpointGroup string
+
+const (
+	GroupMarketData EndpointGroup = "market_data" // Market data and metadata: K-lines, tickers, depth, trades, funding, coins and exchanges
+	GroupAccount    EndpointGroup = "account"     // Account queries: address, subaccounts, orders, positions, assets and their history
+	GroupBroadcast  EndpointGroup = "broadcast"   // Transaction broadcasts
+	GroupExplorer   EndpointGroup = "explorer"    // Explorer transaction and block queries
+	GroupOther      EndpointGroup = "other"       // Paths outside the groups above, e.g. those sent with Do
+)
+
+// endpointGroups group of each gateway path
+var endpointGroups = map[string]EndpointGroup{
+	constants.GetCoinListPath:                    GroupMarketData,
+	constants.GetExchangeListPath:                GroupMarketData,
+	constants.GetKlinePath:                       GroupMarketData,
+	constants.GetTickerPath:                      GroupMarketData,
+	constants.GetDepthPath:                       GroupMarketData,
+	constants.GetTradePath:                       GroupMarketData,
+	constants.GetFundingHistoryPath:              GroupMarketData,
+	constants.GetPricePath:                       GroupMarketData,
+	constants.GetAddressInfoPath:                 GroupAccount,
+	constants.GetSubaccountPath:                  GroupAccount,
+	constants.GetActiveOrderPath:                 GroupAccount,
+	constants.GetHistoryOrderPath:                GroupAccount,
+	constants.GetPerpetualAccountAssetPath:       GroupAccount,
+	constants.GetPositionTransactionPath:         GroupAccount,
+	constants.GetCollateralTransactionPath:       GroupAccount,
+	constants.GetAssetSnapshotPath:               GroupAccount,
+	constants.GetHistoryOrderFillTransactionPath: GroupAccount,
+	constants.GetHistoryPositionTermPath:         GroupAccount,
+	constants.SendTransactionPath:                GroupBroadcast,
+	constants.SendSyncTransactionPath:            GroupBroadcast,
+}
+
+// EndpointGroupOf returns the group of a gateway path, given before the API prefix and the path overrides are applied
+func EndpointGroupOf(path string) EndpointGroup {
+	if group, ok := endpointGroups[path]; ok {
+		return group
+	}
+	if strings.HasPrefix(path, constants.GetTransactionPath+"/") {
+		return GroupExplorer
+	}
+	return GroupOther
+}
+
+// RateLimit token bucket rate of an endpoint group
+type RateLimit struct {
+	Rate  float64 // Requests per second
+	Burst int     // Requests allowed at once after an idle period, defaults to Rate rounded up
+}
+
+// RateLimiter token bucket rate limiter of the gateway requests by endpoint group, so backfill loops stay below the
+// limits of the gateway. Groups without a limit are not limited. It is safe for concurrent use and may be shared by
+// several clients, e.g. those of the subaccounts of one IP address, to enforce a common limit.
+type RateLimiter struct {
+	mu      sync.Mutex
+	buckets map[EndpointGroup]*tokenBucket
+}
+
+// tokenBucket tokens of an endpoint group, negative while requests wait for tokens reserved in advance
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a rate limiter with limits by endpoint group
+func NewRateLimiter(limits map[EndpointGroup]RateLimit) *RateLimiter {
+	l := &RateLimiter{buckets: make(map[EndpointGroup]*tokenBucket)}
+	for group, limit := range limits {
+		l.SetLimit(group, limit)
+	}
+	return l
+}
+
+// SetLimit sets the limit of an endpoint group, a zero rate removes it
+func (l *RateLimiter) SetLimit(group EndpointGroup, limit RateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if limit.Rate <= 0 {
+		delete(l.buckets, group)
+		return
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = int(math.Ceil(limit.Rate))
+	}
+	l.buckets[group] = &tokenBucket{limit: limit, tokens: float64(limit.Burst), last: time.Now()}
+}
+
+// Wait blocks until a request of the endpoint group may be sent, it returns the error of ctx when it is done first
+func (l *RateLimiter) Wait(ctx context.Context, group EndpointGroup) error {
+	delay, bucket := l.reserve(group)
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Return the token reserved so the requests queued behind are not delayed by a request never sent
+		l.mu.Lock()
+		bucket.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token of the endpoint group and returns the time until it is available
+func (l *RateLimiter) reserve(group EndpointGroup) (time.Duration, *tokenBucket) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	bucket, ok := l.buckets[group]
+	if !ok {
+		return 0, nil
+	}
+	now := time.Now()
+	bucket.tokens = math.Min(bucket.tokens+now.Sub(bucket.last).Seconds()*bucket.limit.Rate, float64(bucket.limit.Burst))
+	bucket.last = now
+	bucket.tokens--
+	if bucket.tokens >= 0 {
+		return 0, bucket
+	}
+	return time.Duration(-bucket.tokens / bucket.limit.Rate * float64(time.Second)), bucket
+}
+
+// SetRateLimiter sets the rate limiter the gateway requests wait for, retries included, nil disables rate limiting
+func (c *Client) SetRateLimiter(limiter *RateLimiter) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.rateLimiter = limiter
+}
+
+// RateLimiter returns the rate limiter of the client, nil when requests are not rate limited
+func (c *Client) RateLimiter() *RateLimiter {
+	c.settingsMu.RLock()
+	defer c.settingsMu.RUnlock()
+	return c.rateLimiter
+}
@@ -0,0 +1,117 @@
+package query
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// ResponseMeta raw gateway response, for debugging schema mismatches and building compatibility shims
+type ResponseMeta struct {
+	Method      string         // Request method
+	Path        string         // Request path before overrides
+	StatusCode  int            // HTTP status code
+	Header      http.Header    // Response headers
+	Body        []byte         // Raw response body
+	BaseResp    types.BaseResp // Response code and message, a numeric code is read as its decimal string
+	HasBaseResp bool           // Whether the body carries a response code
+	TraceId     string         // Trace ID of the gateway, empty when the body carries none
+
+	baseParsed bool // whether BaseResp, HasBaseResp and TraceId are set
+}
+
+// baseResponse typed response embedding types.BaseResp
+type baseResponse interface {
+	Base() types.BaseResp
+}
+
+// metaCaptureKey context key of the response recorded for the *WithMeta methods
+type metaCaptureKey struct{}
+
+// withMetaCapture returns a context recording the raw response of the requests sent with it into the returned pointer
+func withMetaCapture(ctx context.Context) (context.Context, **ResponseMeta) {
+	capture := new(*ResponseMeta)
+	return context.WithValue(ctx, metaCaptureKey{}, capture), capture
+}
+
+// captureMeta records the response of a request sent with a context of withMetaCapture
+func captureMeta(ctx context.Context, meta *ResponseMeta) {
+	if capture, ok := ctx.Value(metaCaptureKey{}).(**ResponseMeta); ok {
+		*capture = meta
+	}
+}
+
+// SetResponseHook sets a function called with the raw response of every gateway request, including those of the typed
+// methods, so the envelope of a typed result can be inspected. nil removes the hook. The hook must not modify the body.
+func (c *Client) SetResponseHook(hook func(*ResponseMeta)) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.responseHook = hook
+}
+
+// apiError returns the *types.APIError of a response code other than "0" decoded from the response of meta
+func apiError(meta *ResponseMeta, base types.BaseResp) *types.APIError {
+	e := &types.APIError{Code: base.Code, Msg: base.Msg, TraceId: base.TraceId}
+	if meta != nil {
+		meta.parseBaseResp()
+		e.HTTPStatus = meta.StatusCode
+		e.TraceId = meta.TraceId
+		e.Path = meta.Path
+	}
+	return e
+}
+
+// parseBaseResp reads the response code, the message and the trace ID from the body unless they are already set
+func (m *ResponseMeta) parseBaseResp() {
+	if m.baseParsed {
+		return
+	}
+	m.baseParsed = true
+	var base struct {
+		Code    json.RawMessage `json:"code"`
+		Msg     string          `json:"msg"`
+		TraceId string          `json:"traceId"`
+	}
+	if err := json.Unmarshal(m.Body, &base); err == nil && len(base.Code) > 0 && string(base.Code) != "null" {
+		m.BaseResp = types.BaseResp{Code: string(bytes.Trim(base.Code, `"`)), Msg: base.Msg, TraceId: base.TraceId}
+		m.HasBaseResp = true
+		m.TraceId = base.TraceId
+	}
+}
+
+// setBaseResp sets the response code, the message and the trace ID from the typed result the body was decoded into,
+// so the body is not decoded again for them
+func (m *ResponseMeta) setBaseResp(result interface{}) {
+	typed, ok := result.(baseResponse)
+	if m.baseParsed || !ok {
+		return
+	}
+	base := typed.Base()
+	m.baseParsed = true
+	m.BaseResp = base
+	m.HasBaseResp = base.Code != ""
+	m.TraceId = base.TraceId
+}
+
+// observe builds the ResponseMeta of a response and passes it to the response hook. The response code is read from the
+// body here only for the hook, the typed methods take it from their own decoding of the body.
+func (c *Client) observe(method, path string, resp *http.Response, body []byte) *ResponseMeta {
+	meta := &ResponseMeta{
+		Method:     method,
+		Path:       path,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+	}
+	c.settingsMu.RLock()
+	hook := c.responseHook
+	c.settingsMu.RUnlock()
+	if hook != nil {
+		meta.parseBaseResp()
+		hook(meta)
+	}
+	return meta
+}
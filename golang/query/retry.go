@@ -0,0 +1,194 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultMaxRetries default number of retries of a request failing with a transport error or a 429/5xx status
+	DefaultMaxRetries = 2
+	// DefaultRetryBackoff default delay before the first retry
+	DefaultRetryBackoff = 500 * time.Millisecond
+	// DefaultMaxRetryBackoff default upper bound of the delay between two attempts
+	DefaultMaxRetryBackoff = 10 * time.Second
+	// DefaultRetryMultiplier default growth factor of the delay on each retry
+	DefaultRetryMultiplier = 2.0
+	// DefaultRetryJitter default fraction of each delay randomized
+	DefaultRetryJitter = 0.2
+)
+
+// RetryPolicy retries of the gateway requests failing with a transport error, a 429 or a 5xx status. POST requests
+// are only retried when the gateway cannot have processed them, on a 429 or a failed connection, unless RetryPOST is
+// set.
+type RetryPolicy struct {
+	MaxRetries     int           // Retries after the first attempt, 0 disables retries
+	InitialBackoff time.Duration // Delay before the first retry, defaults to DefaultRetryBackoff
+	MaxBackoff     time.Duration // Upper bound of the delay, defaults to DefaultMaxRetryBackoff
+	Multiplier     float64       // Growth factor of the delay on each retry, defaults to DefaultRetryMultiplier
+	Jitter         float64       // Fraction of each delay randomized, e.g. 0.2 spreads it over ±20%, negative disables jitter, defaults to DefaultRetryJitter
+	Budget         time.Duration // Total time a request may take across its attempts and delays, 0 for no limit
+	RetryPOST      bool          // Whether POST requests are also retried on 5xx statuses and errors after they were sent, which may resend a processed request
+}
+
+// DefaultRetryPolicy returns the retry policy of new clients
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     DefaultMaxRetries,
+		InitialBackoff: DefaultRetryBackoff,
+		MaxBackoff:     DefaultMaxRetryBackoff,
+		Multiplier:     DefaultRetryMultiplier,
+		Jitter:         DefaultRetryJitter,
+	}
+}
+
+// withDefaults returns the policy with its zero fields set to their defaults
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.InitialBackoff <= 0 {
+		p.InitialBackoff = DefaultRetryBackoff
+	}
+	if p.MaxBackoff <= 0 {
+		p.MaxBackoff = DefaultMaxRetryBackoff
+	}
+	if p.Multiplier < 1 {
+		p.Multiplier = DefaultRetryMultiplier
+	}
+	if p.Jitter == 0 {
+		p.Jitter = DefaultRetryJitter
+	}
+	return p
+}
+
+// Backoff returns the delay before the retry following attempt, starting at 1, before jitter
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	p = p.withDefaults()
+	delay := float64(p.InitialBackoff)
+	for i := 1; i < attempt && delay < float64(p.MaxBackoff); i++ {
+		delay *= p.Multiplier
+	}
+	if delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	return time.Duration(delay)
+}
+
+// jittered returns the delay randomized by the jitter of the policy
+func (p RetryPolicy) jittered(delay time.Duration) time.Duration {
+	if p.Jitter <= 0 {
+		return delay
+	}
+	return time.Duration(float64(delay) * (1 + p.Jitter*(2*rand.Float64()-1)))
+}
+
+// RetryError error of a request that was attempted more than once
+type RetryError struct {
+	Attempts int   // Attempts made, including the first
+	Err      error // Error of the last attempt
+}
+
+// Error returns the error of the last attempt and the number of attempts
+func (e *RetryError) Error() string {
+	return fmt.Sprintf("%v (after %d attempts)", e.Err, e.Attempts)
+}
+
+// Unwrap returns the error of the last attempt
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// Attempts returns the number of attempts behind an error of the client, 1 when it was not retried
+func Attempts(err error) int {
+	var retryErr *RetryError
+	if errors.As(err, &retryErr) {
+		return retryErr.Attempts
+	}
+	return 1
+}
+
+// SetRetry sets the retry policy of the gateway requests, applying to those sent afterwards
+func (c *Client) SetRetry(policy RetryPolicy) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.retry = policy.withDefaults()
+}
+
+// Retry returns the retry policy of the gateway requests
+func (c *Client) Retry() RetryPolicy {
+	c.settingsMu.RLock()
+	defer c.settingsMu.RUnlock()
+	return c.retry
+}
+
+// SetRetryPolicy sets the number of retries and the first delay of the retry policy, maxRetries 0 disables retries
+func (c *Client) SetRetryPolicy(maxRetries int, backoff time.Duration) {
+	policy := c.Retry()
+	policy.MaxRetries = maxRetries
+	policy.InitialBackoff = backoff
+	c.SetRetry(policy)
+}
+
+// doRetrying sends a request with the retry policy and returns the response of the last attempt, nil when no response
+// was received. The error of a request attempted more than once is a *RetryError.
+func (c *Client) doRetrying(ctx context.Context, method, path string, params map[string]string, payload []byte) (*ResponseMeta, error) {
+	policy := c.Retry()
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		meta, retryable, err := c.do(ctx, method, path, params, payload)
+		if err == nil {
+			return meta, nil
+		}
+		if !retryable || attempt > policy.MaxRetries || !retrySafe(method, policy, err) {
+			return meta, attemptsError(err, attempt)
+		}
+		delay := policy.jittered(policy.Backoff(attempt))
+		if after := retryAfter(meta); after > delay {
+			delay = min(after, policy.MaxBackoff)
+		}
+		if policy.Budget > 0 && time.Since(start)+delay > policy.Budget {
+			return meta, attemptsError(err, attempt)
+		}
+		select {
+		case <-ctx.Done():
+			return meta, attemptsError(err, attempt)
+		case <-time.After(delay):
+		}
+	}
+}
+
+// attemptsError wraps the error of a request attempted more than once in a *RetryError
+func attemptsError(err error, attempts int) error {
+	if attempts <= 1 {
+		return err
+	}
+	return &RetryError{Attempts: attempts, Err: err}
+}
+
+// retrySafe reports whether a failed request may be resent without the risk of the gateway processing it twice
+func retrySafe(method string, policy RetryPolicy, err error) bool {
+	if method == http.MethodGet || policy.RetryPOST || errors.Is(err, ErrThrottled) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// retryAfter returns the delay of the Retry-After header of a response, 0 when absent
+func retryAfter(meta *ResponseMeta) time.Duration {
+	if meta == nil || meta.Header == nil {
+		return 0
+	}
+	value := meta.Header.Get("Retry-After")
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
@@ -0,0 +1,241 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// CodespaceSDK codespace of the errors registered by the cosmos-sdk
+const CodespaceSDK = "sdk"
+
+// Error codes of the cosmos-sdk codespace
+const (
+	TxCodeInvalidSequence   uint32 = 3  // Account sequence mismatch
+	TxCodeUnauthorized      uint32 = 4  // Signature verification failed or signer not allowed
+	TxCodeInsufficientFunds uint32 = 5  // Balance too low to pay the fee or transfer
+	TxCodeOutOfGas          uint32 = 11 // Gas limit exceeded
+	TxCodeInsufficientFee   uint32 = 13 // Fee below the minimum gas price
+	TxCodeTimeoutHeight     uint32 = 30 // Transaction timeout height passed
+	TxCodeWrongSequence     uint32 = 32 // Account sequence mismatch
+)
+
+// Error codes of the antx modules, see ExchangeErr, AgentErr and OrderErr of antx-proto. The ranges do not overlap, so
+// the codes are matched whatever codespace the module reports them in.
+const (
+	TxCodeExchangeMin                uint32 = 1100 // First ExchangeErr code
+	TxCodeExchangeMax                uint32 = 1199 // Last ExchangeErr code
+	TxCodeAgentMin                   uint32 = 1400 // First AgentErr code
+	TxCodeAgentMax                   uint32 = 1499 // Last AgentErr code
+	TxCodeOrderInvalidParamMin       uint32 = 1700 // First OrderErr code of an invalid order parameter
+	TxCodeOrderInvalidReduceOnly     uint32 = 1713 // ORDER_ERR_INVALID_REDUCE_ONLY
+	TxCodeOrderInvalidParamMax       uint32 = 1736 // Last OrderErr code of an invalid order parameter
+	TxCodeOrderRepeatedConflict      uint32 = 1750 // ORDER_ERR_CREATE_REPEATED_CONFLICT
+	TxCodeOrderExchangeNotFound      uint32 = 1751 // ORDER_ERR_EXCHANGE_NOT_FOUND
+	TxCodeOrderExchangeNotPerpetual  uint32 = 1752 // ORDER_ERR_EXCHANGE_NOT_PERPETUAL
+	TxCodeOrderMarginModeUnsupported uint32 = 1753 // ORDER_ERR_EXCHANGE_MARGIN_MODE_UNSUPPORTED
+	TxCodeOrderCreateDisabled        uint32 = 1754 // ORDER_ERR_EXCHANGE_DISABLE_ORDER_CREATE
+	TxCodeOrderFillDisabled          uint32 = 1755 // ORDER_ERR_EXCHANGE_DISABLE_ORDER_FILL
+	TxCodeOrderPositionOpenDisabled  uint32 = 1756 // ORDER_ERR_EXCHANGE_DISABLE_POSITION_OPEN
+	TxCodeOrderSizeMaxExceed         uint32 = 1757 // ORDER_ERR_EXCHANGE_ORDER_SIZE_MAX_EXCEED
+	TxCodeOrderOraclePriceNotFound   uint32 = 1758 // ORDER_ERR_ORACLE_PRICE_NOT_FOUND
+	TxCodeOrderOraclePriceInvalid    uint32 = 1759 // ORDER_ERR_ORACLE_PRICE_INVALID
+	TxCodeOrderMaxBuyPriceExceed     uint32 = 1760 // ORDER_ERR_MAX_BUY_PRICE_EXCEED
+	TxCodeOrderMinSellPriceExceed    uint32 = 1761 // ORDER_ERR_MIN_SELL_PRICE_EXCEED
+	TxCodeOrderTriggerImmediately    uint32 = 1762 // ORDER_ERR_TRIGGER_PRICE_WOULD_IMMEDIATE_TRIGGER
+	TxCodeOrderMaxOrderNumExceed     uint32 = 1763 // ORDER_ERR_MAX_ORDER_NUM_FOR_SUBACCOUNT_EXCEED
+	TxCodeOrderAvailableNotEnough    uint32 = 1764 // ORDER_ERR_AVAILABLE_AMOUNT_NOT_ENOUGH
+	TxCodeOrderLiquidating           uint32 = 1765 // ORDER_ERR_COULD_NOT_OPEN_POSITION_WHEN_LIQUIDATING
+)
+
+// Errors matched by errors.Is on a *TxError
+var (
+	ErrTxFailed             = errors.New("transaction failed")
+	ErrTxInsufficientMargin = errors.New("insufficient margin")
+	ErrTxPriceBand          = errors.New("price outside the allowed band")
+	ErrTxReduceOnly         = errors.New("reduce-only order would increase the position")
+	ErrTxInvalidOrder       = errors.New("invalid order parameters")
+	ErrTxDuplicateOrder     = errors.New("client order ID already used")
+	ErrTxTradingDisabled    = errors.New("trading disabled on the exchange")
+	ErrTxOraclePrice        = errors.New("oracle price unavailable")
+	ErrTxOrderLimit         = errors.New("maximum open orders of the subaccount reached")
+	ErrTxLiquidating        = errors.New("subaccount is being liquidated")
+	ErrTxExchange           = errors.New("exchange module rejected the transaction")
+	ErrTxAgent              = errors.New("agent module rejected the transaction")
+	ErrTxInsufficientFunds  = errors.New("insufficient funds")
+	ErrTxInsufficientFee    = errors.New("insufficient fee")
+	ErrTxOutOfGas           = errors.New("out of gas")
+	ErrTxWrongSequence      = errors.New("account sequence mismatch")
+	ErrTxUnauthorized       = errors.New("unauthorized")
+	ErrTxTimeoutHeight      = errors.New("transaction timeout height passed")
+)
+
+// txCodeKey codespace and code of a registered error
+type txCodeKey struct {
+	codespace string
+	code      uint32
+}
+
+var (
+	txCodesMu sync.RWMutex
+	txCodes   = map[txCodeKey]error{
+		{CodespaceSDK, TxCodeInvalidSequence}:   ErrTxWrongSequence,
+		{CodespaceSDK, TxCodeUnauthorized}:      ErrTxUnauthorized,
+		{CodespaceSDK, TxCodeInsufficientFunds}: ErrTxInsufficientFunds,
+		{CodespaceSDK, TxCodeOutOfGas}:          ErrTxOutOfGas,
+		{CodespaceSDK, TxCodeInsufficientFee}:   ErrTxInsufficientFee,
+		{CodespaceSDK, TxCodeTimeoutHeight}:     ErrTxTimeoutHeight,
+		{CodespaceSDK, TxCodeWrongSequence}:     ErrTxWrongSequence,
+	}
+	// txCodeLog codespace and code embedded in a log string
+	txCodeLog = regexp.MustCompile(`codespace[:=]\s*"?(\w+)"?,?\s*code[:=]\s*(\d+)`)
+)
+
+// RegisterTxError maps a chain error code to an error matched by errors.Is, e.g. to map the code of another module to
+// an application error. Registered codes take precedence over the built-in mapping.
+func RegisterTxError(codespace string, code uint32, err error) {
+	txCodesMu.Lock()
+	defer txCodesMu.Unlock()
+	txCodes[txCodeKey{codespace, code}] = err
+}
+
+// TxError transaction included in a block with a failed status, or rejected by a simulation. errors.Is matches
+// ErrTxFailed and the error of its code, e.g. errors.Is(err, ErrTxInsufficientMargin).
+type TxError struct {
+	Hash      string // Transaction hash, empty for a rejected simulation
+	Block     uint64 // Block height, 0 for a rejected simulation
+	Codespace string // Codespace of the error, empty when the gateway only returned a log
+	Code      uint32 // Code of the error within the codespace, 0 when unknown
+	Log       string // Failure log
+	Kind      error  // Mapped error, nil when the code is unknown
+}
+
+// Error returns the failure with its code when known
+func (e *TxError) Error() string {
+	msg := fmt.Sprintf("transaction %s failed in block %d: %s", e.Hash, e.Block, e.Log)
+	if e.Hash == "" {
+		msg = "transaction rejected by simulation: " + e.Log
+	}
+	if e.Codespace != "" {
+		msg += fmt.Sprintf(" (codespace %s, code %d)", e.Codespace, e.Code)
+	}
+	return msg
+}
+
+// Unwrap returns ErrTxFailed and the mapped error
+func (e *TxError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{ErrTxFailed}
+	}
+	return []error{ErrTxFailed, e.Kind}
+}
+
+// TxResultError returns the *TxError of a failed transaction result, nil when the transaction succeeded
+func TxResultError(result *types.GetTransactionResultRespData) error {
+	if result == nil || result.Status {
+		return nil
+	}
+	e := &TxError{Hash: result.Hash, Block: result.Block}
+	switch v := result.Error.(type) {
+	case string:
+		e.Log = v
+	case map[string]interface{}:
+		e.Codespace, _ = v["codespace"].(string)
+		e.Code = txErrorCode(v["code"])
+		for _, key := range []string{"log", "message", "msg"} {
+			if log, ok := v[key].(string); ok && log != "" {
+				e.Log = log
+				break
+			}
+		}
+	case nil:
+	default:
+		e.Log = fmt.Sprint(v)
+	}
+	e.classify()
+	return e
+}
+
+// TxLogError returns the *TxError of a failure log, e.g. the error of a rejected simulation. Its Kind is only set when
+// the log embeds the codespace and the code.
+func TxLogError(log string) *TxError {
+	e := &TxError{Log: log}
+	e.classify()
+	return e
+}
+
+// classify reads the codespace and code embedded in the log when the gateway did not return them and maps the code
+func (e *TxError) classify() {
+	if e.Codespace == "" {
+		if m := txCodeLog.FindStringSubmatch(e.Log); m != nil {
+			code, _ := strconv.ParseUint(m[2], 10, 32)
+			e.Codespace, e.Code = m[1], uint32(code)
+		}
+	}
+	e.Kind = mapTxError(e.Codespace, e.Code)
+}
+
+// txErrorCode reads a code decoded from JSON as a number or a string
+func txErrorCode(v interface{}) uint32 {
+	switch code := v.(type) {
+	case float64:
+		return uint32(code)
+	case string:
+		n, _ := strconv.ParseUint(code, 10, 32)
+		return uint32(n)
+	}
+	return 0
+}
+
+// mapTxError maps a codespace and a code to a registered error or to the error of an antx module code, nil when the
+// code is unknown. The log wording is not relied upon.
+func mapTxError(codespace string, code uint32) error {
+	if code == 0 {
+		return nil
+	}
+	txCodesMu.RLock()
+	err, ok := txCodes[txCodeKey{codespace, code}]
+	txCodesMu.RUnlock()
+	if ok || codespace == CodespaceSDK {
+		return err
+	}
+	return moduleTxError(code)
+}
+
+// moduleTxError maps a code of the antx modules
+func moduleTxError(code uint32) error {
+	switch {
+	case code == TxCodeOrderInvalidReduceOnly:
+		return ErrTxReduceOnly
+	case code >= TxCodeOrderInvalidParamMin && code <= TxCodeOrderInvalidParamMax:
+		return ErrTxInvalidOrder
+	case code >= TxCodeExchangeMin && code <= TxCodeExchangeMax:
+		return ErrTxExchange
+	case code >= TxCodeAgentMin && code <= TxCodeAgentMax:
+		return ErrTxAgent
+	}
+	switch code {
+	case TxCodeOrderRepeatedConflict:
+		return ErrTxDuplicateOrder
+	case TxCodeOrderExchangeNotFound, TxCodeOrderExchangeNotPerpetual, TxCodeOrderMarginModeUnsupported,
+		TxCodeOrderSizeMaxExceed, TxCodeOrderTriggerImmediately:
+		return ErrTxInvalidOrder
+	case TxCodeOrderCreateDisabled, TxCodeOrderFillDisabled, TxCodeOrderPositionOpenDisabled:
+		return ErrTxTradingDisabled
+	case TxCodeOrderOraclePriceNotFound, TxCodeOrderOraclePriceInvalid:
+		return ErrTxOraclePrice
+	case TxCodeOrderMaxBuyPriceExceed, TxCodeOrderMinSellPriceExceed:
+		return ErrTxPriceBand
+	case TxCodeOrderMaxOrderNumExceed:
+		return ErrTxOrderLimit
+	case TxCodeOrderAvailableNotEnough:
+		return ErrTxInsufficientMargin
+	case TxCodeOrderLiquidating:
+		return ErrTxLiquidating
+	}
+	return nil
+}
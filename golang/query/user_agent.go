@@ -0,0 +1,62 @@
+package query
+
+import (
+	"runtime/debug"
+	"strings"
+)
+
+// SDKModule module path of the SDK, identifying it in the User-Agent
+const SDKModule = "github.com/antxprotocol/antx-sdk-golang"
+
+// Version SDK module version read from the build information, "devel" when the SDK is built from a source tree
+var Version = sdkVersion()
+
+// sdkVersion returns the version of the SDK module in the binary
+func sdkVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "devel"
+	}
+	module := &info.Main
+	for _, dep := range info.Deps {
+		if dep.Path == SDKModule {
+			module = dep
+			break
+		}
+	}
+	if module.Path != SDKModule || module.Version == "" || module.Version == "(devel)" {
+		return "devel"
+	}
+	if module.Replace != nil && module.Replace.Version != "" {
+		return module.Replace.Version
+	}
+	return module.Version
+}
+
+// UserAgent returns the User-Agent of the SDK, antx-sdk-golang/<version>, followed by an application tag when not
+// empty, e.g. "antx-sdk-golang/v1.2.0 my-bot/0.3"
+func UserAgent(tag string) string {
+	userAgent := "antx-sdk-golang/" + Version
+	if tag = strings.TrimSpace(tag); tag != "" {
+		userAgent += " " + tag
+	}
+	return userAgent
+}
+
+// SetUserAgentTag appends an application tag to the User-Agent of the HTTP requests and WebSocket connections, so
+// gateway operators can attribute the traffic, e.g. "my-bot/0.3"
+func (c *Client) SetUserAgentTag(tag string) {
+	c.settingsMu.Lock()
+	defer c.settingsMu.Unlock()
+	c.userAgent = UserAgent(tag)
+}
+
+// UserAgent returns the User-Agent sent by the client
+func (c *Client) UserAgent() string {
+	c.settingsMu.RLock()
+	defer c.settingsMu.RUnlock()
+	if c.userAgent == "" {
+		return UserAgent("")
+	}
+	return c.userAgent
+}
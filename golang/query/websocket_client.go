@@ -0,0 +1,663 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// WsReqBase WebSocket request base structure
+type WsReqBase struct {
+	Method string `json:"method"` // Request method
+}
+
+// WsRegisterReq WebSocket subscription registration request structure
+type WsRegisterReq struct {
+	Channel      string `json:"channel"`                // Channel
+	ChainType    int32  `json:"chainType,omitempty"`    // Chain type
+	ChainAddress string `json:"chainAddress,omitempty"` // ETH address
+}
+
+// WsSubscribeReq WebSocket subscription request structure
+type WsSubscribeReq struct {
+	WsReqBase
+	Subscription WsRegisterReq `json:"subscription"` // Subscription
+}
+
+// WsRespBase WebSocket response base structure
+type WsRespBase struct {
+	Channel string `json:"channel"`         // Channel
+	Event   string `json:"event,omitempty"` // Event
+	User    string `json:"user,omitempty"`  // ETH address
+}
+
+// wsConn WebSocket connection, dialed with gorilla/websocket natively and with the browser WebSocket API on js/wasm
+type wsConn interface {
+	NextReader() (int, io.Reader, error)
+	WriteJSON(v interface{}) error
+	Close() error
+}
+
+// WebSocketClient encapsulates WebSocket connection. Subscriptions may be made from several goroutines, the Set methods
+// must be called before Connect.
+type WebSocketClient struct {
+	url           string
+	errorHandler  func(error)
+	credentials   APICredentials
+	userAgent     string
+	headers       map[string]string
+	transport     *TransportConfig
+	noCompression bool // whether permessage-deflate is not negotiated
+	chaos         *Chaos
+	logger        Logger
+	dropHandler   func(channel string)
+	connectHook   func(error)
+	dropped       atomic.Uint64
+
+	connMu    sync.Mutex // guards conn and serializes the writes to it
+	conn      wsConn
+	connected atomic.Bool
+
+	handlerMu      sync.RWMutex
+	messageHandler func([]byte)
+	handlers       map[string][]*wsHandler // subscription handlers by channel
+
+	subMu         sync.Mutex
+	subscriptions []WsRegisterReq
+}
+
+// NewWebSocketClient creates a new WebSocket client
+func NewWebSocketClient(wsURL string, messageHandler func([]byte), errorHandler func(error)) *WebSocketClient {
+	// If a complete URL is passed, use it directly; otherwise use old logic
+	var u url.URL
+	if strings.HasPrefix(wsURL, "ws://") || strings.HasPrefix(wsURL, "wss://") {
+		parsedURL, err := url.Parse(wsURL)
+		if err != nil {
+			// If parsing fails, fallback
+			u = url.URL{Scheme: "ws", Host: wsURL, Path: "/api/v1/ws"}
+		} else {
+			u = *parsedURL
+		}
+	} else {
+		u = url.URL{Scheme: "ws", Host: wsURL, Path: "/api/v1/ws"}
+	}
+	return &WebSocketClient{
+		url:            u.String(),
+		messageHandler: messageHandler,
+		errorHandler:   errorHandler,
+		userAgent:      UserAgent(""),
+	}
+}
+
+// SetDropHandler sets a function called with the channel of each message dropped because its subscriber is not
+// keeping up, nil removes it
+func (c *WebSocketClient) SetDropHandler(handler func(channel string)) {
+	c.dropHandler = handler
+}
+
+// Dropped returns the number of messages dropped because their subscriber was not keeping up
+func (c *WebSocketClient) Dropped() uint64 {
+	return c.dropped.Load()
+}
+
+// drop records a message dropped on a full subscriber channel
+func (c *WebSocketClient) drop(channel string) {
+	c.dropped.Add(1)
+	if c.dropHandler != nil {
+		c.dropHandler(channel)
+	}
+}
+
+// Connect establishes WebSocket connection
+func (c *WebSocketClient) Connect() error {
+	return c.ConnectContext(context.Background())
+}
+
+// ConnectContext is Connect with a context bounding the handshake
+func (c *WebSocketClient) ConnectContext(ctx context.Context) error {
+	c.log().Debugf("connecting to %s", c.url)
+
+	// Set request headers to avoid WAF blocking
+	header := make(http.Header)
+	header.Set("Origin", c.getOriginFromURL())
+	setGatewayHeader(header, c.credentials, c.userAgent, c.headers, http.MethodGet, c.requestURI(), nil)
+
+	conn, err := dialWebSocket(ctx, c.url, header, c.transport, !c.noCompression)
+	if c.connectHook != nil && ctx.Err() == nil {
+		c.connectHook(err)
+	}
+	if err != nil {
+		c.connected.Store(false)
+		return fmt.Errorf("websocket dial error: %w", err)
+	}
+	if c.chaos != nil {
+		conn = &chaosConn{wsConn: conn, chaos: c.chaos}
+	}
+	c.connMu.Lock()
+	c.conn = conn
+	c.connected.Store(true)
+	c.connMu.Unlock()
+	c.log().Debugf("websocket connected")
+
+	go c.listenForMessages(conn)
+	return nil
+}
+
+// SetAPICredentials sets the API key sent when connecting, on js/wasm the browser does not allow request headers and
+// the key is not sent
+func (c *WebSocketClient) SetAPICredentials(credentials APICredentials) {
+	c.credentials = credentials
+}
+
+// SetUserAgent sets the User-Agent sent when connecting, see UserAgent
+func (c *WebSocketClient) SetUserAgent(userAgent string) {
+	c.userAgent = userAgent
+}
+
+// requestURI returns the path and query of the WebSocket URL, signed with the API secret
+func (c *WebSocketClient) requestURI() string {
+	u, err := url.Parse(c.url)
+	if err != nil {
+		return ""
+	}
+	return u.RequestURI()
+}
+
+// getOriginFromURL extracts Origin from WebSocket URL
+func (c *WebSocketClient) getOriginFromURL() string {
+	u, err := url.Parse(c.url)
+	if err != nil {
+		return ""
+	}
+	// Convert ws:// or wss:// to http:// or https://
+	scheme := "http"
+	if u.Scheme == "wss" {
+		scheme = "https"
+	}
+	return fmt.Sprintf("%s://%s", scheme, u.Host)
+}
+
+// listenForMessages listens for the WebSocket messages of a connection
+func (c *WebSocketClient) listenForMessages(conn wsConn) {
+	defer func() {
+		c.connMu.Lock()
+		if c.conn == conn {
+			c.connected.Store(false)
+		}
+		c.connMu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		message, err := readMessage(conn)
+		if err != nil {
+			if c.errorHandler != nil {
+				c.errorHandler(fmt.Errorf("websocket read error: %w", err))
+			}
+			return
+		}
+		c.dispatch(message)
+		message.Release()
+	}
+}
+
+// wsHandler subscription handler of the messages of a channel, receiving either a copy or the pooled message
+type wsHandler struct {
+	bytes  func(header *pushHeader, msg []byte)
+	pooled func(header *pushHeader, msg *WsMessage)
+}
+
+// dispatch passes a message to the message handler of the connection and to the subscription handlers of its channel,
+// reading the channel once
+func (c *WebSocketClient) dispatch(message *WsMessage) {
+	header, err := readPushHeader(message.Data)
+	c.handlerMu.RLock()
+	messageHandler := c.messageHandler
+	var handlers []*wsHandler
+	if err == nil {
+		handlers = c.handlers[header.Channel]
+	}
+	c.handlerMu.RUnlock()
+
+	// Byte handlers may keep the slice, so they share a copy made only when one of them receives the message
+	var data []byte
+	copied := false
+	bytes := func() []byte {
+		if !copied {
+			data, copied = append([]byte(nil), message.Data...), true
+		}
+		return data
+	}
+	if messageHandler != nil {
+		messageHandler(bytes())
+	}
+	for _, handler := range handlers {
+		if handler.pooled != nil {
+			handler.pooled(&header, message)
+		} else {
+			handler.bytes(&header, bytes())
+		}
+	}
+}
+
+// addHandler registers a subscription handler of the messages of a channel
+func (c *WebSocketClient) addHandler(channel string, handler *wsHandler) {
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
+	if c.handlers == nil {
+		c.handlers = make(map[string][]*wsHandler)
+	}
+	c.handlers[channel] = append(c.handlers[channel], handler)
+}
+
+// removeHandler removes a subscription handler, the handlers of the channel are copied so a dispatch in progress keeps
+// its own
+func (c *WebSocketClient) removeHandler(channel string, handler *wsHandler) {
+	c.handlerMu.Lock()
+	defer c.handlerMu.Unlock()
+	handlers := make([]*wsHandler, 0, len(c.handlers[channel]))
+	for _, h := range c.handlers[channel] {
+		if h != handler {
+			handlers = append(handlers, h)
+		}
+	}
+	if len(handlers) == 0 {
+		delete(c.handlers, channel)
+		return
+	}
+	c.handlers[channel] = handlers
+}
+
+// forward registers a subscription handler sending the messages of a channel to messageChan, dropping them while it
+// is full
+func (c *WebSocketClient) forward(channel string, messageChan chan []byte) {
+	c.addHandler(channel, &wsHandler{bytes: func(_ *pushHeader, msg []byte) {
+		select {
+		case messageChan <- msg:
+		default:
+			c.drop(channel)
+		}
+	}})
+}
+
+// writeJSON writes a request to the connection, one writer at a time
+func (c *WebSocketClient) writeJSON(v interface{}) error {
+	c.connMu.Lock()
+	defer c.connMu.Unlock()
+	if c.conn == nil || !c.connected.Load() {
+		return ErrNotConnected
+	}
+	return c.conn.WriteJSON(v)
+}
+
+// readMessage reads the next message of a connection into a pooled buffer
+func readMessage(conn wsConn) (*WsMessage, error) {
+	_, r, err := conn.NextReader()
+	if err != nil {
+		return nil, err
+	}
+	message := wsMessagePool.Get().(*WsMessage)
+	message.refs = 1
+	message.buf.Reset()
+	if _, err := message.buf.ReadFrom(r); err != nil {
+		message.Release()
+		return nil, err
+	}
+	message.Data = message.buf.Bytes()
+	return message, nil
+}
+
+// Subscribe subscribes to WebSocket channel
+func (c *WebSocketClient) Subscribe(channel string) error {
+	return c.subscribe(WsRegisterReq{Channel: channel})
+}
+
+// subscribe sends a subscription request and records it
+func (c *WebSocketClient) subscribe(subscription WsRegisterReq) error {
+	req := WsSubscribeReq{
+		WsReqBase: WsReqBase{
+			Method: "subscribe",
+		},
+		Subscription: subscription,
+	}
+	if err := c.writeJSON(req); err != nil {
+		return err
+	}
+
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	for _, s := range c.subscriptions {
+		if s == subscription {
+			return nil
+		}
+	}
+	c.subscriptions = append(c.subscriptions, subscription)
+	return nil
+}
+
+// Subscriptions returns the active subscriptions in subscription order
+func (c *WebSocketClient) Subscriptions() []WsRegisterReq {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	return append([]WsRegisterReq(nil), c.subscriptions...)
+}
+
+// Resubscribe sends the subscriptions that are not active, e.g. those of a snapshot after a restart. Their messages
+// reach the message handler of the connection.
+func (c *WebSocketClient) Resubscribe(subscriptions []WsRegisterReq) error {
+	active := make(map[WsRegisterReq]bool)
+	for _, s := range c.Subscriptions() {
+		active[s] = true
+	}
+	for _, s := range subscriptions {
+		if active[s] {
+			continue
+		}
+		if err := c.subscribe(s); err != nil {
+			return fmt.Errorf("failed to resubscribe to %s: %w", s.Channel, err)
+		}
+	}
+	return nil
+}
+
+// SubscribeTo subscribes like the typed Subscribe methods, e.g. to a subscription of a snapshot after a restart, and
+// returns the channel of its messages: depth channels resync like SubscribeToDepth and tradeData delivers the events of
+// the subscription address. Messages are dropped while the channel is full.
+func (c *WebSocketClient) SubscribeTo(subscription WsRegisterReq) (<-chan []byte, error) {
+	if subscription.Channel == "tradeData" {
+		return c.SubscribeToTradeData(subscription.ChainAddress)
+	}
+	if parts := strings.Split(subscription.Channel, "."); len(parts) == 3 && parts[0] == "depth" {
+		return c.SubscribeToDepth(parts[1], parts[2])
+	}
+	if err := c.subscribe(subscription); err != nil {
+		return nil, err
+	}
+
+	messageChan := make(chan []byte, 100)
+	c.forward(subscription.Channel, messageChan)
+	return messageChan, nil
+}
+
+// Unsubscribe unsubscribes from WebSocket channel
+func (c *WebSocketClient) Unsubscribe(channel string) error {
+	req := WsSubscribeReq{
+		WsReqBase: WsReqBase{
+			Method: "unsubscribe",
+		},
+		Subscription: WsRegisterReq{
+			Channel: channel,
+		},
+	}
+	if err := c.writeJSON(req); err != nil {
+		return err
+	}
+
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	for i, s := range c.subscriptions {
+		if s.Channel == channel {
+			c.subscriptions = append(c.subscriptions[:i], c.subscriptions[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// SubscribePooled subscribes to a channel with zero-copy delivery: messages are pooled buffers shared with other subscribers,
+// they must not be modified and Release must be called on each once it is no longer used
+func (c *WebSocketClient) SubscribePooled(channel string) (<-chan *WsMessage, error) {
+	err := c.Subscribe(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a channel to receive data
+	messageChan := make(chan *WsMessage, 100)
+
+	// Set pooled message handler
+	c.addHandler(channel, &wsHandler{pooled: func(_ *pushHeader, msg *WsMessage) {
+		msg.retain()
+		select {
+		case messageChan <- msg:
+		default:
+			// If channel is full, drop message
+			msg.Release()
+			c.drop(channel)
+		}
+	}})
+
+	return messageChan, nil
+}
+
+// SubscribeContext subscribes to a channel and delivers its messages until ctx is done, then unsubscribes and closes
+// the returned channel. Messages are dropped while the channel is full.
+func (c *WebSocketClient) SubscribeContext(ctx context.Context, channel string) (<-chan []byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if err := c.Subscribe(channel); err != nil {
+		return nil, err
+	}
+
+	messageChan := make(chan []byte, 100)
+	var mu sync.Mutex
+	closed := false
+	handler := &wsHandler{bytes: func(_ *pushHeader, msg []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return
+		}
+		select {
+		case messageChan <- msg:
+		default:
+			c.drop(channel)
+		}
+	}}
+	c.addHandler(channel, handler)
+
+	go func() {
+		<-ctx.Done()
+		c.removeHandler(channel, handler)
+		_ = c.Unsubscribe(channel)
+		mu.Lock()
+		closed = true
+		close(messageChan)
+		mu.Unlock()
+	}()
+	return messageChan, nil
+}
+
+// SubscribeToTicker subscribes to Ticker data
+func (c *WebSocketClient) SubscribeToTicker(exchangeId string) (<-chan []byte, error) {
+	channel := fmt.Sprintf("ticker.%s", exchangeId)
+	err := c.Subscribe(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a channel to receive data
+	tickerChan := make(chan []byte, 100)
+
+	// Set message handler
+	c.forward(channel, tickerChan)
+
+	return tickerChan, nil
+}
+
+// SubscribeToKline subscribes to K-line data
+func (c *WebSocketClient) SubscribeToKline(priceType, exchangeId, klineType string) (<-chan []byte, error) {
+	channel := fmt.Sprintf("kline.%s.%s.%s", priceType, exchangeId, klineType)
+	err := c.Subscribe(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a channel to receive data
+	klineChan := make(chan []byte, 100)
+
+	// Set message handler
+	c.forward(channel, klineChan)
+
+	return klineChan, nil
+}
+
+// SubscribeToDepth subscribes to order book depth data. When an update is dropped because the channel is full, the
+// updates after it are discarded and the channel is resubscribed, so the next message received is a new snapshot.
+func (c *WebSocketClient) SubscribeToDepth(exchangeId, level string) (<-chan []byte, error) {
+	channel := fmt.Sprintf("depth.%s.%s", exchangeId, level)
+	err := c.Subscribe(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a channel to receive data
+	depthChan := make(chan []byte, 100)
+
+	// stale is set once an update is dropped: the updates after it would not continue the book of the consumer, so
+	// they are discarded until the gateway pushes the snapshot requested by resubscribing
+	var stale atomic.Bool
+
+	// Set message handler
+	c.addHandler(channel, &wsHandler{bytes: func(header *pushHeader, msg []byte) {
+		snapshot := header.Snapshot
+		if stale.Load() && !snapshot {
+			c.drop(channel)
+			return
+		}
+		select {
+		case depthChan <- msg:
+			if snapshot {
+				stale.Store(false)
+			}
+		default:
+			// If channel is full, drop message and resync from a new snapshot
+			c.drop(channel)
+			if stale.CompareAndSwap(false, true) || snapshot {
+				go c.resyncDepth(channel)
+			}
+		}
+	}})
+
+	return depthChan, nil
+}
+
+// resyncDepth resubscribes to a depth channel so that the gateway pushes a new snapshot
+func (c *WebSocketClient) resyncDepth(channel string) {
+	if err := c.Unsubscribe(channel); err != nil {
+		c.log().Errorf("failed to unsubscribe from %s for resync: %v", channel, err)
+		return
+	}
+	if err := c.Subscribe(channel); err != nil {
+		c.log().Errorf("failed to resubscribe to %s for resync: %v", channel, err)
+	}
+}
+
+// SubscribeToTradeData subscribes to private account events of an EVM address
+func (c *WebSocketClient) SubscribeToTradeData(chainAddress string) (<-chan []byte, error) {
+	err := c.subscribe(WsRegisterReq{
+		Channel:      "tradeData",
+		ChainType:    1,
+		ChainAddress: chainAddress,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a channel to receive data
+	tradeDataChan := make(chan []byte, 100)
+
+	// Set message handler
+	c.addHandler("tradeData", &wsHandler{bytes: func(header *pushHeader, msg []byte) {
+		// Check if it's trade data of the address
+		if !strings.EqualFold(header.User, chainAddress) {
+			return
+		}
+		select {
+		case tradeDataChan <- msg:
+		default:
+			// If channel is full, drop message
+			c.drop(header.Channel)
+		}
+	}})
+
+	return tradeDataChan, nil
+}
+
+// Disconnect disconnects WebSocket connection
+func (c *WebSocketClient) Disconnect() error {
+	c.connMu.Lock()
+	conn := c.conn
+	c.connected.Store(false)
+	c.connMu.Unlock()
+	if conn != nil {
+		return conn.Close()
+	}
+	return nil
+}
+
+// IsConnected checks connection status
+func (c *WebSocketClient) IsConnected() bool {
+	return c.connected.Load()
+}
+
+// ParseTickerData parses Ticker data
+func ParseTickerData(data []byte) (*types.TickerData, error) {
+	if fastJSONDefault {
+		return decodeTickerPush(data)
+	}
+
+	// WebSocket returns wrapped data structure, need to parse outer structure first
+	var wsResponse struct {
+		Channel string             `json:"channel"`
+		Event   string             `json:"event"`
+		Data    []types.TickerData `json:"data"`
+	}
+
+	if err := json.Unmarshal(data, &wsResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse websocket response: %w", err)
+	}
+
+	// Check if there is data
+	if len(wsResponse.Data) == 0 {
+		return nil, fmt.Errorf("no ticker data in response")
+	}
+
+	// Return first ticker data
+	return &wsResponse.Data[0], nil
+}
+
+// ParseKlineData parses K-line data
+func ParseKlineData(data []byte) (*types.KLine, error) {
+	if fastJSONDefault {
+		return decodeKlinePush(data)
+	}
+
+	// WebSocket returns wrapped data structure, need to parse outer structure first
+	var wsResponse struct {
+		Channel string        `json:"channel"`
+		Event   string        `json:"event"`
+		Data    []types.KLine `json:"data"`
+	}
+
+	if err := json.Unmarshal(data, &wsResponse); err != nil {
+		return nil, fmt.Errorf("failed to parse websocket response: %w", err)
+	}
+
+	// Check if there is data
+	if len(wsResponse.Data) == 0 {
+		return nil, fmt.Errorf("no kline data in response")
+	}
+
+	// Return first kline data
+	return &wsResponse.Data[0], nil
+}
@@ -0,0 +1,122 @@
+package query
+
+import (
+	"context"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// The *WithMeta methods are the typed queries also returning the raw response of their last attempt, nil when no
+// response was received, e.g. to inspect the headers or the envelope of a result that does not decode as expected.
+
+// GetCoinListWithMeta is GetCoinListContext also returning the raw response
+func (c *Client) GetCoinListWithMeta(ctx context.Context) ([]types.Coin, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetCoinListContext(ctx)
+	return result, *meta, err
+}
+
+// GetSubaccountListWithMeta is GetSubaccountListContext also returning the raw response
+func (c *Client) GetSubaccountListWithMeta(ctx context.Context, chainType int32, chainAddress, agentAddress string) ([]types.Subaccount, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetSubaccountListContext(ctx, chainType, chainAddress, agentAddress)
+	return result, *meta, err
+}
+
+// GetExchangeListWithMeta is GetExchangeListContext also returning the raw response
+func (c *Client) GetExchangeListWithMeta(ctx context.Context) ([]types.Exchange, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetExchangeListContext(ctx)
+	return result, *meta, err
+}
+
+// GetKlineWithMeta is GetKlineContext also returning the raw response
+func (c *Client) GetKlineWithMeta(ctx context.Context, req types.GetKLineReq) (*types.GetKLineResp, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetKlineContext(ctx, req)
+	return result, *meta, err
+}
+
+// GetTickerWithMeta is GetTickerContext also returning the raw response
+func (c *Client) GetTickerWithMeta(ctx context.Context, req types.GetTickerReq) (*types.GetTickerResp, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetTickerContext(ctx, req)
+	return result, *meta, err
+}
+
+// GetDepthWithMeta is GetDepthContext also returning the raw response
+func (c *Client) GetDepthWithMeta(ctx context.Context, req types.GetDepthReq) (*types.GetDepthResp, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetDepthContext(ctx, req)
+	return result, *meta, err
+}
+
+// GetFundingHistoryWithMeta is GetFundingHistoryContext also returning the raw response
+func (c *Client) GetFundingHistoryWithMeta(ctx context.Context, req types.GetFundingHistoryReq) (*types.GetFundingHistoryResp, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetFundingHistoryContext(ctx, req)
+	return result, *meta, err
+}
+
+// GetActiveOrderWithMeta is GetActiveOrderContext also returning the raw response
+func (c *Client) GetActiveOrderWithMeta(ctx context.Context, req types.GetActiveOrderReq) (*types.GetActiveOrderResp, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetActiveOrderContext(ctx, req)
+	return result, *meta, err
+}
+
+// GetHistoryOrderWithMeta is GetHistoryOrderContext also returning the raw response
+func (c *Client) GetHistoryOrderWithMeta(ctx context.Context, req types.GetHistoryOrderReq) (*types.GetHistoryOrderResp, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetHistoryOrderContext(ctx, req)
+	return result, *meta, err
+}
+
+// GetPerpetualAccountAssetWithMeta is GetPerpetualAccountAssetContext also returning the raw response
+func (c *Client) GetPerpetualAccountAssetWithMeta(ctx context.Context, req types.GetPerpetualAccountAssetReq) (*types.GetPerpetualAccountAssetResp, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetPerpetualAccountAssetContext(ctx, req)
+	return result, *meta, err
+}
+
+// GetPositionTransactionWithMeta is GetPositionTransactionContext also returning the raw response
+func (c *Client) GetPositionTransactionWithMeta(ctx context.Context, req types.GetPositionTransactionReq) (*types.GetPositionTransactionResp, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetPositionTransactionContext(ctx, req)
+	return result, *meta, err
+}
+
+// GetCollateralTransactionWithMeta is GetCollateralTransactionContext also returning the raw response
+func (c *Client) GetCollateralTransactionWithMeta(ctx context.Context, req types.GetCollateralTransactionReq) (*types.GetCollateralTransactionResp, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetCollateralTransactionContext(ctx, req)
+	return result, *meta, err
+}
+
+// GetAssetSnapshotWithMeta is GetAssetSnapshotContext also returning the raw response
+func (c *Client) GetAssetSnapshotWithMeta(ctx context.Context, req types.GetAssetSnapshotReq) (*types.GetAssetSnapshotResp, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetAssetSnapshotContext(ctx, req)
+	return result, *meta, err
+}
+
+// GetHistoryOrderFillTransactionWithMeta is GetHistoryOrderFillTransactionContext also returning the raw response
+func (c *Client) GetHistoryOrderFillTransactionWithMeta(ctx context.Context, req types.GetHistoryOrderFillTransactionReq) (*types.GetHistoryOrderFillTransactionResp, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetHistoryOrderFillTransactionContext(ctx, req)
+	return result, *meta, err
+}
+
+// GetHistoryPositionTermWithMeta is GetHistoryPositionTermContext also returning the raw response
+func (c *Client) GetHistoryPositionTermWithMeta(ctx context.Context, req types.GetHistoryPositionTermReq) (*types.GetHistoryPositionTermResp, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetHistoryPositionTermContext(ctx, req)
+	return result, *meta, err
+}
+
+// GetTransactionResultWithMeta is GetTransactionResultContext also returning the raw response
+func (c *Client) GetTransactionResultWithMeta(ctx context.Context, hash string) (*types.GetTransactionResultRespData, *ResponseMeta, error) {
+	ctx, meta := withMetaCapture(ctx)
+	result, err := c.GetTransactionResultContext(ctx, hash)
+	return result, *meta, err
+}
@@ -0,0 +1,38 @@
+//go:build !js
+
+package query
+
+import (
+	"context"
+	"net"
+	"net/http"
+
+	"github.com/gorilla/websocket"
+)
+
+// dialWebSocket dials a WebSocket connection with the request headers through the proxy and with the TLS settings of
+// transport, the defaults when nil, negotiating permessage-deflate when compression is set. ctx bounds the handshake.
+func dialWebSocket(ctx context.Context, wsURL string, header http.Header, transport *TransportConfig, compression bool) (wsConn, error) {
+	dialer := *websocket.DefaultDialer
+	if transport != nil {
+		proxy, err := transport.proxy()
+		if err != nil {
+			return nil, err
+		}
+		config := transport.withDefaults()
+		dialer = websocket.Dialer{
+			Proxy:            proxy,
+			HandshakeTimeout: config.TLSHandshakeTimeout + config.DialTimeout,
+			NetDialContext:   (&net.Dialer{Timeout: config.DialTimeout, KeepAlive: config.KeepAlive}).DialContext,
+		}
+		if transport.TLSConfig != nil {
+			dialer.TLSClientConfig = transport.TLSConfig.Clone()
+		}
+	}
+	dialer.EnableCompression = compression
+	conn, _, err := dialer.DialContext(ctx, wsURL, header)
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
@@ -0,0 +1,182 @@
+//go:build js && wasm
+
+package query
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"syscall/js"
+)
+
+// browserConn WebSocket connection through the browser WebSocket API. Messages are queued by the JavaScript event
+// handlers, which must not block, and read from Go goroutines.
+type browserConn struct {
+	ws       js.Value
+	handlers map[string]js.Func
+
+	mu    sync.Mutex
+	queue [][]byte
+	err   error
+	ready chan struct{}
+	once  sync.Once
+}
+
+// dialWebSocket dials a WebSocket connection with the browser WebSocket API, which sets Origin itself and does not
+// allow custom request headers, so header is ignored. It blocks until the connection opens and must not be called
+// from a JavaScript callback. The connection is closed when ctx is done before it opens.
+func dialWebSocket(ctx context.Context, wsURL string, header http.Header, _ *TransportConfig, _ bool) (wsConn, error) {
+	var ws js.Value
+	if err := jsCatch(func() { ws = js.Global().Get("WebSocket").New(wsURL) }); err != nil {
+		return nil, err
+	}
+	ws.Set("binaryType", "arraybuffer")
+	c := &browserConn{ws: ws, handlers: make(map[string]js.Func), ready: make(chan struct{}, 1)}
+
+	opened := make(chan error, 1)
+	c.handle("open", func(js.Value) {
+		select {
+		case opened <- nil:
+		default:
+		}
+	})
+	c.handle("error", func(js.Value) {
+		// The browser reports no details, the close event that follows carries the code
+		select {
+		case opened <- fmt.Errorf("websocket error on %s", wsURL):
+		default:
+		}
+	})
+	c.handle("close", func(event js.Value) {
+		err := fmt.Errorf("websocket closed with code %d: %s", event.Get("code").Int(), event.Get("reason").String())
+		select {
+		case opened <- err:
+		default:
+		}
+		c.shutdown(err)
+		c.release()
+	})
+	c.handle("message", func(event js.Value) {
+		data := event.Get("data")
+		var message []byte
+		if data.Type() == js.TypeString {
+			message = []byte(data.String())
+		} else {
+			array := js.Global().Get("Uint8Array").New(data)
+			message = make([]byte, array.Get("length").Int())
+			js.CopyBytesToGo(message, array)
+		}
+		c.mu.Lock()
+		c.queue = append(c.queue, message)
+		c.mu.Unlock()
+		c.signal()
+	})
+
+	select {
+	case err := <-opened:
+		if err != nil {
+			_ = c.Close()
+			return nil, err
+		}
+	case <-ctx.Done():
+		_ = c.Close()
+		return nil, ctx.Err()
+	}
+	return c, nil
+}
+
+// NextReader returns the next message, blocking until one arrives or the connection closes
+func (c *browserConn) NextReader() (int, io.Reader, error) {
+	for {
+		c.mu.Lock()
+		if len(c.queue) > 0 {
+			message := c.queue[0]
+			c.queue[0] = nil
+			c.queue = c.queue[1:]
+			c.mu.Unlock()
+			return 1, bytes.NewReader(message), nil // text message
+		}
+		err := c.err
+		c.mu.Unlock()
+		if err != nil {
+			return 0, nil, err
+		}
+		<-c.ready
+	}
+}
+
+// WriteJSON sends v as a JSON text message
+func (c *browserConn) WriteJSON(v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	err = c.err
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return jsCatch(func() { c.ws.Call("send", string(b)) })
+}
+
+// Close closes the connection, pending messages can still be read
+func (c *browserConn) Close() error {
+	c.shutdown(fmt.Errorf("websocket closed"))
+	return jsCatch(func() { c.ws.Call("close") })
+}
+
+// handle registers a JavaScript event handler
+func (c *browserConn) handle(event string, handler func(event js.Value)) {
+	fn := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		handler(args[0])
+		return nil
+	})
+	c.handlers[event] = fn
+	c.ws.Set("on"+event, fn)
+}
+
+// release detaches and releases the event handlers once the connection is closed
+func (c *browserConn) release() {
+	for event, fn := range c.handlers {
+		c.ws.Set("on"+event, js.Null())
+		fn.Release()
+	}
+}
+
+// shutdown records the error returned once the queued messages are read
+func (c *browserConn) shutdown(err error) {
+	c.once.Do(func() {
+		c.mu.Lock()
+		c.err = err
+		c.mu.Unlock()
+		c.signal()
+	})
+}
+
+// signal wakes up a blocked reader
+func (c *browserConn) signal() {
+	select {
+	case c.ready <- struct{}{}:
+	default:
+	}
+}
+
+// jsCatch runs fn and turns a JavaScript exception into an error
+func jsCatch(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if jsErr, ok := r.(js.Error); ok {
+				err = jsErr
+				return
+			}
+			panic(r)
+		}
+	}()
+	fn()
+	return nil
+}
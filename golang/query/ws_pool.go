@@ -0,0 +1,39 @@
+package query
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+)
+
+// maxPooledMessageSize buffers grown beyond this size are released to the garbage collector instead of the pool
+const maxPooledMessageSize = 1 << 20
+
+// wsMessagePool pool of WebSocket message buffers
+var wsMessagePool = sync.Pool{
+	New: func() interface{} { return &WsMessage{} },
+}
+
+// WsMessage WebSocket message in a pooled buffer, shared by the subscribers it is delivered to
+type WsMessage struct {
+	Data []byte // Raw message, valid until Release
+
+	buf  bytes.Buffer
+	refs int32
+}
+
+// Release returns the message buffer to the pool once every holder released it, Data must not be used afterwards
+func (m *WsMessage) Release() {
+	if atomic.AddInt32(&m.refs, -1) != 0 {
+		return
+	}
+	m.Data = nil
+	if m.buf.Cap() <= maxPooledMessageSize {
+		wsMessagePool.Put(m)
+	}
+}
+
+// retain adds a holder of the message
+func (m *WsMessage) retain() {
+	atomic.AddInt32(&m.refs, 1)
+}
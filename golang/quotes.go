@@ -0,0 +1,221 @@
+package sdk
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	exchangetypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/exchange"
+	ordertypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/order"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// DefaultQuoteClientOrderIdPrefix default client order ID prefix of quote orders
+const DefaultQuoteClientOrderIdPrefix = "q-"
+
+// Quote a desired resting limit order on one side of the book
+type Quote struct {
+	Price decimal.Decimal // Limit price, must be a multiple of the tick size
+	Size  decimal.Decimal // Size, must be a multiple of the step size
+}
+
+// QuoteConfig order settings of quote updates
+type QuoteConfig struct {
+	MarginMode          exchangetypes.MarginMode // Margin mode, defaults to cross
+	Leverage            uint32                   // Leverage, defaults to the exchange default leverage
+	TimeInForce         ordertypes.TimeInForce   // Time in force, defaults to POST_ONLY
+	ClientOrderIdPrefix string                   // Client order ID prefix, defaults to DefaultQuoteClientOrderIdPrefix
+}
+
+// QuoteUpdate result of a quote update
+type QuoteUpdate struct {
+	Kept         []TrackedOrder            // Live orders matching a desired quote
+	Cancelled    []TrackedOrder            // Live orders cancelled
+	Created      []*CreateOrderBatchDetail // Orders created
+	CancelTxHash []string                  // Cancel transaction hashes
+	CreateTxHash string                    // Create transaction hash
+}
+
+// UpdateQuotes diffs desired two-sided quotes against the tracked live orders and sends the minimal set of cancels and
+// creates. Quotes filled or cancelled elsewhere leave the tracker on the next Sync, or as they happen with TrackOrders.
+func (c *AntxClient) UpdateQuotes(subaccountId uint64, exchangeId string, bids []Quote, asks []Quote) (*QuoteUpdate, error) {
+	return c.UpdateQuotesWithConfig(subaccountId, exchangeId, bids, asks, QuoteConfig{})
+}
+
+// UpdateQuotesWithConfig updates quotes like UpdateQuotes with custom order settings
+func (c *AntxClient) UpdateQuotesWithConfig(subaccountId uint64, exchangeId string, bids []Quote, asks []Quote, config QuoteConfig) (*QuoteUpdate, error) {
+	exchange, err := c.GetExchange(exchangeId)
+	if err != nil {
+		return nil, err
+	}
+	exchangeIdValue, err := strconv.ParseUint(exchange.Id, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse exchange ID %s: %w", exchange.Id, err)
+	}
+	if config.MarginMode == exchangetypes.MarginMode_MARGIN_MODE_UNSPECIFIED {
+		config.MarginMode = exchangetypes.MarginMode_MARGIN_MODE_CROSS
+	}
+	if config.Leverage == 0 {
+		config.Leverage = exchange.Perpetual.DefaultLeverage
+		if config.Leverage == 0 {
+			config.Leverage = 1
+		}
+	}
+	if config.TimeInForce == ordertypes.TimeInForce_TIME_IN_FORCE_UNSPECIFIED {
+		config.TimeInForce = ordertypes.TimeInForce_TIME_IN_FORCE_POST_ONLY
+	}
+	if config.ClientOrderIdPrefix == "" {
+		config.ClientOrderIdPrefix = DefaultQuoteClientOrderIdPrefix
+	}
+
+	tracker := c.OrderTracker(subaccountId)
+	if !tracker.Synced() {
+		if err := tracker.Sync(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Only plain resting limit orders are quotes, conditional and reduce-only orders are left alone
+	var liveBids, liveAsks []TrackedOrder
+	for _, order := range tracker.Orders(exchange.Id) {
+		if order.IsConditional || order.ReduceOnly || !order.Price.IsPositive() {
+			continue
+		}
+		if order.IsBuy {
+			liveBids = append(liveBids, order)
+		} else {
+			liveAsks = append(liveAsks, order)
+		}
+	}
+
+	update := &QuoteUpdate{}
+	createBids, err := diffQuotes(update, liveBids, bids)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bid: %w", err)
+	}
+	createAsks, err := diffQuotes(update, liveAsks, asks)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ask: %w", err)
+	}
+
+	// Build creates before sending anything, so that an invalid quote cancels nothing
+	prefix := config.ClientOrderIdPrefix + strconv.FormatInt(time.Now().UnixNano(), 36) + "-"
+	for i, quote := range append(createBids, createAsks...) {
+		priceValue, err := ScaleDecimal(quote.Price, exchange.TickSizeScale)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quote price: %w", err)
+		}
+		sizeValue, err := ScaleDecimal(quote.Size, exchange.StepSizeScale)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quote size: %w", err)
+		}
+		update.Created = append(update.Created, &CreateOrderBatchDetail{
+			IsBuy:         i < len(createBids),
+			PriceScale:    exchange.TickSizeScale,
+			PriceValue:    priceValue,
+			SizeScale:     exchange.StepSizeScale,
+			SizeValue:     sizeValue,
+			ClientOrderId: prefix + strconv.Itoa(i),
+			TimeInForce:   config.TimeInForce,
+		})
+	}
+
+	// Cancel first so that the margin of stale quotes is released for the new ones
+	if err := c.cancelQuotes(tracker, update); err != nil {
+		return update, err
+	}
+
+	if len(update.Created) > 0 {
+		txHash, err := c.CreateOrderBatch(&CreateOrderBatchParam{
+			AgentAddress:     c.GetAgentAddress(),
+			SubaccountId:     subaccountId,
+			ExchangeId:       exchangeIdValue,
+			MarginMode:       config.MarginMode,
+			Leverage:         config.Leverage,
+			CreateOrderParam: update.Created,
+		})
+		if err != nil {
+			return update, fmt.Errorf("failed to create quotes: %w", err)
+		}
+		update.CreateTxHash = txHash
+		for _, order := range update.Created {
+			tracker.TrackSubmitted(exchange.Id, order)
+		}
+	}
+	return update, nil
+}
+
+// diffQuotes matches desired quotes to live orders with the same price and size, unmatched live orders are marked for cancellation
+func diffQuotes(update *QuoteUpdate, live []TrackedOrder, desired []Quote) ([]Quote, error) {
+	matched := make([]bool, len(live))
+	var create []Quote
+	for _, quote := range desired {
+		if !quote.Price.IsPositive() || !quote.Size.IsPositive() {
+			return nil, fmt.Errorf("price %s and size %s must be greater than 0", quote.Price.String(), quote.Size.String())
+		}
+		found := false
+		for i, order := range live {
+			if !matched[i] && order.Price.Equal(quote.Price) && order.Size.Equal(quote.Size) {
+				matched[i] = true
+				found = true
+				update.Kept = append(update.Kept, order)
+				break
+			}
+		}
+		if !found {
+			create = append(create, quote)
+		}
+	}
+	for i, order := range live {
+		if !matched[i] {
+			update.Cancelled = append(update.Cancelled, order)
+		}
+	}
+	return create, nil
+}
+
+// cancelQuotes cancels stale quotes by order ID, or by client order ID while their order ID is not known yet
+func (c *AntxClient) cancelQuotes(tracker *OrderTracker, update *QuoteUpdate) error {
+	var orderIds []uint64
+	var clientOrderIds []string
+	for _, order := range update.Cancelled {
+		if order.OrderId == "" {
+			clientOrderIds = append(clientOrderIds, order.ClientOrderId)
+			continue
+		}
+		orderId, err := strconv.ParseUint(order.OrderId, 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse order ID %s: %w", order.OrderId, err)
+		}
+		orderIds = append(orderIds, orderId)
+	}
+
+	if len(orderIds) > 0 {
+		txHash, err := c.CancelOrder(&types.CancelOrderParam{
+			AgentAddress: c.GetAgentAddress(),
+			SubaccountId: tracker.SubaccountId,
+			OrderIdList:  orderIds,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to cancel quotes: %w", err)
+		}
+		update.CancelTxHash = append(update.CancelTxHash, txHash)
+	}
+	if len(clientOrderIds) > 0 {
+		txHash, err := c.CancelOrderByClientId(&types.CancelOrderByClientIdParam{
+			AgentAddress:      c.GetAgentAddress(),
+			SubaccountId:      tracker.SubaccountId,
+			ClientOrderIdList: clientOrderIds,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to cancel quotes: %w", err)
+		}
+		update.CancelTxHash = append(update.CancelTxHash, txHash)
+	}
+
+	for _, order := range update.Cancelled {
+		tracker.Untrack(order)
+	}
+	return nil
+}
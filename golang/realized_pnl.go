@@ -0,0 +1,171 @@
+package sdk
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// PnlPeriod length of the periods realized PnL is grouped by, in UTC
+type PnlPeriod string
+
+const (
+	PnlPeriodDay   PnlPeriod = "day"   // Calendar day
+	PnlPeriodWeek  PnlPeriod = "week"  // Week starting on Monday
+	PnlPeriodMonth PnlPeriod = "month" // Calendar month
+)
+
+// RealizedPnl realized PnL of a subaccount over one period
+type RealizedPnl struct {
+	Start       time.Time       // Period start, inclusive
+	End         time.Time       // Period end, exclusive
+	RealizedPnl decimal.Decimal // Realized PnL of the closing fills
+	Fees        decimal.Decimal // Fill and liquidation fees, as reported on the fills
+	Funding     decimal.Decimal // Funding settled, negative when paid
+	NetPnl      decimal.Decimal // Realized PnL plus fees and funding
+	Fills       int             // Number of fills
+}
+
+// GetRealizedPnl reads the fills and funding settlements of a subaccount created in [start, end) and aggregates them
+// by period, periods without activity are left out
+func (c *AntxClient) GetRealizedPnl(subaccountId string, groupBy PnlPeriod, start, end time.Time) ([]RealizedPnl, error) {
+	if _, err := pnlPeriodStart(start, groupBy); err != nil {
+		return nil, err
+	}
+	fills, collaterals, err := c.getFillsAndCollaterals(subaccountId, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return AggregateRealizedPnl(fills, collaterals, groupBy)
+}
+
+// getFillsAndCollaterals reads all the fills and collateral transactions of a subaccount created in [start, end)
+func (c *AntxClient) getFillsAndCollaterals(subaccountId string, start, end time.Time) ([]types.OrderFillTransaction, []types.CollateralTransaction, error) {
+	from, to := uint64(start.UnixMilli()), uint64(end.UnixMilli())
+
+	fillReq := types.GetHistoryOrderFillTransactionReq{
+		SubaccountId:                    subaccountId,
+		Size:                            100,
+		FilterStartCreatedTimeInclusive: from,
+		FilterEndCreatedTimeExclusive:   to,
+	}
+	fills, err := types.FetchAllPages(fillReq.Size, func(cursor types.Cursor) ([]types.OrderFillTransaction, types.IndexerPageOffsetData, error) {
+		fillReq.PageOffsetDataCreatedTime, fillReq.PageOffsetDataItemId = cursor.CreatedTime, cursor.ItemId
+		resp, err := c.GetHistoryOrderFillTransaction(fillReq)
+		if err != nil {
+			return nil, types.IndexerPageOffsetData{}, err
+		}
+		return resp.Data.OrderFillTransactionList, resp.Data.PageOffsetData, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	collateralReq := types.GetCollateralTransactionReq{
+		SubaccountId:                    subaccountId,
+		Size:                            100,
+		FilterStartCreatedTimeInclusive: from,
+		FilterEndCreatedTimeExclusive:   to,
+	}
+	collaterals, err := types.FetchAllPages(collateralReq.Size, func(cursor types.Cursor) ([]types.CollateralTransaction, types.IndexerPageOffsetData, error) {
+		collateralReq.PageOffsetDataCreatedTime, collateralReq.PageOffsetDataItemId = cursor.CreatedTime, cursor.ItemId
+		resp, err := c.GetCollateralTransaction(collateralReq)
+		if err != nil {
+			return nil, types.IndexerPageOffsetData{}, err
+		}
+		return resp.Data.CollateralTransactionList, resp.Data.PageOffsetData, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return fills, collaterals, nil
+}
+
+// AggregateRealizedPnl groups the realized PnL and fees of fills and the funding settlements among collateral
+// transactions by period, oldest first
+func AggregateRealizedPnl(fills []types.OrderFillTransaction, collaterals []types.CollateralTransaction, groupBy PnlPeriod) ([]RealizedPnl, error) {
+	periods := make(map[time.Time]*RealizedPnl)
+	period := func(createdTime uint64) (*RealizedPnl, error) {
+		start, err := pnlPeriodStart(time.UnixMilli(int64(createdTime)), groupBy)
+		if err != nil {
+			return nil, err
+		}
+		p, ok := periods[start]
+		if !ok {
+			p = &RealizedPnl{Start: start, End: pnlPeriodEnd(start, groupBy)}
+			periods[start] = p
+		}
+		return p, nil
+	}
+
+	for _, fill := range fills {
+		var values [3]decimal.Decimal
+		for i, value := range []string{fill.RealizePnl, fill.FillFee, fill.LiquidateFee} {
+			var err error
+			if values[i], err = parseOptionalDecimal(value); err != nil {
+				return nil, fmt.Errorf("failed to parse fill %s: %w", fill.Id, err)
+			}
+		}
+		p, err := period(fill.CreatedTime)
+		if err != nil {
+			return nil, err
+		}
+		p.RealizedPnl = p.RealizedPnl.Add(values[0])
+		p.Fees = p.Fees.Add(values[1]).Add(values[2])
+		p.Fills++
+	}
+	for _, tx := range collaterals {
+		if tx.FundingTime == 0 {
+			continue
+		}
+		delta, err := parseOptionalDecimal(tx.DeltaAmount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse collateral transaction %s: %w", tx.Id, err)
+		}
+		p, err := period(tx.CreatedTime)
+		if err != nil {
+			return nil, err
+		}
+		p.Funding = p.Funding.Add(delta)
+	}
+
+	result := make([]RealizedPnl, 0, len(periods))
+	for _, p := range periods {
+		p.NetPnl = p.RealizedPnl.Add(p.Fees).Add(p.Funding)
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Start.Before(result[j].Start) })
+	return result, nil
+}
+
+// pnlPeriodStart returns the start of the period containing t, in UTC
+func pnlPeriodStart(t time.Time, period PnlPeriod) (time.Time, error) {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case PnlPeriodDay:
+		return day, nil
+	case PnlPeriodWeek:
+		// Weekday counts from Sunday
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7), nil
+	case PnlPeriodMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown PnL period %q", period)
+	}
+}
+
+// pnlPeriodEnd returns the end of the period starting at start
+func pnlPeriodEnd(start time.Time, period PnlPeriod) time.Time {
+	switch period {
+	case PnlPeriodWeek:
+		return start.AddDate(0, 0, 7)
+	case PnlPeriodMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
@@ -0,0 +1,183 @@
+package sdk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/antxprotocol/antx-sdk-golang/query"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	ethCrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+// Reload applies a new configuration to a live client, so long-running services pick up configuration changes without a
+// restart. The gateway address and its fallbacks, API prefix, path overrides and credentials, the User-Agent tag, the
+// custom headers, request and response compression, the simulator and gas settings, the fee and its granter, the authz
+// granter, the risk limits, read-only mode, the chat notifier tokens and the agent key are replaced, as are the
+// WebSocket address, the HTTP client, its timeout, its transport, the retry policy, the rate limiter, the mock
+// transport and the logger when set. Without an HTTP client or a mock transport the gateway requests go through a new
+// default HTTP client, ending a previous mock transport. The chain ID and the ETH key cannot change and may be left
+// empty. Deduplication settings only apply to new clients.
+//
+// The configuration is validated, and the account number of a new agent fetched, before anything is applied. Reload
+// then waits for the transactions being signed and sent to complete, and holds new ones until it returns, so no
+// transaction mixes the previous and the new agent or gas settings. Connected WebSockets stay on the previous gateway
+// until they reconnect.
+func (c *AntxClient) Reload(config Config) error {
+	if config.ChainID != "" && config.ChainID != c.chainID {
+		return fmt.Errorf("chain ID cannot be changed from %s to %s", c.chainID, config.ChainID)
+	}
+	var err error
+	if config.EthPrivateKey, err = resolveKey(config.EthPrivateKey, config.EthKeyProvider, "eth"); err != nil {
+		return err
+	}
+	if config.EthPrivateKey != "" {
+		ethPrivateKey, err := ethCrypto.HexToECDSA(strings.TrimPrefix(config.EthPrivateKey, "0x"))
+		if err != nil {
+			return fmt.Errorf("failed to decode eth private key: %w", err)
+		}
+		if ethCrypto.PubkeyToAddress(ethPrivateKey.PublicKey) != c.ethAddress {
+			return fmt.Errorf("eth private key cannot be changed")
+		}
+	}
+	if config.AgentPrivateKey, err = resolveKey(config.AgentPrivateKey, config.AgentKeyProvider, "agent"); err != nil {
+		return err
+	}
+	if config.AgentPrivateKey == "" && config.Signer == nil {
+		return fmt.Errorf("agent private key cannot be empty")
+	}
+	if config.GatewayHost == "" && config.MockTransport == nil {
+		return fmt.Errorf("gateway host cannot be empty, set MockTransport to run without a gateway")
+	}
+	for i, endpoint := range config.FallbackGateways {
+		if endpoint.BaseURL == "" {
+			return fmt.Errorf("fallback gateway %d has no base URL", i)
+		}
+	}
+	if config.Transport != nil {
+		if _, err := query.NewTransport(*config.Transport); err != nil {
+			return err
+		}
+	}
+	if config.SimulateBeforeSend && config.Simulator == nil {
+		return fmt.Errorf("simulate before send requires a simulator")
+	}
+	if config.GasAdjustment <= 0 {
+		config.GasAdjustment = DefaultGasAdjustment
+	}
+	feeAmount, err := sdk.ParseCoinsNormalized(config.Fee)
+	if err != nil {
+		return fmt.Errorf("invalid fee %q: %w", config.Fee, err)
+	}
+	var feeGranter sdk.AccAddress
+	if config.FeeGranter != "" {
+		if feeGranter, err = parseAddress(config.FeeGranter); err != nil {
+			return fmt.Errorf("invalid fee granter: %w", err)
+		}
+	}
+	var authzGranter sdk.AccAddress
+	if config.AuthzGranter != "" {
+		if authzGranter, err = parseAddress(config.AuthzGranter); err != nil {
+			return fmt.Errorf("invalid authz granter: %w", err)
+		}
+	}
+	signer, err := agentSigner(config)
+	if err != nil {
+		return err
+	}
+
+	// A new agent has its own account number, fetched from the new gateway before the old agent stops signing
+	rotate := !signer.Address().Equals(c.agentAddress)
+	accountNumber := c.accountNumber
+	if rotate {
+		gateway := query.NewClient(config.GatewayHost, "")
+		gateway.SetAPIPrefix(config.APIPrefix)
+		gateway.SetAPICredentials(query.APICredentials{Key: config.APIKey, Secret: config.APISecret})
+		gateway.SetUserAgentTag(config.UserAgentTag)
+		gateway.SetHeaders(config.Headers)
+		gateway.SetLogger(orLogx(config.Logger))
+		gateway.ResetPathOverrides(config.PathOverrides)
+		if config.MockTransport != nil {
+			gateway.SetMockTransport(config.MockTransport)
+		}
+		number, _, err := gateway.GetAccountNumberAndSequence(signer.Address().String())
+		if err != nil {
+			return fmt.Errorf("failed to get account number and sequence: %w", err)
+		}
+		if accountNumber, err = strconv.ParseUint(number, 10, 64); err != nil {
+			return fmt.Errorf("failed to parse account number: %w", err)
+		}
+	}
+
+	c.txMu.Lock()
+	defer c.txMu.Unlock()
+	_, wsURL := c.Gateway()
+	if config.WebSocketURL != "" {
+		wsURL = config.WebSocketURL
+	}
+	c.SetGateway(config.GatewayHost, wsURL)
+	c.gatewayHost = config.GatewayHost
+	failover := failoverConfig(config)
+	if len(failover.Endpoints) > 0 {
+		failover.Endpoints[0].WsURL = wsURL
+	}
+	if err := c.SetFailover(failover); err != nil {
+		return err
+	}
+	c.SetAPIPrefix(config.APIPrefix)
+	c.SetAPICredentials(query.APICredentials{Key: config.APIKey, Secret: config.APISecret})
+	c.SetUserAgentTag(config.UserAgentTag)
+	c.SetHeaders(config.Headers)
+	c.SetRequestCompression(config.CompressRequestsAbove)
+	c.SetResponseCompression(!config.DisableResponseCompression)
+	if config.Logger != nil {
+		c.SetLogger(config.Logger)
+	}
+	if config.HTTPClient != nil {
+		c.SetHTTPClient(config.HTTPClient)
+	} else if config.MockTransport == nil {
+		// A client started on a mock transport must not keep answering from it once reloaded for a real gateway
+		c.SetHTTPClient(nil)
+	}
+	if config.HTTPTimeout > 0 {
+		c.SetHTTPTimeout(config.HTTPTimeout)
+	}
+	if config.Transport != nil {
+		if err := c.SetTransport(*config.Transport); err != nil {
+			return err
+		}
+	}
+	if config.RetryPolicy != nil {
+		c.SetRetry(*config.RetryPolicy)
+	}
+	if config.RateLimiter != nil {
+		c.SetRateLimiter(config.RateLimiter)
+	}
+	if config.MockTransport != nil {
+		c.SetMockTransport(config.MockTransport)
+	}
+	c.ResetPathOverrides(config.PathOverrides)
+
+	if rotate {
+		c.Logger().Infof("agent rotated from %s to %s", c.agentAddress.String(), signer.Address().String())
+	}
+	c.signer = signer
+	c.agentAddress = signer.Address()
+	c.accountNumber = accountNumber
+	c.readOnly = config.ReadOnly
+	c.simulator = config.Simulator
+	c.simulateBeforeSend = config.SimulateBeforeSend
+	c.gasAdjustment = config.GasAdjustment
+	c.gasLimits = config.GasLimits
+	c.feeAmount = feeAmount
+	c.feeGranter = feeGranter
+	c.authzGranter = authzGranter
+	c.SetRiskLimits(config.RiskLimits)
+	c.chat = chatTokens{
+		TelegramBotToken:  config.TelegramBotToken,
+		TelegramChatId:    config.TelegramChatId,
+		SlackWebhookURL:   config.SlackWebhookURL,
+		DiscordWebhookURL: config.DiscordWebhookURL,
+	}
+	return nil
+}
@@ -0,0 +1,360 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	exchangetypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/exchange"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+// ErrRiskLimit an order was rejected locally by the pre-trade risk limits
+var ErrRiskLimit = errors.New("risk limit exceeded")
+
+// DefaultRiskStateMaxAge default time the account state and mark prices read by the risk checks are reused
+const DefaultRiskStateMaxAge = 2 * time.Second
+
+// RiskLimits pre-trade limits checked by CreateOrder and CreateOrderBatch before signing, zero values disable a limit
+type RiskLimits struct {
+	MaxOpenOrders          int             // Maximum active orders of a subaccount including the new ones
+	MaxNotionalPerExchange decimal.Decimal // Maximum position plus active order notional on one exchange after the new orders, at the mark price
+	MaxLeverage            decimal.Decimal // Maximum notional of the cross positions and orders over the cross equity, as in ComputeMarginSummary
+	BannedExchanges        []string        // Exchanges no order is sent to
+	StateMaxAge            time.Duration   // Time the active orders, account asset and mark prices read by the checks are reused, defaults to DefaultRiskStateMaxAge, negative reads them for every check
+}
+
+// riskOrder order as seen by the risk checks
+type riskOrder struct {
+	exchangeId string
+	reduceOnly bool
+	isMarket   bool
+	isolated   bool
+	price      decimal.Decimal
+	size       decimal.Decimal
+}
+
+// riskState account state of a subaccount read by the risk checks, the orders accepted since are added to its orders
+// so that they count against the limits until it is read again
+type riskState struct {
+	orders []riskOrder
+	asset  types.GetPerpetualAccountAssetRespData
+	at     time.Time
+}
+
+// riskMark mark price of an exchange read by the risk checks
+type riskMark struct {
+	price decimal.Decimal
+	at    time.Time
+}
+
+// SetRiskLimits enables the pre-trade risk checks of order creation, nil disables them
+func (c *AntxClient) SetRiskLimits(limits *RiskLimits) {
+	c.riskMu.Lock()
+	defer c.riskMu.Unlock()
+	c.riskLimits = limits
+}
+
+// RiskLimits returns the pre-trade risk limits, nil when disabled
+func (c *AntxClient) RiskLimits() *RiskLimits {
+	c.riskMu.Lock()
+	defer c.riskMu.Unlock()
+	return c.riskLimits
+}
+
+// CheckOrderRisk checks orders against the risk limits, a rejection wraps ErrRiskLimit
+func (c *AntxClient) CheckOrderRisk(orders ...*CreateOrderParam) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	pending := make([]riskOrder, 0, len(orders))
+	for _, order := range orders {
+		pending = append(pending, riskOrder{
+			exchangeId: strconv.FormatUint(order.ExchangeId, 10),
+			reduceOnly: order.ReduceOnly,
+			isMarket:   order.IsMarket,
+			isolated:   order.MarginMode == exchangetypes.MarginMode_MARGIN_MODE_ISOLATED,
+			price:      UnscaleDecimal(order.PriceValue, order.PriceScale),
+			size:       UnscaleDecimal(order.SizeValue, order.SizeScale),
+		})
+	}
+	return c.checkRisk(strconv.FormatUint(orders[0].SubaccountId, 10), pending)
+}
+
+// checkBatchRisk checks the orders of a batch against the risk limits
+func (c *AntxClient) checkBatchRisk(orders *CreateOrderBatchParam) error {
+	exchangeId := strconv.FormatUint(orders.ExchangeId, 10)
+	pending := make([]riskOrder, 0, len(orders.CreateOrderParam))
+	for _, order := range orders.CreateOrderParam {
+		pending = append(pending, riskOrder{
+			exchangeId: exchangeId,
+			reduceOnly: order.ReduceOnly,
+			isMarket:   order.IsMarket,
+			isolated:   orders.MarginMode == exchangetypes.MarginMode_MARGIN_MODE_ISOLATED,
+			price:      UnscaleDecimal(order.PriceValue, order.PriceScale),
+			size:       UnscaleDecimal(order.SizeValue, order.SizeScale),
+		})
+	}
+	return c.checkRisk(strconv.FormatUint(orders.SubaccountId, 10), pending)
+}
+
+// checkRisk checks new orders of a subaccount against the risk limits and publishes rejections as alerts
+func (c *AntxClient) checkRisk(subaccountId string, pending []riskOrder) error {
+	err := c.checkRiskLimits(subaccountId, pending)
+	if errors.Is(err, ErrRiskLimit) {
+		exchangeId := ""
+		if len(pending) > 0 {
+			exchangeId = pending[0].exchangeId
+		}
+		c.publish(TopicAlert, subaccountId, exchangeId, Alert{Kind: AlertRiskLimit, Message: err.Error(), Data: err})
+	}
+	return err
+}
+
+// checkRiskLimits checks new orders of a subaccount against the risk limits, reading the account only for the limits set
+func (c *AntxClient) checkRiskLimits(subaccountId string, pending []riskOrder) error {
+	limits := c.RiskLimits()
+	if limits == nil {
+		return nil
+	}
+	for _, order := range pending {
+		for _, banned := range limits.BannedExchanges {
+			if order.exchangeId == banned {
+				return fmt.Errorf("exchange %s is banned: %w", banned, ErrRiskLimit)
+			}
+		}
+	}
+	if limits.MaxOpenOrders <= 0 && !limits.MaxNotionalPerExchange.IsPositive() && !limits.MaxLeverage.IsPositive() {
+		return nil
+	}
+	maxAge := limits.StateMaxAge
+	if maxAge == 0 {
+		maxAge = DefaultRiskStateMaxAge
+	}
+
+	state, err := c.riskAccountState(subaccountId, maxAge)
+	if err != nil {
+		return err
+	}
+	if limits.MaxOpenOrders > 0 && len(state.orders)+len(pending) > limits.MaxOpenOrders {
+		return fmt.Errorf("%d active and %d new orders exceed %d open orders: %w",
+			len(state.orders), len(pending), limits.MaxOpenOrders, ErrRiskLimit)
+	}
+	if limits.MaxNotionalPerExchange.IsPositive() || limits.MaxLeverage.IsPositive() {
+		if err := c.checkRiskNotional(subaccountId, limits, state, pending, maxAge); err != nil {
+			return err
+		}
+	}
+	c.addRiskOrders(subaccountId, state, pending)
+	return nil
+}
+
+// checkRiskNotional checks the notional per exchange and the leverage of a subaccount after new orders
+func (c *AntxClient) checkRiskNotional(subaccountId string, limits *RiskLimits, state *riskState, pending []riskOrder, maxAge time.Duration) error {
+	orders := append(append([]riskOrder(nil), pending...), state.orders...)
+	exchangeIds := make([]string, 0, len(orders)+len(state.asset.PositionList))
+	for _, order := range orders {
+		exchangeIds = append(exchangeIds, order.exchangeId)
+	}
+	for _, position := range state.asset.PositionList {
+		exchangeIds = append(exchangeIds, position.ExchangeId)
+	}
+	marks, err := c.riskMarkPrices(exchangeIds, maxAge)
+	if err != nil {
+		return fmt.Errorf("failed to get mark prices for risk check: %w", err)
+	}
+
+	notional := make(map[string]decimal.Decimal)
+	cross := decimal.Zero
+	for _, position := range state.asset.PositionList {
+		size, err := parseOptionalDecimal(position.OpenSize)
+		if err != nil {
+			return fmt.Errorf("failed to parse position of exchange %s: %w", position.ExchangeId, err)
+		}
+		value := size.Abs().Mul(marks[position.ExchangeId])
+		notional[position.ExchangeId] = notional[position.ExchangeId].Add(value)
+		if position.MarginMode != uint32(exchangetypes.MarginMode_MARGIN_MODE_ISOLATED) {
+			cross = cross.Add(value)
+		}
+	}
+	for _, order := range orders {
+		// Reduce-only orders can only lower the exposure
+		if order.reduceOnly {
+			continue
+		}
+		price := order.price
+		if order.isMarket || price.IsZero() {
+			price = marks[order.exchangeId]
+		}
+		value := order.size.Mul(price)
+		notional[order.exchangeId] = notional[order.exchangeId].Add(value)
+		if !order.isolated {
+			cross = cross.Add(value)
+		}
+	}
+
+	if limits.MaxNotionalPerExchange.IsPositive() {
+		for exchangeId, value := range notional {
+			if value.GreaterThan(limits.MaxNotionalPerExchange) {
+				return fmt.Errorf("notional %s on exchange %s exceeds %s: %w",
+					value.StringFixed(2), exchangeId, limits.MaxNotionalPerExchange, ErrRiskLimit)
+			}
+		}
+	}
+	if limits.MaxLeverage.IsPositive() {
+		equity, err := accountEquity(&state.asset, marks)
+		if err != nil {
+			return err
+		}
+		if !equity.IsPositive() {
+			return fmt.Errorf("subaccount %s has no equity: %w", subaccountId, ErrRiskLimit)
+		}
+		if leverage := cross.Div(equity); leverage.GreaterThan(limits.MaxLeverage) {
+			return fmt.Errorf("leverage %s exceeds %s: %w", leverage.StringFixed(2), limits.MaxLeverage, ErrRiskLimit)
+		}
+	}
+	return nil
+}
+
+// riskAccountState returns the active orders and the account asset of a subaccount, reading them again when the ones
+// read before are older than maxAge
+func (c *AntxClient) riskAccountState(subaccountId string, maxAge time.Duration) (*riskState, error) {
+	c.riskMu.Lock()
+	cached, ok := c.riskStates[subaccountId]
+	if ok && time.Since(cached.at) < maxAge {
+		state := &riskState{orders: append([]riskOrder(nil), cached.orders...), asset: cached.asset, at: cached.at}
+		c.riskMu.Unlock()
+		return state, nil
+	}
+	c.riskMu.Unlock()
+
+	active, err := c.getAllActiveOrders(subaccountId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active orders for risk check: %w", err)
+	}
+	asset, err := c.GetPerpetualAccountAsset(types.GetPerpetualAccountAssetReq{SubaccountId: subaccountId})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account asset for risk check: %w", err)
+	}
+	state := &riskState{orders: make([]riskOrder, 0, len(active)), asset: asset.Data, at: time.Now()}
+	for _, order := range active {
+		var values [3]decimal.Decimal
+		for i, value := range []string{order.Price, order.Size, order.CumFillSize} {
+			if values[i], err = parseOptionalDecimal(value); err != nil {
+				return nil, fmt.Errorf("failed to parse order %s: %w", order.Id, err)
+			}
+		}
+		state.orders = append(state.orders, riskOrder{
+			exchangeId: order.ExchangeId,
+			reduceOnly: order.ReduceOnly,
+			isMarket:   values[0].IsZero(),
+			isolated:   order.MarginMode == uint32(exchangetypes.MarginMode_MARGIN_MODE_ISOLATED),
+			price:      values[0],
+			size:       values[1].Sub(values[2]),
+		})
+	}
+	if maxAge > 0 {
+		c.riskMu.Lock()
+		if c.riskStates == nil {
+			c.riskStates = make(map[string]*riskState)
+		}
+		c.riskStates[subaccountId] = &riskState{orders: append([]riskOrder(nil), state.orders...), asset: state.asset, at: state.at}
+		c.riskMu.Unlock()
+	}
+	return state, nil
+}
+
+// addRiskOrders adds orders that passed the checks to the cached state they were checked against
+func (c *AntxClient) addRiskOrders(subaccountId string, state *riskState, pending []riskOrder) {
+	c.riskMu.Lock()
+	defer c.riskMu.Unlock()
+	if cached, ok := c.riskStates[subaccountId]; ok && cached.at.Equal(state.at) {
+		cached.orders = append(cached.orders, pending...)
+	}
+}
+
+// riskMarkPrices returns the mark prices of exchanges from their tickers, reading again those older than maxAge
+func (c *AntxClient) riskMarkPrices(exchangeIds []string, maxAge time.Duration) (map[string]decimal.Decimal, error) {
+	marks := make(map[string]decimal.Decimal, len(exchangeIds))
+	var missing []string
+	c.riskMu.Lock()
+	for _, exchangeId := range exchangeIds {
+		if mark, ok := c.riskMarks[exchangeId]; ok && time.Since(mark.at) < maxAge {
+			marks[exchangeId] = mark.price
+		} else {
+			missing = append(missing, exchangeId)
+		}
+	}
+	c.riskMu.Unlock()
+	if len(missing) == 0 {
+		return marks, nil
+	}
+
+	read, err := c.markPrices(missing)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	c.riskMu.Lock()
+	defer c.riskMu.Unlock()
+	if c.riskMarks == nil {
+		c.riskMarks = make(map[string]riskMark)
+	}
+	for exchangeId, price := range read {
+		marks[exchangeId] = price
+		if maxAge > 0 {
+			c.riskMarks[exchangeId] = riskMark{price: price, at: now}
+		}
+	}
+	return marks, nil
+}
+
+// markPrices gets the mark prices of exchanges from their tickers
+func (c *AntxClient) markPrices(exchangeIds []string) (map[string]decimal.Decimal, error) {
+	marks := make(map[string]decimal.Decimal, len(exchangeIds))
+	for _, exchangeId := range exchangeIds {
+		if _, ok := marks[exchangeId]; ok {
+			continue
+		}
+		resp, err := c.GetTicker(types.GetTickerReq{ExchangeId: exchangeId})
+		if err != nil {
+			return nil, err
+		}
+		if len(resp.Data.TickerList) == 0 {
+			return nil, fmt.Errorf("no ticker for exchange %s", exchangeId)
+		}
+		mark, err := decimal.NewFromString(resp.Data.TickerList[0].MarkPrice)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse mark price of exchange %s: %w", exchangeId, err)
+		}
+		marks[exchangeId] = mark
+	}
+	return marks, nil
+}
+
+// accountEquity returns the cross equity of a subaccount at mark prices, as the CrossEquity of ComputeMarginSummary.
+// Opening a position books its open value against the collateral, so the equity is the collateral plus the mark value of
+// the cross positions. Isolated positions are margined by their own collateral and left out.
+func accountEquity(asset *types.GetPerpetualAccountAssetRespData, marks map[string]decimal.Decimal) (decimal.Decimal, error) {
+	equity := decimal.Zero
+	for _, collateral := range asset.CollateralList {
+		amount, err := parseOptionalDecimal(collateral.Amount)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("failed to parse collateral of coin %s: %w", collateral.CoinId, err)
+		}
+		equity = equity.Add(amount)
+	}
+	for _, position := range asset.PositionList {
+		if position.MarginMode == uint32(exchangetypes.MarginMode_MARGIN_MODE_ISOLATED) {
+			continue
+		}
+		size, err := parseOptionalDecimal(position.OpenSize)
+		if err != nil {
+			return decimal.Zero, fmt.Errorf("failed to parse position of exchange %s: %w", position.ExchangeId, err)
+		}
+		equity = equity.Add(size.Mul(marks[position.ExchangeId]))
+	}
+	return equity, nil
+}
@@ -0,0 +1,158 @@
+package sdk
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule returns the activation times of a recurring job
+type Schedule interface {
+	// Next returns the first activation strictly after the given time, or the zero time when there is none
+	Next(after time.Time) time.Time
+}
+
+// Every schedule firing at a fixed interval, aligned to multiples of the interval since the Unix epoch
+type Every time.Duration
+
+// Next returns the next multiple of the interval after the given time
+func (e Every) Next(after time.Time) time.Time {
+	interval := time.Duration(e)
+	if interval <= 0 {
+		return time.Time{}
+	}
+	return after.Truncate(interval).Add(interval)
+}
+
+// cronField allowed values of one cron field as a bit set
+type cronField uint64
+
+// cronSchedule schedule parsed from a cron expression
+type cronSchedule struct {
+	minute, hour, dom, month, dow cronField
+	anyDom, anyDow                bool
+	location                      *time.Location
+}
+
+// cronBounds bounds of the minute, hour, day of month, month and day of week fields
+var cronBounds = [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+
+// ParseCron parses a standard five-field cron expression "minute hour day-of-month month day-of-week" evaluated in
+// the given location, UTC when nil. Fields accept *, values, ranges a-b, lists a,b and steps */n or a-b/n; day of week
+// 0 and 7 are Sunday. As in cron, a job whose day of month and day of week are both restricted runs when either matches.
+func ParseCron(expr string, location *time.Location) (Schedule, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q: expected 5 fields, got %d", expr, len(fields))
+	}
+	var parsed [5]cronField
+	for i, field := range fields {
+		bits, err := parseCronField(field, cronBounds[i][0], cronBounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", expr, err)
+		}
+		parsed[i] = bits
+	}
+	if location == nil {
+		location = time.UTC
+	}
+	dow := parsed[4]
+	if dow&(1<<7) != 0 {
+		dow |= 1
+	}
+	return &cronSchedule{
+		minute:   parsed[0],
+		hour:     parsed[1],
+		dom:      parsed[2],
+		month:    parsed[3],
+		dow:      dow,
+		anyDom:   fields[2] == "*" || fields[2] == "?",
+		anyDow:   fields[4] == "*" || fields[4] == "?",
+		location: location,
+	}, nil
+}
+
+// parseCronField parses a comma-separated cron field into a bit set of values within [min, max]
+func parseCronField(field string, min, max int) (cronField, error) {
+	var bits cronField
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:i], n
+		}
+		lo, hi := min, max
+		switch {
+		case rangePart == "*" || rangePart == "?":
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err1, err2 error
+			lo, err1 = strconv.Atoi(bounds[0])
+			hi, err2 = strconv.Atoi(bounds[1])
+			if err1 != nil || err2 != nil {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+		default:
+			v, err := strconv.Atoi(rangePart)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", part)
+			}
+			lo = v
+			if step == 1 {
+				hi = v
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return 0, fmt.Errorf("value %q out of range [%d, %d]", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// has reports whether the value is allowed
+func (f cronField) has(v int) bool {
+	return f&(1<<uint(v)) != 0
+}
+
+// Next returns the first matching minute strictly after the given time, searching up to five years ahead
+func (s *cronSchedule) Next(after time.Time) time.Time {
+	t := after.In(s.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		if !s.month.has(int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.hour.has(t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.location)
+			continue
+		}
+		if !s.minute.has(t.Minute()) {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches applies the cron rule combining the day of month and day of week fields
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom.has(t.Day())
+	dowMatch := s.dow.has(int(t.Weekday()))
+	if s.anyDom || s.anyDow {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,145 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+)
+
+const (
+	// DefaultServerClockSamples default number of recent samples the server clock offset is estimated from
+	DefaultServerClockSamples = 16
+	// DefaultServerClockMaxAge default age after which a sample no longer counts
+	DefaultServerClockMaxAge = 10 * time.Minute
+)
+
+// ServerClock estimates the offset of the gateway clock from the local clock, NTP style: each sample compares a
+// server timestamp with the midpoint of its round trip, and the sample with the shortest round trip wins. The zero
+// value is ready to use.
+type ServerClock struct {
+	mu      sync.Mutex
+	samples []clockSample
+}
+
+// clockSample one offset measurement
+type clockSample struct {
+	offset     time.Duration
+	roundTrip  time.Duration
+	receivedAt time.Time
+}
+
+// Observe records a server timestamp received in response to a request sent at sentAt and answered at receivedAt
+func (k *ServerClock) Observe(sentAt, receivedAt, serverTime time.Time) {
+	roundTrip := receivedAt.Sub(sentAt)
+	if roundTrip < 0 || serverTime.IsZero() {
+		return
+	}
+	sample := clockSample{
+		offset:     serverTime.Sub(sentAt.Add(roundTrip / 2)),
+		roundTrip:  roundTrip,
+		receivedAt: receivedAt,
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.samples = append(k.samples, sample)
+	if len(k.samples) > DefaultServerClockSamples {
+		k.samples = k.samples[len(k.samples)-DefaultServerClockSamples:]
+	}
+}
+
+// Offset returns the estimated server time minus local time and its uncertainty, half the round trip of the sample
+// used. ok is false when no recent sample exists.
+func (k *ServerClock) Offset() (offset, uncertainty time.Duration, ok bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	var best *clockSample
+	for i := range k.samples {
+		sample := &k.samples[i]
+		if time.Since(sample.receivedAt) > DefaultServerClockMaxAge {
+			continue
+		}
+		if best == nil || sample.roundTrip < best.roundTrip {
+			best = sample
+		}
+	}
+	if best == nil {
+		return 0, 0, false
+	}
+	return best.offset, best.roundTrip / 2, true
+}
+
+// Now returns the estimated server time, the local time when no recent sample exists
+func (k *ServerClock) Now() time.Time {
+	offset, _, _ := k.Offset()
+	return time.Now().Add(offset)
+}
+
+// ServerClock returns the server clock estimator of the client, fed by the transactions it sends and by
+// SyncServerTime
+func (c *AntxClient) ServerClock() *ServerClock {
+	return &c.serverClock
+}
+
+// ServerNow returns the estimated gateway time, the local time until the offset was measured
+func (c *AntxClient) ServerNow() time.Time {
+	return c.serverClock.Now()
+}
+
+// SyncServerTime measures the gateway clock offset with a few lightweight requests and returns the estimate, call it
+// at startup on hosts whose clock may drift
+func (c *AntxClient) SyncServerTime() (time.Duration, error) {
+	const probes = 3
+	var lastErr error
+	for i := 0; i < probes; i++ {
+		var envelope struct {
+			ResponseTime string `json:"responseTime"`
+		}
+		sentAt := time.Now()
+		meta, err := c.DoWithMeta(context.Background(), http.MethodGet, constants.GetCoinListPath, nil, nil, &envelope)
+		receivedAt := time.Now()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		serverTime, ok := parseServerTime(envelope.ResponseTime, meta.Header)
+		if !ok {
+			lastErr = fmt.Errorf("gateway response carries no server time")
+			continue
+		}
+		c.serverClock.Observe(sentAt, receivedAt, serverTime)
+	}
+	offset, _, ok := c.serverClock.Offset()
+	if !ok {
+		return 0, fmt.Errorf("failed to measure server time: %w", lastErr)
+	}
+	return offset, nil
+}
+
+// ExpireTimeAfter returns the millisecond ExpireTime of an order living ttl from now in server time
+func (c *AntxClient) ExpireTimeAfter(ttl time.Duration) uint64 {
+	return uint64(c.ServerNow().Add(ttl).UnixMilli())
+}
+
+// BindAgentTimes returns the millisecond CreateTime and ExpireTime of a BindAgent message valid for ttl from now in
+// server time
+func (c *AntxClient) BindAgentTimes(ttl time.Duration) (createTime, expireTime uint64) {
+	now := c.ServerNow()
+	return uint64(now.UnixMilli()), uint64(now.Add(ttl).UnixMilli())
+}
+
+// parseServerTime reads the millisecond responseTime of a gateway envelope, falling back to the second resolution
+// Date header
+func parseServerTime(responseTime string, header http.Header) (time.Time, bool) {
+	if ms, err := strconv.ParseInt(responseTime, 10, 64); err == nil && ms > 0 {
+		return time.UnixMilli(ms), true
+	}
+	if date, err := http.ParseTime(header.Get("Date")); err == nil {
+		return date, true
+	}
+	return time.Time{}, false
+}
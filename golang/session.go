@@ -0,0 +1,320 @@
+package sdk
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/store"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	ethCrypto "github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	// DefaultSessionTTL default binding lifetime of a session key
+	DefaultSessionTTL = 24 * time.Hour
+	// DefaultSessionRenewBefore default time before expiry at which a session key is rebound
+	DefaultSessionRenewBefore = time.Hour
+	// sessionRetryInterval delay before retrying a failed rebind
+	sessionRetryInterval = time.Minute
+)
+
+// SessionConfig session key manager configuration
+type SessionConfig struct {
+	Config       Config        // Client configuration of the owner, AgentPrivateKey is replaced by the session key
+	TTL          time.Duration // Binding lifetime of a session key, defaults to DefaultSessionTTL
+	RenewBefore  time.Duration // Rebind this long before expiry, defaults to DefaultSessionRenewBefore
+	KeyPath      string        // File holding the encrypted session key, the key is not persisted when empty
+	Store        store.Store   // Store holding the encrypted session key instead of KeyPath
+	Passphrase   string        // Passphrase encrypting the session key, required with KeyPath or Store
+	ErrorHandler func(error)   // Called on renewal errors, errors are logged when nil
+}
+
+// sessionFile persisted session key
+type sessionFile struct {
+	AgentAddress string              `json:"agentAddress"` // Agent address of the session key
+	OwnerAddress string              `json:"ownerAddress"` // Owner the key is bound to
+	ChainId      string              `json:"chainId"`      // Chain ID
+	ExpireTime   int64               `json:"expireTime"`   // Binding expiration time, unit: milliseconds
+	Crypto       keystore.CryptoJSON `json:"crypto"`       // Encrypted agent private key
+}
+
+// SessionManager runs the lifecycle of an ephemeral agent key: it generates the key, binds it with a TTL, persists it encrypted,
+// rebinds it before expiry and unbinds it on Stop
+type SessionManager struct {
+	config       SessionConfig
+	ownerAddress string
+
+	mu         sync.RWMutex
+	client     *AntxClient
+	agentKey   string
+	expireTime time.Time
+
+	done chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// NewSessionManager creates a session key manager
+func NewSessionManager(config SessionConfig) (*SessionManager, error) {
+	var err error
+	if config.Config.EthPrivateKey, err = resolveKey(config.Config.EthPrivateKey, config.Config.EthKeyProvider, "eth"); err != nil {
+		return nil, err
+	}
+	if config.Config.EthPrivateKey == "" {
+		return nil, fmt.Errorf("eth private key cannot be empty")
+	}
+	if config.TTL <= 0 {
+		config.TTL = DefaultSessionTTL
+	}
+	if config.RenewBefore <= 0 {
+		config.RenewBefore = DefaultSessionRenewBefore
+	}
+	if config.RenewBefore >= config.TTL {
+		return nil, fmt.Errorf("renew before %s must be shorter than the TTL %s", config.RenewBefore, config.TTL)
+	}
+	if (config.KeyPath != "" || config.Store != nil) && config.Passphrase == "" {
+		return nil, fmt.Errorf("passphrase is required to persist the session key")
+	}
+	ownerKey, err := ethCrypto.HexToECDSA(strings.TrimPrefix(config.Config.EthPrivateKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode eth private key: %w", err)
+	}
+	return &SessionManager{
+		config:       config,
+		ownerAddress: ethCrypto.PubkeyToAddress(ownerKey.PublicKey).Hex(),
+		done:         make(chan struct{}),
+	}, nil
+}
+
+// Start resumes the persisted session key or generates and binds a new one, then keeps it bound until Stop is called
+func (m *SessionManager) Start() error {
+	agentKey, expireTime, err := m.load()
+	if err != nil {
+		return err
+	}
+	if agentKey == "" {
+		key := secp256k1.GenPrivKey()
+		agentKey = hex.EncodeToString(key.Key)
+	}
+	if err := m.use(agentKey, expireTime); err != nil {
+		return err
+	}
+	if time.Until(expireTime) <= m.config.RenewBefore {
+		if err := m.renew(); err != nil {
+			return err
+		}
+	}
+
+	m.wg.Add(1)
+	go m.run()
+	return nil
+}
+
+// Stop stops renewing, unbinds the session key and removes the persisted key
+func (m *SessionManager) Stop() error {
+	m.once.Do(func() { close(m.done) })
+	m.wg.Wait()
+
+	client := m.Client()
+	if client == nil {
+		return nil
+	}
+	if _, err := client.UnbindAgent(m.ownerAddress); err != nil {
+		return fmt.Errorf("failed to unbind session key: %w", err)
+	}
+	if m.config.Store != nil {
+		if err := m.config.Store.Delete(m.storeKey()); err != nil {
+			return fmt.Errorf("failed to remove stored session key: %w", err)
+		}
+	} else if m.config.KeyPath != "" {
+		if err := os.Remove(m.config.KeyPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove session key file: %w", err)
+		}
+	}
+	return nil
+}
+
+// Client returns the client signing with the session key, nil before Start
+func (m *SessionManager) Client() *AntxClient {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.client
+}
+
+// ExpireTime returns the binding expiration time of the session key
+func (m *SessionManager) ExpireTime() time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.expireTime
+}
+
+// run rebinds the session key before it expires
+func (m *SessionManager) run() {
+	defer m.wg.Done()
+	for {
+		wait := time.Until(m.ExpireTime()) - m.config.RenewBefore
+		timer := time.NewTimer(wait)
+		select {
+		case <-m.done:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if err := m.renew(); err != nil {
+			m.report(err)
+			select {
+			case <-m.done:
+				return
+			case <-time.After(sessionRetryInterval):
+			}
+		}
+	}
+}
+
+// use creates the client signing with agentKey
+func (m *SessionManager) use(agentKey string, expireTime time.Time) error {
+	config := m.config.Config
+	config.AgentPrivateKey = agentKey
+	client, err := NewAntxClientWithConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create session client: %w", err)
+	}
+	m.mu.Lock()
+	m.client = client
+	m.agentKey = agentKey
+	m.expireTime = expireTime
+	m.mu.Unlock()
+	return nil
+}
+
+// renew binds the session key for another TTL and persists the new expiry
+func (m *SessionManager) renew() error {
+	client := m.Client()
+	expireTime := client.ServerNow().Add(m.config.TTL)
+	if _, err := client.BindAgent(m.config.Config.EthPrivateKey, m.config.Config.ChainID, uint64(m.config.TTL.Seconds())); err != nil {
+		return fmt.Errorf("failed to bind session key: %w", err)
+	}
+	m.mu.Lock()
+	m.expireTime = expireTime
+	m.mu.Unlock()
+	if err := m.save(); err != nil {
+		return err
+	}
+	client.Logger().Infof("session key %s bound until %s", client.GetAgentAddress(), expireTime.Format(time.RFC3339))
+	return nil
+}
+
+// load reads the persisted session key of the configured owner and chain, returns an empty key when there is none
+func (m *SessionManager) load() (string, time.Time, error) {
+	var data []byte
+	var err error
+	switch {
+	case m.config.Store != nil:
+		data, err = m.config.Store.Get(m.storeKey())
+		if errors.Is(err, store.ErrNotFound) {
+			return "", time.Time{}, nil
+		}
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to read stored session key: %w", err)
+		}
+	case m.config.KeyPath != "":
+		data, err = os.ReadFile(m.config.KeyPath)
+		if errors.Is(err, os.ErrNotExist) {
+			return "", time.Time{}, nil
+		}
+		if err != nil {
+			return "", time.Time{}, fmt.Errorf("failed to read session key file: %w", err)
+		}
+	default:
+		return "", time.Time{}, nil
+	}
+	var file sessionFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to parse session key file: %w", err)
+	}
+	// A key of another owner or chain is left untouched and replaced on the next save
+	if file.ChainId != m.config.Config.ChainID || file.OwnerAddress != m.ownerAddress {
+		return "", time.Time{}, nil
+	}
+	key, err := keystore.DecryptDataV3(file.Crypto, m.config.Passphrase)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decrypt session key: %w", err)
+	}
+	return hex.EncodeToString(key), time.UnixMilli(file.ExpireTime), nil
+}
+
+// save persists the session key encrypted with the passphrase to the store, or to the file replaced atomically
+func (m *SessionManager) save() error {
+	if m.config.KeyPath == "" && m.config.Store == nil {
+		return nil
+	}
+	m.mu.RLock()
+	client, agentKey, expireTime := m.client, m.agentKey, m.expireTime
+	m.mu.RUnlock()
+
+	key, err := hex.DecodeString(agentKey)
+	if err != nil {
+		return fmt.Errorf("failed to decode session key: %w", err)
+	}
+	encrypted, err := keystore.EncryptDataV3(key, []byte(m.config.Passphrase), keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt session key: %w", err)
+	}
+	data, err := json.MarshalIndent(sessionFile{
+		AgentAddress: client.GetAgentAddress(),
+		OwnerAddress: m.ownerAddress,
+		ChainId:      m.config.Config.ChainID,
+		ExpireTime:   expireTime.UnixMilli(),
+		Crypto:       encrypted,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode session key file: %w", err)
+	}
+	if m.config.Store != nil {
+		if err := m.config.Store.Put(m.storeKey(), data); err != nil {
+			return fmt.Errorf("failed to store session key: %w", err)
+		}
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(m.config.KeyPath), filepath.Base(m.config.KeyPath)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to write session key file: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write session key file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write session key file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), m.config.KeyPath); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write session key file: %w", err)
+	}
+	return nil
+}
+
+// storeKey returns the store key of the session key of the owner on the chain
+func (m *SessionManager) storeKey() string {
+	return fmt.Sprintf("session/%s/%s", m.ownerAddress, m.config.Config.ChainID)
+}
+
+// report passes an error to the configured handler
+func (m *SessionManager) report(err error) {
+	if m.config.ErrorHandler != nil {
+		m.config.ErrorHandler(err)
+		return
+	}
+	orLogx(m.config.Config.Logger).Errorf("session manager: %v", err)
+}
@@ -0,0 +1,15 @@
+package sdk
+
+import (
+	txsigning "cosmossdk.io/x/tx/signing"
+	"github.com/antxprotocol/antx-sdk-golang/sign"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// Transaction construction, signing and verification live in the sign package, the client signs through a
+// sign.TxSigner. The names below keep existing imports of this package working.
+
+// VerifyTransactionSignature performs complete signature verification, see sign.VerifyTransactionSignature
+func VerifyTransactionSignature(tx sdk.Tx, chainID string, accountNumber uint64, signModeHandler *txsigning.HandlerMap) error {
+	return sign.VerifyTransactionSignature(tx, chainID, accountNumber, signModeHandler)
+}
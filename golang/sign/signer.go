@@ -0,0 +1,171 @@
+// Package sign builds and signs Antx chain transactions. It does not talk to the gateway, so alternative signers and
+// server-side verifiers can depend on it without the HTTP and WebSocket code.
+package sign
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/codec"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
+	"github.com/cosmos/cosmos-sdk/crypto/keyring"
+	cryptotypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+const (
+	// DefaultGasLimit gas limit of transactions that do not set one
+	DefaultGasLimit = 200000
+	// keyName name of the signing key in the keyring
+	keyName = "temp-key"
+)
+
+// TxSigner signs transactions carrying a single message
+type TxSigner interface {
+	// Address returns the address of the signing account
+	Address() sdk.AccAddress
+	// SignTx builds a transaction carrying msg, signs it and returns the encoded transaction
+	SignTx(ctx context.Context, msg sdk.Msg, opts TxOptions) ([]byte, error)
+}
+
+// PhasedTxSigner optional interface of a TxSigner reporting the time spent in each phase of signing
+type PhasedTxSigner interface {
+	TxSigner
+	// SignTxPhases is SignTx also returning the time of each phase
+	SignTxPhases(ctx context.Context, msg sdk.Msg, opts TxOptions) ([]byte, SignPhases, error)
+}
+
+// SignPhases time spent in each phase of signing a transaction
+type SignPhases struct {
+	Build  time.Duration // Building the unsigned transaction
+	Sign   time.Duration // Signing
+	Encode time.Duration // Encoding the signed transaction
+}
+
+// TxOptions transaction parameters
+type TxOptions struct {
+	ChainID       string         // Chain ID
+	AccountNumber uint64         // Account number of the signer
+	Sequence      uint64         // Account sequence, ignored for unordered transactions
+	Unordered     bool           // Whether the transaction is unordered
+	Timeout       time.Time      // Timeout of unordered transactions
+	GasLimit      uint64         // Gas limit, defaults to DefaultGasLimit
+	FeeAmount     sdk.Coins      // Fee, transactions carry no fee when empty
+	FeeGranter    sdk.AccAddress // Account paying the fee under a fee grant, the signer pays when empty
+}
+
+// NewTxConfig returns the transaction encoding configuration of the Antx chain
+func NewTxConfig() client.TxConfig {
+	return authtx.NewTxConfig(newCodec(), authtx.DefaultSignModes)
+}
+
+// newCodec returns a codec with the key and transaction interfaces registered
+func newCodec() *codec.ProtoCodec {
+	interfaceRegistry := codectypes.NewInterfaceRegistry()
+	cryptocodec.RegisterInterfaces(interfaceRegistry)
+	authtypes.RegisterInterfaces(interfaceRegistry)
+	banktypes.RegisterInterfaces(interfaceRegistry)
+	authz.RegisterInterfaces(interfaceRegistry)
+	return codec.NewProtoCodec(interfaceRegistry)
+}
+
+// KeyringSigner signs with a secp256k1 private key held in an in-memory keyring
+type KeyringSigner struct {
+	txConfig client.TxConfig
+	keyring  keyring.Keyring
+	address  sdk.AccAddress
+}
+
+// NewKeyringSigner creates a signer for a private key
+func NewKeyringSigner(privKey cryptotypes.PrivKey) (*KeyringSigner, error) {
+	cdc := newCodec()
+	kr := keyring.NewInMemory(cdc)
+	if err := kr.ImportPrivKeyHex(keyName, hex.EncodeToString(privKey.Bytes()), "secp256k1"); err != nil {
+		return nil, fmt.Errorf("failed to import private key to keyring: %w", err)
+	}
+	return &KeyringSigner{
+		txConfig: authtx.NewTxConfig(cdc, authtx.DefaultSignModes),
+		keyring:  kr,
+		address:  sdk.AccAddress(privKey.PubKey().Address()),
+	}, nil
+}
+
+// Address returns the address of the signing account
+func (s *KeyringSigner) Address() sdk.AccAddress {
+	return s.address
+}
+
+// TxConfig returns the transaction encoding configuration, e.g. to decode transactions for verification
+func (s *KeyringSigner) TxConfig() client.TxConfig {
+	return s.txConfig
+}
+
+// SignTx builds a transaction carrying msg, signs it and returns the encoded transaction
+func (s *KeyringSigner) SignTx(ctx context.Context, msg sdk.Msg, opts TxOptions) ([]byte, error) {
+	txBytes, _, err := s.SignTxPhases(ctx, msg, opts)
+	return txBytes, err
+}
+
+// SignTxPhases is SignTx also returning the time of each phase
+func (s *KeyringSigner) SignTxPhases(ctx context.Context, msg sdk.Msg, opts TxOptions) ([]byte, SignPhases, error) {
+	var phases SignPhases
+	phaseStart := time.Now()
+
+	// Create transaction builder
+	txBuilder := s.txConfig.NewTxBuilder()
+	if err := txBuilder.SetMsgs(msg); err != nil {
+		return nil, phases, fmt.Errorf("failed to set messages: %w", err)
+	}
+	if opts.Unordered {
+		txBuilder.SetUnordered(true)
+		txBuilder.SetTimeoutTimestamp(opts.Timeout)
+	}
+
+	// Set gas and fee
+	gasLimit := opts.GasLimit
+	if gasLimit == 0 {
+		gasLimit = DefaultGasLimit
+	}
+	txBuilder.SetGasLimit(gasLimit)
+	feeAmount := opts.FeeAmount
+	if feeAmount == nil {
+		feeAmount = sdk.NewCoins() // No fee
+	}
+	txBuilder.SetFeeAmount(feeAmount)
+	if !opts.FeeGranter.Empty() {
+		txBuilder.SetFeeGranter(opts.FeeGranter)
+	}
+
+	// Create transaction factory
+	txFactory := tx.Factory{}.
+		WithChainID(opts.ChainID).
+		WithTxConfig(s.txConfig).
+		WithAccountNumber(opts.AccountNumber).
+		WithSignMode(authtx.DefaultSignModes[0]).
+		WithKeybase(s.keyring)
+	if !opts.Unordered {
+		txFactory = txFactory.WithSequence(opts.Sequence)
+	}
+
+	phases.Build, phaseStart = time.Since(phaseStart), time.Now()
+
+	if err := tx.Sign(ctx, txFactory, keyName, txBuilder, true); err != nil {
+		return nil, phases, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+	phases.Sign, phaseStart = time.Since(phaseStart), time.Now()
+	txBytes, err := s.txConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		return nil, phases, fmt.Errorf("failed to encode transaction: %w", err)
+	}
+	phases.Encode = time.Since(phaseStart)
+	return txBytes, phases, nil
+}
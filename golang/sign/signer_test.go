@@ -0,0 +1,61 @@
+package sign
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	banktypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+// benchmarkTx message and options of the signed benchmark transactions
+func benchmarkTx(address sdk.AccAddress) (sdk.Msg, TxOptions) {
+	msg := &banktypes.MsgSend{
+		FromAddress: address.String(),
+		ToAddress:   address.String(),
+		Amount:      sdk.NewCoins(sdk.NewInt64Coin("uantx", 1)),
+	}
+	return msg, TxOptions{
+		ChainID:       "antx-devnet",
+		AccountNumber: 1,
+		Unordered:     true,
+		Timeout:       time.Now().Add(time.Minute),
+	}
+}
+
+// BenchmarkSignTx signs with one signer, its keyring holding the imported key across transactions
+func BenchmarkSignTx(b *testing.B) {
+	privKey := secp256k1.GenPrivKey()
+	signer, err := NewKeyringSigner(privKey)
+	if err != nil {
+		b.Fatal(err)
+	}
+	msg, opts := benchmarkTx(signer.Address())
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := signer.SignTx(context.Background(), msg, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkSignTxNewKeyring signs with a new signer per transaction, rebuilding the keyring and importing the key each
+// time as the client did before the signer was kept
+func BenchmarkSignTxNewKeyring(b *testing.B) {
+	privKey := secp256k1.GenPrivKey()
+	msg, opts := benchmarkTx(sdk.AccAddress(privKey.PubKey().Address()))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		signer, err := NewKeyringSigner(privKey)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := signer.SignTx(context.Background(), msg, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
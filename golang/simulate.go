@@ -0,0 +1,119 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/antxprotocol/antx-sdk-golang/query"
+	"github.com/antxprotocol/antx-sdk-golang/sign"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultGasAdjustment default multiplier of the simulated gas giving the gas limit of the broadcast
+const DefaultGasAdjustment = 1.5
+
+// TxSimulation outcome of a successful simulation
+type TxSimulation struct {
+	GasUsed  uint64 // Gas consumed by the simulation
+	GasLimit uint64 // Gas limit for the broadcast, GasUsed times the gas adjustment
+	Log      string // Log of the simulated execution
+}
+
+// TxSimulator executes signed transactions against the current chain state without including them in a block, so no
+// sequence is consumed and no order reaches the order history
+type TxSimulator interface {
+	// Simulate executes a transaction, a rejection is returned as a *query.TxError
+	Simulate(ctx context.Context, txBytes []byte) (*TxSimulation, error)
+}
+
+// GRPCSimulator simulates through the cosmos-sdk transaction service of a node
+type GRPCSimulator struct {
+	service txtypes.ServiceClient
+}
+
+// NewGRPCSimulator creates a simulator on a gRPC connection to a node, e.g. grpc.NewClient("127.0.0.1:9090", ...)
+func NewGRPCSimulator(conn grpc.ClientConnInterface) *GRPCSimulator {
+	return &GRPCSimulator{service: txtypes.NewServiceClient(conn)}
+}
+
+// Simulate executes a transaction through the Simulate method of the transaction service
+func (s *GRPCSimulator) Simulate(ctx context.Context, txBytes []byte) (*TxSimulation, error) {
+	resp, err := s.service.Simulate(ctx, &txtypes.SimulateRequest{TxBytes: txBytes})
+	if err != nil {
+		// The node reports execution failures as Unknown and undecodable transactions as InvalidArgument,
+		// other codes are transport errors
+		if st, ok := status.FromError(err); ok && (st.Code() == codes.Unknown || st.Code() == codes.InvalidArgument) {
+			log, _, _ := strings.Cut(st.Message(), " With gas wanted")
+			return nil, query.TxLogError(strings.TrimSpace(log))
+		}
+		return nil, fmt.Errorf("failed to simulate transaction: %w", err)
+	}
+	simulation := &TxSimulation{}
+	if resp.GasInfo != nil {
+		simulation.GasUsed = resp.GasInfo.GasUsed
+	}
+	if resp.Result != nil {
+		simulation.Log = resp.Result.Log
+	}
+	return simulation, nil
+}
+
+// SimulateTx signs a transaction carrying msg and simulates it without broadcasting, a rejection is returned as a
+// *TxError, e.g. errors.Is(err, ErrTxInsufficientMargin)
+func (c *AntxClient) SimulateTx(msg sdk.Msg, unordered bool) (*TxSimulation, error) {
+	return c.SimulateTxContext(context.Background(), msg, unordered)
+}
+
+// SimulateTxContext is SimulateTx with a context canceling the account and simulation requests
+func (c *AntxClient) SimulateTxContext(ctx context.Context, msg sdk.Msg, unordered bool) (*TxSimulation, error) {
+	c.txMu.RLock()
+	defer c.txMu.RUnlock()
+	opts, err := c.txOptions(ctx, unordered)
+	if err != nil {
+		return nil, err
+	}
+	if exec, ok := c.authzExec(msg); ok {
+		msg = exec
+	}
+	return c.simulate(ctx, msg, opts)
+}
+
+// SimulateOrder validates an order and simulates its creation, returning the rejection reason or the gas estimate
+// without broadcasting
+func (c *AntxClient) SimulateOrder(order *CreateOrderParam) (*TxSimulation, error) {
+	if err := ValidateCreateOrderParam(order); err != nil {
+		return nil, err
+	}
+	sizeValue, err := c.applyReduceOnlyPolicy(order)
+	if err != nil {
+		return nil, err
+	}
+	msg := c.createOrderMsg(order)
+	msg.SizeValue = sizeValue
+	return c.SimulateTx(&msg, true)
+}
+
+// simulate signs msg with opts and runs it through the simulator
+func (c *AntxClient) simulate(ctx context.Context, msg sdk.Msg, opts sign.TxOptions) (*TxSimulation, error) {
+	if c.isReadOnly() {
+		return nil, ErrReadOnly
+	}
+	if c.simulator == nil {
+		return nil, fmt.Errorf("no transaction simulator configured")
+	}
+	txBytes, err := c.signer.SignTx(ctx, msg, opts)
+	if err != nil {
+		return nil, err
+	}
+	simulation, err := c.simulator.Simulate(ctx, txBytes)
+	if err != nil {
+		return nil, err
+	}
+	simulation.GasLimit = uint64(float64(simulation.GasUsed) * c.gasAdjustment)
+	return simulation, nil
+}
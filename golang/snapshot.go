@@ -0,0 +1,137 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/query"
+)
+
+// clientSnapshotVersion format version of ClientSnapshot
+const clientSnapshotVersion = 1
+
+// ClientSnapshot serialized state of the client trackers, for warm restarts and blue/green deploys
+type ClientSnapshot struct {
+	Version       int                    `json:"version"`       // Format version
+	CreatedTime   int64                  `json:"createdTime"`   // Snapshot time, unit: milliseconds
+	OrderTrackers []OrderTrackerSnapshot `json:"orderTrackers"` // Order trackers by subaccount
+	Inventories   []InventorySnapshot    `json:"inventories"`   // Inventory trackers fed by TrackInventory
+	Subscriptions []query.WsRegisterReq  `json:"subscriptions"` // Active WebSocket subscriptions
+}
+
+// Snapshot serializes the order trackers, the inventory trackers fed by TrackInventory and the WebSocket
+// subscriptions to JSON
+func (c *AntxClient) Snapshot() ([]byte, error) {
+	snapshot := ClientSnapshot{
+		Version:     clientSnapshotVersion,
+		CreatedTime: time.Now().UnixMilli(),
+	}
+	c.trackerMu.Lock()
+	for _, tracker := range c.orderTrackers {
+		snapshot.OrderTrackers = append(snapshot.OrderTrackers, tracker.Snapshot())
+	}
+	for _, tracker := range c.inventoryTrackers {
+		snapshot.Inventories = append(snapshot.Inventories, tracker.Snapshot())
+	}
+	c.trackerMu.Unlock()
+	sort.Slice(snapshot.OrderTrackers, func(i, j int) bool {
+		return snapshot.OrderTrackers[i].SubaccountId < snapshot.OrderTrackers[j].SubaccountId
+	})
+	sort.Slice(snapshot.Inventories, func(i, j int) bool {
+		return snapshot.Inventories[i].SubaccountId < snapshot.Inventories[j].SubaccountId
+	})
+	if ws := c.WebSocket(); ws != nil {
+		snapshot.Subscriptions = ws.Subscriptions()
+	}
+	return json.Marshal(snapshot)
+}
+
+// Restore loads a snapshot taken by Snapshot. Order trackers are restored unsynced and inventory trackers are restored
+// now or when they are passed to TrackInventory. The subscriptions are restored by RestoreSubscriptions.
+func (c *AntxClient) Restore(data []byte) error {
+	snapshot, err := parseClientSnapshot(data)
+	if err != nil {
+		return err
+	}
+
+	for _, trackerSnapshot := range snapshot.OrderTrackers {
+		if err := c.OrderTracker(trackerSnapshot.SubaccountId).Restore(trackerSnapshot); err != nil {
+			return err
+		}
+	}
+
+	c.trackerMu.Lock()
+	if c.pendingInventories == nil {
+		c.pendingInventories = make(map[string]InventorySnapshot)
+	}
+	var restore []*InventoryTracker
+	var snapshots []InventorySnapshot
+	for _, inventorySnapshot := range snapshot.Inventories {
+		if tracker, ok := c.inventoryTrackers[inventorySnapshot.SubaccountId]; ok {
+			restore = append(restore, tracker)
+			snapshots = append(snapshots, inventorySnapshot)
+		} else {
+			c.pendingInventories[inventorySnapshot.SubaccountId] = inventorySnapshot
+		}
+	}
+	c.trackerMu.Unlock()
+	for i, tracker := range restore {
+		if err := tracker.Restore(snapshots[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RestoreSubscriptions subscribes the connected WebSocket to the subscriptions of a snapshot taken by Snapshot and
+// returns the channels of their messages by subscription, see query.WebSocketClient.SubscribeTo
+func (c *AntxClient) RestoreSubscriptions(data []byte) (map[query.WsRegisterReq]<-chan []byte, error) {
+	snapshot, err := parseClientSnapshot(data)
+	if err != nil {
+		return nil, err
+	}
+	ws := c.WebSocket()
+	if ws == nil || !ws.IsConnected() {
+		return nil, query.ErrNotConnected
+	}
+	channels := make(map[query.WsRegisterReq]<-chan []byte, len(snapshot.Subscriptions))
+	for _, subscription := range snapshot.Subscriptions {
+		messages, err := ws.SubscribeTo(subscription)
+		if err != nil {
+			return channels, fmt.Errorf("failed to resubscribe to %s: %w", subscription.Channel, err)
+		}
+		channels[subscription] = messages
+	}
+	return channels, nil
+}
+
+// parseClientSnapshot parses a snapshot taken by Snapshot
+func parseClientSnapshot(data []byte) (*ClientSnapshot, error) {
+	var snapshot ClientSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot: %w", err)
+	}
+	if snapshot.Version != clientSnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", snapshot.Version)
+	}
+	return &snapshot, nil
+}
+
+// registerInventoryTracker records a tracker for Snapshot and restores a pending snapshot of its subaccount
+func (c *AntxClient) registerInventoryTracker(tracker *InventoryTracker) error {
+	c.trackerMu.Lock()
+	if c.inventoryTrackers == nil {
+		c.inventoryTrackers = make(map[string]*InventoryTracker)
+	}
+	c.inventoryTrackers[tracker.SubaccountId] = tracker
+	snapshot, ok := c.pendingInventories[tracker.SubaccountId]
+	delete(c.pendingInventories, tracker.SubaccountId)
+	c.trackerMu.Unlock()
+	if ok {
+		return tracker.Restore(snapshot)
+	}
+	return nil
+}
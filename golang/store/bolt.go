@@ -0,0 +1,102 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// defaultBoltBucket bucket holding the values of a BoltStore
+const defaultBoltBucket = "antx"
+
+// BoltStore store in a BoltDB file, a single process may open the file at a time
+type BoltStore struct {
+	db     *bolt.DB
+	bucket []byte
+}
+
+// OpenBolt opens or creates a BoltDB file, waiting up to one second for another process to release it
+func OpenBolt(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bolt store %s: %w", path, err)
+	}
+	s := &BoltStore{db: db, bucket: []byte(defaultBoltBucket)}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(s.bucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create bolt bucket: %w", err)
+	}
+	return s, nil
+}
+
+// Get returns the value of a key
+func (s *BoltStore) Get(key string) ([]byte, error) {
+	var value []byte
+	err := s.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(s.bucket).Get([]byte(key))
+		if v == nil {
+			return ErrNotFound
+		}
+		// Values are only valid during the transaction
+		value = append([]byte(nil), v...)
+		return nil
+	})
+	return value, err
+}
+
+// Put sets the value of a key, syncing it to disk before returning
+func (s *BoltStore) Put(key string, value []byte) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Put([]byte(key), value)
+	})
+}
+
+// Delete removes a key
+func (s *BoltStore) Delete(key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(s.bucket).Delete([]byte(key))
+	})
+}
+
+// Apply removes the keys of deletes and sets the values of puts in one transaction, synced to disk once
+func (s *BoltStore) Apply(puts map[string][]byte, deletes []string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(s.bucket)
+		for _, key := range deletes {
+			if err := bucket.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+		for key, value := range puts {
+			if err := bucket.Put([]byte(key), value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Scan calls fn for the keys with the prefix inside a read transaction, fn must not modify the store
+func (s *BoltStore) Scan(prefix string, fn func(key string, value []byte) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(s.bucket).Cursor()
+		p := []byte(prefix)
+		for k, v := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, v = c.Next() {
+			if err := fn(string(k), append([]byte(nil), v...)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Close closes the file
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
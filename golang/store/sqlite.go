@@ -0,0 +1,140 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultSQLTable table holding the values of a SQLStore
+const DefaultSQLTable = "antx_state"
+
+// sqlIdentifier allowed table names, they cannot be passed as query parameters
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLStore store in a SQLite table. The SDK does not link a SQLite driver, the application registers one, e.g. by
+// importing modernc.org/sqlite (driver "sqlite") or github.com/mattn/go-sqlite3 (driver "sqlite3").
+type SQLStore struct {
+	db     *sql.DB
+	table  string
+	closer bool
+}
+
+// OpenSQLite opens or creates a SQLite database with a registered driver and the DefaultSQLTable table
+func OpenSQLite(driverName, dsn string) (*SQLStore, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite store: %w", err)
+	}
+	// SQLite allows a single writer, serialize the connections instead of failing with SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+	s, err := NewSQLStore(db, DefaultSQLTable)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	s.closer = true
+	return s, nil
+}
+
+// NewSQLStore creates a store in a table of an open SQLite database, creating the table if needed. Close leaves the
+// database open.
+func NewSQLStore(db *sql.DB, table string) (*SQLStore, error) {
+	if !sqlIdentifier.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+	_, err := db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (id TEXT PRIMARY KEY, value BLOB NOT NULL)", table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create table %s: %w", table, err)
+	}
+	return &SQLStore{db: db, table: table}, nil
+}
+
+// Get returns the value of a key
+func (s *SQLStore) Get(key string) ([]byte, error) {
+	var value []byte
+	err := s.db.QueryRow(fmt.Sprintf("SELECT value FROM %s WHERE id = ?", s.table), key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return value, err
+}
+
+// Put sets the value of a key
+func (s *SQLStore) Put(key string, value []byte) error {
+	_, err := s.db.Exec(fmt.Sprintf("INSERT INTO %s (id, value) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET value = excluded.value", s.table), key, value)
+	return err
+}
+
+// Delete removes a key
+func (s *SQLStore) Delete(key string) error {
+	_, err := s.db.Exec(fmt.Sprintf("DELETE FROM %s WHERE id = ?", s.table), key)
+	return err
+}
+
+// Apply removes the keys of deletes and sets the values of puts in one transaction
+func (s *SQLStore) Apply(puts map[string][]byte, deletes []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, key := range deletes {
+		if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE id = ?", s.table), key); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	for key, value := range puts {
+		if _, err := tx.Exec(fmt.Sprintf("INSERT INTO %s (id, value) VALUES (?, ?) ON CONFLICT (id) DO UPDATE SET value = excluded.value", s.table), key, value); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Scan calls fn for the keys with the prefix, the rows are read before the first call so that fn may modify the store
+func (s *SQLStore) Scan(prefix string, fn func(key string, value []byte) error) error {
+	rows, err := s.db.Query(fmt.Sprintf("SELECT id, value FROM %s WHERE id >= ? ORDER BY id", s.table), prefix)
+	if err != nil {
+		return err
+	}
+	type entry struct {
+		key   string
+		value []byte
+	}
+	var entries []entry
+	for rows.Next() {
+		var e entry
+		if err := rows.Scan(&e.key, &e.value); err != nil {
+			rows.Close()
+			return err
+		}
+		if !strings.HasPrefix(e.key, prefix) {
+			break
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := fn(e.key, e.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the database opened by OpenSQLite
+func (s *SQLStore) Close() error {
+	if s.closer {
+		return s.db.Close()
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+// Package store persists bot state such as tracked orders and session keys, so that it survives restarts. Stores
+// are flat key-value maps, callers namespace their keys with a prefix, e.g. "orders/<subaccount>/".
+package store
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound returned by Get when the key does not exist
+var ErrNotFound = errors.New("key not found")
+
+// Store persistent key-value store, implementations must be safe for concurrent use
+type Store interface {
+	// Get returns the value of a key, ErrNotFound when it does not exist
+	Get(key string) ([]byte, error)
+	// Put sets the value of a key
+	Put(key string, value []byte) error
+	// Delete removes a key, deleting a missing key is not an error
+	Delete(key string) error
+	// Scan calls fn for each key with the prefix in ascending key order, stopping at the first error of fn
+	Scan(prefix string, fn func(key string, value []byte) error) error
+	// Close releases the store
+	Close() error
+}
+
+// Batcher optional interface of a Store applying several writes at once, e.g. in one transaction synced to disk once
+type Batcher interface {
+	// Apply removes the keys of deletes and sets the values of puts
+	Apply(puts map[string][]byte, deletes []string) error
+}
+
+// Apply removes the keys of deletes and sets the values of puts, at once when s implements Batcher
+func Apply(s Store, puts map[string][]byte, deletes []string) error {
+	if b, ok := s.(Batcher); ok {
+		return b.Apply(puts, deletes)
+	}
+	for _, key := range deletes {
+		if err := s.Delete(key); err != nil {
+			return err
+		}
+	}
+	for key, value := range puts {
+		if err := s.Put(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MemoryStore in-memory store, state is lost on restart, for tests and as a default
+type MemoryStore struct {
+	mu     sync.RWMutex
+	values map[string][]byte
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{values: make(map[string][]byte)}
+}
+
+// Get returns a copy of the value of a key
+func (s *MemoryStore) Get(key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	value, ok := s.values[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), value...), nil
+}
+
+// Put stores a copy of the value
+func (s *MemoryStore) Put(key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = append([]byte(nil), value...)
+	return nil
+}
+
+// Delete removes a key
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+	return nil
+}
+
+// Apply removes the keys of deletes and stores copies of the values of puts
+func (s *MemoryStore) Apply(puts map[string][]byte, deletes []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, key := range deletes {
+		delete(s.values, key)
+	}
+	for key, value := range puts {
+		s.values[key] = append([]byte(nil), value...)
+	}
+	return nil
+}
+
+// Scan calls fn for the keys with the prefix, on a snapshot taken before the first call so that fn may modify the store
+func (s *MemoryStore) Scan(prefix string, fn func(key string, value []byte) error) error {
+	s.mu.RLock()
+	keys := make([]string, 0, len(s.values))
+	values := make(map[string][]byte)
+	for key, value := range s.values {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+			values[key] = append([]byte(nil), value...)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := fn(key, values[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close does nothing
+func (s *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,74 @@
+package sdk
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// ErrSubaccountNotFound no subaccount of the client has the requested client account ID
+var ErrSubaccountNotFound = errors.New("subaccount not found")
+
+// Subaccounts returns the subaccounts of the client ETH address the agent trades for, the list is cached after the
+// first query
+func (c *AntxClient) Subaccounts() ([]types.Subaccount, error) {
+	c.metaMu.RLock()
+	subaccounts := c.subaccounts
+	c.metaMu.RUnlock()
+	if subaccounts != nil {
+		return append([]types.Subaccount(nil), subaccounts...), nil
+	}
+	return c.RefreshSubaccounts()
+}
+
+// RefreshSubaccounts reloads the cached subaccount list from the gateway
+func (c *AntxClient) RefreshSubaccounts() ([]types.Subaccount, error) {
+	subaccounts, err := c.GetSubaccountList(1, c.GetEthAddress(), c.GetAgentAddress()) // chain type 1: EVM
+	if err != nil {
+		return nil, err
+	}
+	if subaccounts == nil {
+		subaccounts = []types.Subaccount{}
+	}
+	c.metaMu.Lock()
+	c.subaccounts = subaccounts
+	c.metaMu.Unlock()
+	return append([]types.Subaccount(nil), subaccounts...), nil
+}
+
+// SubaccountByClientAccountId returns the subaccount with a client account ID, refreshing the cache once when it is
+// missing, e.g. when it was created after the last refresh
+func (c *AntxClient) SubaccountByClientAccountId(clientAccountId string) (*types.Subaccount, error) {
+	subaccounts, err := c.Subaccounts()
+	if err != nil {
+		return nil, err
+	}
+	if subaccount := findSubaccount(subaccounts, clientAccountId); subaccount != nil {
+		return subaccount, nil
+	}
+	if subaccounts, err = c.RefreshSubaccounts(); err != nil {
+		return nil, err
+	}
+	if subaccount := findSubaccount(subaccounts, clientAccountId); subaccount != nil {
+		return subaccount, nil
+	}
+	return nil, fmt.Errorf("client account ID %q: %w", clientAccountId, ErrSubaccountNotFound)
+}
+
+// EnsureSubaccount returns the subaccount with a client account ID. The chain has no subaccount creation message yet,
+// so a missing subaccount is returned as ErrSubaccountNotFound instead of being created.
+func (c *AntxClient) EnsureSubaccount(clientAccountId string) (*types.Subaccount, error) {
+	return c.SubaccountByClientAccountId(clientAccountId)
+}
+
+// findSubaccount returns the subaccount with a client account ID, nil when there is none
+func findSubaccount(subaccounts []types.Subaccount, clientAccountId string) *types.Subaccount {
+	for i := range subaccounts {
+		if subaccounts[i].ClientAccountId == clientAccountId {
+			subaccount := subaccounts[i]
+			return &subaccount
+		}
+	}
+	return nil
+}
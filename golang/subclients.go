@@ -0,0 +1,138 @@
+package sdk
+
+import (
+	"context"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// The sub-clients group the methods of AntxClient by surface, so applications depend on the interface of the part they
+// use, e.g. a strategy on MarketDataClient and OrderClient, and mock it in tests without the rest of the client.
+// They narrow the API without splitting the client: Market, Account and Chain are the AntxClient itself, so a type
+// assertion recovers the whole client, and only Orders is a separate value.
+
+// MarketDataClient market data queries of the gateway, see AntxClient.Market
+type MarketDataClient interface {
+	GetCoinList() ([]types.Coin, error)
+	GetCoinListContext(ctx context.Context) ([]types.Coin, error)
+	GetExchangeList() ([]types.Exchange, error)
+	GetExchangeListContext(ctx context.Context) ([]types.Exchange, error)
+	GetKline(req types.GetKLineReq) (*types.GetKLineResp, error)
+	GetKlineContext(ctx context.Context, req types.GetKLineReq) (*types.GetKLineResp, error)
+	GetTicker(req types.GetTickerReq) (*types.GetTickerResp, error)
+	GetTickerContext(ctx context.Context, req types.GetTickerReq) (*types.GetTickerResp, error)
+	GetDepth(req types.GetDepthReq) (*types.GetDepthResp, error)
+	GetDepthContext(ctx context.Context, req types.GetDepthReq) (*types.GetDepthResp, error)
+	GetFundingHistory(req types.GetFundingHistoryReq) (*types.GetFundingHistoryResp, error)
+	GetFundingHistoryContext(ctx context.Context, req types.GetFundingHistoryReq) (*types.GetFundingHistoryResp, error)
+	MarketSnapshot() (*types.MarketSnapshot, error)
+}
+
+// AccountClient account queries of the gateway, see AntxClient.Account
+type AccountClient interface {
+	GetAddressInfo(address string) (*types.AddressInfo, error)
+	GetSubaccountList(chainType int32, chainAddress, agentAddress string) ([]types.Subaccount, error)
+	GetSubaccountListContext(ctx context.Context, chainType int32, chainAddress, agentAddress string) ([]types.Subaccount, error)
+	Subaccounts() ([]types.Subaccount, error)
+	GetAccountState(subaccountId string) (*AccountState, error)
+	GetActiveOrder(req types.GetActiveOrderReq) (*types.GetActiveOrderResp, error)
+	GetActiveOrderContext(ctx context.Context, req types.GetActiveOrderReq) (*types.GetActiveOrderResp, error)
+	GetHistoryOrder(req types.GetHistoryOrderReq) (*types.GetHistoryOrderResp, error)
+	GetHistoryOrderContext(ctx context.Context, req types.GetHistoryOrderReq) (*types.GetHistoryOrderResp, error)
+	GetPerpetualAccountAsset(req types.GetPerpetualAccountAssetReq) (*types.GetPerpetualAccountAssetResp, error)
+	GetPerpetualAccountAssetContext(ctx context.Context, req types.GetPerpetualAccountAssetReq) (*types.GetPerpetualAccountAssetResp, error)
+	GetPositionTransaction(req types.GetPositionTransactionReq) (*types.GetPositionTransactionResp, error)
+	GetPositionTransactionContext(ctx context.Context, req types.GetPositionTransactionReq) (*types.GetPositionTransactionResp, error)
+	GetCollateralTransaction(req types.GetCollateralTransactionReq) (*types.GetCollateralTransactionResp, error)
+	GetCollateralTransactionContext(ctx context.Context, req types.GetCollateralTransactionReq) (*types.GetCollateralTransactionResp, error)
+	GetAssetSnapshot(req types.GetAssetSnapshotReq) (*types.GetAssetSnapshotResp, error)
+	GetAssetSnapshotContext(ctx context.Context, req types.GetAssetSnapshotReq) (*types.GetAssetSnapshotResp, error)
+	GetHistoryOrderFillTransaction(req types.GetHistoryOrderFillTransactionReq) (*types.GetHistoryOrderFillTransactionResp, error)
+	GetHistoryOrderFillTransactionContext(ctx context.Context, req types.GetHistoryOrderFillTransactionReq) (*types.GetHistoryOrderFillTransactionResp, error)
+	GetHistoryPositionTerm(req types.GetHistoryPositionTermReq) (*types.GetHistoryPositionTermResp, error)
+	GetHistoryPositionTermContext(ctx context.Context, req types.GetHistoryPositionTermReq) (*types.GetHistoryPositionTermResp, error)
+}
+
+// OrderClient order transactions signed by the agent, see AntxClient.Orders. The methods return the transaction hash.
+type OrderClient interface {
+	Create(ctx context.Context, order *CreateOrderParam) (string, error)
+	CreateBatch(ctx context.Context, orders *CreateOrderBatchParam) (string, error)
+	Cancel(ctx context.Context, order *types.CancelOrderParam) (string, error)
+	CancelByClientId(ctx context.Context, order *types.CancelOrderByClientIdParam) (string, error)
+	CancelAll(ctx context.Context, order *types.CancelAllOrderParam) (string, error)
+	CloseAllPositions(ctx context.Context, order *types.CloseAllPositionParam) (string, error)
+	Simulate(order *CreateOrderParam) (*TxSimulation, error)
+}
+
+// ChainClient transaction signing and chain queries, see AntxClient.Chain
+type ChainClient interface {
+	GetAgentAddress() string
+	GetEthAddress() string
+	GetAccountNumberAndSequence(address string) (string, string, error)
+	GetAccountNumberAndSequenceContext(ctx context.Context, address string) (string, string, error)
+	SignAndSendTx(typeURL string, msg sdk.Msg, unordered bool) (string, error)
+	SignAndSendTxContext(ctx context.Context, typeURL string, msg sdk.Msg, unordered bool) (string, error)
+	SimulateTx(msg sdk.Msg, unordered bool) (*TxSimulation, error)
+	SimulateTxContext(ctx context.Context, msg sdk.Msg, unordered bool) (*TxSimulation, error)
+	GetTransactionResult(hash string) (*types.GetTransactionResultRespData, error)
+	GetTransactionResultContext(ctx context.Context, hash string) (*types.GetTransactionResultRespData, error)
+	WaitForTransaction(hash string, timeout time.Duration) (*types.GetTransactionResultRespData, error)
+	WaitForTransactionContext(ctx context.Context, hash string, timeout time.Duration) (*types.GetTransactionResultRespData, error)
+}
+
+var (
+	_ MarketDataClient = (*AntxClient)(nil)
+	_ AccountClient    = (*AntxClient)(nil)
+	_ OrderClient      = orderClient{}
+	_ ChainClient      = (*AntxClient)(nil)
+)
+
+// orderClient OrderClient of an AntxClient
+type orderClient struct {
+	client *AntxClient
+}
+
+// Create creates an order, see AntxClient.CreateOrder
+func (o orderClient) Create(ctx context.Context, order *CreateOrderParam) (string, error) {
+	return o.client.CreateOrderContext(ctx, order)
+}
+
+// CreateBatch creates orders in one transaction, see AntxClient.CreateOrderBatch
+func (o orderClient) CreateBatch(ctx context.Context, orders *CreateOrderBatchParam) (string, error) {
+	return o.client.CreateOrderBatchContext(ctx, orders)
+}
+
+// Cancel cancels orders by ID, see AntxClient.CancelOrder
+func (o orderClient) Cancel(ctx context.Context, order *types.CancelOrderParam) (string, error) {
+	return o.client.CancelOrderContext(ctx, order)
+}
+
+// CancelByClientId cancels orders by client order ID, see AntxClient.CancelOrderByClientId
+func (o orderClient) CancelByClientId(ctx context.Context, order *types.CancelOrderByClientIdParam) (string, error) {
+	return o.client.CancelOrderByClientIdContext(ctx, order)
+}
+
+// CancelAll cancels all orders of a subaccount, see AntxClient.CancelAllOrder
+func (o orderClient) CancelAll(ctx context.Context, order *types.CancelAllOrderParam) (string, error) {
+	return o.client.CancelAllOrderContext(ctx, order)
+}
+
+// CloseAllPositions closes all positions of a subaccount, see AntxClient.CloseAllPosition
+func (o orderClient) CloseAllPositions(ctx context.Context, order *types.CloseAllPositionParam) (string, error) {
+	return o.client.CloseAllPositionContext(ctx, order)
+}
+
+// Simulate validates an order and simulates its creation, see AntxClient.SimulateOrder
+func (o orderClient) Simulate(order *CreateOrderParam) (*TxSimulation, error) {
+	return o.client.SimulateOrder(order)
+}
+
+// initSubClients sets the sub-clients of the client, Market, Account and Chain to the client itself
+func (c *AntxClient) initSubClients() {
+	c.Market = c
+	c.Account = c
+	c.Orders = orderClient{client: c}
+	c.Chain = c
+}
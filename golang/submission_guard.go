@@ -0,0 +1,302 @@
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/store"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+const (
+	// DefaultSubmissionSettleTime default time after which a submission missing from the gateway is known to be dropped
+	DefaultSubmissionSettleTime = 30 * time.Second
+	// DefaultSubmissionRetention default time a recorded submission is kept
+	DefaultSubmissionRetention = 24 * time.Hour
+)
+
+var (
+	// ErrOrderAlreadySubmitted the order of a client order ID already reached the chain
+	ErrOrderAlreadySubmitted = errors.New("order already submitted")
+	// ErrSubmissionPending a previous submission of a client order ID may still be included
+	ErrSubmissionPending = errors.New("previous submission still pending")
+)
+
+// GuardedSubmission order submission recorded by a SubmissionGuard
+type GuardedSubmission struct {
+	SubaccountId  uint64 `json:"subaccountId"`     // Subaccount ID
+	ExchangeId    uint64 `json:"exchangeId"`       // Exchange ID
+	ClientOrderId string `json:"clientOrderId"`    // Client order ID
+	TxHash        string `json:"txHash,omitempty"` // Transaction hash, empty when the broadcast did not return
+	SubmittedAt   int64  `json:"submittedAt"`      // Submission time, unit: milliseconds
+}
+
+// SubmissionGuardConfig submission guard configuration
+type SubmissionGuardConfig struct {
+	Store      store.Store   // Store of the in-flight submissions, defaults to the client store, or memory when none is set
+	SettleTime time.Duration // Time after which an order missing from the gateway is considered never included, must exceed the transaction timeout and the indexer lag, defaults to DefaultSubmissionSettleTime
+	Retention  time.Duration // Time a submission is kept before it is forgotten, defaults to DefaultSubmissionRetention
+}
+
+// SubmissionGuard records the client order IDs of submitted orders, persisted in a store, so that a resubmission after
+// a timeout or a restart between broadcast and acknowledgement does not create the order twice: a client order ID
+// submitted before is only sent again once the gateway shows that its order never reached the chain. Submissions of
+// different client order IDs run concurrently.
+type SubmissionGuard struct {
+	client *AntxClient
+	config SubmissionGuardConfig
+
+	mu          sync.Mutex
+	submissions map[string]GuardedSubmission
+	keyLocks    map[string]*submissionLock // locks of the client order IDs being submitted
+}
+
+// submissionLock lock of a client order ID and the number of its holders and waiters
+type submissionLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// NewSubmissionGuard creates a submission guard, loading the submissions persisted in its store
+func (c *AntxClient) NewSubmissionGuard(config SubmissionGuardConfig) (*SubmissionGuard, error) {
+	if config.Store == nil {
+		c.trackerMu.Lock()
+		config.Store = c.store
+		c.trackerMu.Unlock()
+	}
+	if config.Store == nil {
+		config.Store = store.NewMemoryStore()
+	}
+	if config.SettleTime <= 0 {
+		config.SettleTime = DefaultSubmissionSettleTime
+	}
+	if config.Retention <= 0 {
+		config.Retention = DefaultSubmissionRetention
+	}
+	g := &SubmissionGuard{
+		client:      c,
+		config:      config,
+		submissions: make(map[string]GuardedSubmission),
+		keyLocks:    make(map[string]*submissionLock),
+	}
+	err := config.Store.Scan(submissionStoreKey(""), func(key string, value []byte) error {
+		var submission GuardedSubmission
+		if err := json.Unmarshal(value, &submission); err != nil {
+			return fmt.Errorf("failed to parse stored submission %s: %w", key, err)
+		}
+		g.submissions[key] = submission
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// CreateOrder creates an order unless its client order ID was submitted before. A previous submission is verified on
+// the gateway: ErrOrderAlreadySubmitted is returned with its transaction hash when the order exists, ErrSubmissionPending
+// while its inclusion cannot be ruled out yet, and the order is sent again when it was never included.
+func (g *SubmissionGuard) CreateOrder(order *CreateOrderParam) (string, error) {
+	if order.ClientOrderId == "" {
+		return "", fmt.Errorf("submission guard requires a client order ID")
+	}
+	if err := ValidateCreateOrderParam(order); err != nil {
+		return "", err
+	}
+	key := submissionStoreKey(fmt.Sprintf("%d/%s", order.SubaccountId, order.ClientOrderId))
+
+	// Hold the lock of the ID across the broadcast so that concurrent calls for the same ID cannot both send
+	unlock := g.lock(key)
+	defer unlock()
+	g.prune()
+	g.mu.Lock()
+	previous, ok := g.submissions[key]
+	g.mu.Unlock()
+	if ok {
+		if err := g.verify(previous); err != nil {
+			return previous.TxHash, err
+		}
+	}
+
+	submission := GuardedSubmission{
+		SubaccountId:  order.SubaccountId,
+		ExchangeId:    order.ExchangeId,
+		ClientOrderId: order.ClientOrderId,
+		SubmittedAt:   time.Now().UnixMilli(),
+	}
+	// Record before sending, the broadcast may reach the chain even when the call fails or the process dies
+	if err := g.put(key, submission); err != nil {
+		return "", err
+	}
+	txHash, err := g.client.CreateOrder(order)
+	if err != nil {
+		return "", err
+	}
+	submission.TxHash = txHash
+	if err := g.put(key, submission); err != nil {
+		g.client.Logger().Errorf("submission guard: %v", err)
+	}
+	return txHash, nil
+}
+
+// Forget drops the submission of a client order ID, e.g. once its order is final and the ID will not be reused
+func (g *SubmissionGuard) Forget(subaccountId uint64, clientOrderId string) error {
+	key := submissionStoreKey(fmt.Sprintf("%d/%s", subaccountId, clientOrderId))
+	unlock := g.lock(key)
+	defer unlock()
+	g.mu.Lock()
+	delete(g.submissions, key)
+	g.mu.Unlock()
+	return g.config.Store.Delete(key)
+}
+
+// Submissions returns the recorded submissions
+func (g *SubmissionGuard) Submissions() []GuardedSubmission {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	submissions := make([]GuardedSubmission, 0, len(g.submissions))
+	for _, submission := range g.submissions {
+		submissions = append(submissions, submission)
+	}
+	return submissions
+}
+
+// verify returns nil when a previous submission is known to have never reached the chain
+func (g *SubmissionGuard) verify(previous GuardedSubmission) error {
+	if previous.TxHash != "" {
+		result, err := g.client.GetTransactionResult(previous.TxHash)
+		if err == nil && result.Block > 0 {
+			if TxResultError(result) != nil {
+				// Included but failed, the order does not exist
+				return nil
+			}
+			return fmt.Errorf("client order ID %s: %w", previous.ClientOrderId, ErrOrderAlreadySubmitted)
+		}
+	}
+	subaccountId := strconv.FormatUint(previous.SubaccountId, 10)
+	exchangeId := strconv.FormatUint(previous.ExchangeId, 10)
+	// Tolerate clock skew between the client and the chain
+	since := uint64(previous.SubmittedAt - time.Minute.Milliseconds())
+	existing, err := g.client.GetOrderByClientOrderId(subaccountId, exchangeId, previous.ClientOrderId, since)
+	if err == nil {
+		return fmt.Errorf("client order ID %s (order %s): %w", previous.ClientOrderId, existing.Id, ErrOrderAlreadySubmitted)
+	}
+	if !errors.Is(err, ErrOrderNotFound) {
+		return fmt.Errorf("failed to verify previous submission of %s: %w", previous.ClientOrderId, err)
+	}
+	if time.Since(time.UnixMilli(previous.SubmittedAt)) < g.config.SettleTime {
+		return fmt.Errorf("client order ID %s: %w", previous.ClientOrderId, ErrSubmissionPending)
+	}
+	return nil
+}
+
+// lock locks a client order ID and returns its unlock function
+func (g *SubmissionGuard) lock(key string) func() {
+	g.mu.Lock()
+	l, ok := g.keyLocks[key]
+	if !ok {
+		l = &submissionLock{}
+		g.keyLocks[key] = l
+	}
+	l.refs++
+	g.mu.Unlock()
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		g.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(g.keyLocks, key)
+		}
+		g.mu.Unlock()
+	}
+}
+
+// put records and persists a submission, must be called with the lock of its client order ID held
+func (g *SubmissionGuard) put(key string, submission GuardedSubmission) error {
+	value, err := json.Marshal(submission)
+	if err != nil {
+		return err
+	}
+	if err := g.config.Store.Put(key, value); err != nil {
+		return fmt.Errorf("failed to persist submission %s: %w", submission.ClientOrderId, err)
+	}
+	g.mu.Lock()
+	g.submissions[key] = submission
+	g.mu.Unlock()
+	return nil
+}
+
+// prune drops the submissions older than the retention, except those of the client order IDs being submitted
+func (g *SubmissionGuard) prune() {
+	cutoff := time.Now().Add(-g.config.Retention).UnixMilli()
+	var expired []GuardedSubmission
+	var keys []string
+	g.mu.Lock()
+	for key, submission := range g.submissions {
+		// A locked ID is being submitted, its record is replaced rather than dropped
+		if _, locked := g.keyLocks[key]; !locked && submission.SubmittedAt < cutoff {
+			delete(g.submissions, key)
+			keys = append(keys, key)
+			expired = append(expired, submission)
+		}
+	}
+	g.mu.Unlock()
+	for i, key := range keys {
+		if err := g.config.Store.Delete(key); err != nil {
+			g.client.Logger().Errorf("submission guard: failed to delete submission %s: %v", expired[i].ClientOrderId, err)
+		}
+	}
+}
+
+// submissionStoreKey returns the store key of a submission
+func submissionStoreKey(key string) string {
+	return "submissions/" + key
+}
+
+// GetOrderByClientOrderId finds the order of a client order ID among the active orders of a subaccount and its history
+// orders created at or after since (unit: milliseconds), ErrOrderNotFound when there is none
+func (c *AntxClient) GetOrderByClientOrderId(subaccountId, exchangeId, clientOrderId string, since uint64) (*types.Order, error) {
+	active, err := c.getAllActiveOrders(subaccountId)
+	if err != nil {
+		return nil, err
+	}
+	for i := range active {
+		if active[i].ClientOrderId == clientOrderId && active[i].ExchangeId == exchangeId {
+			return &active[i], nil
+		}
+	}
+	req := types.GetHistoryOrderReq{
+		SubaccountId:                    subaccountId,
+		Size:                            100,
+		FilterExchangeIdList:            exchangeId,
+		FilterStartCreatedTimeInclusive: since,
+	}
+	var found *types.Order
+	err = types.ForEachPage(req.Size, func(cursor types.Cursor) ([]types.Order, types.IndexerPageOffsetData, error) {
+		req.PageOffsetDataCreatedTime, req.PageOffsetDataItemId = cursor.CreatedTime, cursor.ItemId
+		resp, err := c.GetHistoryOrder(req)
+		if err != nil {
+			return nil, types.IndexerPageOffsetData{}, err
+		}
+		return resp.Data.OrderList, resp.Data.PageOffsetData, nil
+	}, func(orders []types.Order) bool {
+		for i := range orders {
+			if orders[i].ClientOrderId == clientOrderId {
+				found = &orders[i]
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("client order ID %s: %w", clientOrderId, ErrOrderNotFound)
+	}
+	return found, nil
+}
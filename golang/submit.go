@@ -0,0 +1,182 @@
+package sdk
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+const (
+	// DefaultSubmitWorkers default number of goroutines signing and broadcasting in parallel
+	DefaultSubmitWorkers = 8
+	// DefaultSubmitMaxInFlight default number of submissions accepted but not yet completed
+	DefaultSubmitMaxInFlight = 256
+)
+
+// Submission transaction submitted through a SubmitPipeline
+type Submission struct {
+	Id          string                              // Caller reference echoed in the result
+	OrderingKey string                              // Submissions sharing a non-empty key are sent one at a time in submission order, e.g. a subaccount ID
+	Send        func(c *AntxClient) (string, error) // Signs and broadcasts the transaction, returns the transaction hash
+}
+
+// SubmitResult outcome of a submission
+type SubmitResult struct {
+	Submission Submission    // Submission
+	TxHash     string        // Transaction hash, empty on error
+	Err        error         // Error
+	Latency    time.Duration // Time from SubmitAsync to completion
+}
+
+// SubmitPipelineConfig submission pipeline configuration
+type SubmitPipelineConfig struct {
+	Workers     int // Goroutines signing and broadcasting in parallel, defaults to DefaultSubmitWorkers
+	MaxInFlight int // Submissions accepted but not yet completed, SubmitAsync blocks above it, defaults to DefaultSubmitMaxInFlight
+}
+
+// SubmitPipeline signs and broadcasts unordered transactions on a worker pool, results are delivered on Results in completion order
+type SubmitPipeline struct {
+	client   *AntxClient
+	config   SubmitPipelineConfig
+	shared   chan queuedSubmission
+	lanes    []chan queuedSubmission
+	inFlight chan struct{}
+	results  chan SubmitResult
+
+	mu      sync.RWMutex
+	stopped bool
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// queuedSubmission submission waiting for a worker
+type queuedSubmission struct {
+	submission Submission
+	queuedAt   time.Time
+}
+
+// NewSubmitPipeline creates a submission pipeline and starts its workers
+func (c *AntxClient) NewSubmitPipeline(config SubmitPipelineConfig) *SubmitPipeline {
+	if config.Workers <= 0 {
+		config.Workers = DefaultSubmitWorkers
+	}
+	if config.MaxInFlight <= 0 {
+		config.MaxInFlight = DefaultSubmitMaxInFlight
+	}
+	p := &SubmitPipeline{
+		client:   c,
+		config:   config,
+		shared:   make(chan queuedSubmission, config.MaxInFlight),
+		lanes:    make([]chan queuedSubmission, config.Workers),
+		inFlight: make(chan struct{}, config.MaxInFlight),
+		results:  make(chan SubmitResult, config.MaxInFlight),
+	}
+	for i := range p.lanes {
+		p.lanes[i] = make(chan queuedSubmission, config.MaxInFlight)
+		p.wg.Add(1)
+		go p.work(p.lanes[i])
+	}
+	return p
+}
+
+// SubmitAsync queues a submission, blocking while MaxInFlight submissions are pending
+func (p *SubmitPipeline) SubmitAsync(submission Submission) error {
+	if submission.Send == nil {
+		return fmt.Errorf("submission %s has no send function", submission.Id)
+	}
+	p.inFlight <- struct{}{}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.stopped {
+		<-p.inFlight
+		return fmt.Errorf("submit pipeline is stopped")
+	}
+	queued := queuedSubmission{submission: submission, queuedAt: time.Now()}
+	if submission.OrderingKey == "" {
+		p.shared <- queued
+		return nil
+	}
+	// Keyed submissions always go to the same worker, which runs them in order
+	h := fnv.New32a()
+	h.Write([]byte(submission.OrderingKey))
+	p.lanes[h.Sum32()%uint32(len(p.lanes))] <- queued
+	return nil
+}
+
+// Results returns the channel of submission results, it must be drained and is closed by Stop
+func (p *SubmitPipeline) Results() <-chan SubmitResult {
+	return p.results
+}
+
+// Stop waits for the queued submissions to complete and closes Results
+func (p *SubmitPipeline) Stop() {
+	p.once.Do(func() {
+		p.mu.Lock()
+		p.stopped = true
+		close(p.shared)
+		for _, lane := range p.lanes {
+			close(lane)
+		}
+		p.mu.Unlock()
+		p.wg.Wait()
+		close(p.results)
+	})
+}
+
+// work runs the submissions of its lane and of the shared queue
+func (p *SubmitPipeline) work(lane chan queuedSubmission) {
+	defer p.wg.Done()
+	shared := p.shared
+	for lane != nil || shared != nil {
+		select {
+		case queued, ok := <-lane:
+			if !ok {
+				lane = nil
+				continue
+			}
+			p.run(queued)
+		case queued, ok := <-shared:
+			if !ok {
+				shared = nil
+				continue
+			}
+			p.run(queued)
+		}
+	}
+}
+
+// run sends one submission and publishes its result
+func (p *SubmitPipeline) run(queued queuedSubmission) {
+	txHash, err := queued.submission.Send(p.client)
+	p.results <- SubmitResult{
+		Submission: queued.submission,
+		TxHash:     txHash,
+		Err:        err,
+		Latency:    time.Since(queued.queuedAt),
+	}
+	<-p.inFlight
+}
+
+// CreateOrderSubmission returns a submission creating an order
+func CreateOrderSubmission(id string, order *CreateOrderParam) Submission {
+	return Submission{Id: id, Send: func(c *AntxClient) (string, error) { return c.CreateOrder(order) }}
+}
+
+// CreateOrderBatchSubmission returns a submission creating orders in batch
+func CreateOrderBatchSubmission(id string, orders *CreateOrderBatchParam) Submission {
+	return Submission{Id: id, Send: func(c *AntxClient) (string, error) { return c.CreateOrderBatch(orders) }}
+}
+
+// CancelOrderSubmission returns a submission cancelling orders by ID
+func CancelOrderSubmission(id string, order *types.CancelOrderParam) Submission {
+	return Submission{Id: id, Send: func(c *AntxClient) (string, error) { return c.CancelOrder(order) }}
+}
+
+// CancelOrderByClientIdSubmission returns a submission cancelling orders by client ID
+func CancelOrderByClientIdSubmission(id string, order *types.CancelOrderByClientIdParam) Submission {
+	return Submission{Id: id, Send: func(c *AntxClient) (string, error) { return c.CancelOrderByClientId(order) }}
+}
@@ -0,0 +1,750 @@
+// Package testutil offline stand-ins of the Antx chain and gateway for integration tests: a matching engine executing
+// the order messages of the SDK and a gateway serving it over HTTP and WebSocket
+package testutil
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	ordertypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/order"
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// ErrUnsupportedMsg the engine does not execute messages of this type
+	ErrUnsupportedMsg = errors.New("unsupported message")
+	// ErrOrderNotFound the order is not active on the engine
+	ErrOrderNotFound = errors.New("order not found")
+	// ErrInvalidOrder the order parameters are rejected before reaching the book
+	ErrInvalidOrder = errors.New("invalid order")
+)
+
+// EngineConfig matching engine configuration
+type EngineConfig struct {
+	MakerFeeRatePpm uint32           // Maker fee rate, unit: parts per million
+	TakerFeeRatePpm uint32           // Taker fee rate, unit: parts per million
+	Now             func() time.Time // Clock of the order and fill times, defaults to time.Now
+}
+
+// Engine price-time priority matching engine over the order messages of the SDK. Market orders and the remainder of IOC
+// and FOK orders are cancelled instead of resting, post-only orders that would cross are rejected, reduce-only orders
+// are capped to the position they close. Conditional orders are not supported.
+type Engine struct {
+	config EngineConfig
+
+	mu          sync.Mutex
+	lastId      uint64
+	version     uint64
+	books       map[string]*book                    // Books by exchange ID
+	orders      map[string]*types.Order             // Orders by ID
+	orderIds    []string                            // Order IDs in creation order
+	positions   map[string]*types.PerpetualPosition // Positions by subaccount ID and exchange ID
+	fills       []types.OrderFillTransaction
+	onTradeData []func(subaccountId string, event types.TradeDataEvent)
+	onDepth     []func(types.DepthData)
+}
+
+// book resting orders of an exchange, best price first then oldest first
+type book struct {
+	version uint64
+	bids    []*bookEntry
+	asks    []*bookEntry
+}
+
+// bookEntry resting order with its unfilled size
+type bookEntry struct {
+	order     *types.Order
+	price     decimal.Decimal
+	remaining decimal.Decimal
+}
+
+// execution changes of one message, passed to the callbacks once the message is executed
+type execution struct {
+	events      map[string]*types.TradeDataEvent // Events by subaccount ID
+	subaccounts []string                         // Subaccount IDs in the order their events started
+	exchanges   map[string]bool                  // Exchanges whose book changed
+}
+
+// NewEngine creates a matching engine with empty books
+func NewEngine(config EngineConfig) *Engine {
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+	return &Engine{
+		config:    config,
+		books:     make(map[string]*book),
+		orders:    make(map[string]*types.Order),
+		positions: make(map[string]*types.PerpetualPosition),
+	}
+}
+
+// OnTradeData registers a callback invoked with the private event of each subaccount changed by a message, in
+// execution order. Callbacks run with the engine locked and must not call it.
+func (e *Engine) OnTradeData(fn func(subaccountId string, event types.TradeDataEvent)) {
+	e.mu.Lock()
+	e.onTradeData = append(e.onTradeData, fn)
+	e.mu.Unlock()
+}
+
+// OnDepth registers a callback invoked with a full depth snapshot of each book changed by a message. Callbacks run with
+// the engine locked and must not call it.
+func (e *Engine) OnDepth(fn func(types.DepthData)) {
+	e.mu.Lock()
+	e.onDepth = append(e.onDepth, fn)
+	e.mu.Unlock()
+}
+
+// Execute executes an order message: MsgCreateOrder, MsgCreateOrderBatch, MsgCancelOrder, MsgCancelOrderByClientId or
+// MsgCancelAllOrder
+func (e *Engine) Execute(msg proto.Message) error {
+	switch msg := msg.(type) {
+	case *ordertypes.MsgCreateOrder:
+		_, err := e.CreateOrder(msg)
+		return err
+	case *ordertypes.MsgCreateOrderBatch:
+		_, err := e.CreateOrderBatch(msg)
+		return err
+	case *ordertypes.MsgCancelOrder:
+		return e.CancelOrder(msg)
+	case *ordertypes.MsgCancelOrderByClientId:
+		return e.CancelOrderByClientId(msg)
+	case *ordertypes.MsgCancelAllOrder:
+		return e.CancelAllOrder(msg)
+	default:
+		return fmt.Errorf("%T: %w", msg, ErrUnsupportedMsg)
+	}
+}
+
+// CreateOrder matches an order against the book and rests its remainder, returns the order after matching
+func (e *Engine) CreateOrder(msg *ordertypes.MsgCreateOrder) (types.Order, error) {
+	param := &ordertypes.CreateOrderParam{
+		IsBuy:             msg.IsBuy,
+		PriceScale:        msg.PriceScale,
+		PriceValue:        msg.PriceValue,
+		SizeScale:         msg.SizeScale,
+		SizeValue:         msg.SizeValue,
+		ClientOrderId:     msg.ClientOrderId,
+		TimeInForce:       msg.TimeInForce,
+		ReduceOnly:        msg.ReduceOnly,
+		ExpireTime:        msg.ExpireTime,
+		IsMarket:          msg.IsMarket,
+		IsPositionTp:      msg.IsPositionTp,
+		IsPositionSl:      msg.IsPositionSl,
+		TriggerType:       msg.TriggerType,
+		TriggerPriceType:  msg.TriggerPriceType,
+		TriggerPriceValue: msg.TriggerPriceValue,
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	x := newExecution()
+	order, err := e.submit(x, msg.SubaccountId, msg.ExchangeId, uint32(msg.MarginMode), msg.Leverage, param)
+	if err != nil {
+		return types.Order{}, err
+	}
+	e.notify(x)
+	return *order, nil
+}
+
+// CreateOrderBatch matches the orders of a batch in order, all orders are validated before any is matched
+func (e *Engine) CreateOrderBatch(msg *ordertypes.MsgCreateOrderBatch) ([]types.Order, error) {
+	for i, param := range msg.CreateOrderParam {
+		if err := validateOrderParam(param); err != nil {
+			return nil, fmt.Errorf("order %d: %w", i, err)
+		}
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	x := newExecution()
+	orders := make([]types.Order, 0, len(msg.CreateOrderParam))
+	for i, param := range msg.CreateOrderParam {
+		order, err := e.submit(x, msg.SubaccountId, msg.ExchangeId, uint32(msg.MarginMode), msg.Leverage, param)
+		if err != nil {
+			e.notify(x)
+			return orders, fmt.Errorf("order %d: %w", i, err)
+		}
+		orders = append(orders, *order)
+	}
+	e.notify(x)
+	return orders, nil
+}
+
+// CancelOrder cancels active orders of a subaccount by ID, fails without cancelling any when one is not active
+func (e *Engine) CancelOrder(msg *ordertypes.MsgCancelOrder) error {
+	subaccountId := strconv.FormatUint(msg.SubaccountId, 10)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	orders := make([]*types.Order, 0, len(msg.OrderId))
+	for _, id := range msg.OrderId {
+		order, ok := e.orders[strconv.FormatUint(id, 10)]
+		if !ok || order.SubaccountId != subaccountId || !isActive(order) {
+			return fmt.Errorf("order %d: %w", id, ErrOrderNotFound)
+		}
+		orders = append(orders, order)
+	}
+	e.cancel(orders)
+	return nil
+}
+
+// CancelOrderByClientId cancels active orders of a subaccount by client order ID, fails without cancelling any when one
+// is not active
+func (e *Engine) CancelOrderByClientId(msg *ordertypes.MsgCancelOrderByClientId) error {
+	subaccountId := strconv.FormatUint(msg.SubaccountId, 10)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	orders := make([]*types.Order, 0, len(msg.ClientOrderId))
+	for _, clientOrderId := range msg.ClientOrderId {
+		order := e.activeByClientId(subaccountId, clientOrderId)
+		if order == nil {
+			return fmt.Errorf("client order %s: %w", clientOrderId, ErrOrderNotFound)
+		}
+		orders = append(orders, order)
+	}
+	e.cancel(orders)
+	return nil
+}
+
+// CancelAllOrder cancels the active orders of a subaccount, only those of the filtered exchanges when set
+func (e *Engine) CancelAllOrder(msg *ordertypes.MsgCancelAllOrder) error {
+	subaccountId := strconv.FormatUint(msg.SubaccountId, 10)
+	exchanges := make(map[string]bool, len(msg.FilterExchangeId))
+	for _, id := range msg.FilterExchangeId {
+		exchanges[strconv.FormatUint(id, 10)] = true
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var orders []*types.Order
+	for _, id := range e.orderIds {
+		order := e.orders[id]
+		if order.SubaccountId == subaccountId && isActive(order) && (len(exchanges) == 0 || exchanges[order.ExchangeId]) {
+			orders = append(orders, order)
+		}
+	}
+	e.cancel(orders)
+	return nil
+}
+
+// PlaceLimit places a good-til-cancel limit order, e.g. to seed the book with liquidity of another subaccount
+func (e *Engine) PlaceLimit(subaccountId, exchangeId uint64, isBuy bool, price, size decimal.Decimal) (types.Order, error) {
+	priceScale, priceValue := scaledValue(price)
+	sizeScale, sizeValue := scaledValue(size)
+	return e.CreateOrder(&ordertypes.MsgCreateOrder{
+		SubaccountId: subaccountId,
+		ExchangeId:   exchangeId,
+		IsBuy:        isBuy,
+		PriceScale:   priceScale,
+		PriceValue:   priceValue,
+		SizeScale:    sizeScale,
+		SizeValue:    sizeValue,
+		TimeInForce:  ordertypes.TimeInForce_TIME_IN_FORCE_GOOD_TIL_CANCEL,
+	})
+}
+
+// Order returns an order by ID
+func (e *Engine) Order(orderId string) (types.Order, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	order, ok := e.orders[orderId]
+	if !ok {
+		return types.Order{}, false
+	}
+	return *order, true
+}
+
+// ActiveOrders returns the pending and partially filled orders of a subaccount, oldest first
+func (e *Engine) ActiveOrders(subaccountId string) []types.Order {
+	return e.subaccountOrders(subaccountId, true)
+}
+
+// HistoryOrders returns the filled, cancelled and rejected orders of a subaccount, oldest first
+func (e *Engine) HistoryOrders(subaccountId string) []types.Order {
+	return e.subaccountOrders(subaccountId, false)
+}
+
+// Fills returns the fills of a subaccount, oldest first
+func (e *Engine) Fills(subaccountId string) []types.OrderFillTransaction {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var fills []types.OrderFillTransaction
+	for _, fill := range e.fills {
+		if fill.SubaccountId == subaccountId {
+			fills = append(fills, fill)
+		}
+	}
+	return fills
+}
+
+// Positions returns the positions of a subaccount, by exchange ID
+func (e *Engine) Positions(subaccountId string) []types.PerpetualPosition {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var positions []types.PerpetualPosition
+	for _, position := range e.positions {
+		if position.SubaccountId == subaccountId {
+			positions = append(positions, *position)
+		}
+	}
+	sort.Slice(positions, func(i, j int) bool { return positions[i].ExchangeId < positions[j].ExchangeId })
+	return positions
+}
+
+// Depth returns a depth snapshot of an exchange with up to level levels per side, all levels when level <= 0
+func (e *Engine) Depth(exchangeId string, level int) types.DepthData {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return TruncateDepth(e.depth(exchangeId), level)
+}
+
+// TruncateDepth returns a depth snapshot limited to level levels per side, unchanged when level <= 0
+func TruncateDepth(depth types.DepthData, level int) types.DepthData {
+	if level <= 0 {
+		return depth
+	}
+	if len(depth.Bids) > level {
+		depth.Bids = depth.Bids[:level]
+	}
+	if len(depth.Asks) > level {
+		depth.Asks = depth.Asks[:level]
+	}
+	depth.Level = uint32(level)
+	return depth
+}
+
+// submit creates an order, matches it and rests its remainder, must be called with the lock held
+func (e *Engine) submit(x *execution, subaccountId, exchangeId uint64, marginMode, leverage uint32, param *ordertypes.CreateOrderParam) (*types.Order, error) {
+	if err := validateOrderParam(param); err != nil {
+		return nil, err
+	}
+	subaccount := strconv.FormatUint(subaccountId, 10)
+	if param.ClientOrderId != "" && e.activeByClientId(subaccount, param.ClientOrderId) != nil {
+		return nil, fmt.Errorf("duplicate client order ID %s: %w", param.ClientOrderId, ErrInvalidOrder)
+	}
+
+	price := decimal.New(int64(param.PriceValue), -param.PriceScale)
+	size := decimal.New(int64(param.SizeValue), -param.SizeScale)
+	isMarket := param.IsMarket || param.PriceValue == 0
+	if isMarket {
+		price = decimal.Zero
+	}
+	now := uint64(e.config.Now().UnixMilli())
+	e.lastId++
+	order := &types.Order{
+		Id:              strconv.FormatUint(e.lastId, 10),
+		SubaccountId:    subaccount,
+		ExchangeId:      strconv.FormatUint(exchangeId, 10),
+		IsBuy:           param.IsBuy,
+		Price:           price.String(),
+		Size:            size.String(),
+		ClientOrderId:   param.ClientOrderId,
+		TimeInForce:     uint32(param.TimeInForce),
+		ReduceOnly:      param.ReduceOnly,
+		ExpireTime:      param.ExpireTime,
+		IsPositionTp:    param.IsPositionTp,
+		IsPositionSl:    param.IsPositionSl,
+		MarginMode:      marginMode,
+		Leverage:        leverage,
+		TakerFeeRatePpm: e.config.TakerFeeRatePpm,
+		MakerFeeRatePpm: e.config.MakerFeeRatePpm,
+		Status:          constants.OrderStatusPending,
+		CumFillSize:     "0",
+		CumFillValue:    "0",
+		CumFillFee:      "0",
+		CumRealizePnl:   "0",
+		CreatedTime:     now,
+		UpdatedTime:     now,
+	}
+	e.orders[order.Id] = order
+	e.orderIds = append(e.orderIds, order.Id)
+
+	remaining := size
+	if param.ReduceOnly {
+		if closable := e.closableSize(order); closable.LessThan(remaining) {
+			remaining = closable
+		}
+		if !remaining.IsPositive() {
+			e.finish(x, order, constants.OrderStatusRejected)
+			return order, nil
+		}
+	}
+
+	b := e.book(order.ExchangeId)
+	crosses := func(entry *bookEntry) bool {
+		if isMarket {
+			return true
+		}
+		if order.IsBuy {
+			return entry.price.LessThanOrEqual(price)
+		}
+		return entry.price.GreaterThanOrEqual(price)
+	}
+	opposite := &b.asks
+	if !order.IsBuy {
+		opposite = &b.bids
+	}
+	switch param.TimeInForce {
+	case ordertypes.TimeInForce_TIME_IN_FORCE_POST_ONLY:
+		if len(*opposite) > 0 && crosses((*opposite)[0]) {
+			e.finish(x, order, constants.OrderStatusRejected)
+			return order, nil
+		}
+	case ordertypes.TimeInForce_TIME_IN_FORCE_FILL_OR_KILL:
+		available := decimal.Zero
+		for _, entry := range *opposite {
+			if !crosses(entry) {
+				break
+			}
+			available = available.Add(entry.remaining)
+		}
+		if available.LessThan(remaining) {
+			e.finish(x, order, constants.OrderStatusCancelled)
+			return order, nil
+		}
+	}
+
+	for remaining.IsPositive() && len(*opposite) > 0 && crosses((*opposite)[0]) {
+		maker := (*opposite)[0]
+		fillSize := decimal.Min(remaining, maker.remaining)
+		e.fill(x, maker.order, true, fillSize, maker.price)
+		e.fill(x, order, false, fillSize, maker.price)
+		remaining = remaining.Sub(fillSize)
+		maker.remaining = maker.remaining.Sub(fillSize)
+		if !maker.remaining.IsPositive() {
+			*opposite = (*opposite)[1:]
+			e.finish(x, maker.order, constants.OrderStatusFilled)
+		}
+		x.exchanges[order.ExchangeId] = true
+	}
+
+	switch {
+	case !remaining.IsPositive():
+		e.finish(x, order, constants.OrderStatusFilled)
+	case isMarket || param.TimeInForce == ordertypes.TimeInForce_TIME_IN_FORCE_IMMEDIATE_OR_CANCEL ||
+		param.TimeInForce == ordertypes.TimeInForce_TIME_IN_FORCE_FILL_OR_KILL:
+		e.finish(x, order, constants.OrderStatusCancelled)
+	default:
+		order.AddOrderBookBlockTime = now
+		b.insert(&bookEntry{order: order, price: price, remaining: remaining})
+		x.exchanges[order.ExchangeId] = true
+		x.order(order)
+	}
+	return order, nil
+}
+
+// fill records a fill of an order and updates its position, must be called with the lock held
+func (e *Engine) fill(x *execution, order *types.Order, isMaker bool, size, price decimal.Decimal) {
+	value := size.Mul(price)
+	feeRatePpm := order.TakerFeeRatePpm
+	if isMaker {
+		feeRatePpm = order.MakerFeeRatePpm
+	}
+	// Fees are paid out of collateral, hence negative
+	fee := value.Mul(decimal.NewFromInt(int64(feeRatePpm))).Div(decimal.NewFromInt(1000000)).Neg()
+	delta := size
+	if !order.IsBuy {
+		delta = size.Neg()
+	}
+	position := e.position(order)
+	realizedPnl := applyFill(position, delta, price)
+
+	now := uint64(e.config.Now().UnixMilli())
+	order.CumFillSize = parseDecimal(order.CumFillSize).Add(size).String()
+	order.CumFillValue = parseDecimal(order.CumFillValue).Add(value).String()
+	order.CumFillFee = parseDecimal(order.CumFillFee).Add(fee).String()
+	order.CumRealizePnl = parseDecimal(order.CumRealizePnl).Add(realizedPnl).String()
+	if order.MaxFillPrice == "" || price.GreaterThan(parseDecimal(order.MaxFillPrice)) {
+		order.MaxFillPrice = price.String()
+	}
+	if order.MinFillPrice == "" || price.LessThan(parseDecimal(order.MinFillPrice)) {
+		order.MinFillPrice = price.String()
+	}
+	order.Status = constants.OrderStatusPartiallyFilled
+	order.UpdatedTime = now
+	position.UpdatedTime = now
+
+	e.lastId++
+	fill := types.OrderFillTransaction{
+		Id:           strconv.FormatUint(e.lastId, 10),
+		SubaccountId: order.SubaccountId,
+		CoinId:       order.CoinId,
+		ExchangeId:   order.ExchangeId,
+		OrderId:      order.Id,
+		IsBuy:        order.IsBuy,
+		FillSize:     size.String(),
+		FillValue:    value.String(),
+		FillFee:      fee.String(),
+		FillPrice:    price.String(),
+		RealizePnl:   realizedPnl.String(),
+		IsMaker:      isMaker,
+		IsPositionTp: order.IsPositionTp,
+		IsPositionSl: order.IsPositionSl,
+		BlockTime:    now,
+		CreatedTime:  now,
+		UpdatedTime:  now,
+	}
+	e.fills = append(e.fills, fill)
+	event := x.event(order.SubaccountId)
+	event.OrderFillTransactionList = append(event.OrderFillTransactionList, fill)
+	event.PositionList = upsertPosition(event.PositionList, *position)
+	x.order(order)
+}
+
+// applyFill applies a signed fill size to a position, returns the PnL realized by the part that closes it
+func applyFill(position *types.PerpetualPosition, delta, price decimal.Decimal) decimal.Decimal {
+	size, value := parseDecimal(position.OpenSize), parseDecimal(position.OpenValue)
+	realizedPnl := decimal.Zero
+	if !size.IsZero() && size.Sign() != delta.Sign() {
+		closed := decimal.Min(delta.Abs(), size.Abs())
+		entryPrice := value.Div(size)
+		realizedPnl = closed.Mul(price.Sub(entryPrice))
+		if size.IsNegative() {
+			realizedPnl = realizedPnl.Neg()
+		}
+		value = value.Sub(value.Mul(closed).Div(size.Abs()))
+		if delta.IsNegative() {
+			closed = closed.Neg()
+		}
+		size = size.Add(closed)
+		delta = delta.Sub(closed)
+	}
+	size = size.Add(delta)
+	value = value.Add(delta.Mul(price))
+	position.OpenSize = size.String()
+	position.OpenValue = value.String()
+	return realizedPnl
+}
+
+// cancel cancels active orders and removes them from their books, must be called with the lock held
+func (e *Engine) cancel(orders []*types.Order) {
+	x := newExecution()
+	for _, order := range orders {
+		b := e.book(order.ExchangeId)
+		b.remove(order.Id)
+		x.exchanges[order.ExchangeId] = true
+		e.finish(x, order, constants.OrderStatusCancelled)
+	}
+	e.notify(x)
+}
+
+// finish sets the final status of an order, must be called with the lock held
+func (e *Engine) finish(x *execution, order *types.Order, status uint32) {
+	order.Status = status
+	order.UpdatedTime = uint64(e.config.Now().UnixMilli())
+	x.order(order)
+}
+
+// notify passes the changes of an execution to the callbacks, must be called with the lock held
+func (e *Engine) notify(x *execution) {
+	for _, subaccountId := range x.subaccounts {
+		e.version++
+		event := x.events[subaccountId]
+		event.Version = strconv.FormatUint(e.version, 10)
+		for _, fn := range e.onTradeData {
+			fn(subaccountId, *event)
+		}
+	}
+	exchangeIds := make([]string, 0, len(x.exchanges))
+	for exchangeId := range x.exchanges {
+		exchangeIds = append(exchangeIds, exchangeId)
+	}
+	sort.Strings(exchangeIds)
+	for _, exchangeId := range exchangeIds {
+		e.book(exchangeId).version++
+		depth := e.depth(exchangeId)
+		for _, fn := range e.onDepth {
+			fn(depth)
+		}
+	}
+}
+
+// depth returns a full depth snapshot of an exchange, must be called with the lock held
+func (e *Engine) depth(exchangeId string) types.DepthData {
+	b := e.book(exchangeId)
+	version := strconv.FormatUint(b.version, 10)
+	return types.DepthData{
+		IsSnapshot:   true,
+		StartVersion: version,
+		EndVersion:   version,
+		ExchangeId:   exchangeId,
+		Bids:         bookLevels(b.bids),
+		Asks:         bookLevels(b.asks),
+		UpdatedTime:  uint64(e.config.Now().UnixMilli()),
+	}
+}
+
+// book returns the book of an exchange, creating it when missing
+func (e *Engine) book(exchangeId string) *book {
+	b, ok := e.books[exchangeId]
+	if !ok {
+		b = &book{}
+		e.books[exchangeId] = b
+	}
+	return b
+}
+
+// position returns the position of the subaccount and exchange of an order, creating it when missing
+func (e *Engine) position(order *types.Order) *types.PerpetualPosition {
+	key := order.SubaccountId + "/" + order.ExchangeId
+	position, ok := e.positions[key]
+	if !ok {
+		position = &types.PerpetualPosition{
+			SubaccountId: order.SubaccountId,
+			CoinId:       order.CoinId,
+			ExchangeId:   order.ExchangeId,
+			MarginMode:   order.MarginMode,
+			OpenSize:     "0",
+			OpenValue:    "0",
+			CreatedTime:  order.CreatedTime,
+		}
+		e.positions[key] = position
+	}
+	return position
+}
+
+// closableSize returns the size of the position an order closes, zero when it would open or extend one
+func (e *Engine) closableSize(order *types.Order) decimal.Decimal {
+	position, ok := e.positions[order.SubaccountId+"/"+order.ExchangeId]
+	if !ok {
+		return decimal.Zero
+	}
+	size := parseDecimal(position.OpenSize)
+	if order.IsBuy == size.IsNegative() {
+		return size.Abs()
+	}
+	return decimal.Zero
+}
+
+// activeByClientId returns the active order of a subaccount with a client order ID, nil when there is none
+func (e *Engine) activeByClientId(subaccountId, clientOrderId string) *types.Order {
+	for _, id := range e.orderIds {
+		order := e.orders[id]
+		if order.SubaccountId == subaccountId && order.ClientOrderId == clientOrderId && isActive(order) {
+			return order
+		}
+	}
+	return nil
+}
+
+// subaccountOrders returns the active or final orders of a subaccount, oldest first
+func (e *Engine) subaccountOrders(subaccountId string, active bool) []types.Order {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var orders []types.Order
+	for _, id := range e.orderIds {
+		order := e.orders[id]
+		if order.SubaccountId == subaccountId && isActive(order) == active {
+			orders = append(orders, *order)
+		}
+	}
+	return orders
+}
+
+// insert rests an order behind the orders at the same or a better price
+func (b *book) insert(entry *bookEntry) {
+	side := &b.asks
+	behind := func(other *bookEntry) bool { return other.price.LessThanOrEqual(entry.price) }
+	if entry.order.IsBuy {
+		side = &b.bids
+		behind = func(other *bookEntry) bool { return other.price.GreaterThanOrEqual(entry.price) }
+	}
+	i := sort.Search(len(*side), func(i int) bool { return !behind((*side)[i]) })
+	*side = append(*side, nil)
+	copy((*side)[i+1:], (*side)[i:])
+	(*side)[i] = entry
+}
+
+// remove removes a resting order
+func (b *book) remove(orderId string) {
+	for _, side := range []*[]*bookEntry{&b.bids, &b.asks} {
+		for i, entry := range *side {
+			if entry.order.Id == orderId {
+				*side = append((*side)[:i], (*side)[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// newExecution creates an empty execution
+func newExecution() *execution {
+	return &execution{events: make(map[string]*types.TradeDataEvent), exchanges: make(map[string]bool)}
+}
+
+// event returns the event of a subaccount, creating it when missing
+func (x *execution) event(subaccountId string) *types.TradeDataEvent {
+	event, ok := x.events[subaccountId]
+	if !ok {
+		event = &types.TradeDataEvent{EventType: types.TradeDataEventOrderUpdate}
+		x.events[subaccountId] = event
+		x.subaccounts = append(x.subaccounts, subaccountId)
+	}
+	return event
+}
+
+// order records the latest state of an order in the event of its subaccount
+func (x *execution) order(order *types.Order) {
+	event := x.event(order.SubaccountId)
+	for i := range event.OrderList {
+		if event.OrderList[i].Id == order.Id {
+			event.OrderList[i] = *order
+			return
+		}
+	}
+	event.OrderList = append(event.OrderList, *order)
+}
+
+// upsertPosition replaces the position of the same exchange in a list or appends it
+func upsertPosition(positions []types.PerpetualPosition, position types.PerpetualPosition) []types.PerpetualPosition {
+	for i := range positions {
+		if positions[i].ExchangeId == position.ExchangeId {
+			positions[i] = position
+			return positions
+		}
+	}
+	return append(positions, position)
+}
+
+// bookLevels aggregates resting orders into price levels
+func bookLevels(entries []*bookEntry) []types.BookOrder {
+	levels := make([]types.BookOrder, 0, len(entries))
+	for i := 0; i < len(entries); {
+		price, size := entries[i].price, decimal.Zero
+		for ; i < len(entries) && entries[i].price.Equal(price); i++ {
+			size = size.Add(entries[i].remaining)
+		}
+		levels = append(levels, types.BookOrder{Price: price.String(), Size: size.String()})
+	}
+	return levels
+}
+
+// validateOrderParam rejects the orders the engine cannot execute
+func validateOrderParam(param *ordertypes.CreateOrderParam) error {
+	if param.SizeValue == 0 {
+		return fmt.Errorf("zero size: %w", ErrInvalidOrder)
+	}
+	if param.TriggerType != ordertypes.TriggerType_TRIGGER_TYPE_UNSPECIFIED {
+		return fmt.Errorf("conditional orders are not supported: %w", ErrInvalidOrder)
+	}
+	return nil
+}
+
+// isActive reports whether an order can still fill
+func isActive(order *types.Order) bool {
+	return order.Status == constants.OrderStatusPending || order.Status == constants.OrderStatusPartiallyFilled
+}
+
+// scaledValue splits a decimal into the scale and value of the order messages
+func scaledValue(d decimal.Decimal) (int32, uint64) {
+	return -d.Exponent(), d.Coefficient().Uint64()
+}
+
+// parseDecimal parses a decimal written by the engine
+func parseDecimal(s string) decimal.Decimal {
+	d, _ := decimal.NewFromString(s)
+	return d
+}
@@ -0,0 +1,370 @@
+package testutil
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/query"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	codectypes "github.com/cosmos/cosmos-sdk/codec/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	"github.com/gorilla/websocket"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Gateway mock gateway executing the transactions it receives on a matching engine. It serves the exchange list,
+// account sequence, order, fill and depth queries, and pushes the engine events on the tradeData and depth WebSocket
+// channels, so a client pointed at URL and WSURL runs offline.
+type Gateway struct {
+	Engine *Engine
+
+	server   *httptest.Server
+	upgrader websocket.Upgrader
+
+	mu        sync.Mutex
+	exchanges []types.Exchange
+	accounts  map[string]string // ETH addresses by subaccount ID
+	sequence  uint64
+	conns     map[*gatewayConn]struct{}
+}
+
+// gatewayConn WebSocket connection of the gateway with its subscriptions
+type gatewayConn struct {
+	mu            sync.Mutex
+	conn          *websocket.Conn
+	subscriptions map[string]query.WsRegisterReq // Subscriptions by channel
+}
+
+// NewGateway starts a gateway serving an engine and an exchange list, Close must be called once it is no longer used
+func NewGateway(engine *Engine, exchanges ...types.Exchange) *Gateway {
+	g := &Gateway{
+		Engine:    engine,
+		upgrader:  websocket.Upgrader{CheckOrigin: func(*http.Request) bool { return true }},
+		exchanges: exchanges,
+		accounts:  make(map[string]string),
+		conns:     make(map[*gatewayConn]struct{}),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(constants.GetAddressInfoPath, g.serveAddressInfo)
+	mux.HandleFunc(constants.GetExchangeListPath, g.serveExchangeList)
+	mux.HandleFunc(constants.SendTransactionPath, g.serveSendTransaction)
+	mux.HandleFunc(constants.GetActiveOrderPath, g.serveOrders(true))
+	mux.HandleFunc(constants.GetHistoryOrderPath, g.serveOrders(false))
+	mux.HandleFunc(constants.GetHistoryOrderFillTransactionPath, g.serveFills)
+	mux.HandleFunc(constants.GetDepthPath, g.serveDepth)
+	mux.HandleFunc(constants.WebSocketPath, g.serveWebSocket)
+	g.server = httptest.NewServer(mux)
+	engine.OnTradeData(g.pushTradeData)
+	engine.OnDepth(g.pushDepth)
+	return g
+}
+
+// URL returns the HTTP address of the gateway
+func (g *Gateway) URL() string {
+	return g.server.URL
+}
+
+// WSURL returns the WebSocket address of the gateway
+func (g *Gateway) WSURL() string {
+	return "ws" + strings.TrimPrefix(g.server.URL, "http") + constants.WebSocketPath
+}
+
+// Close closes the WebSocket connections and stops the gateway
+func (g *Gateway) Close() {
+	g.mu.Lock()
+	for c := range g.conns {
+		c.conn.Close()
+	}
+	g.mu.Unlock()
+	g.server.Close()
+}
+
+// AddAccount routes the private events of subaccounts to the tradeData subscriptions of an ETH address. The events of
+// subaccounts without an account reach every tradeData subscription.
+func (g *Gateway) AddAccount(ethAddress string, subaccountIds ...uint64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, id := range subaccountIds {
+		g.accounts[strconv.FormatUint(id, 10)] = ethAddress
+	}
+}
+
+// serveAddressInfo serves the account number and sequence, the sequence counts the transactions received
+func (g *Gateway) serveAddressInfo(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	sequence := g.sequence
+	g.mu.Unlock()
+	writeJSON(w, types.GetAccountNumberAndSequenceResponse{
+		BaseResp: types.BaseResp{Code: "0", Msg: "success"},
+		Data: types.GetAccountNumberAndSequenceResponseData{
+			Exist:         true,
+			AccountNumber: "1",
+			Sequence:      strconv.FormatUint(sequence, 10),
+		},
+	})
+}
+
+// serveExchangeList serves the exchange list
+func (g *Gateway) serveExchangeList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, types.GetExchangeListResponse{
+		BaseResp: types.BaseResp{Code: "0", Msg: "success"},
+		Data:     types.GetExchangeListRespData{ExchangeList: g.exchanges},
+	})
+}
+
+// serveSendTransaction executes the messages of a transaction in order, those before a failing message stay executed
+func (g *Gateway) serveSendTransaction(w http.ResponseWriter, r *http.Request) {
+	var req types.SendRawTxRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, types.SendRawTxResponse{BaseResp: types.BaseResp{Code: "400", Msg: err.Error()}})
+		return
+	}
+	txBytes, err := base64.StdEncoding.DecodeString(req.RawTx)
+	if err != nil {
+		writeJSON(w, types.SendRawTxResponse{BaseResp: types.BaseResp{Code: "400", Msg: err.Error()}})
+		return
+	}
+	msgs, err := DecodeTxMsgs(txBytes)
+	if err != nil {
+		writeJSON(w, types.SendRawTxResponse{BaseResp: types.BaseResp{Code: "400", Msg: err.Error()}})
+		return
+	}
+	g.mu.Lock()
+	g.sequence++
+	g.mu.Unlock()
+	for _, msg := range msgs {
+		if err := g.Engine.Execute(msg); err != nil {
+			writeJSON(w, types.SendRawTxResponse{BaseResp: types.BaseResp{Code: "500", Msg: err.Error()}})
+			return
+		}
+	}
+	hash := sha256.Sum256(txBytes)
+	writeJSON(w, types.SendRawTxResponse{
+		BaseResp: types.BaseResp{Code: "0", Msg: "success"},
+		Data:     types.SendRawTxResponseData{TxHash: strings.ToUpper(hex.EncodeToString(hash[:])), RawTx: req.RawTx},
+	})
+}
+
+// serveOrders serves the active or history orders of a subaccount, in one page
+func (g *Gateway) serveOrders(active bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		orders := g.Engine.HistoryOrders(q.Get("subaccountId"))
+		if active {
+			orders = g.Engine.ActiveOrders(q.Get("subaccountId"))
+		}
+		exchangeIds, orderIds := listFilter(q.Get("filterExchangeIdList")), listFilter(q.Get("filterOrderIdList"))
+		filtered := make([]types.Order, 0, len(orders))
+		for _, order := range orders {
+			if exchangeIds.match(order.ExchangeId) && orderIds.match(order.Id) {
+				filtered = append(filtered, order)
+			}
+		}
+		writeJSON(w, types.GetActiveOrderResp{
+			BaseResp: types.BaseResp{Code: "0", Msg: "success"},
+			Data:     types.GetActiveOrderRespData{OrderList: filtered},
+		})
+	}
+}
+
+// serveFills serves the fills of a subaccount, in one page
+func (g *Gateway) serveFills(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	exchangeIds, orderIds := listFilter(q.Get("filterExchangeIdList")), listFilter(q.Get("filterOrderIdList"))
+	var fills []types.OrderFillTransaction
+	for _, fill := range g.Engine.Fills(q.Get("subaccountId")) {
+		if exchangeIds.match(fill.ExchangeId) && orderIds.match(fill.OrderId) {
+			fills = append(fills, fill)
+		}
+	}
+	writeJSON(w, types.GetHistoryOrderFillTransactionResp{
+		BaseResp: types.BaseResp{Code: "0", Msg: "success"},
+		Data:     types.GetHistoryOrderFillTransactionRespData{OrderFillTransactionList: fills},
+	})
+}
+
+// serveDepth serves a depth snapshot
+func (g *Gateway) serveDepth(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	level, _ := strconv.Atoi(q.Get("level"))
+	writeJSON(w, types.GetDepthResp{
+		BaseResp: types.BaseResp{Code: "0", Msg: "success"},
+		Data:     types.GetDepthRespData{DepthList: []types.DepthData{g.Engine.Depth(q.Get("exchangeId"), level)}},
+	})
+}
+
+// serveWebSocket serves a WebSocket connection, a depth subscription is answered with a snapshot
+func (g *Gateway) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := g.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	c := &gatewayConn{conn: conn, subscriptions: make(map[string]query.WsRegisterReq)}
+	g.mu.Lock()
+	g.conns[c] = struct{}{}
+	g.mu.Unlock()
+	defer func() {
+		g.mu.Lock()
+		delete(g.conns, c)
+		g.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		var req query.WsSubscribeReq
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		channel := req.Subscription.Channel
+		switch req.Method {
+		case "subscribe":
+			c.mu.Lock()
+			c.subscriptions[channel] = req.Subscription
+			c.mu.Unlock()
+			if exchangeId, level, ok := depthChannel(channel); ok {
+				c.send(depthPush(channel, g.Engine.Depth(exchangeId, level)))
+			}
+		case "unsubscribe":
+			c.mu.Lock()
+			delete(c.subscriptions, channel)
+			c.mu.Unlock()
+		}
+	}
+}
+
+// pushTradeData pushes the private event of a subaccount to the tradeData subscriptions of its account
+func (g *Gateway) pushTradeData(subaccountId string, event types.TradeDataEvent) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	account := g.accounts[subaccountId]
+	for c := range g.conns {
+		c.mu.Lock()
+		subscription, ok := c.subscriptions["tradeData"]
+		c.mu.Unlock()
+		if !ok || (account != "" && !strings.EqualFold(account, subscription.ChainAddress)) {
+			continue
+		}
+		c.send(map[string]interface{}{"channel": "tradeData", "user": subscription.ChainAddress, "data": event})
+	}
+}
+
+// pushDepth pushes a depth snapshot to the depth subscriptions of its exchange
+func (g *Gateway) pushDepth(depth types.DepthData) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for c := range g.conns {
+		c.mu.Lock()
+		var channels []string
+		for channel := range c.subscriptions {
+			channels = append(channels, channel)
+		}
+		c.mu.Unlock()
+		for _, channel := range channels {
+			if exchangeId, level, ok := depthChannel(channel); ok && exchangeId == depth.ExchangeId {
+				c.send(depthPush(channel, TruncateDepth(depth, level)))
+			}
+		}
+	}
+}
+
+// send writes a message, a failing connection is closed and dropped by its read loop
+func (c *gatewayConn) send(v interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.conn.WriteJSON(v); err != nil {
+		c.conn.Close()
+	}
+}
+
+// DecodeTxMsgs decodes the order messages of a signed transaction, unwrapping authz MsgExec messages
+func DecodeTxMsgs(txBytes []byte) ([]proto.Message, error) {
+	var raw txtypes.TxRaw
+	if err := raw.Unmarshal(txBytes); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction: %w", err)
+	}
+	var body txtypes.TxBody
+	if err := body.Unmarshal(raw.BodyBytes); err != nil {
+		return nil, fmt.Errorf("failed to decode transaction body: %w", err)
+	}
+	return decodeAnyMsgs(body.Messages)
+}
+
+// decodeAnyMsgs decodes packed messages by type URL
+func decodeAnyMsgs(packed []*codectypes.Any) ([]proto.Message, error) {
+	var msgs []proto.Message
+	for _, msgAny := range packed {
+		if msgAny.TypeUrl == constants.MsgExecTypeURL {
+			var exec authz.MsgExec
+			if err := exec.Unmarshal(msgAny.Value); err != nil {
+				return nil, fmt.Errorf("failed to decode %s: %w", msgAny.TypeUrl, err)
+			}
+			inner, err := decodeAnyMsgs(exec.Msgs)
+			if err != nil {
+				return nil, err
+			}
+			msgs = append(msgs, inner...)
+			continue
+		}
+		messageType, err := protoregistry.GlobalTypes.FindMessageByURL(msgAny.TypeUrl)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", msgAny.TypeUrl, ErrUnsupportedMsg)
+		}
+		msg := messageType.New().Interface()
+		if err := proto.Unmarshal(msgAny.Value, msg); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", msgAny.TypeUrl, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// depthChannel parses a depth channel name, depth.<exchange ID>.<level>
+func depthChannel(channel string) (string, int, bool) {
+	parts := strings.Split(channel, ".")
+	if len(parts) != 3 || parts[0] != "depth" {
+		return "", 0, false
+	}
+	level, _ := strconv.Atoi(parts[2])
+	return parts[1], level, true
+}
+
+// depthPush returns the WebSocket push of a depth snapshot
+func depthPush(channel string, depth types.DepthData) interface{} {
+	return map[string]interface{}{"channel": channel, "event": "payload", "data": []types.DepthData{depth}}
+}
+
+// idFilter comma separated ID list filter of the gateway queries, empty matches all
+type idFilter map[string]bool
+
+// listFilter parses a comma separated ID list
+func listFilter(list string) idFilter {
+	filter := make(idFilter)
+	for _, id := range strings.Split(list, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			filter[id] = true
+		}
+	}
+	return filter
+}
+
+// match reports whether an ID passes the filter
+func (f idFilter) match(id string) bool {
+	return len(f) == 0 || f[id]
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
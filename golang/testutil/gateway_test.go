@@ -0,0 +1,190 @@
+package testutil_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	exchangetypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/exchange"
+	ordertypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/order"
+	sdk "github.com/antxprotocol/antx-sdk-golang"
+	"github.com/antxprotocol/antx-sdk-golang/query"
+	"github.com/antxprotocol/antx-sdk-golang/testutil"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	testSubaccountId   = 1 // Subaccount of the client
+	testCounterpartyId = 2 // Subaccount trading against the client on the engine
+	testExchangeId     = 200001
+)
+
+// testExchange exchange listed by the test gateways
+var testExchange = types.Exchange{
+	Id:            "200001",
+	Symbol:        "BTC-USDT",
+	StepSizeScale: 3,
+	TickSizeScale: 1,
+	Perpetual:     types.Perpetual{DefaultLeverage: 1, EnableOrderCreate: true, EnableOrderFill: true, EnablePositionOpen: true},
+}
+
+// newTestGateway starts a gateway over a new engine and a client of it, whose tradeData subscriptions receive the events
+// of the test subaccount
+func newTestGateway(t *testing.T) (*testutil.Gateway, *sdk.AntxClient) {
+	t.Helper()
+	gateway := testutil.NewGateway(testutil.NewEngine(testutil.EngineConfig{}), testExchange)
+	t.Cleanup(gateway.Close)
+	client, err := sdk.NewAntxClientWithConfig(sdk.Config{
+		GatewayHost:     gateway.URL(),
+		WebSocketURL:    gateway.WSURL(),
+		ChainID:         "antx-devnet",
+		EthPrivateKey:   strings.Repeat("11", 32),
+		AgentPrivateKey: strings.Repeat("22", 32),
+		Logger:          query.NopLogger(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gateway.AddAccount(client.GetEthAddress(), testSubaccountId)
+	return gateway, client
+}
+
+// waitFor polls cond until it holds, failing the test after a few seconds
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// TestOrderFillLoop places an order through the client, fills it on the engine and follows the fill back through the
+// tradeData push to the fill stream and the order tracker
+func TestOrderFillLoop(t *testing.T) {
+	gateway, client := newTestGateway(t)
+
+	fills := make(chan types.OrderFillTransaction, 10)
+	var stream *sdk.FillStream
+	stream, err := client.NewFillStream(sdk.FillStreamConfig{
+		SubaccountId: strconv.Itoa(testSubaccountId),
+		OnFill: func(fill types.OrderFillTransaction) {
+			// Reading the resume point to persist it from the handler must not block the stream
+			_ = stream.LastFillTime()
+			fills <- fill
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := stream.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Stop()
+
+	if _, err := client.CreateOrder(&sdk.CreateOrderParam{
+		SubaccountId:  testSubaccountId,
+		ExchangeId:    testExchangeId,
+		MarginMode:    exchangetypes.MarginMode_MARGIN_MODE_CROSS,
+		Leverage:      1,
+		IsBuy:         true,
+		PriceScale:    1,
+		PriceValue:    1000000, // 100000.0
+		SizeScale:     3,
+		SizeValue:     500, // 0.500
+		ClientOrderId: "loop-1",
+		TimeInForce:   ordertypes.TimeInForce_TIME_IN_FORCE_GOOD_TIL_CANCEL,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker := client.OrderTracker(testSubaccountId)
+	if err := tracker.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	order, ok := tracker.Get("loop-1")
+	if !ok || order.OrderId == "" || !order.Size.Equal(decimal.RequireFromString("0.5")) {
+		t.Fatalf("tracked order %+v, found %v, want the resting order of size 0.5", order, ok)
+	}
+
+	if _, err := gateway.Engine.PlaceLimit(testCounterpartyId, testExchangeId, false, decimal.RequireFromString("100000"), decimal.RequireFromString("0.2")); err != nil {
+		t.Fatal(err)
+	}
+	var fill types.OrderFillTransaction
+	select {
+	case fill = <-fills:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the fill")
+	}
+	if fill.OrderId != order.OrderId || !decimal.RequireFromString(fill.FillSize).Equal(decimal.RequireFromString("0.2")) {
+		t.Errorf("fill of order %s size %s, want order %s size 0.2", fill.OrderId, fill.FillSize, order.OrderId)
+	}
+	waitFor(t, "the resume point to reach the fill", func() bool { return stream.LastFillTime() >= fill.CreatedTime })
+
+	if err := tracker.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if order, _ := tracker.Get("loop-1"); !order.Size.Equal(decimal.RequireFromString("0.3")) {
+		t.Errorf("tracked size %s after the fill, want 0.3", order.Size)
+	}
+}
+
+// TestQuoteRefillAfterFill fills a quote on the engine and checks that the next quote update replaces its level instead
+// of keeping the filled order
+func TestQuoteRefillAfterFill(t *testing.T) {
+	gateway, client := newTestGateway(t)
+	if err := client.ConnectWebSocket(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer client.DisconnectWebSocket()
+	stop, err := client.TrackOrders(testSubaccountId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	bid := sdk.Quote{Price: decimal.RequireFromString("99990"), Size: decimal.RequireFromString("0.1")}
+	ask := sdk.Quote{Price: decimal.RequireFromString("100010"), Size: decimal.RequireFromString("0.1")}
+	update, err := client.UpdateQuotes(testSubaccountId, testExchange.Id, []sdk.Quote{bid}, []sdk.Quote{ask})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(update.Created) != 2 {
+		t.Fatalf("created %d quotes, want 2", len(update.Created))
+	}
+	tracker := client.OrderTracker(testSubaccountId)
+	bidOrder := update.Created[0].ClientOrderId
+	waitFor(t, "the quotes to be acknowledged", func() bool {
+		order, ok := tracker.Get(bidOrder)
+		return ok && order.OrderId != ""
+	})
+
+	if _, err := gateway.Engine.PlaceLimit(testCounterpartyId, testExchangeId, false, bid.Price, bid.Size); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "the filled bid to leave the tracker", func() bool {
+		_, ok := tracker.Get(bidOrder)
+		return !ok
+	})
+
+	update, err = client.UpdateQuotes(testSubaccountId, testExchange.Id, []sdk.Quote{bid}, []sdk.Quote{ask})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(update.Created) != 1 || !update.Created[0].IsBuy || len(update.Kept) != 1 || update.Kept[0].IsBuy {
+		t.Fatalf("created %d and kept %d quotes, want the bid created and the ask kept", len(update.Created), len(update.Kept))
+	}
+	bids := 0
+	for _, order := range gateway.Engine.ActiveOrders(strconv.Itoa(testSubaccountId)) {
+		if order.IsBuy && decimal.RequireFromString(order.Price).Equal(bid.Price) {
+			bids++
+		}
+	}
+	if bids != 1 {
+		t.Errorf("%d active bids at %s on the engine, want 1", bids, bid.Price)
+	}
+}
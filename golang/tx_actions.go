@@ -0,0 +1,55 @@
+package sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// ErrUnknownTxAction the type URL of an explorer transaction action is not a known chain message
+var ErrUnknownTxAction = errors.New("unknown transaction action type")
+
+// DecodeTxAction decodes the detail of an explorer transaction action into the chain message of its type URL, e.g. a
+// *order.MsgCreateOrder for constants.MsgCreateOrderTypeURL or a *agent.MsgBindAgent for constants.MsgBindAgentTypeURL.
+// Any message of the antx-proto packages is known.
+func DecodeTxAction(action types.ExplorerTxAction) (proto.Message, error) {
+	messageType, err := protoregistry.GlobalTypes.FindMessageByURL(action.TypeUrl)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", action.TypeUrl, ErrUnknownTxAction)
+	}
+	var data []byte
+	switch detail := action.Detail.(type) {
+	case string:
+		data = []byte(detail)
+	case nil:
+		data = []byte("{}")
+	default:
+		if data, err = json.Marshal(detail); err != nil {
+			return nil, fmt.Errorf("failed to encode %s detail: %w", action.TypeUrl, err)
+		}
+	}
+	msg := messageType.New().Interface()
+	// Accept both JSON and proto field names, and skip fields added by the explorer or newer chain versions
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, msg); err != nil {
+		return nil, fmt.Errorf("failed to decode %s detail: %w", action.TypeUrl, err)
+	}
+	return msg, nil
+}
+
+// DecodeTxActions decodes the actions of a transaction result, in order
+func DecodeTxActions(result *types.GetTransactionResultRespData) ([]proto.Message, error) {
+	msgs := make([]proto.Message, 0, len(result.ActionList))
+	for i, action := range result.ActionList {
+		msg, err := DecodeTxAction(action)
+		if err != nil {
+			return nil, fmt.Errorf("action %d: %w", i, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
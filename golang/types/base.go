@@ -1,9 +1,35 @@
 package types
 
+import "fmt"
+
 // BaseResp base response structure
 type BaseResp struct {
-	Code string `json:"code"` // Response code
-	Msg  string `json:"msg"`  // Response message
+	Code    string `json:"code"`              // Response code
+	Msg     string `json:"msg"`               // Response message
+	TraceId string `json:"traceId,omitempty"` // Trace ID of the gateway, empty when the response carries none
+}
+
+// Base returns the response code, the message and the trace ID, promoted to the responses embedding BaseResp
+func (r BaseResp) Base() BaseResp {
+	return r
+}
+
+// APIError gateway response with a response code other than "0", matched with errors.As
+type APIError struct {
+	Code       string // Response code
+	Msg        string // Response message
+	HTTPStatus int    // HTTP status code, 0 when unknown
+	TraceId    string // Trace ID of the gateway, to quote when reporting an issue
+	Path       string // Request path
+}
+
+// Error returns the path, the code and the message
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s failed: code %s: %s", e.Path, e.Code, e.Msg)
+	if e.TraceId != "" {
+		msg += fmt.Sprintf(" (trace %s)", e.TraceId)
+	}
+	return msg
 }
 
 // IndexerPageOffsetData pagination offset data
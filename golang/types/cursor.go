@@ -0,0 +1,69 @@
+package types
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Cursor resume token of a paginated history query, the creation time and item ID of the next page encoded as one
+// base64 string, so a position in the history can be persisted as a single value. The zero Cursor is the first page.
+//
+//	cursor := resp.Data.PageOffsetData.Cursor()
+//	req.PageOffsetDataCreatedTime, req.PageOffsetDataItemId = cursor.CreatedTime, cursor.ItemId
+type Cursor struct {
+	CreatedTime string // Pagination offset data, creation time
+	ItemId      string // Pagination offset data, itemId
+}
+
+// Cursor returns the cursor of the next page, the zero Cursor when there is no next page
+func (d IndexerPageOffsetData) Cursor() Cursor {
+	if d.ItemId == "" {
+		return Cursor{}
+	}
+	return Cursor{CreatedTime: d.CreateTime, ItemId: d.ItemId}
+}
+
+// ParseCursor decodes a cursor encoded by Cursor.String, an empty string is the zero Cursor
+func ParseCursor(s string) (Cursor, error) {
+	if s == "" {
+		return Cursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("invalid cursor: %w", err)
+	}
+	createdTime, itemId, ok := strings.Cut(string(raw), ":")
+	if !ok || itemId == "" {
+		return Cursor{}, fmt.Errorf("invalid cursor %q", s)
+	}
+	return Cursor{CreatedTime: createdTime, ItemId: itemId}, nil
+}
+
+// IsZero reports whether the cursor is the first page
+func (c Cursor) IsZero() bool {
+	return c.ItemId == ""
+}
+
+// String encodes the cursor, the zero Cursor encodes to an empty string
+func (c Cursor) String() string {
+	if c.IsZero() {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(c.CreatedTime + ":" + c.ItemId))
+}
+
+// MarshalText encodes the cursor as its string, so it can be stored in JSON
+func (c Cursor) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText decodes a cursor encoded by MarshalText
+func (c *Cursor) UnmarshalText(text []byte) error {
+	cursor, err := ParseCursor(string(text))
+	if err != nil {
+		return err
+	}
+	*c = cursor
+	return nil
+}
@@ -1,11 +1,5 @@
 package types
 
-import (
-	exchangetypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/exchange"
-	ordertypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/order"
-	pricetypes "github.com/antxprotocol/antx-proto/gen/go/antx/chain/price"
-)
-
 // =============================== Base Response Types ===============================
 // BaseResp is already defined in base.go, not repeated here
 
@@ -24,6 +18,16 @@ type GetAccountNumberAndSequenceResponse struct {
 	Data GetAccountNumberAndSequenceResponseData `json:"data"`
 }
 
+// AddressInfo chain account and trading subaccounts of an address
+type AddressInfo struct {
+	Address        string       `json:"address"`        // Antx address
+	EthAddress     string       `json:"ethAddress"`     // Checksummed ETH address
+	Exist          bool         `json:"exist"`          // Whether the chain account exists
+	AccountNumber  uint64       `json:"accountNumber"`  // Chain account number
+	Sequence       uint64       `json:"sequence"`       // Chain account sequence
+	SubaccountList []Subaccount `json:"subaccountList"` // Trading subaccounts owned by the address
+}
+
 // =============================== Subaccount Related Types ===============================
 
 // GetSubaccountListResponse get subaccount list response
@@ -139,7 +143,9 @@ type SendRawTxRequest struct {
 // SendRawTxResponse send raw transaction response
 type SendRawTxResponse struct {
 	BaseResp
-	Data SendRawTxResponseData `json:"data"`
+	RequestTime  string                `json:"requestTime,omitempty"`  // Time the gateway received the request, unit: milliseconds
+	ResponseTime string                `json:"responseTime,omitempty"` // Time the gateway responded, unit: milliseconds
+	Data         SendRawTxResponseData `json:"data"`
 }
 
 // SendRawTxResponseData send raw transaction response data
@@ -196,73 +202,12 @@ type ExplorerTxAction struct {
 
 // =============================== Order Related Types ===============================
 
-// CreateOrderParam create order parameter
-type CreateOrderParam struct {
-	AgentAddress          string
-	SubaccountId          uint64
-	ExchangeId            uint64
-	MarginMode            exchangetypes.MarginMode
-	Leverage              uint32
-	IsBuy                 bool
-	PriceScale            int32
-	PriceValue            uint64
-	SizeScale             int32
-	SizeValue             uint64
-	ClientOrderId         string
-	TimeInForce           ordertypes.TimeInForce
-	ReduceOnly            bool
-	ExpireTime            uint64
-	IsMarket              bool
-	IsPositionTp          bool
-	IsPositionSl          bool
-	TriggerType           ordertypes.TriggerType
-	TriggerPriceType      pricetypes.PriceType
-	TriggerPriceValue     uint64
-	OpenTpslParentOrderId uint64
-	IsSetOpenTp           bool
-	OpenTpParam           ordertypes.OpenTpSlParam
-	IsSetOpenSl           bool
-	OpenSlParam           ordertypes.OpenTpSlParam
-}
-
-// CreateOrderBatchParam create order batch parameter
-type CreateOrderBatchParam struct {
-	AgentAddress     string
-	SubaccountId     uint64
-	ExchangeId       uint64
-	MarginMode       exchangetypes.MarginMode
-	Leverage         uint32
-	CreateOrderParam []*CreateOrderBatchDetail
-}
-
-// CreateOrderBatchDetail create order batch detail
-type CreateOrderBatchDetail struct {
-	IsBuy             bool
-	PriceScale        int32
-	PriceValue        uint64
-	SizeScale         int32
-	SizeValue         uint64
-	ClientOrderId     string
-	TimeInForce       ordertypes.TimeInForce
-	ReduceOnly        bool
-	ExpireTime        uint64
-	IsMarket          bool
-	IsPositionTp      bool
-	IsPositionSl      bool
-	TriggerType       ordertypes.TriggerType
-	TriggerPriceType  pricetypes.PriceType
-	TriggerPriceValue uint64
-	IsSetOpenTp       bool
-	OpenTpParam       ordertypes.OpenTpSlParam
-	IsSetOpenSl       bool
-	OpenSlParam       ordertypes.OpenTpSlParam
-}
-
 // CancelOrderParam cancel order parameter
 type CancelOrderParam struct {
 	AgentAddress string
 	SubaccountId uint64
 	OrderIdList  []uint64
+	GasLimit     uint64
 }
 
 // CancelOrderByClientIdParam cancel order by client ID parameter
@@ -270,6 +215,7 @@ type CancelOrderByClientIdParam struct {
 	AgentAddress      string
 	SubaccountId      uint64
 	ClientOrderIdList []string
+	GasLimit          uint64
 }
 
 // CancelAllOrderParam cancel all orders parameter
@@ -277,6 +223,7 @@ type CancelAllOrderParam struct {
 	AgentAddress         string
 	SubaccountId         uint64
 	FilterExchangeIdList []uint64
+	GasLimit             uint64
 }
 
 // CloseAllPositionParam close all positions parameter
@@ -284,4 +231,5 @@ type CloseAllPositionParam struct {
 	AgentAddress         string
 	SubaccountId         uint64
 	FilterExchangeIdList []uint64
+	GasLimit             uint64
 }
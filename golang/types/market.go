@@ -1,6 +1,9 @@
 package types
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 // =============================== Market Data Related Structures ===============================
 
@@ -50,10 +53,14 @@ type TickerData struct {
 
 // DepthData depth data
 type DepthData struct {
-	ExchangeId  string      `json:"exchangeId"`  // Exchange ID
-	Bids        []BookOrder `json:"bids"`        // Buy order list
-	Asks        []BookOrder `json:"asks"`        // Sell order list
-	UpdatedTime uint64      `json:"updatedTime"` // Updated time
+	IsSnapshot   bool        `json:"isSnapshot"`   // Whether it is a full snapshot rather than an incremental update
+	StartVersion string      `json:"startVersion"` // Start version
+	EndVersion   string      `json:"endVersion"`   // End version
+	Level        uint32      `json:"level"`        // Depth level
+	ExchangeId   string      `json:"exchangeId"`   // Exchange ID
+	Bids         []BookOrder `json:"bids"`         // Buy order list
+	Asks         []BookOrder `json:"asks"`         // Sell order list
+	UpdatedTime  uint64      `json:"updatedTime"`  // Updated time
 }
 
 // BookOrder order book order
@@ -138,6 +145,55 @@ type GetFundingHistoryResp struct {
 	Data GetFundingHistoryRespData `json:"data,omitempty"`
 }
 
+// GetTickerReq get ticker request
+type GetTickerReq struct {
+	ExchangeId string `form:"exchangeId"` // Exchange ID
+}
+
+// GetTickerRespData get ticker response data
+type GetTickerRespData struct {
+	TickerList []TickerData `json:"tickerList"` // Ticker list
+}
+
+// GetTickerResp get ticker response
+type GetTickerResp struct {
+	BaseResp
+	Data GetTickerRespData `json:"data,omitempty"`
+}
+
+// GetDepthReq get order book depth request
+type GetDepthReq struct {
+	ExchangeId string `form:"exchangeId"`     // Exchange ID
+	Level      uint32 `form:"level,optional"` // Depth level, number of price levels per side
+}
+
+// GetDepthRespData get order book depth response data
+type GetDepthRespData struct {
+	DepthList []DepthData `json:"depthList"` // Depth list
+}
+
+// GetDepthResp get order book depth response
+type GetDepthResp struct {
+	BaseResp
+	Data GetDepthRespData `json:"data,omitempty"`
+}
+
+// MarketSnapshotEntry market data of one exchange in a MarketSnapshot
+type MarketSnapshotEntry struct {
+	Exchange     Exchange     `json:"exchange"`          // Exchange
+	Ticker       TickerData   `json:"ticker"`            // 24h ticker
+	Funding      *FundingRate `json:"funding,omitempty"` // Latest funding rate, nil when the exchange has none
+	OpenInterest string       `json:"openInterest"`      // Open interest, from the ticker
+	Error        string       `json:"error,omitempty"`   // Failure of the requests of the exchange, the entry is empty then
+}
+
+// MarketSnapshot market data of all exchanges captured together
+type MarketSnapshot struct {
+	CapturedAt time.Time                      `json:"capturedAt"` // Time the requests were sent
+	Elapsed    time.Duration                  `json:"elapsed"`    // Time taken to capture all exchanges
+	Markets    map[string]MarketSnapshotEntry `json:"markets"`    // Market data by exchange ID
+}
+
 // =============================== Helper Methods ===============================
 
 // =============================== Helper Methods ===============================
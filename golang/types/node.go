@@ -0,0 +1,94 @@
+package types
+
+import "encoding/json"
+
+// =============================== Node REST API Types ===============================
+
+// NodeResp error fields of a cosmos-sdk REST API response, Code is 0 on success
+type NodeResp struct {
+	Code    int    `json:"code"`    // gRPC status code of a failed query
+	Message string `json:"message"` // Error message of a failed query
+}
+
+// NodePageResp pagination of a cosmos-sdk REST API list response
+type NodePageResp struct {
+	NextKey string `json:"next_key"` // Key of the next page, empty on the last page
+	Total   string `json:"total"`    // Total number of entries when requested
+}
+
+// FeeGrant fee allowance granted by a granter to a grantee
+type FeeGrant struct {
+	Granter   string          `json:"granter"`   // Address paying the fees
+	Grantee   string          `json:"grantee"`   // Address whose fees are paid
+	Allowance json.RawMessage `json:"allowance"` // Allowance object, its "@type" is e.g. "/cosmos.feegrant.v1beta1.BasicAllowance"
+}
+
+// GetFeeGrantResp fee grant query response
+type GetFeeGrantResp struct {
+	NodeResp
+	Allowance FeeGrant `json:"allowance"`
+}
+
+// GetFeeGrantListResp fee grant list query response
+type GetFeeGrantListResp struct {
+	NodeResp
+	Allowances []FeeGrant    `json:"allowances"`
+	Pagination *NodePageResp `json:"pagination"`
+}
+
+// AuthzGrant authorization granted by a granter to a grantee
+type AuthzGrant struct {
+	Granter       string          `json:"granter"`       // Address granting the authorization
+	Grantee       string          `json:"grantee"`       // Address allowed to execute messages of the granter
+	Authorization json.RawMessage `json:"authorization"` // Authorization object, its "@type" is e.g. "/cosmos.authz.v1beta1.GenericAuthorization"
+	Expiration    string          `json:"expiration"`    // Expiration time in RFC 3339, empty when the grant does not expire
+}
+
+// GetAuthzGrantListResp authz grant list query response
+type GetAuthzGrantListResp struct {
+	NodeResp
+	Grants     []AuthzGrant  `json:"grants"`
+	Pagination *NodePageResp `json:"pagination"`
+}
+
+// NodeBlock block header of the node REST API
+type NodeBlock struct {
+	BlockId struct {
+		Hash string `json:"hash"` // Block hash in base64
+	} `json:"block_id"`
+	Block struct {
+		Header struct {
+			Height          string `json:"height"`           // Block height
+			Time            string `json:"time"`             // Block time in RFC 3339
+			ProposerAddress string `json:"proposer_address"` // Proposer address in base64
+		} `json:"header"`
+		Data struct {
+			Txs []string `json:"txs"` // Raw transactions in base64
+		} `json:"data"`
+	} `json:"block"`
+}
+
+// GetNodeBlockResp block query response
+type GetNodeBlockResp struct {
+	NodeResp
+	NodeBlock
+}
+
+// NodeTxResponse result of a transaction included in a block
+type NodeTxResponse struct {
+	Height    string `json:"height"`     // Block height
+	TxHash    string `json:"txhash"`     // Transaction hash
+	Codespace string `json:"codespace"`  // Codespace of the error, empty on success
+	Code      uint32 `json:"code"`       // Code of the error within the codespace, 0 on success
+	RawLog    string `json:"raw_log"`    // Failure log
+	GasWanted string `json:"gas_wanted"` // Gas limit
+	GasUsed   string `json:"gas_used"`   // Gas used
+	Timestamp string `json:"timestamp"`  // Block time in RFC 3339
+}
+
+// GetNodeTxListResp transaction search response
+type GetNodeTxListResp struct {
+	NodeResp
+	TxResponses []NodeTxResponse `json:"tx_responses"`
+	Total       string           `json:"total"` // Number of matching transactions
+}
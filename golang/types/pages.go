@@ -0,0 +1,31 @@
+package types
+
+// ForEachPage reads the pages of a paginated history query from the first one. fetch gets the page at a cursor, of at
+// most size records, and returns its records and pagination data, visit is called with the records of each page and
+// returns false to stop. The pages end at the first page shorter than size or without a next page.
+func ForEachPage[T any](size uint32, fetch func(cursor Cursor) ([]T, IndexerPageOffsetData, error), visit func(records []T) bool) error {
+	var cursor Cursor
+	for {
+		records, next, err := fetch(cursor)
+		if err != nil {
+			return err
+		}
+		if !visit(records) {
+			return nil
+		}
+		cursor = next.Cursor()
+		if len(records) < int(size) || cursor.IsZero() {
+			return nil
+		}
+	}
+}
+
+// FetchAllPages reads the records of all the pages of a paginated history query, see ForEachPage
+func FetchAllPages[T any](size uint32, fetch func(cursor Cursor) ([]T, IndexerPageOffsetData, error)) ([]T, error) {
+	var all []T
+	err := ForEachPage(size, fetch, func(records []T) bool {
+		all = append(all, records...)
+		return true
+	})
+	return all, err
+}
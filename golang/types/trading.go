@@ -269,6 +269,30 @@ type OrderFillTransaction struct {
 	UpdatedTime                           uint64 `json:"updatedTime"`                           // Updated time
 }
 
+// Trade data event types
+const (
+	TradeDataEventUnspecified       uint32 = 0 // Unspecified
+	TradeDataEventAgentBindCreate   uint32 = 1 // Agent binding creation event
+	TradeDataEventAgentBindRemove   uint32 = 2 // Agent binding removal event
+	TradeDataEventSubaccountUpdate  uint32 = 3 // Subaccount update event
+	TradeDataEventOrderUpdate       uint32 = 4 // Order update event
+	TradeDataEventCollateralChange  uint32 = 5 // Position collateral change event
+	TradeDataEventTransferInUpdate  uint32 = 6 // Transfer-in update event
+	TradeDataEventTransferOutUpdate uint32 = 7 // Transfer-out update event
+)
+
+// TradeDataEvent private account event pushed on the tradeData WebSocket channel
+type TradeDataEvent struct {
+	EventType                uint32                 `json:"eventType"`                // Event type
+	IsSnapshot               bool                   `json:"isSnapshot"`               // Whether it is a full snapshot
+	Version                  string                 `json:"version"`                  // Event version
+	SubaccountList           []Subaccount           `json:"subaccountList"`           // Updated subaccounts
+	OrderList                []Order                `json:"orderList"`                // Updated orders
+	CollateralList           []PerpetualCollateral  `json:"collateralList"`           // Updated collaterals
+	PositionList             []PerpetualPosition    `json:"positionList"`             // Updated positions
+	OrderFillTransactionList []OrderFillTransaction `json:"orderFillTransactionList"` // New order fills
+}
+
 // =============================== Request and Response Structures ===============================
 
 // GetActiveOrderReq get active orders request
@@ -0,0 +1,164 @@
+package udf
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// Handler serves the /config, /symbols, /history and /time endpoints of the UDF protocol, mount it under the datafeed
+// URL given to the UDF adapter, e.g. http.Handle("/udf/", http.StripPrefix("/udf", handler))
+type Handler struct {
+	source    Source
+	priceType string
+	mux       *http.ServeMux
+
+	mu        sync.Mutex
+	exchanges map[string]types.Exchange // Exchanges by symbol and by ID
+}
+
+// NewHandler creates a UDF handler serving the K-lines of a price type, defaults to constants.PriceTypeLast
+func NewHandler(source Source, priceType string) *Handler {
+	if priceType == "" {
+		priceType = constants.PriceTypeLast
+	}
+	h := &Handler{source: source, priceType: priceType, mux: http.NewServeMux()}
+	h.mux.HandleFunc("/config", h.serveConfig)
+	h.mux.HandleFunc("/symbols", h.serveSymbols)
+	h.mux.HandleFunc("/history", h.serveHistory)
+	h.mux.HandleFunc("/time", h.serveTime)
+	return h
+}
+
+// ServeHTTP serves a UDF request
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// History reads the K-lines of a symbol, or exchange ID, between from and to (unit: seconds, inclusive), limited to the
+// last countback bars when countback is positive
+func (h *Handler) History(symbol, resolution string, from, to int64, countback int) (*History, error) {
+	exchange, err := h.exchange(symbol)
+	if err != nil {
+		return nil, err
+	}
+	klineType, err := KlineType(resolution)
+	if err != nil {
+		return nil, err
+	}
+	req := types.GetKLineReq{
+		ExchangeId:                    exchange.Id,
+		KlineType:                     klineType,
+		PriceType:                     h.priceType,
+		Size:                          100,
+		FilterBeginKlineTimeInclusive: from * 1000,
+		FilterEndKlineTimeExclusive:   (to + 1) * 1000,
+	}
+	var klines []types.KLine
+	for {
+		resp, err := h.source.GetKline(req)
+		if err != nil {
+			return nil, err
+		}
+		klines = append(klines, resp.Data.KlineList...)
+		if resp.Data.NextPageOffsetData == "" {
+			break
+		}
+		req.OffsetData = resp.Data.NextPageOffsetData
+	}
+	history, err := NewHistory(klines)
+	if err != nil {
+		return nil, err
+	}
+	if n := len(history.Time); countback > 0 && n > countback {
+		history.Time = history.Time[n-countback:]
+		history.Open = history.Open[n-countback:]
+		history.High = history.High[n-countback:]
+		history.Low = history.Low[n-countback:]
+		history.Close = history.Close[n-countback:]
+		history.Volume = history.Volume[n-countback:]
+	}
+	return history, nil
+}
+
+// serveConfig serves the datafeed configuration
+func (h *Handler) serveConfig(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, Config{
+		SupportedResolutions: SupportedResolutions(),
+		SupportsTime:         true,
+	})
+}
+
+// serveSymbols serves the information of a symbol
+func (h *Handler) serveSymbols(w http.ResponseWriter, r *http.Request) {
+	exchange, err := h.exchange(r.URL.Query().Get("symbol"))
+	if err != nil {
+		writeJSON(w, http.StatusNotFound, History{Status: "error", ErrMsg: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, NewSymbolInfo(&exchange))
+}
+
+// serveHistory serves the bars of a symbol
+func (h *Handler) serveHistory(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	from, err := strconv.ParseInt(q.Get("from"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, History{Status: "error", ErrMsg: "invalid from"})
+		return
+	}
+	to, err := strconv.ParseInt(q.Get("to"), 10, 64)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, History{Status: "error", ErrMsg: "invalid to"})
+		return
+	}
+	countback, _ := strconv.Atoi(q.Get("countback"))
+	history, err := h.History(q.Get("symbol"), q.Get("resolution"), from, to, countback)
+	if err != nil {
+		// The UDF adapter reads errors from the body
+		writeJSON(w, http.StatusOK, History{Status: "error", ErrMsg: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, history)
+}
+
+// serveTime serves the server time in seconds
+func (h *Handler) serveTime(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain")
+	fmt.Fprint(w, time.Now().Unix())
+}
+
+// exchange finds an exchange by symbol or ID, reloading the exchange list once when it is unknown
+func (h *Handler) exchange(symbol string) (types.Exchange, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if exchange, ok := h.exchanges[symbol]; ok {
+		return exchange, nil
+	}
+	list, err := h.source.GetExchangeList()
+	if err != nil {
+		return types.Exchange{}, err
+	}
+	h.exchanges = make(map[string]types.Exchange, 2*len(list))
+	for _, exchange := range list {
+		h.exchanges[exchange.Symbol] = exchange
+		h.exchanges[exchange.Id] = exchange
+	}
+	if exchange, ok := h.exchanges[symbol]; ok {
+		return exchange, nil
+	}
+	return types.Exchange{}, fmt.Errorf("unknown symbol %q", symbol)
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,211 @@
+// Package udf serves K-lines in the TradingView charting library UDF format, the datafeed protocol of its UDF adapter
+package udf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+)
+
+// Resolutions TradingView resolutions by K-line type
+var Resolutions = map[string]string{
+	constants.KlineTypeMinute1:  "1",
+	constants.KlineTypeMinute5:  "5",
+	constants.KlineTypeMinute15: "15",
+	constants.KlineTypeMinute30: "30",
+	constants.KlineTypeHour1:    "60",
+	constants.KlineTypeHour2:    "120",
+	constants.KlineTypeHour4:    "240",
+	constants.KlineTypeHour6:    "360",
+	constants.KlineTypeHour8:    "480",
+	constants.KlineTypeHour12:   "720",
+	constants.KlineTypeDay1:     "1D",
+	constants.KlineTypeWeek1:    "1W",
+	constants.KlineTypeMonth1:   "1M",
+}
+
+// Source gateway queries the adapter reads from, e.g. a *query.Client or an *sdk.AntxClient
+type Source interface {
+	GetExchangeList() ([]types.Exchange, error)
+	GetKline(req types.GetKLineReq) (*types.GetKLineResp, error)
+}
+
+// Config UDF /config response
+type Config struct {
+	SupportedResolutions   []string `json:"supported_resolutions"`
+	SupportsGroupRequest   bool     `json:"supports_group_request"`
+	SupportsMarks          bool     `json:"supports_marks"`
+	SupportsSearch         bool     `json:"supports_search"`
+	SupportsTimescaleMarks bool     `json:"supports_timescale_marks"`
+	SupportsTime           bool     `json:"supports_time"`
+}
+
+// SymbolInfo UDF /symbols response
+type SymbolInfo struct {
+	Name                 string   `json:"name"`
+	Ticker               string   `json:"ticker"`
+	Description          string   `json:"description"`
+	Type                 string   `json:"type"`
+	Session              string   `json:"session"`
+	Timezone             string   `json:"timezone"`
+	Exchange             string   `json:"exchange"`
+	ListedExchange       string   `json:"listed_exchange"`
+	MinMov               int      `json:"minmov"`
+	PriceScale           int64    `json:"pricescale"`
+	HasIntraday          bool     `json:"has_intraday"`
+	HasDaily             bool     `json:"has_daily"`
+	HasWeeklyAndMonthly  bool     `json:"has_weekly_and_monthly"`
+	VolumePrecision      int32    `json:"volume_precision"`
+	DataStatus           string   `json:"data_status"`
+	SupportedResolutions []string `json:"supported_resolutions"`
+}
+
+// History UDF /history response, bar times in seconds
+type History struct {
+	Status   string    `json:"s"`
+	ErrMsg   string    `json:"errmsg,omitempty"`
+	NextTime int64     `json:"nextTime,omitempty"`
+	Time     []int64   `json:"t,omitempty"`
+	Open     []float64 `json:"o,omitempty"`
+	High     []float64 `json:"h,omitempty"`
+	Low      []float64 `json:"l,omitempty"`
+	Close    []float64 `json:"c,omitempty"`
+	Volume   []float64 `json:"v,omitempty"`
+}
+
+// Bar bar passed to the onRealtimeCallback of a datafeed subscribeBars, time in milliseconds
+type Bar struct {
+	Time   int64   `json:"time"`
+	Open   float64 `json:"open"`
+	High   float64 `json:"high"`
+	Low    float64 `json:"low"`
+	Close  float64 `json:"close"`
+	Volume float64 `json:"volume"`
+}
+
+// SupportedResolutions returns the TradingView resolutions of all K-line types, shortest first
+func SupportedResolutions() []string {
+	resolutions := make([]string, 0, len(Resolutions))
+	for _, resolution := range Resolutions {
+		resolutions = append(resolutions, resolution)
+	}
+	sort.Slice(resolutions, func(i, j int) bool { return resolutionOrder(resolutions[i]) < resolutionOrder(resolutions[j]) })
+	return resolutions
+}
+
+// KlineType returns the K-line type of a TradingView resolution, accepting the D, W and M shorthands
+func KlineType(resolution string) (string, error) {
+	switch resolution {
+	case "D":
+		resolution = "1D"
+	case "W":
+		resolution = "1W"
+	case "M":
+		resolution = "1M"
+	}
+	for klineType, r := range Resolutions {
+		if r == resolution {
+			return klineType, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported resolution %q", resolution)
+}
+
+// NewSymbolInfo returns the symbol information of an exchange
+func NewSymbolInfo(exchange *types.Exchange) SymbolInfo {
+	priceScale := int64(1)
+	if exchange.TickSizeScale > 0 {
+		priceScale = int64(math.Pow10(int(exchange.TickSizeScale)))
+	}
+	volumePrecision := exchange.StepSizeScale
+	if volumePrecision < 0 {
+		volumePrecision = 0
+	}
+	return SymbolInfo{
+		Name:                 exchange.Symbol,
+		Ticker:               exchange.Symbol,
+		Description:          exchange.Symbol,
+		Type:                 "crypto",
+		Session:              "24x7",
+		Timezone:             "Etc/UTC",
+		Exchange:             "ANTX",
+		ListedExchange:       "ANTX",
+		MinMov:               1,
+		PriceScale:           priceScale,
+		HasIntraday:          true,
+		HasDaily:             true,
+		HasWeeklyAndMonthly:  true,
+		VolumePrecision:      volumePrecision,
+		DataStatus:           "streaming",
+		SupportedResolutions: SupportedResolutions(),
+	}
+}
+
+// NewBar converts a K-line, e.g. from a WebSocket kline push, to a realtime bar
+func NewBar(kline *types.KLine) (Bar, error) {
+	var values [5]float64
+	for i, value := range []string{kline.Open, kline.High, kline.Low, kline.Close, kline.Size} {
+		if value == "" {
+			continue
+		}
+		var err error
+		if values[i], err = strconv.ParseFloat(value, 64); err != nil {
+			return Bar{}, fmt.Errorf("failed to parse kline %d: %w", kline.KlineTime, err)
+		}
+	}
+	return Bar{
+		Time:   int64(kline.KlineTime),
+		Open:   values[0],
+		High:   values[1],
+		Low:    values[2],
+		Close:  values[3],
+		Volume: values[4],
+	}, nil
+}
+
+// NewHistory converts K-lines to a history response, sorted by time, no_data when there are none
+func NewHistory(klines []types.KLine) (*History, error) {
+	sorted := append([]types.KLine(nil), klines...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].KlineTime < sorted[j].KlineTime })
+	history := &History{Status: "ok"}
+	if len(sorted) == 0 {
+		history.Status = "no_data"
+		return history, nil
+	}
+	for i := range sorted {
+		bar, err := NewBar(&sorted[i])
+		if err != nil {
+			return nil, err
+		}
+		history.Time = append(history.Time, bar.Time/1000)
+		history.Open = append(history.Open, bar.Open)
+		history.High = append(history.High, bar.High)
+		history.Low = append(history.Low, bar.Low)
+		history.Close = append(history.Close, bar.Close)
+		history.Volume = append(history.Volume, bar.Volume)
+	}
+	return history, nil
+}
+
+// resolutionOrder returns the length of a resolution in minutes for sorting
+func resolutionOrder(resolution string) int {
+	unit := 1
+	switch {
+	case strings.HasSuffix(resolution, "D"):
+		unit = 24 * 60
+	case strings.HasSuffix(resolution, "W"):
+		unit = 7 * 24 * 60
+	case strings.HasSuffix(resolution, "M"):
+		unit = 30 * 24 * 60
+	}
+	n, err := strconv.Atoi(strings.TrimRight(resolution, "DWM"))
+	if err != nil {
+		n = 1
+	}
+	return n * unit
+}
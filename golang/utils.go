@@ -8,51 +8,103 @@ import (
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/common"
 	ethCrypto "github.com/ethereum/go-ethereum/crypto"
-	"github.com/zeromicro/go-zero/core/logx"
 )
 
-func ConvertToAntxAddr(addrString string) (string, error) {
+// parseAddress parses a hex, bech32 account or bech32 validator address into its bytes
+func parseAddress(addrString string) ([]byte, error) {
 	if addrString == "" {
-		return "", fmt.Errorf("addr can't be empty")
+		return nil, fmt.Errorf("addr can't be empty")
 	}
 	conf := sdk.GetConfig()
-	var addr []byte
 	switch {
 	case common.IsHexAddress(addrString):
-		addr = common.HexToAddress(addrString).Bytes()
+		return common.HexToAddress(addrString).Bytes(), nil
 	case strings.HasPrefix(addrString, conf.GetBech32ValidatorAddrPrefix()):
-		addr, _ = sdk.ValAddressFromBech32(addrString)
+		addr, err := sdk.ValAddressFromBech32(addrString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bech32 validator address '%s': %w", addrString, err)
+		}
+		return addr, nil
 	case strings.HasPrefix(addrString, conf.GetBech32AccountAddrPrefix()):
-		addr, _ = sdk.AccAddressFromBech32(addrString)
+		addr, err := sdk.AccAddressFromBech32(addrString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bech32 account address '%s': %w", addrString, err)
+		}
+		return addr, nil
 	default:
-		return "", fmt.Errorf("expected a valid hex or bech32 address (acc prefix %s), got '%s'",
+		return nil, fmt.Errorf("expected a valid hex or bech32 address (acc prefix %s), got '%s'",
 			conf.GetBech32AccountAddrPrefix(), addrString)
 	}
+}
 
-	return sdk.AccAddress(addr).String(), nil
+// ValidateAddress checks that addrString is a valid hex or bech32 address
+func ValidateAddress(addrString string) error {
+	_, err := parseAddress(addrString)
+	return err
 }
 
-func ConvertToEthAddr(addrString string) (string, error) {
-	if addrString == "" {
-		return "", fmt.Errorf("addr can't be empty")
+// ValidateEthAddress checks that addrString is a valid hex address, mixed-case addresses must carry a valid EIP-55 checksum
+func ValidateEthAddress(addrString string) error {
+	if !common.IsHexAddress(addrString) {
+		return fmt.Errorf("invalid hex address '%s'", addrString)
 	}
-	conf := sdk.GetConfig()
-	var addr []byte
-	switch {
-	case common.IsHexAddress(addrString):
-		addr = common.HexToAddress(addrString).Bytes()
-	case strings.HasPrefix(addrString, conf.GetBech32ValidatorAddrPrefix()):
-		addr, _ = sdk.ValAddressFromBech32(addrString)
-	case strings.HasPrefix(addrString, conf.GetBech32AccountAddrPrefix()):
-		addr, _ = sdk.AccAddressFromBech32(addrString)
-	default:
-		return "", fmt.Errorf("expected a valid hex or bech32 address (acc prefix %s), got '%s'",
-			conf.GetBech32AccountAddrPrefix(), addrString)
+	hexPart := strings.TrimPrefix(strings.TrimPrefix(addrString, "0x"), "0X")
+	if hexPart != strings.ToLower(hexPart) && hexPart != strings.ToUpper(hexPart) &&
+		common.HexToAddress(addrString).Hex()[2:] != hexPart {
+		return fmt.Errorf("invalid checksum of hex address '%s'", addrString)
+	}
+	return nil
+}
+
+// NormalizeEthAddress returns the EIP-55 checksummed form of a hex address
+func NormalizeEthAddress(addrString string) (string, error) {
+	if err := ValidateEthAddress(addrString); err != nil {
+		return "", err
 	}
+	return common.HexToAddress(addrString).Hex(), nil
+}
 
+// ConvertToAntxAddr converts a hex or bech32 address to a bech32 account address
+func ConvertToAntxAddr(addrString string) (string, error) {
+	addr, err := parseAddress(addrString)
+	if err != nil {
+		return "", err
+	}
+	return sdk.AccAddress(addr).String(), nil
+}
+
+// ConvertToEthAddr converts a hex or bech32 address to a checksummed hex address
+func ConvertToEthAddr(addrString string) (string, error) {
+	addr, err := parseAddress(addrString)
+	if err != nil {
+		return "", err
+	}
 	return common.BytesToAddress(addr).Hex(), nil
 }
 
+// ConvertToAntxAddrs converts addresses with ConvertToAntxAddr, the error names the index of the first invalid address
+func ConvertToAntxAddrs(addrStrings []string) ([]string, error) {
+	return convertAddrs(addrStrings, ConvertToAntxAddr)
+}
+
+// ConvertToEthAddrs converts addresses with ConvertToEthAddr, the error names the index of the first invalid address
+func ConvertToEthAddrs(addrStrings []string) ([]string, error) {
+	return convertAddrs(addrStrings, ConvertToEthAddr)
+}
+
+// convertAddrs applies convert to every address
+func convertAddrs(addrStrings []string, convert func(string) (string, error)) ([]string, error) {
+	converted := make([]string, len(addrStrings))
+	for i, addrString := range addrStrings {
+		addr, err := convert(addrString)
+		if err != nil {
+			return nil, fmt.Errorf("address %d: %w", i, err)
+		}
+		converted[i] = addr
+	}
+	return converted, nil
+}
+
 func VerifyEthPersonalSignature(address string, data []byte, sig []byte) bool {
 	sigHash, _ := accounts.TextAndHash(data)
 
@@ -62,8 +114,6 @@ func VerifyEthPersonalSignature(address string, data []byte, sig []byte) bool {
 
 	sigPublicKey, err := ethCrypto.SigToPub(sigHash, sig)
 	if err != nil {
-		logx.Errorf("invalid signature crypto.SigToPub err: %v", err)
-
 		return false
 	}
 
@@ -0,0 +1,96 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	// DefaultWalletConnectChain default CAIP-2 chain of WalletConnect requests
+	DefaultWalletConnectChain = "eip155:1"
+	// DefaultWalletConnectTimeout default time the wallet user has to approve a request
+	DefaultWalletConnectTimeout = 5 * time.Minute
+)
+
+// WalletConnectSession approved WalletConnect v2 session, implemented on top of a WalletConnect client library
+type WalletConnectSession interface {
+	// Accounts returns the CAIP-10 accounts approved in the session, e.g. "eip155:1:0xab16..."
+	Accounts() []string
+	// Request sends a JSON-RPC request to the wallet on a CAIP-2 chain and returns the raw result
+	Request(ctx context.Context, chain, method string, params []interface{}) (json.RawMessage, error)
+}
+
+// WalletConnectSigner signs BindAgent messages with personal_sign in a mobile wallet connected through WalletConnect v2,
+// so that the owner private key never reaches the application
+type WalletConnectSigner struct {
+	Session WalletConnectSession // Approved session
+	Chain   string               // CAIP-2 chain of the requests, defaults to DefaultWalletConnectChain
+	Address string               // Owner address, defaults to the first session account on Chain
+	Timeout time.Duration        // Time the user has to approve a request, defaults to DefaultWalletConnectTimeout
+}
+
+// OwnerAddress returns the checksummed owner address that signs
+func (s *WalletConnectSigner) OwnerAddress() (string, error) {
+	if s.Address != "" {
+		return NormalizeEthAddress(s.Address)
+	}
+	prefix := s.chain() + ":"
+	for _, account := range s.Session.Accounts() {
+		if strings.HasPrefix(account, prefix) {
+			return NormalizeEthAddress(strings.TrimPrefix(account, prefix))
+		}
+	}
+	return "", fmt.Errorf("walletconnect session has no account on %s", s.chain())
+}
+
+// Sign requests the personal_sign signature of message from the wallet
+func (s *WalletConnectSigner) Sign(message string) ([]byte, error) {
+	address, err := s.OwnerAddress()
+	if err != nil {
+		return nil, err
+	}
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultWalletConnectTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	result, err := s.Session.Request(ctx, s.chain(), "personal_sign", []interface{}{hexutil.Encode([]byte(message)), address})
+	if err != nil {
+		return nil, fmt.Errorf("walletconnect personal_sign failed: %w", err)
+	}
+	var signature string
+	if err := json.Unmarshal(result, &signature); err != nil {
+		return nil, fmt.Errorf("failed to parse walletconnect signature: %w", err)
+	}
+	decoded, err := hexutil.Decode(signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid walletconnect signature: %w", err)
+	}
+	return decoded, nil
+}
+
+// chain returns the CAIP-2 chain of the requests
+func (s *WalletConnectSigner) chain() string {
+	if s.Chain == "" {
+		return DefaultWalletConnectChain
+	}
+	return s.Chain
+}
+
+// BindAgentWithWalletConnect binds the client agent to the wallet owner of a WalletConnect session, expireTime is in seconds from now.
+// When caller is not nil contract wallet signatures are accepted through EIP-1271
+func (c *AntxClient) BindAgentWithWalletConnect(signer *WalletConnectSigner, chainId string, expireTime uint64, caller ethereum.ContractCaller) (string, error) {
+	ownerAddress, err := signer.OwnerAddress()
+	if err != nil {
+		return "", err
+	}
+	return c.BindAgentWithSigner(ownerAddress, chainId, expireTime, signer.Sign, caller)
+}
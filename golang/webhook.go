@@ -0,0 +1,344 @@
+package sdk
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/antxprotocol/antx-sdk-golang/constants"
+	"github.com/antxprotocol/antx-sdk-golang/types"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// DefaultWebhookMaxRetries default number of retries of a failed delivery
+	DefaultWebhookMaxRetries = 5
+	// DefaultWebhookRetryBackoff default delay before the first retry, doubled on each retry
+	DefaultWebhookRetryBackoff = time.Second
+	// DefaultWebhookTimeout default timeout of one delivery attempt
+	DefaultWebhookTimeout = 10 * time.Second
+	// DefaultWebhookQueueSize default number of events waiting for delivery, further events are dropped
+	DefaultWebhookQueueSize = 1024
+	// maxWebhookRetryBackoff upper bound of the retry delay
+	maxWebhookRetryBackoff = time.Minute
+)
+
+// Webhook request headers
+const (
+	WebhookHeaderSignature = "X-Antx-Signature" // "sha256=" followed by the hex HMAC-SHA256 of timestamp + "." + body
+	WebhookHeaderTimestamp = "X-Antx-Timestamp" // Signing time, unit: milliseconds
+	WebhookHeaderEvent     = "X-Antx-Event"     // Event type
+	WebhookHeaderDelivery  = "X-Antx-Delivery"  // Event ID, the same on every retry
+)
+
+// WebhookEventType webhook event type
+type WebhookEventType string
+
+// Webhook event types
+const (
+	WebhookEventFill              WebhookEventType = "fill"                // Order fill, data is a types.OrderFillTransaction
+	WebhookEventLiquidation       WebhookEventType = "liquidation"         // Liquidation fill, data is a types.OrderFillTransaction
+	WebhookEventOrderRejected     WebhookEventType = "order_rejected"      // Rejected order, data is a types.Order
+	WebhookEventMarginRatioBreach WebhookEventType = "margin_ratio_breach" // Margin ratio above the threshold, data is a MarginRatioBreach
+	WebhookEventPnlAlert          WebhookEventType = "pnl_alert"           // Fill realizing a PnL beyond the threshold, data is a types.OrderFillTransaction
+)
+
+// WebhookEvent body of a webhook request
+type WebhookEvent struct {
+	Id           string           `json:"id"`           // Event ID, for deduplication by the receiver
+	Type         WebhookEventType `json:"type"`         // Event type
+	SubaccountId string           `json:"subaccountId"` // Subaccount ID
+	Time         int64            `json:"time"`         // Event time, unit: milliseconds
+	Data         interface{}      `json:"data"`         // Event data, depending on the type
+}
+
+// MarginRatioBreach data of a margin ratio breach event
+type MarginRatioBreach struct {
+	SubaccountId string `json:"subaccountId"` // Subaccount ID
+	MarginRatio  string `json:"marginRatio"`  // Reported margin ratio
+	Threshold    string `json:"threshold"`    // Configured threshold
+}
+
+// WebhookConfig webhook notifier configuration
+type WebhookConfig struct {
+	URL                  string                             // Endpoint receiving the POST requests
+	Secret               string                             // HMAC-SHA256 key of the signature header, unsigned when empty
+	Events               []WebhookEventType                 // Events to deliver, all when empty
+	MarginRatioThreshold decimal.Decimal                    // Margin ratio above which a breach is notified, zero disables
+	PnlAlertThreshold    decimal.Decimal                    // Absolute realized PnL of a fill from which a PnL alert is notified, zero disables
+	Encode               func(WebhookEvent) ([]byte, error) // Request body of an event, the event as JSON when nil
+	MaxRetries           int                                // Retries of a failed delivery, defaults to DefaultWebhookMaxRetries
+	RetryBackoff         time.Duration                      // Delay before the first retry, defaults to DefaultWebhookRetryBackoff
+	Timeout              time.Duration                      // Timeout of one attempt, defaults to DefaultWebhookTimeout
+	QueueSize            int                                // Events waiting for delivery, defaults to DefaultWebhookQueueSize
+	HTTPClient           *http.Client                       // HTTP client, a client with Timeout when nil
+	ErrorHandler         func(error)                        // Called on dropped and undeliverable events, errors are logged when nil
+	Logger               Logger                             // Receives the errors when ErrorHandler is nil, nil for go-zero logx
+}
+
+// WebhookNotifier POSTs signed JSON events to a webhook endpoint, retrying failed deliveries with exponential backoff
+type WebhookNotifier struct {
+	config WebhookConfig
+	events map[WebhookEventType]bool
+	queue  chan WebhookEvent
+
+	mu       sync.Mutex
+	breached map[string]bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// NewWebhookNotifier creates a webhook notifier, Start begins the deliveries
+func NewWebhookNotifier(config WebhookConfig) (*WebhookNotifier, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("webhook URL is required")
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = DefaultWebhookMaxRetries
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = DefaultWebhookRetryBackoff
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = DefaultWebhookTimeout
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultWebhookQueueSize
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: config.Timeout}
+	}
+	if config.Encode == nil {
+		config.Encode = func(event WebhookEvent) ([]byte, error) { return json.Marshal(event) }
+	}
+	var events map[WebhookEventType]bool
+	if len(config.Events) > 0 {
+		events = make(map[WebhookEventType]bool, len(config.Events))
+		for _, eventType := range config.Events {
+			events[eventType] = true
+		}
+	}
+	return &WebhookNotifier{
+		config:   config,
+		events:   events,
+		queue:    make(chan WebhookEvent, config.QueueSize),
+		breached: make(map[string]bool),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// Start delivers queued events until Stop is called
+func (n *WebhookNotifier) Start() {
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		for {
+			select {
+			case <-n.done:
+				return
+			case event := <-n.queue:
+				if err := n.deliver(event); err != nil {
+					n.report(err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the deliveries, events still queued are not delivered
+func (n *WebhookNotifier) Stop() {
+	n.once.Do(func() { close(n.done) })
+	n.wg.Wait()
+}
+
+// Notify queues an event unless its type is filtered out, the event is dropped when the queue is full
+func (n *WebhookNotifier) Notify(event WebhookEvent) {
+	if n.events != nil && !n.events[event.Type] {
+		return
+	}
+	if event.Time == 0 {
+		event.Time = time.Now().UnixMilli()
+	}
+	select {
+	case n.queue <- event:
+	default:
+		n.report(fmt.Errorf("queue full, dropped %s event %s", event.Type, event.Id))
+	}
+}
+
+// ApplyTradeData notifies the fills, liquidation fills, PnL alerts and rejected orders of an account event
+func (n *WebhookNotifier) ApplyTradeData(event *types.TradeDataEvent) {
+	if event.IsSnapshot {
+		return
+	}
+	for _, fill := range event.OrderFillTransactionList {
+		n.Notify(WebhookEvent{Id: "fill-" + fill.Id, Type: WebhookEventFill, SubaccountId: fill.SubaccountId, Time: int64(fill.CreatedTime), Data: fill})
+		if fill.IsLiquidate {
+			n.Notify(WebhookEvent{Id: "liquidation-" + fill.Id, Type: WebhookEventLiquidation, SubaccountId: fill.SubaccountId, Time: int64(fill.CreatedTime), Data: fill})
+		}
+		if n.config.PnlAlertThreshold.IsPositive() && fill.RealizePnl != "" {
+			pnl, err := decimal.NewFromString(fill.RealizePnl)
+			if err == nil && pnl.Abs().GreaterThanOrEqual(n.config.PnlAlertThreshold) {
+				n.Notify(WebhookEvent{Id: "pnl_alert-" + fill.Id, Type: WebhookEventPnlAlert, SubaccountId: fill.SubaccountId, Time: int64(fill.CreatedTime), Data: fill})
+			}
+		}
+	}
+	for _, order := range event.OrderList {
+		if order.Status == constants.OrderStatusRejected {
+			n.Notify(WebhookEvent{Id: "order_rejected-" + order.Id, Type: WebhookEventOrderRejected, SubaccountId: order.SubaccountId, Time: int64(order.UpdatedTime), Data: order})
+		}
+	}
+}
+
+// UpdateMarginRatio notifies a breach when the margin ratio of a subaccount rises above the threshold, once until it
+// falls back below. The ratio is computed by the caller, e.g. maintenance margin over equity.
+func (n *WebhookNotifier) UpdateMarginRatio(subaccountId string, marginRatio decimal.Decimal) {
+	threshold := n.config.MarginRatioThreshold
+	if !threshold.IsPositive() {
+		return
+	}
+	breached := marginRatio.GreaterThan(threshold)
+	n.mu.Lock()
+	wasBreached := n.breached[subaccountId]
+	n.breached[subaccountId] = breached
+	n.mu.Unlock()
+	if !breached || wasBreached {
+		return
+	}
+	now := time.Now().UnixMilli()
+	n.Notify(WebhookEvent{
+		Id:           fmt.Sprintf("margin_ratio_breach-%s-%d", subaccountId, now),
+		Type:         WebhookEventMarginRatioBreach,
+		SubaccountId: subaccountId,
+		Time:         now,
+		Data:         MarginRatioBreach{SubaccountId: subaccountId, MarginRatio: marginRatio.String(), Threshold: threshold.String()},
+	})
+}
+
+// NotifyWebhooks feeds webhook notifiers from the private account stream until the returned stop function is called
+func (c *AntxClient) NotifyWebhooks(notifiers ...*WebhookNotifier) (stop func(), err error) {
+	if len(notifiers) == 0 {
+		return nil, fmt.Errorf("at least one notifier is required")
+	}
+	tradeDataChan, err := c.SubscribeToTradeData()
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(chan struct{})
+	var once sync.Once
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case msg := <-tradeDataChan:
+				event, err := c.ParseTradeDataEvent(msg)
+				if err != nil {
+					notifiers[0].report(err)
+					continue
+				}
+				for _, notifier := range notifiers {
+					notifier.ApplyTradeData(event)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(done) })
+	}, nil
+}
+
+// deliver posts an event, retrying network errors, 429 and 5xx responses
+func (n *WebhookNotifier) deliver(event WebhookEvent) error {
+	body, err := n.config.Encode(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s event %s: %w", event.Type, event.Id, err)
+	}
+	backoff := n.config.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		retryable, err := n.post(event, body)
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= n.config.MaxRetries {
+			return fmt.Errorf("failed to deliver %s event %s after %d attempts: %w", event.Type, event.Id, attempt+1, err)
+		}
+		select {
+		case <-n.done:
+			return fmt.Errorf("stopped before delivering %s event %s: %w", event.Type, event.Id, err)
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > maxWebhookRetryBackoff {
+			backoff = maxWebhookRetryBackoff
+		}
+	}
+}
+
+// post sends one attempt and reports whether a failure is worth retrying
+func (n *WebhookNotifier) post(event WebhookEvent, body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, n.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookHeaderTimestamp, timestamp)
+	req.Header.Set(WebhookHeaderEvent, string(event.Type))
+	req.Header.Set(WebhookHeaderDelivery, event.Id)
+	if n.config.Secret != "" {
+		req.Header.Set(WebhookHeaderSignature, SignWebhook(n.config.Secret, timestamp, body))
+	}
+
+	resp, err := n.config.HTTPClient.Do(req)
+	if err != nil {
+		// Drop the URL from the error, it may carry a token
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return true, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
+
+// report passes an error to the configured handler
+func (n *WebhookNotifier) report(err error) {
+	if n.config.ErrorHandler != nil {
+		n.config.ErrorHandler(err)
+		return
+	}
+	orLogx(n.config.Logger).Errorf("webhook notifier: %v", err)
+}
+
+// SignWebhook returns the signature header value of a webhook body
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook checks the signature of a received webhook in constant time, receivers should also reject stale
+// timestamps to prevent replays
+func VerifyWebhook(secret, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhook(secret, timestamp, body)), []byte(signature))
+}